@@ -0,0 +1,88 @@
+// Command backfill-locations fills the structured location columns (location_country,
+// location_region, location_city, latitude, longitude, remote_scope; migrations/011)
+// and the geohash radius search uses (migrations/012) of jobs stored before locations
+// were resolved, or resolves them again after the gazetteer (GAZETTEER_FILE) changed.
+//
+//	go run ./cmd/backfill-locations [-dry-run]
+package main
+
+import (
+	"flag"
+	"log"
+
+	"openjobs/internal/database"
+	"openjobs/pkg/geo"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+
+	"github.com/joho/godotenv"
+)
+
+// pageSize is the number of jobs read per request
+const pageSize = 500
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("⚠️  No .env file found, using environment variables")
+	}
+	if err := database.Connect(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	store := storage.NewJobStore()
+	gazetteer := geo.FromEnv()
+
+	var scanned, updated, failed int
+	afterID := ""
+	for {
+		jobs, err := store.GetJobsAfter(afterID, pageSize)
+		if err != nil {
+			log.Fatalf("Failed to read jobs after %q: %v", afterID, err)
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		afterID = jobs[len(jobs)-1].ID
+
+		for _, job := range jobs {
+			scanned++
+			before := *job
+			gazetteer.Normalize(job)
+			if sameLocation(&before, job) {
+				continue
+			}
+
+			updated++
+			if *dryRun {
+				log.Printf("   %s: %q → %s / %s / %s %s", job.ID, job.Location, job.LocationCountry, job.LocationRegion, job.LocationCity, job.RemoteScope)
+				continue
+			}
+			if err := store.UpdateJob(job); err != nil {
+				failed++
+				log.Printf("⚠️  Failed to update %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	if *dryRun {
+		log.Printf("✅ Dry run: %d jobs scanned, %d jobs would be updated", scanned, updated)
+		return
+	}
+	log.Printf("✅ %d jobs scanned, %d jobs updated (%d failed)", scanned, updated-failed, failed)
+}
+
+func sameLocation(a, b *models.JobPost) bool {
+	return a.LocationCountry == b.LocationCountry && a.LocationRegion == b.LocationRegion &&
+		a.LocationCity == b.LocationCity && a.RemoteScope == b.RemoteScope && a.Geohash == b.Geohash &&
+		sameFloat(a.Latitude, b.Latitude) && sameFloat(a.Longitude, b.Longitude)
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,141 @@
+// Command backfill-salaries fills salary_annual_min_eur and salary_annual_max_eur for
+// jobs stored before salaries were normalized (migrations/010). Jobs whose salary text
+// was never parsed into a period are parsed again with their connector's defaults;
+// then every job with an annual salary is converted with the current exchange-rate
+// table (EXCHANGE_RATES_FILE), which is stored in exchange_rates first.
+//
+//	go run ./cmd/backfill-salaries [-dry-run]
+package main
+
+import (
+	"flag"
+	"log"
+	"strconv"
+	"strings"
+
+	"openjobs/internal/database"
+	"openjobs/pkg/currency"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+
+	"github.com/joho/godotenv"
+)
+
+// pageSize is the number of jobs read per request
+const pageSize = 500
+
+// defaults are the currency and period each connector assumes for salary texts that
+// name none, by job ID prefix (see connectors/*: salary.Parse)
+var defaults = map[string]struct {
+	currency string
+	period   salary.Period
+}{
+	"arbetsformedlingen": {"SEK", salary.Month},
+	"jooble":             {"SEK", salary.Month},
+	"offentligajobb":     {"SEK", salary.Month},
+	"remotive":           {"USD", salary.Year},
+	"remoteok":           {"USD", salary.Year},
+	"hackernews":         {"", salary.Year},
+	"adzuna":             {"", salary.Year},
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("⚠️  No .env file found, using environment variables")
+	}
+	if err := database.Connect(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	store := storage.NewJobStore()
+	rates := currency.FromEnv()
+	log.Printf("💱 Using exchange rates %s (%d currencies)", rates.Version, len(rates.Rates))
+	if !*dryRun {
+		if err := store.SaveExchangeRates(rates); err != nil {
+			log.Fatalf("Failed to save exchange rates: %v", err)
+		}
+	}
+
+	var scanned, parsed, updated, failed int
+	afterID := ""
+	for {
+		jobs, err := store.GetJobsAfter(afterID, pageSize)
+		if err != nil {
+			log.Fatalf("Failed to read jobs after %q: %v", afterID, err)
+		}
+		if len(jobs) == 0 {
+			break
+		}
+		afterID = jobs[len(jobs)-1].ID
+
+		for _, job := range jobs {
+			scanned++
+			minEUR, maxEUR, version := job.SalaryAnnualMinEUR, job.SalaryAnnualMaxEUR, job.Fields["salary_rates_version"]
+
+			if reparse(job) {
+				parsed++
+			}
+			rates.Normalize(job)
+			if sameInt(minEUR, job.SalaryAnnualMinEUR) && sameInt(maxEUR, job.SalaryAnnualMaxEUR) &&
+				version == job.Fields["salary_rates_version"] {
+				continue
+			}
+
+			updated++
+			if *dryRun {
+				log.Printf("   %s: %s → %s–%s EUR/year", job.ID, job.Salary, format(job.SalaryAnnualMinEUR), format(job.SalaryAnnualMaxEUR))
+				continue
+			}
+			if err := store.UpdateJob(job); err != nil {
+				failed++
+				log.Printf("⚠️  Failed to update %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	if *dryRun {
+		log.Printf("✅ Dry run: %d jobs scanned, %d salaries parsed, %d jobs would be updated", scanned, parsed, updated)
+		return
+	}
+	log.Printf("✅ %d jobs scanned, %d salaries parsed, %d jobs updated (%d failed)", scanned, parsed, updated-failed, failed)
+}
+
+// reparse parses the salary text of a job that was stored before pkg/salary, which
+// left fields.salary_period unset. It reports whether it found a salary.
+func reparse(job *models.JobPost) bool {
+	if job.Salary == "" || job.Fields["salary_period"] != nil {
+		return false
+	}
+	source := strings.SplitN(job.ID, jobid.Separator, 2)[0]
+	def, ok := defaults[source]
+	if !ok {
+		def.currency = job.SalaryCurrency
+	}
+
+	pay := salary.Parse(job.Salary, def.currency, def.period)
+	if !pay.HasAmount() || pay.Period == "" || pay.Currency == "" {
+		// Nothing to convert to euros
+		return false
+	}
+	pay.Apply(job)
+	return true
+}
+
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func format(v *int) string {
+	if v == nil {
+		return "?"
+	}
+	return strconv.Itoa(*v)
+}
@@ -0,0 +1,220 @@
+// Command build-gazetteer builds the gazetteer pkg/geo resolves job locations with
+// from a GeoNames dump (https://download.geonames.org/export/dump/):
+//
+//	countryInfo.txt        countries and their continents
+//	admin1CodesASCII.txt   regions (län, states, ...)
+//	cities15000.txt        cities, or cities500/1000/5000.txt or a country file (SE.txt)
+//	alternateNamesV2.txt   optional: Swedish and English names and abbreviations
+//
+// Without alternateNamesV2.txt places only get their GeoNames and ASCII names.
+//
+//	go run ./cmd/build-gazetteer -dir ./geonames -cities cities5000.txt -o gazetteer.tsv.gz
+//
+// Then set GAZETTEER_FILE to the output.
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type country struct {
+	code, continent, name string
+	geonameID             string
+}
+
+type region struct {
+	key, name, asciiName string
+	geonameID            string
+}
+
+type city struct {
+	geonameID, key, name, asciiName string
+	lat, lon                        string
+	population                      int
+}
+
+// usStateCode matches the admin1 codes that are also what people write ("CA", "NY")
+var usStateCode = regexp.MustCompile(`^US\.[A-Z]{2}$`)
+
+func main() {
+	dir := flag.String("dir", ".", "directory with the GeoNames dump files")
+	citiesFile := flag.String("cities", "cities15000.txt", "GeoNames cities file in -dir")
+	out := flag.String("o", "gazetteer.tsv", "output file, gzipped if it ends in .gz")
+	only := flag.String("countries", "", "comma-separated ISO codes to keep (default all)")
+	minPopulation := flag.Int("min-population", 0, "leave out smaller cities")
+	languages := flag.String("languages", "sv,en,abbr", "alternate name languages to keep")
+	flag.Parse()
+
+	keep := map[string]bool{}
+	for _, code := range strings.Split(*only, ",") {
+		if code = strings.TrimSpace(strings.ToUpper(code)); code != "" {
+			keep[code] = true
+		}
+	}
+	wanted := func(code string) bool { return len(keep) == 0 || keep[code] }
+
+	var countries []*country
+	err := readTSV(filepath.Join(*dir, "countryInfo.txt"), func(cols []string) {
+		if len(cols) > 16 && wanted(cols[0]) {
+			countries = append(countries, &country{code: cols[0], name: cols[4], continent: cols[8], geonameID: cols[16]})
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to read countries: %v", err)
+	}
+
+	var regions []*region
+	err = readTSV(filepath.Join(*dir, "admin1CodesASCII.txt"), func(cols []string) {
+		if len(cols) > 3 && wanted(strings.SplitN(cols[0], ".", 2)[0]) {
+			regions = append(regions, &region{key: cols[0], name: cols[1], asciiName: cols[2], geonameID: cols[3]})
+		}
+	})
+	if err != nil {
+		log.Fatalf("Failed to read regions: %v", err)
+	}
+
+	var cities []*city
+	err = readTSV(filepath.Join(*dir, *citiesFile), func(cols []string) {
+		if len(cols) < 15 || !wanted(cols[8]) || cols[6] != "P" {
+			return
+		}
+		population, _ := strconv.Atoi(cols[14])
+		if population < *minPopulation {
+			return
+		}
+		cities = append(cities, &city{
+			geonameID: cols[0], key: cols[8] + "." + cols[10], name: cols[1], asciiName: cols[2],
+			lat: cols[4], lon: cols[5], population: population,
+		})
+	})
+	if err != nil {
+		log.Fatalf("Failed to read cities: %v", err)
+	}
+
+	// Alternate names of the places above in the wanted languages
+	ids := map[string]bool{}
+	for _, c := range countries {
+		ids[c.geonameID] = true
+	}
+	for _, r := range regions {
+		ids[r.geonameID] = true
+	}
+	for _, c := range cities {
+		ids[c.geonameID] = true
+	}
+	langs := map[string]bool{}
+	for _, lang := range strings.Split(*languages, ",") {
+		langs[strings.TrimSpace(lang)] = true
+	}
+	alternates := map[string][]string{}
+	err = readTSV(filepath.Join(*dir, "alternateNamesV2.txt"), func(cols []string) {
+		// alternateNameId, geonameid, isolanguage, name, isPreferred, isShort, isColloquial, isHistoric
+		if len(cols) < 4 || !ids[cols[1]] || !langs[cols[2]] || (len(cols) > 7 && cols[7] == "1") {
+			return
+		}
+		alternates[cols[1]] = append(alternates[cols[1]], cols[3])
+	})
+	if os.IsNotExist(err) {
+		log.Println("⚠️  No alternateNamesV2.txt, places only get their GeoNames names")
+	} else if err != nil {
+		log.Fatalf("Failed to read alternate names: %v", err)
+	}
+
+	w, closeOutput, err := create(*out)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v", *out, err)
+	}
+	fmt.Fprintln(w, "# OpenJobs gazetteer built by cmd/build-gazetteer from GeoNames (https://www.geonames.org, CC BY 4.0)")
+	for _, c := range countries {
+		fmt.Fprintf(w, "country\t%s\t%s\t%s\t%s\n", c.code, c.continent, c.name, names(c.name, alternates[c.geonameID]))
+	}
+	for _, r := range regions {
+		extra := append([]string{r.asciiName}, alternates[r.geonameID]...)
+		if usStateCode.MatchString(r.key) {
+			extra = append(extra, r.key[3:])
+		}
+		fmt.Fprintf(w, "region\t%s\t%s\t%s\n", r.key, r.name, names(r.name, extra))
+	}
+	sort.SliceStable(cities, func(i, j int) bool { return cities[i].population > cities[j].population })
+	for _, c := range cities {
+		extra := append([]string{c.asciiName}, alternates[c.geonameID]...)
+		fmt.Fprintf(w, "city\t%s\t%s\t%s\t%s\t%d\t%s\n", c.key, c.name, c.lat, c.lon, c.population, names(c.name, extra))
+	}
+	if err := closeOutput(); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+	log.Printf("✅ Wrote %d countries, %d regions and %d cities to %s", len(countries), len(regions), len(cities), *out)
+}
+
+// readTSV calls fn with the columns of each line that isn't a comment
+func readTSV(path string, fn func(cols []string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fn(strings.Split(line, "\t"))
+	}
+	return scanner.Err()
+}
+
+// names joins the alternate names that differ from name, without tabs or duplicates
+func names(name string, alternates []string) string {
+	seen := map[string]bool{strings.ToLower(name): true}
+	var list []string
+	for _, alt := range alternates {
+		alt = strings.TrimSpace(strings.NewReplacer("\t", " ", "|", " ").Replace(alt))
+		if alt == "" || seen[strings.ToLower(alt)] {
+			continue
+		}
+		seen[strings.ToLower(alt)] = true
+		list = append(list, alt)
+	}
+	return strings.Join(list, "|")
+}
+
+// create opens the output file, gzipped if its name ends in .gz
+func create(path string) (io.Writer, func() error, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	buffered := bufio.NewWriter(f)
+	if !strings.HasSuffix(path, ".gz") {
+		return buffered, func() error {
+			if err := buffered.Flush(); err != nil {
+				return err
+			}
+			return f.Close()
+		}, nil
+	}
+	gz := gzip.NewWriter(buffered)
+	return gz, func() error {
+		if err := gz.Close(); err != nil {
+			return err
+		}
+		if err := buffered.Flush(); err != nil {
+			return err
+		}
+		return f.Close()
+	}, nil
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"openjobs/internal/api"
@@ -238,6 +239,10 @@ func main() {
 	http.HandleFunc("/jobs/", func(w http.ResponseWriter, r *http.Request) {
 		switch r.Method {
 		case http.MethodGet:
+			if strings.HasSuffix(r.URL.Path, "/duplicates") {
+				server.GetJobDuplicates(w, r)
+				return
+			}
 			server.GetJobByID(w, r)
 		case http.MethodPut:
 			server.UpdateJob(w, r)
@@ -280,6 +285,10 @@ func main() {
 	fmt.Println("📝 Registering route: /sync/logs")
 	http.HandleFunc("/sync/logs", middleware.CORS(server.SyncLogsHandler))
 
+	// Connector checkpoints (GET to inspect, DELETE ?connector=&query= to reset)
+	fmt.Println("📝 Registering route: /sync/checkpoints")
+	http.HandleFunc("/sync/checkpoints", middleware.CORS(server.CheckpointsHandler))
+
 	// Plugin status route
 	fmt.Println("📝 Registering route: /plugins/status")
 	http.HandleFunc("/plugins/status", middleware.CORS(server.PluginStatusHandler))
@@ -288,6 +297,10 @@ func main() {
 	fmt.Println("📝 Registering route: /platform/metrics")
 	http.HandleFunc("/platform/metrics", middleware.CORS(server.PlatformMetricsHandler))
 
+	// Outbound HTTP metrics (per-host rate limits, 429/503 backoff, robots.txt blocks)
+	fmt.Println("📝 Registering route: /metrics/http")
+	http.HandleFunc("/metrics/http", middleware.CORS(server.HTTPMetricsHandler))
+
 	// Start server
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -9,6 +9,7 @@ import (
 
 	"openjobs/connectors/arbetsformedlingen"
 	"openjobs/internal/database"
+	"openjobs/pkg/httpclient"
 	"openjobs/pkg/models"
 	"openjobs/pkg/storage"
 
@@ -22,6 +23,9 @@ type PluginServer struct {
 }
 
 func main() {
+	// Executable plugin mode: the core talks to us over stdin/stdout
+	protocolOut, stdio := models.StdioPluginMode()
+
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️  No .env file found, using environment variables")
@@ -37,6 +41,13 @@ func main() {
 	store := storage.NewJobStore()
 	connector := arbetsformedlingen.NewArbetsformedlingenConnector(store)
 
+	if stdio {
+		if err := models.ServeExecPlugin(connector, os.Stdin, protocolOut); err != nil {
+			log.Fatalf("Plugin protocol error: %v", err)
+		}
+		return
+	}
+
 	server := &PluginServer{
 		connector: connector,
 		store:     store,
@@ -71,6 +82,7 @@ func (s *PluginServer) healthHandler(w http.ResponseWriter, r *http.Request) {
 		"plugin":    "Arbetsförmedlingen Connector",
 		"plugin_id": s.connector.GetID(),
 		"version":   "1.0.0",
+		"http":      httpclient.Snapshot(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"openjobs/connectors/feeds"
+	"openjobs/internal/database"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+
+	"github.com/joho/godotenv"
+)
+
+// PluginServer handles HTTP requests for the plugin
+type PluginServer struct {
+	connector models.PluginConnector
+	store     *storage.JobStore
+}
+
+func main() {
+	// Executable plugin mode: the core talks to us over stdin/stdout
+	protocolOut, stdio := models.StdioPluginMode()
+
+	// Load .env file if it exists
+	if err := godotenv.Load(); err != nil {
+		log.Println("⚠️  No .env file found, using environment variables")
+	} else {
+		log.Println("✅ Plugin loaded .env file")
+	}
+
+	// Connect to shared database
+	if err := database.Connect(); err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	store := storage.NewJobStore()
+
+	// Feed URLs and extraction rules come from FEEDS_CONFIG
+	configPath := os.Getenv("FEEDS_CONFIG")
+	if configPath == "" {
+		log.Fatal("FEEDS_CONFIG must point to a feed config file (.json, .yaml or .yml)")
+	}
+	config, err := feeds.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load feed config: %v", err)
+	}
+	connector := feeds.NewFeedConnector(store, config)
+
+	if stdio {
+		if err := models.ServeExecPlugin(connector, os.Stdin, protocolOut); err != nil {
+			log.Fatalf("Plugin protocol error: %v", err)
+		}
+		return
+	}
+
+	server := &PluginServer{
+		connector: connector,
+		store:     store,
+	}
+
+	// Register routes
+	http.HandleFunc("/health", server.healthHandler)
+	http.HandleFunc("/sync", server.syncHandler)
+	http.HandleFunc("/jobs", server.jobsHandler)
+
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8092"
+	}
+
+	log.Printf("Feeds Plugin starting on port %s", port)
+	log.Printf("Plugin ID: %s", connector.GetID())
+	log.Printf("Plugin Name: %s", connector.GetName())
+
+	log.Fatal(http.ListenAndServe(":"+port, nil))
+}
+
+// healthHandler returns plugin health status
+func (s *PluginServer) healthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := map[string]interface{}{
+		"status":    "healthy",
+		"plugin":    s.connector.GetName(),
+		"plugin_id": s.connector.GetID(),
+		"version":   "1.0.0",
+		"http":      httpclient.Snapshot(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// syncHandler triggers job synchronization and stores in database
+func (s *PluginServer) syncHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	log.Printf("🔄 Starting %s job sync...", s.connector.GetName())
+
+	err := s.connector.SyncJobs()
+	if err != nil {
+		log.Printf("❌ Sync failed: %v", err)
+		http.Error(w, fmt.Sprintf("Sync failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%s sync completed successfully", s.connector.GetName()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// jobsHandler returns the latest jobs fetched by this connector
+func (s *PluginServer) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	jobs, err := s.connector.FetchJobs()
+	if err != nil {
+		log.Printf("❌ Failed to fetch jobs: %v", err)
+		response := map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to fetch jobs: %v", err),
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"data":    jobs,
+		"count":   len(jobs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -9,12 +9,17 @@ import (
 
 	"openjobs/connectors/indeed"
 	"openjobs/internal/database"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/models"
 	"openjobs/pkg/storage"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	// Executable plugin mode: the core talks to us over stdin/stdout
+	protocolOut, stdio := models.StdioPluginMode()
+
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️  No .env file found, using environment variables")
@@ -33,6 +38,13 @@ func main() {
 	// Create Indeed connector
 	connector := indeed.NewIndeedConnector(store)
 
+	if stdio {
+		if err := models.ServeExecPlugin(connector, os.Stdin, protocolOut); err != nil {
+			log.Fatalf("Plugin protocol error: %v", err)
+		}
+		return
+	}
+
 	// Setup HTTP server
 	http.HandleFunc("/health", healthHandler)
 	http.HandleFunc("/sync", syncHandler(connector))
@@ -40,10 +52,10 @@ func main() {
 
 	port := os.Getenv("PORT")
 	if port == "" {
-		port = "8085"
+		port = "8087"
 	}
 
-	fmt.Printf("🚀 Indeed Sweden Plugin starting on port %s...\n", port)
+	fmt.Printf("🚀 Indeed Plugin starting on port %s...\n", port)
 	fmt.Printf("📍 Endpoints:\n")
 	fmt.Printf("   GET  /health - Health check\n")
 	fmt.Printf("   POST /sync   - Trigger job sync\n")
@@ -60,7 +72,7 @@ func healthHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":    "healthy",
 		"connector": "indeed",
-		"country":   "se",
+		"http":      httpclient.Snapshot(),
 	})
 }
 
@@ -9,12 +9,17 @@ import (
 
 	"openjobs/connectors/jooble"
 	"openjobs/internal/database"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/models"
 	"openjobs/pkg/storage"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
+	// Executable plugin mode: the core talks to us over stdin/stdout
+	protocolOut, stdio := models.StdioPluginMode()
+
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️  No .env file found, using environment variables")
@@ -33,6 +38,13 @@ func main() {
 	// Create Jooble connector
 	connector := jooble.NewJoobleConnector(store)
 
+	if stdio {
+		if err := models.ServeExecPlugin(connector, os.Stdin, protocolOut); err != nil {
+			log.Fatalf("Plugin protocol error: %v", err)
+		}
+		return
+	}
+
 	// Setup HTTP server
 	http.HandleFunc("/health", healthHandler)
 	http.HandleFunc("/sync", syncHandler(connector))
@@ -67,10 +79,11 @@ func main() {
 
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
+	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status":  "healthy",
 		"service": "jooble-plugin",
 		"version": "1.0.0",
+		"http":    httpclient.Snapshot(),
 	})
 }
 
@@ -7,14 +7,25 @@ import (
 	"net/http"
 	"os"
 
-	offentligajobb "openjobs/connectors/offentligajobb"
+	"openjobs/connectors/offentligajobb"
 	"openjobs/internal/database"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/models"
 	"openjobs/pkg/storage"
 
 	"github.com/joho/godotenv"
 )
 
+// PluginServer handles HTTP requests for the plugin
+type PluginServer struct {
+	connector models.PluginConnector
+	store     *storage.JobStore
+}
+
 func main() {
+	// Executable plugin mode: the core talks to us over stdin/stdout
+	protocolOut, stdio := models.StdioPluginMode()
+
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
 		log.Println("⚠️  No .env file found, using environment variables")
@@ -27,110 +38,109 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Initialize storage
 	store := storage.NewJobStore()
 
-	// Create Offentliga Jobb connector
-	connector := offentligajobb.NewIndeedChromeConnector(store)
+	connector := offentligajobb.NewOffentligaJobbConnector(store)
+
+	if stdio {
+		if err := models.ServeExecPlugin(connector, os.Stdin, protocolOut); err != nil {
+			log.Fatalf("Plugin protocol error: %v", err)
+		}
+		return
+	}
+
+	server := &PluginServer{
+		connector: connector,
+		store:     store,
+	}
 
-	// Setup HTTP server
-	http.HandleFunc("/health", healthHandler)
-	http.HandleFunc("/sync", syncHandler(connector))
-	http.HandleFunc("/jobs", jobsHandler(connector))
+	// Register routes
+	http.HandleFunc("/health", server.healthHandler)
+	http.HandleFunc("/sync", server.syncHandler)
+	http.HandleFunc("/jobs", server.jobsHandler)
 
 	port := os.Getenv("PORT")
 	if port == "" {
-		port = "8087"
+		port = "8094"
 	}
 
-	fmt.Println("🌐 ========================================")
-	fmt.Println("🌐 Offentliga Jobb Scraper Plugin")
-	fmt.Println("🌐 Headless Chrome - Bypasses Cloudflare!")
-	fmt.Println("🌐 ========================================")
-	fmt.Println()
-	fmt.Printf("🚀 Offentliga Jobb Plugin starting on port %s...\n", port)
-	fmt.Printf("📍 Endpoints:\n")
-	fmt.Printf("   GET  /health - Health check\n")
-	fmt.Printf("   POST /sync   - Trigger Chrome scraping sync\n")
-	fmt.Printf("   GET  /jobs   - List scraped jobs\n")
-	fmt.Println()
-	fmt.Println("⏱️  Rate limit: 3 seconds between requests")
-	fmt.Println("🔍 Queries: developer, engineer, designer")
-	fmt.Println("📄 Pages per query: 2 (20 jobs)")
-	fmt.Println("📊 Expected: ~40-50 unique jobs per sync")
-	fmt.Println("🌐 Method: Headless Chrome (bypasses bot detection)")
-	fmt.Println()
-
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		log.Fatalf("Server failed: %v", err)
-	}
+	log.Printf("Offentliga Jobb Plugin starting on port %s", port)
+	log.Printf("Plugin ID: %s", connector.GetID())
+	log.Printf("Plugin Name: %s", connector.GetName())
+
+	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
-func healthHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+// healthHandler returns plugin health status
+func (s *PluginServer) healthHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	response := map[string]interface{}{
 		"status":    "healthy",
-		"connector": "offentligajobb",
-		"country":   "se",
-		"method":    "headless_chrome",
-		"advantage": "Bypasses Cloudflare bot detection",
-	})
+		"plugin":    s.connector.GetName(),
+		"plugin_id": s.connector.GetID(),
+		"version":   "1.0.0",
+		"http":      httpclient.Snapshot(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-func syncHandler(connector *offentligajobb.IndeedChromeConnector) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+// syncHandler triggers job synchronization and stores in database
+func (s *PluginServer) syncHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		fmt.Println("🔄 Chrome scraping sync triggered via HTTP")
-		fmt.Println("🌐 This may take 3-5 minutes (Chrome is slower but works!)...")
-		
-		err := connector.SyncJobs()
+	log.Printf("🔄 Starting %s job sync...", s.connector.GetName())
 
-		w.Header().Set("Content-Type", "application/json")
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
-		}
+	err := s.connector.SyncJobs()
+	if err != nil {
+		log.Printf("❌ Sync failed: %v", err)
+		http.Error(w, fmt.Sprintf("Sync failed: %v", err), http.StatusInternalServerError)
+		return
+	}
 
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": true,
-			"message": "Offentliga Jobb scraping completed successfully",
-			"method":  "headless_chrome",
-		})
+	response := map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("%s sync completed successfully", s.connector.GetName()),
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-func jobsHandler(connector *offentligajobb.IndeedChromeConnector) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
+// jobsHandler returns the latest jobs fetched by this connector
+func (s *PluginServer) jobsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-		// Fetch jobs from Indeed
-		jobs, err := connector.FetchJobs()
-		if err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"success": false,
-				"error":   err.Error(),
-			})
-			return
+	jobs, err := s.connector.FetchJobs()
+	if err != nil {
+		log.Printf("❌ Failed to fetch jobs: %v", err)
+		response := map[string]interface{}{
+			"success": false,
+			"error":   fmt.Sprintf("Failed to fetch jobs: %v", err),
 		}
-
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"success": true,
-			"count":   len(jobs),
-			"data":    jobs,
-			"method":  "headless_chrome",
-		})
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(response)
+		return
 	}
+
+	response := map[string]interface{}{
+		"success": true,
+		"data":    jobs,
+		"count":   len(jobs),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
@@ -0,0 +1,478 @@
+package adzuna
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+)
+
+// Countries lists every country the Adzuna API serves, with its salary currency
+var Countries = map[string]string{
+	"at": "EUR", "au": "AUD", "be": "EUR", "br": "BRL", "ca": "CAD",
+	"ch": "CHF", "de": "EUR", "es": "EUR", "fr": "EUR", "gb": "GBP",
+	"in": "INR", "it": "EUR", "mx": "MXN", "nl": "EUR", "nz": "NZD",
+	"pl": "PLN", "sg": "SGD", "us": "USD", "za": "ZAR",
+}
+
+// AdzunaConnector implements a connector for the Adzuna job search API
+type AdzunaConnector struct {
+	store          *storage.JobStore
+	baseURL        string
+	userAgent      string
+	appID          string
+	appKey         string
+	countries      []string
+	what           string
+	resultsPerPage int
+	maxPages       int
+	httpClient     *http.Client
+}
+
+// AdzunaJob represents a job from the Adzuna API
+type AdzunaJob struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Company     struct {
+		DisplayName string `json:"display_name"`
+	} `json:"company"`
+	Location struct {
+		DisplayName string   `json:"display_name"`
+		Area        []string `json:"area"`
+	} `json:"location"`
+	Category struct {
+		Label string `json:"label"`
+		Tag   string `json:"tag"`
+	} `json:"category"`
+	SalaryMin         float64     `json:"salary_min,omitempty"`
+	SalaryMax         float64     `json:"salary_max,omitempty"`
+	SalaryIsPredicted json.Number `json:"salary_is_predicted"` // "0"/"1", sometimes a number
+	ContractType      string      `json:"contract_type"`
+	ContractTime      string      `json:"contract_time"`
+	Created           string      `json:"created"`
+	RedirectURL       string      `json:"redirect_url"`
+	Latitude          float64     `json:"latitude,omitempty"`
+	Longitude         float64     `json:"longitude,omitempty"`
+}
+
+// AdzunaResponse represents the API response structure
+type AdzunaResponse struct {
+	Results []AdzunaJob `json:"results"`
+	Count   int         `json:"count"`
+}
+
+// NewAdzunaConnector creates a new Adzuna connector.
+// ADZUNA_COUNTRIES limits the countries (default: all), ADZUNA_WHAT sets the
+// search keywords, ADZUNA_MAX_PAGES the pages per country and
+// ADZUNA_REQUESTS_PER_MINUTE the API plan's rate limit.
+func NewAdzunaConnector(store *storage.JobStore) *AdzunaConnector {
+	countries := []string{}
+	for _, c := range strings.Split(os.Getenv("ADZUNA_COUNTRIES"), ",") {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			countries = append(countries, c)
+		}
+	}
+	if len(countries) == 0 {
+		countries = sortedCountries()
+	}
+
+	maxPages := 5
+	if n, err := strconv.Atoi(os.Getenv("ADZUNA_MAX_PAGES")); err == nil && n > 0 {
+		maxPages = n
+	}
+
+	return &AdzunaConnector{
+		store:          store,
+		baseURL:        "https://api.adzuna.com/v1/api/jobs",
+		userAgent:      httpclient.UserAgent("OpenJobs-Adzuna-Connector/1.0"),
+		appID:          os.Getenv("ADZUNA_APP_ID"),
+		appKey:         os.Getenv("ADZUNA_APP_KEY"),
+		countries:      countries,
+		what:           os.Getenv("ADZUNA_WHAT"),
+		resultsPerPage: 50, // API maximum
+		maxPages:       maxPages,
+		httpClient:     httpclient.New(httpclient.Options{Interval: requestInterval()}),
+	}
+}
+
+// defaultRequestsPerMinute is the free tier's limit
+const defaultRequestsPerMinute = 25
+
+// requestInterval spaces requests to stay within ADZUNA_REQUESTS_PER_MINUTE
+func requestInterval() time.Duration {
+	perMinute := defaultRequestsPerMinute
+	if n, err := strconv.Atoi(os.Getenv("ADZUNA_REQUESTS_PER_MINUTE")); err == nil && n > 0 {
+		perMinute = n
+	}
+	return time.Minute / time.Duration(perMinute)
+}
+
+// GetID returns the connector ID
+func (ac *AdzunaConnector) GetID() string {
+	return "adzuna"
+}
+
+// GetName returns the connector name
+func (ac *AdzunaConnector) GetName() string {
+	return "Adzuna Connector"
+}
+
+// FetchJobs fetches jobs from every configured Adzuna country, page by page
+func (ac *AdzunaConnector) FetchJobs() ([]models.JobPost, error) {
+	if ac.appID == "" || ac.appKey == "" {
+		return nil, fmt.Errorf("ADZUNA_APP_ID and ADZUNA_APP_KEY must be set")
+	}
+
+	runs, err := ac.fetchCountries()
+	if err != nil {
+		return nil, err
+	}
+	allJobs := []models.JobPost{}
+	for _, run := range runs {
+		allJobs = append(allJobs, run.jobs...)
+	}
+	return allJobs, nil
+}
+
+// countryRun is what one sync fetched for a country, and the page to resume at if it
+// didn't reach the last page
+type countryRun struct {
+	checkpoint *models.Checkpoint
+	jobs       []models.JobPost
+	complete   bool
+	next       int
+}
+
+// fetchCountries fetches every configured country from its checkpoint
+func (ac *AdzunaConnector) fetchCountries() ([]*countryRun, error) {
+	runs := []*countryRun{}
+	failed := 0
+
+	for _, country := range ac.countries {
+		if _, ok := Countries[country]; !ok {
+			fmt.Printf("⚠️  Adzuna does not support country %q, skipping\n", country)
+			failed++
+			continue
+		}
+
+		run, err := ac.fetchCountry(country, ac.getCheckpoint(country))
+		if err != nil {
+			fmt.Printf("⚠️  Error fetching jobs from %s: %v\n", country, err)
+			failed++
+		}
+		runs = append(runs, run)
+		fmt.Printf("   ✅ Fetched %d jobs from %s\n", len(run.jobs), country)
+	}
+
+	if failed == len(ac.countries) {
+		return nil, fmt.Errorf("all %d Adzuna countries failed", failed)
+	}
+	return runs, nil
+}
+
+// fetchCountry pages through one country's results until the last page or maxPages,
+// starting at the page where a truncated run stopped
+func (ac *AdzunaConnector) fetchCountry(country string, checkpoint *models.Checkpoint) (*countryRun, error) {
+	run := &countryRun{checkpoint: checkpoint, jobs: []models.JobPost{}}
+	maxDaysOld := ac.maxDaysOld(country, checkpoint)
+	start := checkpoint.ResumeOffset()
+	if start < 1 {
+		start = 1
+	}
+
+	for page := start; page < start+ac.maxPages; page++ {
+		run.next = page + 1
+		response, err := ac.fetchPage(country, page, maxDaysOld)
+		if err != nil {
+			// Keep what earlier pages returned and resume at this page
+			run.next = page
+			return run, err
+		}
+
+		for _, aj := range response.Results {
+			run.jobs = append(run.jobs, ac.transformJob(country, aj))
+		}
+
+		if len(response.Results) < ac.resultsPerPage || page*ac.resultsPerPage >= response.Count {
+			run.complete = true
+			break
+		}
+	}
+
+	return run, nil
+}
+
+// fetchPage fetches /jobs/{country}/search/{page}
+func (ac *AdzunaConnector) fetchPage(country string, page, maxDaysOld int) (*AdzunaResponse, error) {
+	q := url.Values{}
+	q.Set("app_id", ac.appID)
+	q.Set("app_key", ac.appKey)
+	q.Set("results_per_page", strconv.Itoa(ac.resultsPerPage))
+	q.Set("sort_by", "date")
+	q.Set("content-type", "application/json")
+	if ac.what != "" {
+		q.Set("what", ac.what)
+	}
+	if maxDaysOld > 0 {
+		q.Set("max_days_old", strconv.Itoa(maxDaysOld))
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s/search/%d?%s", ac.baseURL, country, page, q.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", ac.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ac.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("adzuna API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response AdzunaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &response, nil
+}
+
+// transformJob converts an Adzuna job to our JobPost format
+func (ac *AdzunaConnector) transformJob(country string, aj AdzunaJob) models.JobPost {
+	posted := parseAdzunaDate(aj.Created)
+	location := aj.Location.DisplayName
+	if location == "" {
+		location = strings.Join(aj.Location.Area, ", ")
+	}
+
+	job := models.JobPost{
+		ID:             jobid.New("adzuna", aj.ID),
+		Title:          aj.Title,
+		Company:        aj.Company.DisplayName,
+		Description:    aj.Description,
+		Location:       location,
+		IsRemote:       strings.Contains(strings.ToLower(aj.Title+" "+location), "remote"),
+		URL:            aj.RedirectURL,
+		EmploymentType: mapEmploymentType(aj.ContractTime, aj.ContractType),
+		PostedDate:     posted,
+		Requirements:   []string{},
+		Benefits:       []string{},
+		Fields: map[string]interface{}{
+			"source":        "adzuna",
+			"source_url":    aj.RedirectURL,
+			"original_id":   aj.ID,
+			"country":       country,
+			"category":      aj.Category.Label,
+			"category_tag":  aj.Category.Tag,
+			"contract_type": aj.ContractType,
+			"contract_time": aj.ContractTime,
+			"location_area": aj.Location.Area,
+			"connector":     "adzuna",
+			"fetched_at":    time.Now(),
+		},
+	}
+	if aj.Category.Label != "" {
+		job.Requirements = append(job.Requirements, aj.Category.Label)
+	}
+	if aj.Latitude != 0 || aj.Longitude != 0 {
+		job.Fields["coordinates"] = []float64{aj.Longitude, aj.Latitude}
+	}
+
+	// Adzuna estimates salaries for ads without one; keep those out of the structured fields
+	if (aj.SalaryMin > 0 || aj.SalaryMax > 0) && aj.SalaryIsPredicted.String() != "1" {
+		currency := Countries[country]
+		pay := salary.Salary{Currency: currency, Period: salary.Year} // Adzuna salaries are annual
+		if aj.SalaryMin > 0 {
+			min := int(aj.SalaryMin)
+			pay.Min = &min
+		}
+		if aj.SalaryMax > 0 {
+			max := int(aj.SalaryMax)
+			pay.Max = &max
+		}
+		pay.Apply(&job)
+		switch {
+		case aj.SalaryMin > 0 && aj.SalaryMax > 0 && aj.SalaryMin != aj.SalaryMax:
+			job.Salary = fmt.Sprintf("%.0f - %.0f %s", aj.SalaryMin, aj.SalaryMax, currency)
+		case aj.SalaryMin > 0:
+			job.Salary = fmt.Sprintf("%.0f %s", aj.SalaryMin, currency)
+		default:
+			job.Salary = fmt.Sprintf("Up to %.0f %s", aj.SalaryMax, currency)
+		}
+	} else if aj.SalaryIsPredicted.String() == "1" {
+		job.Fields["predicted_salary_min"] = aj.SalaryMin
+		job.Fields["predicted_salary_max"] = aj.SalaryMax
+	}
+
+	return job
+}
+
+// getCheckpoint returns the country's committed checkpoint, or an empty one (all pages)
+func (ac *AdzunaConnector) getCheckpoint(country string) *models.Checkpoint {
+	empty := &models.Checkpoint{ConnectorID: ac.GetID(), QueryKey: country}
+	if ac.store == nil {
+		return empty
+	}
+	checkpoint, err := ac.store.GetCheckpoint(ac.GetID(), country)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to read Adzuna %s checkpoint, fetching all pages: %v\n", country, err)
+		return empty
+	}
+	return checkpoint
+}
+
+// maxDaysOld limits results to ads newer than the country's checkpoint (0 = no limit)
+func (ac *AdzunaConnector) maxDaysOld(country string, checkpoint *models.Checkpoint) int {
+	if checkpoint.HighWatermark.IsZero() {
+		fmt.Printf("📅 No Adzuna %s checkpoint yet - fetching all pages\n", country)
+		return 0
+	}
+
+	days := int(time.Since(checkpoint.HighWatermark).Hours()/24) + 1
+	fmt.Printf("📅 Fetching Adzuna %s jobs from the last %d days\n", country, days)
+	return days
+}
+
+// saveCheckpoints commits a checkpoint for every country from the jobs stored for it.
+// A country that stopped before its last page (maxPages or a failed page) keeps its
+// watermark and resumes at that page, so the older ads past it aren't skipped.
+func (ac *AdzunaConnector) saveCheckpoints(runs []*countryRun) error {
+	for _, run := range runs {
+		switch {
+		case run.complete:
+			run.checkpoint.Complete(run.jobs)
+		case len(run.jobs) > 0:
+			fmt.Printf("📄 Adzuna %s has more pages - resuming at page %d next sync\n", run.checkpoint.QueryKey, run.next)
+			run.checkpoint.Truncate(run.next, run.jobs)
+		default:
+			continue
+		}
+		if err := ac.store.SaveCheckpoint(run.checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncJobs fetches jobs from Adzuna and stores them in the database
+func (ac *AdzunaConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Printf("🔄 Starting Adzuna sync (%d countries)...\n", len(ac.countries))
+
+	runs, err := ac.fetchCountries()
+	if err != nil {
+		ac.store.LogSync(&models.SyncLog{
+			ConnectorName: ac.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+		})
+		return fmt.Errorf("failed to fetch jobs from Adzuna: %w", err)
+	}
+	jobs := []models.JobPost{}
+	for _, run := range runs {
+		jobs = append(jobs, run.jobs...)
+	}
+
+	fmt.Printf("📥 Fetched %d jobs from Adzuna\n", len(jobs))
+
+	stored := 0
+	duplicates := 0
+	failed := 0
+	for _, job := range jobs {
+		existing, err := ac.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := ac.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	// Commit the checkpoint only once every job is stored, so failed jobs are retried
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if err := ac.saveCheckpoints(runs); err != nil {
+		fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+	}
+
+	if err := ac.store.LogSync(&models.SyncLog{
+		ConnectorName:  ac.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 Adzuna sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
+	return nil
+}
+
+// parseAdzunaDate parses Adzuna's created timestamp
+func parseAdzunaDate(dateStr string) time.Time {
+	for _, format := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(format, dateStr); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// mapEmploymentType combines contract_time (full/part) and contract_type (permanent/contract)
+func mapEmploymentType(contractTime, contractType string) string {
+	switch {
+	case contractType == "contract":
+		return "Contract"
+	case contractTime == "part_time":
+		return "Part-time"
+	case contractTime == "full_time":
+		return "Full-time"
+	default:
+		return ""
+	}
+}
+
+// sortedCountries returns the supported country codes in a stable order
+func sortedCountries() []string {
+	countries := make([]string, 0, len(Countries))
+	for c := range Countries {
+		countries = append(countries, c)
+	}
+	sort.Strings(countries)
+	return countries
+}
@@ -0,0 +1,195 @@
+package adzuna
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+func TestFetchJobsPaging(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		if r.URL.Query().Get("app_id") != "id" || r.URL.Query().Get("app_key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/gb/search/1":
+			fmt.Fprint(w, `{"count": 3, "results": [
+				{"id": "1", "title": "Go Developer", "company": {"display_name": "Acme"},
+				 "location": {"display_name": "London, UK", "area": ["UK", "London"]},
+				 "salary_min": 50000, "salary_max": 70000, "salary_is_predicted": "0",
+				 "contract_time": "full_time", "created": "2025-10-01T08:00:00Z", "redirect_url": "https://adzuna.example/1"},
+				{"id": "2", "title": "Remote SRE", "company": {"display_name": "Beta"},
+				 "location": {"display_name": "Leeds"}, "salary_min": 40000, "salary_max": 40000,
+				 "salary_is_predicted": "1", "created": "2025-10-01T09:00:00Z"}
+			]}`)
+		case "/gb/search/2":
+			fmt.Fprint(w, `{"count": 3, "results": [
+				{"id": "3", "title": "Tester", "contract_type": "contract", "created": "2025-10-02T09:00:00Z"}
+			]}`)
+		case "/de/search/1":
+			fmt.Fprint(w, `{"count": 1, "results": [
+				{"id": "4", "title": "Entwickler", "salary_min": 60000, "created": "2025-10-02T09:00:00Z"}
+			]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ac := NewAdzunaConnector(nil)
+	ac.baseURL = server.URL
+	ac.appID, ac.appKey = "id", "key"
+	ac.countries = []string{"gb", "de"}
+	ac.resultsPerPage = 2
+	ac.httpClient = httpclient.New(httpclient.Options{})
+
+	jobs, err := ac.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if len(jobs) != 4 || strings.Join(requests, ",") != "/gb/search/1,/gb/search/2,/de/search/1" {
+		t.Fatalf("Expected 4 jobs over 3 pages, got %d jobs from %v", len(jobs), requests)
+	}
+
+	dev := jobs[0]
+	if dev.ID != "adzuna:1" || dev.SalaryCurrency != "GBP" || dev.Salary != "50000 - 70000 GBP" || dev.EmploymentType != "Full-time" {
+		t.Errorf("Unexpected job: %+v", dev)
+	}
+
+	// Predicted salaries stay out of the structured fields
+	sre := jobs[1]
+	if sre.SalaryMin != nil || sre.Salary != "" || sre.Fields["predicted_salary_min"] != 40000.0 || !sre.IsRemote {
+		t.Errorf("Unexpected predicted salary handling: %+v", sre)
+	}
+	if jobs[2].EmploymentType != "Contract" {
+		t.Errorf("Expected Contract, got %q", jobs[2].EmploymentType)
+	}
+	if jobs[3].SalaryCurrency != "EUR" || jobs[3].Fields["country"] != "de" {
+		t.Errorf("Unexpected German job: %+v", jobs[3])
+	}
+}
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *AdzunaConnector {
+	rec := httpreplay.New(t, "testdata/search.json")
+	rec.IgnoreParams("max_days_old") // Depends on the age of the newest stored job
+
+	ac := NewAdzunaConnector(store)
+	if ac.appID == "" || ac.appKey == "" {
+		ac.appID, ac.appKey = "test", "test" // Credentials are redacted from fixtures
+	}
+	ac.countries = []string{"gb", "de"}
+	ac.what = "golang"
+	ac.maxPages = 1
+	ac.httpClient = rec.Client()
+	return ac
+}
+
+// TestFetchJobsGolden replays recorded API responses. Re-record with
+// HTTPREPLAY=record ADZUNA_APP_ID=... ADZUNA_APP_KEY=... go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	ac := newReplayConnector(t, nil)
+
+	jobs, err := ac.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs)
+}
+
+// TestSyncResumesTruncatedCountry checks that a country with more pages than maxPages
+// keeps its watermark and continues at the next page on the following sync
+func TestSyncResumesTruncatedCountry(t *testing.T) {
+	pages := []string{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Path)
+		switch r.URL.Path {
+		case "/gb/search/1":
+			fmt.Fprint(w, `{"count": 3, "results": [{"id": "1", "created": "2025-10-03T08:00:00Z"}, {"id": "2", "created": "2025-10-02T08:00:00Z"}]}`)
+		case "/gb/search/2":
+			fmt.Fprint(w, `{"count": 3, "results": [{"id": "3", "created": "2025-10-01T08:00:00Z"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	server := storagetest.NewServer(t)
+	ac := NewAdzunaConnector(server.Store())
+	ac.baseURL = api.URL
+	ac.appID, ac.appKey = "id", "key"
+	ac.countries = []string{"gb"}
+	ac.resultsPerPage, ac.maxPages = 2, 1
+	ac.httpClient = httpclient.New(httpclient.Options{})
+
+	if err := ac.SyncJobs(); err != nil {
+		t.Fatalf("First SyncJobs failed: %v", err)
+	}
+	if checkpoint := ac.getCheckpoint("gb"); !checkpoint.HighWatermark.IsZero() || checkpoint.ResumeOffset() != 2 {
+		t.Fatalf("Truncated run committed watermark %v, page %d", checkpoint.HighWatermark, checkpoint.ResumeOffset())
+	}
+
+	if err := ac.SyncJobs(); err != nil {
+		t.Fatalf("Second SyncJobs failed: %v", err)
+	}
+	newest := time.Date(2025, 10, 3, 8, 0, 0, 0, time.UTC)
+	if checkpoint := ac.getCheckpoint("gb"); !checkpoint.HighWatermark.Equal(newest) || checkpoint.ResumeOffset() != 0 {
+		t.Errorf("Complete run committed watermark %v (want %v), page %d", checkpoint.HighWatermark, newest, checkpoint.ResumeOffset())
+	}
+	if got := len(server.Jobs()); got != 3 || fmt.Sprint(pages) != "[/gb/search/1 /gb/search/2]" {
+		t.Errorf("Expected 3 jobs from pages 1 and 2, got %d from %v", got, pages)
+	}
+}
+
+func TestFetchJobsRequiresCredentials(t *testing.T) {
+	ac := NewAdzunaConnector(nil)
+	ac.appID, ac.appKey = "", ""
+	if _, err := ac.FetchJobs(); err == nil {
+		t.Fatal("Expected error without credentials")
+	}
+}
+
+func TestCountriesFromEnv(t *testing.T) {
+	if got := len(NewAdzunaConnector(nil).countries); got != len(Countries) {
+		t.Errorf("Expected all %d countries by default, got %d", len(Countries), got)
+	}
+	t.Setenv("ADZUNA_COUNTRIES", "SE, gb")
+	ac := NewAdzunaConnector(nil)
+	if strings.Join(ac.countries, ",") != "se,gb" {
+		t.Errorf("Unexpected countries: %v", ac.countries)
+	}
+	// se is not an Adzuna market, so only gb is tried
+	ac.appID, ac.appKey, ac.baseURL = "id", "key", "http://127.0.0.1:0"
+	if _, err := ac.FetchJobs(); err == nil {
+		t.Error("Expected error when every country fails")
+	}
+}
+
+func TestRequestIntervalFromEnv(t *testing.T) {
+	if got := requestInterval(); got != 2400*time.Millisecond {
+		t.Errorf("Expected 2.4s between requests on the free tier, got %v", got)
+	}
+	t.Setenv("ADZUNA_REQUESTS_PER_MINUTE", "120")
+	if got := requestInterval(); got != 500*time.Millisecond {
+		t.Errorf("Expected 500ms at 120 requests/minute, got %v", got)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
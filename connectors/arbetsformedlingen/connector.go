@@ -5,19 +5,30 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 )
 
+// searchQuery is the JobSearch query; it is also the key of its checkpoint
+const searchQuery = "utvecklare OR programmer OR software"
+
 // ArbetsformedlingenConnector implements connector for Swedish employment service
 type ArbetsformedlingenConnector struct {
-	store     *storage.JobStore
-	baseURL   string
-	userAgent string
+	store        *storage.JobStore
+	baseURL      string
+	userAgent    string
+	mode         string // "jobstream" (default) or "search"
+	streamURL    string
+	streamClient *http.Client
+	searchClient *http.Client
 }
 
 // AFJob represents a job from Arbetsförmedlingen JobSearch API
@@ -137,27 +148,72 @@ func (ac *ArbetsformedlingenConnector) GetName() string {
 
 // NewArbetsformedlingenConnector creates a new connector
 func NewArbetsformedlingenConnector(store *storage.JobStore) *ArbetsformedlingenConnector {
+	// AF_SYNC_MODE=search keeps the old developer-query /search sync
+	mode := modeJobStream
+	if os.Getenv("AF_SYNC_MODE") == modeSearch {
+		mode = modeSearch
+	}
+
 	return &ArbetsformedlingenConnector{
-		store:     store,
-		baseURL:   "https://jobsearch.api.jobtechdev.se",
-		userAgent: "OpenJobs-Arbetsformedlingen-Connector/1.0",
+		store:        store,
+		baseURL:      "https://jobsearch.api.jobtechdev.se",
+		userAgent:    httpclient.UserAgent("OpenJobs-Arbetsformedlingen-Connector/1.0"),
+		mode:         mode,
+		streamURL:    "https://jobstream.api.jobtechdev.se",
+		streamClient: httpclient.New(httpclient.Options{Timeout: 15 * time.Minute}), // The snapshot is every published ad
+		searchClient: httpclient.New(httpclient.Options{Interval: time.Second}),     // One page per second across all AF clients
 	}
 }
 
-// FetchJobs fetches jobs from Arbetsförmedlingen JobSearch API with pagination
+// FetchJobs fetches jobs from Arbetsförmedlingen (JobStream changes, or JobSearch API with pagination)
 func (ac *ArbetsformedlingenConnector) FetchJobs() ([]models.JobPost, error) {
+	if ac.mode == modeJobStream {
+		return ac.fetchStreamJobs()
+	}
+
+	run, err := ac.fetchSearch(ac.getSearchCheckpoint())
+	if err != nil {
+		return nil, err
+	}
+	return run.jobs, nil
+}
+
+// searchRun is what one search sync fetched, and where to resume if it didn't reach
+// the end of the results
+type searchRun struct {
+	jobs     []models.JobPost
+	complete bool
+	next     int
+}
+
+// maxSearchOffset is the highest offset JobSearch serves
+const maxSearchOffset = 2000
+
+// fetchSearch fetches up to 5 pages of ads published after the checkpoint, newest
+// first, resuming at the offset where a truncated run stopped
+func (ac *ArbetsformedlingenConnector) fetchSearch(checkpoint *models.Checkpoint) (*searchRun, error) {
 	allJobs := make([]models.JobPost, 0)
 	
 	// Get last sync time for incremental sync
-	lastSync := ac.getLastSyncTime()
+	lastSync := checkpoint.HighWatermark
+	start := checkpoint.ResumeOffset()
+	if start > 0 {
+		fmt.Printf("⏩ Resuming truncated search at offset %d\n", start)
+	}
 	
 	// Fetch multiple pages (API limit is 100 per request)
 	// Fetch 5 pages = 500 jobs total
 	maxPages := 5
 	limit := 100 // API maximum
+	complete := false
 	
 	for page := 0; page < maxPages; page++ {
-		offset := page * limit
+		offset := start + page*limit
+		if offset > maxSearchOffset {
+			fmt.Printf("⚠️  JobSearch serves no offsets past %d - older ads are skipped\n", maxSearchOffset)
+			complete = true
+			break
+		}
 		
 		fmt.Printf("📄 Fetching page %d/%d (offset: %d, limit: %d)\n", page+1, maxPages, offset, limit)
 		
@@ -173,7 +229,7 @@ func (ac *ArbetsformedlingenConnector) FetchJobs() ([]models.JobPost, error) {
 		
 		// Add query parameters
 		q := req.URL.Query()
-		q.Add("q", "utvecklare OR programmer OR software") // Search for developer/programmer jobs
+		q.Add("q", searchQuery)                            // Search for developer/programmer jobs
 		q.Add("limit", strconv.Itoa(limit))                // API maximum: 100
 		q.Add("offset", strconv.Itoa(offset))              // Pagination offset
 		q.Add("sort", "pubdate-desc")                      // Sort by publication date descending
@@ -190,8 +246,7 @@ func (ac *ArbetsformedlingenConnector) FetchJobs() ([]models.JobPost, error) {
 		req.URL.RawQuery = q.Encode()
 
 		// Make the request
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(req)
+		resp, err := ac.searchClient.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch jobs: %w", err)
 		}
@@ -225,24 +280,17 @@ func (ac *ArbetsformedlingenConnector) FetchJobs() ([]models.JobPost, error) {
 		// If we got fewer jobs than the limit, we've reached the end
 		if len(afResponse.Hits) < limit {
 			fmt.Printf("📊 Reached end of results at page %d\n", page+1)
+			complete = true
 			break
 		}
-		
-		// Rate limiting: wait 1 second between requests
-		if page < maxPages-1 {
-			time.Sleep(1 * time.Second)
-		}
 	}
 
 	fmt.Printf("🎯 Total jobs fetched from Arbetsförmedlingen: %d\n", len(allJobs))
-	return allJobs, nil
+	return &searchRun{jobs: allJobs, complete: complete, next: start + maxPages*limit}, nil
 }
 
 // transformAFJob converts Arbetsförmedlingen job format to our JobPost format
 func (ac *ArbetsformedlingenConnector) transformAFJob(af AFJob) models.JobPost {
-	// Parse salary
-	salaryMin, salaryMax, currency := ac.parseSalary(af.SalaryDescription)
-	
 	// Detect remote work
 	isRemote := ac.detectRemote(af)
 	
@@ -250,15 +298,13 @@ func (ac *ArbetsformedlingenConnector) transformAFJob(af AFJob) models.JobPost {
 	url := ac.extractURL(af)
 	
 	job := models.JobPost{
-		ID:              fmt.Sprintf("af-%s", af.ID),
+		ID:              jobid.New("arbetsformedlingen", af.ID),
 		Title:           af.Headline,
 		Company:         af.Employer.Name,
 		Description:     ac.extractDescription(af),
 		Location:        ac.formatLocation(af),
 		Salary:          af.SalaryDescription,
-		SalaryMin:       salaryMin,
-		SalaryMax:       salaryMax,
-		SalaryCurrency:  currency,
+		SalaryCurrency:  "SEK",
 		IsRemote:        isRemote,
 		URL:             url,
 		EmploymentType:  ac.mapEmploymentType(af.EmploymentType.Label),
@@ -315,6 +361,9 @@ func (ac *ArbetsformedlingenConnector) transformAFJob(af AFJob) models.JobPost {
 		},
 	}
 
+	// Salary descriptions are free text; Swedish salaries are monthly unless stated
+	salary.Parse(af.SalaryDescription, "SEK", salary.Month).Apply(&job)
+
 	return job
 }
 
@@ -564,78 +613,6 @@ func (ac *ArbetsformedlingenConnector) extractURL(af AFJob) string {
 	return fmt.Sprintf("https://arbetsformedlingen.se/platsbanken/annonser/%s", af.ID)
 }
 
-// parseSalary parses salary string to extract min, max, and currency
-func (ac *ArbetsformedlingenConnector) parseSalary(salaryStr string) (*int, *int, string) {
-	if salaryStr == "" {
-		return nil, nil, "SEK"
-	}
-
-	// Common patterns in Swedish job postings:
-	// "45000 - 65000 SEK/månad"
-	// "SEK 45,000 - 65,000"
-	// "45 000 - 65 000 kr/mån"
-	// "Enligt överenskommelse" (by agreement)
-
-	// Check if it's "by agreement" or similar
-	lower := strings.ToLower(salaryStr)
-	if strings.Contains(lower, "överenskommelse") || 
-	   strings.Contains(lower, "agreement") ||
-	   strings.Contains(lower, "enligt ök") {
-		return nil, nil, "SEK"
-	}
-
-	// Remove common Swedish/English words
-	cleanStr := strings.ReplaceAll(salaryStr, "kr", "")
-	cleanStr = strings.ReplaceAll(cleanStr, "SEK", "")
-	cleanStr = strings.ReplaceAll(cleanStr, "månad", "")
-	cleanStr = strings.ReplaceAll(cleanStr, "mån", "")
-	cleanStr = strings.ReplaceAll(cleanStr, "month", "")
-	cleanStr = strings.ReplaceAll(cleanStr, "/", "")
-	cleanStr = strings.ReplaceAll(cleanStr, ",", "")
-	cleanStr = strings.ReplaceAll(cleanStr, " ", "")
-
-	// Try to find two numbers separated by dash or "till"
-	var min, max int
-	var currency string = "SEK"
-
-	// Pattern: "45000-65000" or "45000till65000"
-	if strings.Contains(cleanStr, "-") {
-		parts := strings.Split(cleanStr, "-")
-		if len(parts) == 2 {
-			if m, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
-				min = m
-			}
-			if m, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-				max = m
-			}
-		}
-	} else if strings.Contains(strings.ToLower(cleanStr), "till") {
-		parts := strings.Split(strings.ToLower(cleanStr), "till")
-		if len(parts) == 2 {
-			if m, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
-				min = m
-			}
-			if m, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-				max = m
-			}
-		}
-	} else {
-		// Single number - use as both min and max
-		if m, err := strconv.Atoi(cleanStr); err == nil {
-			min = m
-			max = m
-		}
-	}
-
-	if min > 0 && max > 0 {
-		return &min, &max, currency
-	} else if min > 0 {
-		return &min, nil, currency
-	}
-
-	return nil, nil, currency
-}
-
 // detectRemote checks if the job allows remote work
 func (ac *ArbetsformedlingenConnector) detectRemote(af AFJob) bool {
 	// Check location
@@ -683,12 +660,19 @@ func (ac *ArbetsformedlingenConnector) parseAFDate(dateStr string) time.Time {
 	return time.Now()
 }
 
-// SyncJobs fetches jobs from Arbetsförmedlingen and stores them
+// SyncJobs fetches jobs from Arbetsförmedlingen and stores them.
+// In JobStream mode additions, updates and removals are all applied.
 func (ac *ArbetsformedlingenConnector) SyncJobs() error {
+	if ac.mode == modeJobStream {
+		fmt.Println("🔄 Starting Arbetsförmedlingen JobStream sync...")
+		return ac.syncJobStream()
+	}
+
 	startTime := time.Now()
 	fmt.Println("🔄 Starting Arbetsförmedlingen job sync...")
 
-	jobs, err := ac.FetchJobs()
+	checkpoint := ac.getSearchCheckpoint()
+	run, err := ac.fetchSearch(checkpoint)
 	if err != nil {
 		// Log failed sync
 		ac.store.LogSync(&models.SyncLog{
@@ -703,16 +687,19 @@ func (ac *ArbetsformedlingenConnector) SyncJobs() error {
 		})
 		return fmt.Errorf("failed to fetch jobs from Arbetsförmedlingen: %w", err)
 	}
+	jobs := run.jobs
 
 	fmt.Printf("📥 Fetched %d jobs from Arbetsförmedlingen\n", len(jobs))
 
 	stored := 0
 	duplicates := 0
+	failed := 0
 	for _, job := range jobs {
 		// Check if job already exists
 		existing, err := ac.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -726,6 +713,7 @@ func (ac *ArbetsformedlingenConnector) SyncJobs() error {
 		err = ac.store.CreateJob(&job)
 		if err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -733,6 +721,13 @@ func (ac *ArbetsformedlingenConnector) SyncJobs() error {
 		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
 	}
 
+	// ⭐ Save sync position for next incremental sync, only once every job is stored
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if err := ac.saveSearchCheckpoint(checkpoint, run); err != nil {
+		fmt.Printf("⚠️  Failed to save sync timestamp: %v\n", err)
+	}
+
 	// Log successful sync
 	if err := ac.store.LogSync(&models.SyncLog{
 		ConnectorName:  ac.GetID(),
@@ -746,11 +741,6 @@ func (ac *ArbetsformedlingenConnector) SyncJobs() error {
 		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
 	}
 
-	// ⭐ Save sync timestamp for next incremental sync
-	if err := ac.saveLastSyncTime(); err != nil {
-		fmt.Printf("⚠️  Failed to save sync timestamp: %v\n", err)
-	}
-	
 	fmt.Printf("🎉 Arbetsförmedlingen sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
 	return nil
 }
@@ -772,24 +762,36 @@ func (ac *ArbetsformedlingenConnector) extractSkillLabels(skills interface{}) []
 	return labels
 }
 
-// getLastSyncTime retrieves the timestamp of the most recent job in database
-// This is used for incremental sync - only fetch jobs newer than this
-func (ac *ArbetsformedlingenConnector) getLastSyncTime() time.Time {
-	// Query the most recent job's posted_date from our connector
-	job, err := ac.store.GetMostRecentJob("af-")
+// getSearchCheckpoint returns the checkpoint committed by the last successful search sync
+// This is used for incremental sync - only fetch jobs newer than its high watermark
+func (ac *ArbetsformedlingenConnector) getSearchCheckpoint() *models.Checkpoint {
+	empty := &models.Checkpoint{ConnectorID: ac.GetID(), QueryKey: searchQuery}
+	if ac.store == nil {
+		return empty
+	}
+	checkpoint, err := ac.store.GetCheckpoint(ac.GetID(), searchQuery)
 	if err != nil {
-		// No jobs yet or error - this is first sync
-		fmt.Println("📅 No previous jobs found - fetching all jobs")
-		return time.Time{}
+		fmt.Printf("⚠️  Failed to read checkpoint, fetching all jobs: %v\n", err)
+		return empty
 	}
-	
-	fmt.Printf("📅 Last job in database: %s (posted: %s)\n", job.Title, job.PostedDate.Format("2006-01-02"))
-	return job.PostedDate
+	if checkpoint.HighWatermark.IsZero() {
+		fmt.Println("📅 No checkpoint yet - fetching all jobs")
+		return checkpoint
+	}
+
+	fmt.Printf("📅 Checkpoint: jobs posted up to %s\n", checkpoint.HighWatermark.Format("2006-01-02"))
+	return checkpoint
 }
 
-// saveLastSyncTime is no longer needed - database tracks this automatically
-// Keeping empty function for compatibility
-func (ac *ArbetsformedlingenConnector) saveLastSyncTime() error {
-	// Database automatically tracks via posted_date - no action needed
-	return nil
+// saveSearchCheckpoint commits the newest posted date of the stored jobs as the checkpoint
+// once a run reaches the end of the results. A truncated run keeps the old watermark and
+// records the offset to resume at, so the ads past the page limit aren't skipped.
+func (ac *ArbetsformedlingenConnector) saveSearchCheckpoint(checkpoint *models.Checkpoint, run *searchRun) error {
+	if run.complete {
+		checkpoint.Complete(run.jobs)
+	} else {
+		fmt.Printf("📄 More ads than the page limit - resuming at offset %d next sync\n", run.next)
+		checkpoint.Truncate(run.next, run.jobs)
+	}
+	return ac.store.SaveCheckpoint(checkpoint)
 }
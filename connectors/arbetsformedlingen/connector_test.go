@@ -0,0 +1,94 @@
+package arbetsformedlingen
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+// newSearchConnector returns a JobSearch mode connector that replays the recorded page
+func newSearchConnector(t *testing.T, store *storage.JobStore) *ArbetsformedlingenConnector {
+	rec := httpreplay.New(t, "testdata/search.json")
+	rec.IgnoreParams("published-after") // Follows the newest stored job
+
+	ac := NewArbetsformedlingenConnector(store)
+	ac.mode = modeSearch
+	ac.searchClient = rec.Client()
+	return ac
+}
+
+// TestSearchGolden replays a recorded JobSearch page. Re-record with
+// HTTPREPLAY=record go test -run Golden
+func TestSearchGolden(t *testing.T) {
+	ac := newSearchConnector(t, nil)
+
+	jobs, err := ac.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs)
+}
+
+// TestSearchResumesTruncatedRun checks that a search with more new ads than the page
+// limit keeps its watermark and resumes at the offset it stopped at
+func TestSearchResumesTruncatedRun(t *testing.T) {
+	newest := time.Date(2025, 10, 10, 12, 0, 0, 0, time.UTC)
+	offsets := []string{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		hits := []string{}
+		for i := offset; i < offset+100 && i < 530; i++ {
+			posted := newest.Add(-time.Duration(i) * time.Minute).Format("2006-01-02T15:04:05")
+			hits = append(hits, fmt.Sprintf(`{"id": "%d", "headline": "Utvecklare", "publication_date": %q}`, i, posted))
+		}
+		fmt.Fprintf(w, `{"hits": [%s]}`, strings.Join(hits, ","))
+	}))
+	defer api.Close()
+
+	server := storagetest.NewServer(t)
+	ac := NewArbetsformedlingenConnector(server.Store())
+	ac.mode = modeSearch
+	ac.baseURL = api.URL
+	ac.searchClient = httpclient.New(httpclient.Options{})
+
+	if err := ac.SyncJobs(); err != nil {
+		t.Fatalf("First SyncJobs failed: %v", err)
+	}
+	checkpoint := ac.getSearchCheckpoint()
+	if !checkpoint.HighWatermark.IsZero() || checkpoint.ResumeOffset() != 500 {
+		t.Fatalf("Truncated run committed watermark %v, offset %d", checkpoint.HighWatermark, checkpoint.ResumeOffset())
+	}
+
+	if err := ac.SyncJobs(); err != nil {
+		t.Fatalf("Second SyncJobs failed: %v", err)
+	}
+	checkpoint = ac.getSearchCheckpoint()
+	if !checkpoint.HighWatermark.Equal(newest) || checkpoint.ResumeOffset() != 0 {
+		t.Errorf("Complete run committed watermark %v (want %v), offset %d", checkpoint.HighWatermark, newest, checkpoint.ResumeOffset())
+	}
+	if got := len(server.Jobs()); got != 530 {
+		t.Errorf("Expected all 530 ads stored, got %d", got)
+	}
+	if fmt.Sprint(offsets) != "[0 100 200 300 400 500]" {
+		t.Errorf("Unexpected offsets: %v", offsets)
+	}
+}
+
+// TestConformance covers JobSearch mode; the stream windows follow the clock
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newSearchConnector(t, store)
+	}, conformance.Options{})
+}
@@ -0,0 +1,417 @@
+package arbetsformedlingen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	_ "time/tzdata" // JobStream timestamps are Stockholm time; alpine images have no zoneinfo
+
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+)
+
+// JobStream modes: a full snapshot on the first run, then incremental /stream polls
+const (
+	modeSearch    = "search"
+	modeJobStream = "jobstream"
+
+	streamTimeLayout = "2006-01-02T15:04:05"
+	// streamQueryKey is the checkpoint query holding the JobStream cursor
+	streamQueryKey = "jobstream"
+	// streamRetryQueryKey is the checkpoint query listing the ads that failed to store
+	streamRetryQueryKey = "jobstream:retry"
+	// maxStreamRetries bounds the retry list; a run with more failures is an outage,
+	// so it is replayed as a whole instead of retried ad by ad
+	maxStreamRetries = 1000
+	// streamLag keeps the upper bound slightly in the past so ads still being indexed aren't skipped
+	streamLag = time.Minute
+)
+
+var stockholm = loadStockholm()
+
+func loadStockholm() *time.Location {
+	if loc, err := time.LoadLocation("Europe/Stockholm"); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// streamAd is a JobStream ad: a full AFJob, or only an ID for removed ads
+type streamAd struct {
+	AFJob
+	Removed     bool   `json:"removed"`
+	RemovedDate string `json:"removed_date"`
+}
+
+// streamResult counts what one JobStream run changed
+type streamResult struct {
+	fetched   int
+	inserted  int
+	updated   int
+	removed   int
+	failed    int
+	failedIDs []string        // Ads to retry on the next run
+	seen      map[string]bool // Ad IDs applied this run
+}
+
+// fail counts an ad that could not be stored and lists it for retry
+func (r *streamResult) fail(id string) {
+	r.failed++
+	r.failedIDs = append(r.failedIDs, id)
+}
+
+// fetchStreamJobs returns the ads added or changed since the last checkpoint
+// (the last 24 hours when there is none) without storing anything
+func (ac *ArbetsformedlingenConnector) fetchStreamJobs() ([]models.JobPost, error) {
+	checkpoint, err := ac.lastCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().Add(-24 * time.Hour)
+	if !checkpoint.IsZero() {
+		since = checkpoint
+	}
+
+	jobs := []models.JobPost{}
+	err = ac.readStream(since, time.Now().Add(-streamLag), func(ad streamAd) error {
+		if !ad.Removed {
+			jobs = append(jobs, ac.transformAFJob(ad.AFJob))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("🎯 %d changed ads in JobStream since %s\n", len(jobs), since.In(stockholm).Format(streamTimeLayout))
+	return jobs, nil
+}
+
+// syncJobStream applies a snapshot (first run) or the stream since the last checkpoint
+// and retries the ads that failed before, then commits the new checkpoint together with
+// the ads that failed this time
+func (ac *ArbetsformedlingenConnector) syncJobStream() error {
+	startTime := time.Now()
+	until := startTime.Add(-streamLag)
+	checkpoint, err := ac.lastCheckpoint()
+	var retries []string
+	if err == nil {
+		retries, err = ac.failedAds()
+	}
+	if err != nil {
+		ac.store.LogSync(&models.SyncLog{
+			ConnectorName: ac.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+		})
+		return err
+	}
+
+	result := &streamResult{seen: map[string]bool{}}
+	apply := func(ad streamAd) error {
+		ac.applyStreamAd(ad, result)
+		return nil
+	}
+
+	if checkpoint.IsZero() {
+		fmt.Println("📸 No JobStream checkpoint - loading full snapshot")
+		err = ac.readSnapshot(apply)
+	} else {
+		fmt.Printf("📡 Streaming changes %s → %s\n",
+			checkpoint.In(stockholm).Format(streamTimeLayout), until.In(stockholm).Format(streamTimeLayout))
+		err = ac.readStream(checkpoint, until, apply)
+	}
+	if err != nil {
+		ac.store.LogSync(&models.SyncLog{
+			ConnectorName: ac.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			JobsFetched:   result.fetched,
+			JobsInserted:  result.inserted,
+			JobsUpdated:   result.updated,
+			JobsRemoved:   result.removed,
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+		})
+		return fmt.Errorf("failed to read Arbetsförmedlingen JobStream: %w", err)
+	}
+
+	// The snapshot is the complete listing: close the stored ads it no longer has
+	var closeErr error
+	if checkpoint.IsZero() {
+		listed := make([]string, 0, len(result.seen))
+		for id := range result.seen {
+			listed = append(listed, jobid.New("arbetsformedlingen", id))
+		}
+		closed, err := ac.store.CloseMissingJobs(ac.GetID(), listed, time.Now())
+		result.removed += len(closed)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to close jobs missing from the snapshot - checkpoint not advanced: %v\n", err)
+			closeErr = err
+		}
+	}
+
+	// A snapshot already applied every current ad, so it supersedes the retry list
+	if len(retries) > 0 && !checkpoint.IsZero() {
+		fmt.Printf("🔁 Retrying %d ads that failed in earlier runs\n", len(retries))
+		ac.retryAds(retries, result)
+	}
+
+	// The checkpoint moves past ads that failed; they are retried one by one next run.
+	// Only a run with too many failures to retry is replayed as a whole.
+	syncLog := &models.SyncLog{
+		ConnectorName: ac.GetID(),
+		StartedAt:     startTime,
+		CompletedAt:   time.Now(),
+		JobsFetched:   result.fetched,
+		JobsInserted:  result.inserted,
+		JobsUpdated:   result.updated,
+		JobsRemoved:   result.removed,
+		Status:        "success",
+	}
+	if result.failed > 0 {
+		syncLog.Status = "partial"
+		syncLog.ErrorMessage = fmt.Sprintf("%d ads could not be stored", result.failed)
+	}
+	if closeErr != nil {
+		syncLog.Status = "partial"
+		syncLog.ErrorMessage = fmt.Sprintf("failed to close jobs missing from the snapshot: %v", closeErr)
+	} else if len(result.failedIDs) > maxStreamRetries {
+		fmt.Printf("⚠️  %d ads failed - checkpoint not advanced\n", result.failed)
+	} else if err := ac.commitCheckpoint(until, result.failedIDs); err != nil {
+		syncLog.Status = "partial"
+		syncLog.ErrorMessage = fmt.Sprintf("failed to commit checkpoint: %v", err)
+		fmt.Printf("⚠️  Failed to commit JobStream checkpoint: %v\n", err)
+	} else if result.failed > 0 {
+		fmt.Printf("⚠️  %d ads failed - retrying them next run\n", result.failed)
+	}
+	if err := ac.store.LogSync(syncLog); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 Arbetsförmedlingen JobStream sync complete! Fetched: %d, Inserted: %d, Updated: %d, Removed: %d\n",
+		result.fetched, result.inserted, result.updated, result.removed)
+	return nil
+}
+
+// commitCheckpoint saves the stream position and the ads to retry. The retry list is
+// saved first, so a failure never moves the position past ads it doesn't list.
+func (ac *ArbetsformedlingenConnector) commitCheckpoint(until time.Time, failedIDs []string) error {
+	if err := ac.store.SaveCheckpoint(&models.Checkpoint{
+		ConnectorID: ac.GetID(),
+		QueryKey:    streamRetryQueryKey,
+		Cursor:      strings.Join(failedIDs, ","),
+	}); err != nil {
+		return err
+	}
+	return ac.store.SaveCheckpoint(&models.Checkpoint{
+		ConnectorID: ac.GetID(),
+		QueryKey:    streamQueryKey,
+		Cursor:      until.Format(time.RFC3339),
+	})
+}
+
+// applyStreamAd inserts, updates or closes the stored job for one ad. Ads that fail
+// are collected in result.failedIDs.
+func (ac *ArbetsformedlingenConnector) applyStreamAd(ad streamAd, result *streamResult) {
+	result.fetched++
+	if result.seen != nil {
+		result.seen[ad.ID] = true
+	}
+	id := jobid.New("arbetsformedlingen", ad.ID)
+
+	if ad.Removed {
+		closedAt := time.Now()
+		if ad.RemovedDate != "" {
+			closedAt = ac.parseAFDate(ad.RemovedDate)
+		}
+		closed, err := ac.store.CloseJob(id, closedAt, "removed")
+		if err != nil {
+			fmt.Printf("⚠️  Error closing removed job %s: %v\n", id, err)
+			result.fail(ad.ID)
+			return
+		}
+		if closed {
+			result.removed++
+		}
+		return
+	}
+
+	job := ac.transformAFJob(ad.AFJob)
+	existing, err := ac.store.GetJob(job.ID)
+	if err != nil && err.Error() != "sql: no rows in result set" {
+		fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+		result.fail(ad.ID)
+		return
+	}
+
+	if existing != nil {
+		if err := ac.store.UpdateJob(&job); err != nil {
+			fmt.Printf("❌ Error updating job %s: %v\n", job.ID, err)
+			result.fail(ad.ID)
+			return
+		}
+		result.updated++
+		return
+	}
+
+	if err := ac.store.CreateJob(&job); err != nil {
+		fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+		result.fail(ad.ID)
+		return
+	}
+	result.inserted++
+}
+
+// retryAds applies the current version of ads that failed in earlier runs, fetched one
+// by one from JobSearch. Ads this run already applied are skipped; ads JobSearch no
+// longer has were unpublished and are closed.
+func (ac *ArbetsformedlingenConnector) retryAds(ids []string, result *streamResult) {
+	for _, id := range ids {
+		if result.seen[id] {
+			continue
+		}
+		ad, err := ac.fetchAd(id)
+		if err != nil {
+			fmt.Printf("⚠️  Error fetching ad %s to retry: %v\n", id, err)
+			result.fetched++
+			result.fail(id)
+			continue
+		}
+		ac.applyStreamAd(*ad, result)
+	}
+}
+
+// fetchAd fetches one ad from JobSearch; a 404 is returned as a removal
+func (ac *ArbetsformedlingenConnector) fetchAd(id string) (*streamAd, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/ad/%s", ac.baseURL, url.PathEscape(id)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", ac.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ac.searchClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ad: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &streamAd{AFJob: AFJob{ID: id}, Removed: true}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("JobSearch API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var ad streamAd
+	if err := json.NewDecoder(resp.Body).Decode(&ad); err != nil {
+		return nil, fmt.Errorf("failed to parse ad: %w", err)
+	}
+	ad.ID = id
+	return &ad, nil
+}
+
+// readSnapshot streams every currently published ad to fn
+func (ac *ArbetsformedlingenConnector) readSnapshot(fn func(streamAd) error) error {
+	return ac.readAds(fmt.Sprintf("%s/snapshot", ac.streamURL), fn)
+}
+
+// readStream streams the ads changed in [since, until) to fn, including removals
+func (ac *ArbetsformedlingenConnector) readStream(since, until time.Time, fn func(streamAd) error) error {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/stream", ac.streamURL), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Add("date", since.In(stockholm).Format(streamTimeLayout))
+	q.Add("updated-before-date", until.In(stockholm).Format(streamTimeLayout))
+	req.URL.RawQuery = q.Encode()
+
+	return ac.readAds(req.URL.String(), fn)
+}
+
+// readAds decodes a JobStream JSON array one ad at a time, so a full snapshot
+// never has to fit in memory
+func (ac *ArbetsformedlingenConnector) readAds(url string, fn func(streamAd) error) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", ac.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ac.streamClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JobStream: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("JobStream API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return fmt.Errorf("failed to parse JobStream response: expected array")
+	}
+	for dec.More() {
+		var ad streamAd
+		if err := dec.Decode(&ad); err != nil {
+			return fmt.Errorf("failed to parse JobStream ad: %w", err)
+		}
+		if ad.ID == "" {
+			continue
+		}
+		if err := fn(ad); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("failed to parse JobStream response: %w", err)
+	}
+	return nil
+}
+
+// lastCheckpoint returns the upper bound committed by the last successful JobStream run
+// (zero when there is none). A failed lookup is an error so it never triggers a re-snapshot.
+func (ac *ArbetsformedlingenConnector) lastCheckpoint() (time.Time, error) {
+	if ac.store == nil {
+		return time.Time{}, nil
+	}
+	checkpoint, err := ac.store.GetCheckpoint(ac.GetID(), streamQueryKey)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read JobStream checkpoint: %w", err)
+	}
+	if checkpoint.Cursor == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, checkpoint.Cursor)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid JobStream checkpoint %q: %w", checkpoint.Cursor, err)
+	}
+	return t, nil
+}
+
+// failedAds returns the ads the last committed JobStream run failed to store
+func (ac *ArbetsformedlingenConnector) failedAds() ([]string, error) {
+	if ac.store == nil {
+		return nil, nil
+	}
+	checkpoint, err := ac.store.GetCheckpoint(ac.GetID(), streamRetryQueryKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JobStream retry list: %w", err)
+	}
+	if checkpoint.Cursor == "" {
+		return nil, nil
+	}
+	return strings.Split(checkpoint.Cursor, ","), nil
+}
@@ -0,0 +1,205 @@
+package arbetsformedlingen
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage/storagetest"
+)
+
+func TestReadStream(t *testing.T) {
+	var gotQuery map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stream" {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = map[string]string{
+			"date":                r.URL.Query().Get("date"),
+			"updated-before-date": r.URL.Query().Get("updated-before-date"),
+		}
+		fmt.Fprint(w, `[
+			{"id": "100", "headline": "Systemutvecklare", "employer": {"name": "Acme AB"},
+			 "workplace_address": {"municipality": "Malmö", "region": "Skåne län", "country": "Sverige"},
+			 "publication_date": "2025-10-01T08:00:00"},
+			{"id": "200", "removed": true, "removed_date": "2025-10-02T09:30:00"},
+			{"headline": "no id"}
+		]`)
+	}))
+	defer server.Close()
+
+	ac := NewArbetsformedlingenConnector(nil)
+	ac.streamURL = server.URL
+
+	since := time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 10, 2, 10, 0, 0, 0, time.UTC)
+	ads := []streamAd{}
+	err := ac.readStream(since, until, func(ad streamAd) error {
+		ads = append(ads, ad)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readStream failed: %v", err)
+	}
+
+	// Timestamps are sent in Stockholm time (CEST in October)
+	if gotQuery["date"] != "2025-10-01T12:00:00" || gotQuery["updated-before-date"] != "2025-10-02T12:00:00" {
+		t.Errorf("Unexpected stream window: %+v", gotQuery)
+	}
+	if len(ads) != 2 {
+		t.Fatalf("Expected 2 ads (ad without id skipped), got %d", len(ads))
+	}
+	if ads[0].Removed || ads[0].Headline != "Systemutvecklare" {
+		t.Errorf("Unexpected first ad: %+v", ads[0])
+	}
+	if !ads[1].Removed || ads[1].ID != "200" || ads[1].RemovedDate != "2025-10-02T09:30:00" {
+		t.Errorf("Expected removal for ad 200, got %+v", ads[1])
+	}
+
+	job := ac.transformAFJob(ads[0].AFJob)
+	if job.ID != "arbetsformedlingen:100" || job.Company != "Acme AB" || job.Location != "Malmö, Skåne län, Sverige" {
+		t.Errorf("Unexpected job: %s %q %q", job.ID, job.Company, job.Location)
+	}
+}
+
+// TestReadStreamGolden replays a recorded JobStream window with a removed ad.
+// Re-record with HTTPREPLAY=record go test -run Golden
+func TestReadStreamGolden(t *testing.T) {
+	rec := httpreplay.New(t, "testdata/stream.json")
+
+	ac := NewArbetsformedlingenConnector(nil)
+	ac.streamClient = rec.Client()
+
+	since := time.Date(2025, 10, 14, 6, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 10, 14, 12, 0, 0, 0, time.UTC)
+	jobs := []models.JobPost{}
+	removed := []string{}
+	err := ac.readStream(since, until, func(ad streamAd) error {
+		if ad.Removed {
+			removed = append(removed, ad.ID)
+		} else {
+			jobs = append(jobs, ac.transformAFJob(ad.AFJob))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("readStream failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "29988771" {
+		t.Errorf("Expected ad 29988771 removed, got %v", removed)
+	}
+	httpreplay.AssertGolden(t, "testdata/stream.golden.json", jobs)
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("case") {
+		case "object":
+			fmt.Fprint(w, `{"error": "not an array"}`)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	ac := NewArbetsformedlingenConnector(nil)
+	ac.streamClient = httpclient.New(httpclient.Options{MaxRetries: -1})
+	noop := func(streamAd) error { return nil }
+
+	if err := ac.readAds(server.URL+"/snapshot", noop); err == nil {
+		t.Error("Expected error for 503 response")
+	}
+	if err := ac.readAds(server.URL+"/snapshot?case=object", noop); err == nil {
+		t.Error("Expected error for non-array response")
+	}
+}
+
+func TestSyncModeFromEnv(t *testing.T) {
+	if mode := NewArbetsformedlingenConnector(nil).mode; mode != modeJobStream {
+		t.Errorf("Expected JobStream by default, got %s", mode)
+	}
+	t.Setenv("AF_SYNC_MODE", "search")
+	if mode := NewArbetsformedlingenConnector(nil).mode; mode != modeSearch {
+		t.Errorf("Expected search mode, got %s", mode)
+	}
+}
+
+// TestSyncJobStreamSnapshotRetries checks that a snapshot closes the stored ads it no
+// longer lists and that later runs retry the ads that failed one by one from JobSearch
+func TestSyncJobStreamSnapshotRetries(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/snapshot":
+			fmt.Fprint(w, `[{"id": "100", "headline": "Systemutvecklare", "publication_date": "2025-10-01T08:00:00"},
+				{"id": "400", "headline": "Testare", "publication_date": "2025-10-01T08:00:00"}]`)
+		case "/stream":
+			fmt.Fprint(w, `[]`)
+		case "/ad/300":
+			fmt.Fprint(w, `{"id": "300", "headline": "Backendutvecklare", "publication_date": "2025-10-01T09:00:00"}`)
+		case "/ad/500":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	server := storagetest.NewServer(t)
+	old := models.JobPost{ID: "arbetsformedlingen:900", Title: "Gammal annons", Fields: map[string]interface{}{"connector": "arbetsformedlingen"}}
+	if err := server.Store().CreateJob(&old); err != nil {
+		t.Fatal(err)
+	}
+
+	ac := NewArbetsformedlingenConnector(server.Store())
+	ac.baseURL, ac.streamURL = api.URL, api.URL
+	ac.searchClient = httpclient.New(httpclient.Options{MaxRetries: -1})
+	runSync := func() {
+		t.Helper()
+		if err := ac.syncJobStream(); err != nil {
+			t.Fatalf("syncJobStream failed: %v", err)
+		}
+	}
+
+	runSync() // Snapshot
+	if cursor, err := ac.lastCheckpoint(); err != nil || cursor.IsZero() {
+		t.Fatalf("Expected a committed JobStream position, got %v (%v)", cursor, err)
+	}
+
+	// Three ads failed after the snapshot: one is fetched now, one was unpublished
+	// meanwhile and one fails again
+	if err := server.Store().SaveCheckpoint(&models.Checkpoint{ConnectorID: "arbetsformedlingen", QueryKey: streamRetryQueryKey, Cursor: "300,400,500"}); err != nil {
+		t.Fatal(err)
+	}
+	runSync()
+
+	want := map[string]string{
+		"arbetsformedlingen:100": "",
+		"arbetsformedlingen:300": "",
+		"arbetsformedlingen:400": "removed",
+		"arbetsformedlingen:900": "missing_from_feed",
+	}
+	jobs := server.Jobs()
+	if len(jobs) != len(want) {
+		t.Fatalf("Expected %d jobs, got %d", len(want), len(jobs))
+	}
+	for _, job := range jobs {
+		if reason, _ := job.Fields["closed_reason"].(string); reason != want[job.ID] {
+			t.Errorf("%s: closed_reason = %q, want %q", job.ID, reason, want[job.ID])
+		}
+	}
+
+	// The position moved on; only the ad that failed again is left to retry
+	if retries, err := ac.failedAds(); err != nil || fmt.Sprint(retries) != "[500]" {
+		t.Errorf("Retry list = %v (%v), want [500]", retries, err)
+	}
+	logs := server.SyncLogs()
+	if len(logs) != 2 || logs[0].JobsRemoved != 1 || logs[1].Status != "partial" || logs[1].JobsRemoved != 1 {
+		t.Errorf("Unexpected sync logs: %+v", logs)
+	}
+}
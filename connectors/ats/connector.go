@@ -0,0 +1,324 @@
+package ats
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+// Board is one company's public job board on an ATS
+type Board struct {
+	Token   string // Board token / account name in the ATS URL
+	Company string // Display name when the ATS doesn't return one
+	Region  string // Lever only: "eu" for api.eu.lever.co
+}
+
+// provider fetches and maps one public board for a specific ATS
+type provider interface {
+	id() string
+	name() string
+	fetchBoard(ac *ATSConnector, board Board) ([]models.JobPost, error)
+}
+
+// ATSConnector implements a connector for the public job boards of one ATS
+type ATSConnector struct {
+	store      *storage.JobStore
+	provider   provider
+	boards     []Board
+	userAgent  string
+	httpClient *http.Client
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// NewGreenhouseConnector creates a connector for Greenhouse job boards
+func NewGreenhouseConnector(store *storage.JobStore, boards []Board) *ATSConnector {
+	return newATSConnector(store, &greenhouse{baseURL: "https://boards-api.greenhouse.io/v1/boards"}, boards)
+}
+
+// NewLeverConnector creates a connector for Lever job boards
+func NewLeverConnector(store *storage.JobStore, boards []Board) *ATSConnector {
+	return newATSConnector(store, &lever{baseURL: "https://api.lever.co/v0/postings", euBaseURL: "https://api.eu.lever.co/v0/postings"}, boards)
+}
+
+// NewWorkableConnector creates a connector for Workable job boards
+func NewWorkableConnector(store *storage.JobStore, boards []Board) *ATSConnector {
+	return newATSConnector(store, &workable{baseURL: "https://apply.workable.com/api/v1/widget/accounts"}, boards)
+}
+
+func newATSConnector(store *storage.JobStore, p provider, boards []Board) *ATSConnector {
+	return &ATSConnector{
+		store:      store,
+		provider:   p,
+		boards:     boards,
+		userAgent:  httpclient.UserAgent("OpenJobs-ATS-Connector/1.0"),
+		httpClient: httpclient.New(httpclient.Options{Interval: 500 * time.Millisecond}), // Public boards, but be polite
+	}
+}
+
+// ParseBoards parses a board list like "spotify,klarna=Klarna AB,acme@eu"
+func ParseBoards(spec string) []Board {
+	boards := []Board{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		token, company, _ := strings.Cut(entry, "=")
+		token, region, _ := strings.Cut(strings.TrimSpace(token), "@")
+		boards = append(boards, Board{
+			Token:   strings.TrimSpace(token),
+			Company: strings.TrimSpace(company),
+			Region:  strings.ToLower(strings.TrimSpace(region)),
+		})
+	}
+	return boards
+}
+
+// GetID returns the connector ID
+func (ac *ATSConnector) GetID() string {
+	return ac.provider.id()
+}
+
+// GetName returns the connector name
+func (ac *ATSConnector) GetName() string {
+	return ac.provider.name()
+}
+
+// FetchJobs fetches every configured board, keeping only jobs newer than the
+// most recent stored job of that company
+func (ac *ATSConnector) FetchJobs() ([]models.JobPost, error) {
+	if len(ac.boards) == 0 {
+		return nil, fmt.Errorf("no %s boards configured", ac.provider.name())
+	}
+
+	allJobs := []models.JobPost{}
+	failed := 0
+
+	for _, board := range ac.boards {
+		jobs, err := ac.provider.fetchBoard(ac, board)
+		if err != nil {
+			fmt.Printf("⚠️  %s board %s failed: %v\n", ac.provider.name(), board.Token, err)
+			failed++
+			continue
+		}
+
+		// Per-company incremental sync
+		lastSync := ac.getLastSyncTime(board)
+		newJobs := 0
+		for _, job := range jobs {
+			if lastSync.IsZero() || job.PostedDate.After(lastSync) {
+				allJobs = append(allJobs, job)
+				newJobs++
+			}
+		}
+		fmt.Printf("🏢 %s/%s: %d open jobs, %d new\n", ac.provider.id(), board.Token, len(jobs), newJobs)
+	}
+
+	if failed == len(ac.boards) {
+		return nil, fmt.Errorf("all %d %s boards failed", failed, ac.provider.name())
+	}
+	return allJobs, nil
+}
+
+// getJSON fetches a public board endpoint and decodes the JSON response
+func (ac *ATSConnector) getJSON(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", ac.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := ac.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// jobID is the ID of one company's job, e.g. "greenhouse:spotify-4012"; ATS job IDs
+// are only guaranteed unique per board
+func (ac *ATSConnector) jobID(board Board, upstreamID string) string {
+	return jobid.New(ac.provider.id(), strings.ToLower(board.Token), upstreamID)
+}
+
+// getLastSyncTime returns the high watermark committed for a company
+func (ac *ATSConnector) getLastSyncTime(board Board) time.Time {
+	if ac.store == nil {
+		return time.Time{}
+	}
+	checkpoint, err := ac.store.GetCheckpoint(ac.GetID(), board.Token)
+	if err != nil {
+		fmt.Printf("⚠️  %s board %s: failed to read checkpoint: %v\n", ac.provider.name(), board.Token, err)
+		return time.Time{}
+	}
+	return checkpoint.HighWatermark
+}
+
+// saveLastSyncTime commits a checkpoint for every board, from the jobs stored for it
+func (ac *ATSConnector) saveLastSyncTime(jobs []models.JobPost) error {
+	for _, board := range ac.boards {
+		boardJobs := []models.JobPost{}
+		for _, job := range jobs {
+			if job.Fields["board_token"] == board.Token {
+				boardJobs = append(boardJobs, job)
+			}
+		}
+		if len(boardJobs) == 0 {
+			continue
+		}
+		if err := ac.store.AdvanceCheckpoint(ac.GetID(), board.Token, boardJobs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// baseFields are the fields every ATS job carries
+func (ac *ATSConnector) baseFields(board Board, originalID, sourceURL string) map[string]interface{} {
+	return map[string]interface{}{
+		"source":      ac.provider.id(),
+		"source_url":  sourceURL,
+		"original_id": originalID,
+		"board_token": board.Token,
+		"ats":         ac.provider.id(),
+		"connector":   ac.provider.id(),
+		"fetched_at":  time.Now(),
+	}
+}
+
+// SyncJobs fetches all boards and stores new jobs
+func (ac *ATSConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Printf("🔄 Starting %s sync (%d boards)...\n", ac.GetName(), len(ac.boards))
+
+	jobs, err := ac.FetchJobs()
+	if err != nil {
+		ac.store.LogSync(&models.SyncLog{
+			ConnectorName: ac.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "failed",
+		})
+		return fmt.Errorf("failed to fetch jobs from %s: %w", ac.GetName(), err)
+	}
+
+	fmt.Printf("📥 Fetched %d jobs from %s\n", len(jobs), ac.GetName())
+
+	stored := 0
+	duplicates := 0
+	failed := 0
+	for _, job := range jobs {
+		existing, err := ac.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := ac.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	// Commit the checkpoint only once every job is stored, so failed jobs are retried
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if err := ac.saveLastSyncTime(jobs); err != nil {
+		fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+	}
+
+	if err := ac.store.LogSync(&models.SyncLog{
+		ConnectorName:  ac.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 %s sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", ac.GetName(), len(jobs), stored, duplicates)
+	return nil
+}
+
+// plainText converts (possibly entity-escaped) HTML descriptions to text
+func plainText(s string) string {
+	s = html.UnescapeString(s)
+	s = html.UnescapeString(htmlTags.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// isRemoteText detects remote roles from location strings
+func isRemoteText(values ...string) bool {
+	for _, v := range values {
+		lower := strings.ToLower(v)
+		if strings.Contains(lower, "remote") || strings.Contains(lower, "distans") {
+			return true
+		}
+	}
+	return false
+}
+
+// salaryText formats a compensation range for JobPost.Salary
+func salaryText(min, max *int, currency, interval string) string {
+	text := ""
+	switch {
+	case min != nil && max != nil && *min != *max:
+		text = fmt.Sprintf("%d-%d %s", *min, *max, currency)
+	case min != nil:
+		text = fmt.Sprintf("%d %s", *min, currency)
+	case max != nil:
+		text = fmt.Sprintf("%d %s", *max, currency)
+	default:
+		return ""
+	}
+	if interval != "" {
+		text += " " + interval
+	}
+	return strings.TrimSpace(text)
+}
+
+// companyName prefers the configured display name, then the ATS name, then the token
+func companyName(board Board, fromATS string) string {
+	if board.Company != "" {
+		return board.Company
+	}
+	if fromATS != "" {
+		return fromATS
+	}
+	return board.Token
+}
@@ -0,0 +1,147 @@
+package ats
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+func newATSServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/greenhouse/acme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "Acme AB"}`)
+	})
+	mux.HandleFunc("/greenhouse/acme/jobs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jobs": [{
+			"id": 4001, "title": "Backend Engineer", "updated_at": "2025-10-02T10:00:00-04:00",
+			"first_published": "2025-10-01T09:00:00-04:00",
+			"absolute_url": "https://boards.greenhouse.io/acme/jobs/4001",
+			"content": "&lt;p&gt;Build &amp;amp; ship&lt;/p&gt;",
+			"location": {"name": "Stockholm or Remote (EU)"},
+			"departments": [{"name": "Engineering"}],
+			"offices": [{"name": "Stockholm", "location": "Stockholm, Sweden"}],
+			"metadata": [{"name": "Employment Type", "value": "Full-time"}],
+			"pay_input_ranges": [{"min_cents": 6000000, "max_cents": 7500000, "currency_type": "eur", "title": "Base"}]
+		}]}`)
+	})
+	mux.HandleFunc("/lever/acme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{
+			"id": "abc-123", "text": "Data Scientist", "hostedUrl": "https://jobs.lever.co/acme/abc-123",
+			"createdAt": 1759312800000, "descriptionPlain": "Models.", "workplaceType": "remote",
+			"categories": {"team": "Data", "department": "R&D", "location": "Oslo", "commitment": "Full-time", "allLocations": ["Oslo"]},
+			"lists": [{"text": "Requirements", "content": "<li>Python</li><li>SQL</li>"}],
+			"salaryRange": {"min": 700000, "max": 900000, "currency": "NOK", "interval": "per-year-salary"}
+		}]`)
+	})
+	mux.HandleFunc("/workable/acme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "Acme Oy", "jobs": [{
+			"title": "Designer", "shortcode": "ABC123", "employment_type": "Part-time", "telecommuting": false,
+			"department": "Design", "url": "https://apply.workable.com/j/ABC123", "published_on": "2025-10-03",
+			"locations": [{"country": "Finland", "city": "Helsinki", "region": "Uusimaa", "hidden": false}],
+			"description": "<p>Design things</p>"
+		}]}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestATSConnectors(t *testing.T) {
+	server := newATSServer()
+	defer server.Close()
+
+	boards := ParseBoards("acme, missing=Missing Co")
+	if len(boards) != 2 || boards[1].Company != "Missing Co" {
+		t.Fatalf("Unexpected boards: %+v", boards)
+	}
+
+	gh := NewGreenhouseConnector(nil, boards)
+	gh.provider = &greenhouse{baseURL: server.URL + "/greenhouse"}
+	gh.httpClient = httpclient.New(httpclient.Options{})
+	jobs, err := gh.FetchJobs()
+	if err != nil {
+		t.Fatalf("Greenhouse FetchJobs() failed: %v", err)
+	}
+	if len(jobs) != 1 {
+		t.Fatalf("Expected 1 Greenhouse job (missing board skipped), got %d", len(jobs))
+	}
+	job := jobs[0]
+	if job.ID != "greenhouse:acme-4001" || job.Company != "Acme AB" || job.Description != "Build & ship" || !job.IsRemote {
+		t.Errorf("Unexpected Greenhouse job: %+v", job)
+	}
+	if *job.SalaryMin != 60000 || *job.SalaryMax != 75000 || job.SalaryCurrency != "EUR" || job.EmploymentType != "Full-time" {
+		t.Errorf("Unexpected Greenhouse compensation: %v %v %q %q", *job.SalaryMin, *job.SalaryMax, job.SalaryCurrency, job.EmploymentType)
+	}
+
+	lv := NewLeverConnector(nil, ParseBoards("acme@eu"))
+	lv.provider = &lever{euBaseURL: server.URL + "/lever"}
+	jobs, err = lv.FetchJobs()
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("Lever FetchJobs() = %d jobs, %v", len(jobs), err)
+	}
+	job = jobs[0]
+	if job.ID != "lever:acme-abc-123" || !job.IsRemote || job.Salary != "700000-900000 NOK per year" || len(job.Requirements) != 2 {
+		t.Errorf("Unexpected Lever job: %+v", job)
+	}
+	if job.Fields["team"] != "Data" || job.PostedDate.Year() != 2025 {
+		t.Errorf("Unexpected Lever fields/date: %v %v", job.Fields, job.PostedDate)
+	}
+
+	wk := NewWorkableConnector(nil, ParseBoards("acme"))
+	wk.provider = &workable{baseURL: server.URL + "/workable"}
+	jobs, err = wk.FetchJobs()
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("Workable FetchJobs() = %d jobs, %v", len(jobs), err)
+	}
+	job = jobs[0]
+	if job.ID != "workable:acme-abc123" || job.Company != "Acme Oy" || job.Location != "Helsinki, Uusimaa, Finland" || job.IsRemote {
+		t.Errorf("Unexpected Workable job: %+v", job)
+	}
+}
+
+// TestFetchJobsGolden replays recorded boards of each ATS. Re-record with
+// HTTPREPLAY=record go test -run Golden
+// recordedBoards are the boards in testdata/<name>.json
+var recordedBoards = []struct {
+	name      string
+	connector func(*storage.JobStore, []Board) *ATSConnector
+	boards    string
+}{
+	{"greenhouse", NewGreenhouseConnector, "spotify"},
+	{"lever", NewLeverConnector, "kahoot@eu=Kahoot!"},
+	{"workable", NewWorkableConnector, "supercell"},
+}
+
+func TestFetchJobsGolden(t *testing.T) {
+	for _, tt := range recordedBoards {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httpreplay.New(t, "testdata/"+tt.name+".json")
+
+			ac := tt.connector(nil, ParseBoards(tt.boards))
+			ac.httpClient = rec.Client()
+
+			jobs, err := ac.FetchJobs()
+			if err != nil {
+				t.Fatalf("FetchJobs failed: %v", err)
+			}
+			httpreplay.AssertGolden(t, "testdata/"+tt.name+".golden.json", jobs)
+		})
+	}
+}
+
+func TestConformance(t *testing.T) {
+	for _, tt := range recordedBoards {
+		t.Run(tt.name, func(t *testing.T) {
+			conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+				ac := tt.connector(store, ParseBoards(tt.boards))
+				ac.httpClient = httpreplay.New(t, "testdata/"+tt.name+".json").Client()
+				return ac
+			}, conformance.Options{})
+		})
+	}
+}
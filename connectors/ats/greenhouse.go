@@ -0,0 +1,171 @@
+package ats
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+)
+
+// greenhouse reads boards-api.greenhouse.io public job boards
+type greenhouse struct {
+	baseURL string
+}
+
+// greenhouseBoard is the board metadata response
+type greenhouseBoard struct {
+	Name string `json:"name"`
+}
+
+// greenhouseJobs is the /jobs?content=true response
+type greenhouseJobs struct {
+	Jobs []greenhouseJob `json:"jobs"`
+}
+
+type greenhouseJob struct {
+	ID             int64  `json:"id"`
+	Title          string `json:"title"`
+	UpdatedAt      string `json:"updated_at"`
+	FirstPublished string `json:"first_published"`
+	AbsoluteURL    string `json:"absolute_url"`
+	Content        string `json:"content"`
+	Location       struct {
+		Name string `json:"name"`
+	} `json:"location"`
+	Departments []struct {
+		Name string `json:"name"`
+	} `json:"departments"`
+	Offices []struct {
+		Name     string `json:"name"`
+		Location string `json:"location"`
+	} `json:"offices"`
+	Metadata []struct {
+		Name  string      `json:"name"`
+		Value interface{} `json:"value"`
+	} `json:"metadata"`
+	PayInputRanges []struct {
+		MinCents     int64  `json:"min_cents"`
+		MaxCents     int64  `json:"max_cents"`
+		CurrencyType string `json:"currency_type"`
+		Title        string `json:"title"`
+	} `json:"pay_input_ranges"`
+}
+
+func (g *greenhouse) id() string   { return "greenhouse" }
+func (g *greenhouse) name() string { return "Greenhouse Job Boards" }
+
+// fetchBoard fetches a Greenhouse board with job content and pay ranges
+func (g *greenhouse) fetchBoard(ac *ATSConnector, board Board) ([]models.JobPost, error) {
+	token := url.PathEscape(board.Token)
+
+	var meta greenhouseBoard
+	if err := ac.getJSON(fmt.Sprintf("%s/%s", g.baseURL, token), &meta); err != nil {
+		// The name is cosmetic - fall back to the configured name or token
+		meta.Name = ""
+	}
+
+	var response greenhouseJobs
+	if err := ac.getJSON(fmt.Sprintf("%s/%s/jobs?content=true&pay_transparency=true", g.baseURL, token), &response); err != nil {
+		return nil, err
+	}
+
+	company := companyName(board, meta.Name)
+	jobs := make([]models.JobPost, 0, len(response.Jobs))
+	for _, gj := range response.Jobs {
+		jobs = append(jobs, g.transformJob(ac, board, company, gj))
+	}
+	return jobs, nil
+}
+
+// transformJob converts a Greenhouse job to our JobPost format
+func (g *greenhouse) transformJob(ac *ATSConnector, board Board, company string, gj greenhouseJob) models.JobPost {
+	originalID := fmt.Sprintf("%d", gj.ID)
+
+	departments := []string{}
+	for _, d := range gj.Departments {
+		departments = append(departments, d.Name)
+	}
+	offices := []string{}
+	officeLocations := []string{}
+	for _, o := range gj.Offices {
+		offices = append(offices, o.Name)
+		if o.Location != "" {
+			officeLocations = append(officeLocations, o.Location)
+		}
+	}
+
+	posted := parseGreenhouseTime(gj.FirstPublished)
+	if posted.IsZero() {
+		posted = parseGreenhouseTime(gj.UpdatedAt)
+	}
+	if posted.IsZero() {
+		posted = time.Now()
+	}
+
+	fields := ac.baseFields(board, originalID, gj.AbsoluteURL)
+	fields["departments"] = departments
+	fields["offices"] = offices
+	fields["office_locations"] = officeLocations
+	fields["updated_at"] = gj.UpdatedAt
+
+	job := models.JobPost{
+		ID:           ac.jobID(board, originalID),
+		Title:        strings.TrimSpace(gj.Title),
+		Company:      company,
+		Description:  plainText(gj.Content),
+		Location:     gj.Location.Name,
+		IsRemote:     isRemoteText(append([]string{gj.Location.Name}, offices...)...),
+		URL:          gj.AbsoluteURL,
+		PostedDate:   posted,
+		Requirements: departments,
+		Benefits:     []string{},
+		Fields:       fields,
+	}
+
+	// Custom metadata often carries employment type and remote policy
+	for _, m := range gj.Metadata {
+		value, ok := m.Value.(string)
+		if !ok || value == "" {
+			continue
+		}
+		name := strings.ToLower(m.Name)
+		switch {
+		case strings.Contains(name, "employment") || strings.Contains(name, "job type"):
+			job.EmploymentType = value
+		case strings.Contains(name, "remote") || strings.Contains(name, "workplace"):
+			fields["workplace_type"] = value
+			if isRemoteText(value) {
+				job.IsRemote = true
+			}
+		}
+	}
+
+	// Pay transparency ranges are in cents
+	if len(gj.PayInputRanges) > 0 {
+		pay := gj.PayInputRanges[0]
+		min := int(pay.MinCents / 100)
+		max := int(pay.MaxCents / 100)
+		// The range title ("Annual Salary", "Hourly Pay") is the only hint of its period
+		salary.Salary{
+			Min:      &min,
+			Max:      &max,
+			Currency: strings.ToUpper(pay.CurrencyType),
+			Period:   salary.ParsePeriod(pay.Title),
+		}.Apply(&job)
+		job.Salary = salaryText(&min, &max, job.SalaryCurrency, "")
+		fields["compensation_title"] = pay.Title
+	}
+
+	return job
+}
+
+// parseGreenhouseTime parses Greenhouse timestamps (RFC3339 with offset)
+func parseGreenhouseTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,138 @@
+package ats
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+)
+
+// lever reads api.lever.co public postings (api.eu.lever.co for EU accounts)
+type lever struct {
+	baseURL   string
+	euBaseURL string
+}
+
+type leverPosting struct {
+	ID               string `json:"id"`
+	Text             string `json:"text"`
+	HostedURL        string `json:"hostedUrl"`
+	ApplyURL         string `json:"applyUrl"`
+	CreatedAt        int64  `json:"createdAt"` // Unix milliseconds
+	DescriptionPlain string `json:"descriptionPlain"`
+	Description      string `json:"description"`
+	WorkplaceType    string `json:"workplaceType"` // remote, hybrid, on-site, unspecified
+	Country          string `json:"country"`
+	Categories       struct {
+		Team         string   `json:"team"`
+		Department   string   `json:"department"`
+		Location     string   `json:"location"`
+		Commitment   string   `json:"commitment"`
+		AllLocations []string `json:"allLocations"`
+	} `json:"categories"`
+	Lists []struct {
+		Text    string `json:"text"`
+		Content string `json:"content"`
+	} `json:"lists"`
+	SalaryRange *struct {
+		Min      float64 `json:"min"`
+		Max      float64 `json:"max"`
+		Currency string  `json:"currency"`
+		Interval string  `json:"interval"` // per-year-salary, per-month-salary, per-hour-wage
+	} `json:"salaryRange"`
+}
+
+func (l *lever) id() string   { return "lever" }
+func (l *lever) name() string { return "Lever Job Boards" }
+
+// fetchBoard fetches all published postings of a Lever account
+func (l *lever) fetchBoard(ac *ATSConnector, board Board) ([]models.JobPost, error) {
+	base := l.baseURL
+	if board.Region == "eu" {
+		base = l.euBaseURL
+	}
+
+	var postings []leverPosting
+	if err := ac.getJSON(fmt.Sprintf("%s/%s?mode=json", base, url.PathEscape(board.Token)), &postings); err != nil {
+		return nil, err
+	}
+
+	company := companyName(board, "")
+	jobs := make([]models.JobPost, 0, len(postings))
+	for _, lp := range postings {
+		jobs = append(jobs, l.transformPosting(ac, board, company, lp))
+	}
+	return jobs, nil
+}
+
+// transformPosting converts a Lever posting to our JobPost format
+func (l *lever) transformPosting(ac *ATSConnector, board Board, company string, lp leverPosting) models.JobPost {
+	description := lp.DescriptionPlain
+	if description == "" {
+		description = plainText(lp.Description)
+	}
+	// Requirement lists ("What you'll do", "Requirements") are separate from the description
+	requirements := []string{}
+	for _, list := range lp.Lists {
+		description += "\n\n" + list.Text + "\n" + plainText(list.Content)
+		if strings.Contains(strings.ToLower(list.Text), "require") || strings.Contains(strings.ToLower(list.Text), "qualif") {
+			for _, item := range strings.Split(list.Content, "</li>") {
+				if text := plainText(item); text != "" {
+					requirements = append(requirements, text)
+				}
+			}
+		}
+	}
+
+	posted := time.Now()
+	if lp.CreatedAt > 0 {
+		posted = time.UnixMilli(lp.CreatedAt).UTC()
+	}
+
+	location := lp.Categories.Location
+	if len(lp.Categories.AllLocations) > 1 {
+		location = strings.Join(lp.Categories.AllLocations, "; ")
+	}
+
+	fields := ac.baseFields(board, lp.ID, lp.HostedURL)
+	fields["team"] = lp.Categories.Team
+	fields["department"] = lp.Categories.Department
+	fields["offices"] = lp.Categories.AllLocations
+	fields["workplace_type"] = lp.WorkplaceType
+	fields["country"] = lp.Country
+	fields["apply_url"] = lp.ApplyURL
+
+	job := models.JobPost{
+		ID:             ac.jobID(board, lp.ID),
+		Title:          strings.TrimSpace(lp.Text),
+		Company:        company,
+		Description:    strings.TrimSpace(description),
+		Location:       location,
+		IsRemote:       lp.WorkplaceType == "remote" || isRemoteText(location),
+		URL:            lp.HostedURL,
+		EmploymentType: lp.Categories.Commitment,
+		PostedDate:     posted,
+		Requirements:   requirements,
+		Benefits:       []string{},
+		Fields:         fields,
+	}
+
+	if lp.SalaryRange != nil && (lp.SalaryRange.Min > 0 || lp.SalaryRange.Max > 0) {
+		min := int(lp.SalaryRange.Min)
+		max := int(lp.SalaryRange.Max)
+		salary.Salary{
+			Min:      &min,
+			Max:      &max,
+			Currency: strings.ToUpper(lp.SalaryRange.Currency),
+			Period:   salary.ParsePeriod(lp.SalaryRange.Interval),
+		}.Apply(&job)
+		interval := strings.TrimSuffix(strings.TrimSuffix(lp.SalaryRange.Interval, "-salary"), "-wage")
+		job.Salary = salaryText(&min, &max, job.SalaryCurrency, strings.ReplaceAll(interval, "-", " "))
+		fields["salary_interval"] = lp.SalaryRange.Interval
+	}
+
+	return job
+}
@@ -0,0 +1,133 @@
+package ats
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"openjobs/pkg/models"
+)
+
+// workable reads the public Workable widget API (apply.workable.com)
+type workable struct {
+	baseURL string
+}
+
+type workableAccount struct {
+	Name string        `json:"name"`
+	Jobs []workableJob `json:"jobs"`
+}
+
+type workableJob struct {
+	Title          string `json:"title"`
+	Shortcode      string `json:"shortcode"`
+	EmploymentType string `json:"employment_type"`
+	Telecommuting  bool   `json:"telecommuting"`
+	Department     string `json:"department"`
+	URL            string `json:"url"`
+	ApplicationURL string `json:"application_url"`
+	PublishedOn    string `json:"published_on"`
+	CreatedAt      string `json:"created_at"`
+	Country        string `json:"country"`
+	City           string `json:"city"`
+	State          string `json:"state"`
+	Experience     string `json:"experience"`
+	Function       string `json:"function"`
+	Industry       string `json:"industry"`
+	Description    string `json:"description"`
+	Locations      []struct {
+		Country     string `json:"country"`
+		CountryCode string `json:"countryCode"`
+		City        string `json:"city"`
+		Region      string `json:"region"`
+		Hidden      bool   `json:"hidden"`
+	} `json:"locations"`
+}
+
+func (w *workable) id() string   { return "workable" }
+func (w *workable) name() string { return "Workable Job Boards" }
+
+// fetchBoard fetches a Workable account's published jobs with descriptions
+func (w *workable) fetchBoard(ac *ATSConnector, board Board) ([]models.JobPost, error) {
+	var account workableAccount
+	if err := ac.getJSON(fmt.Sprintf("%s/%s?details=true", w.baseURL, url.PathEscape(board.Token)), &account); err != nil {
+		return nil, err
+	}
+
+	company := companyName(board, account.Name)
+	jobs := make([]models.JobPost, 0, len(account.Jobs))
+	for _, wj := range account.Jobs {
+		jobs = append(jobs, w.transformJob(ac, board, company, wj))
+	}
+	return jobs, nil
+}
+
+// transformJob converts a Workable job to our JobPost format.
+// The widget API has no compensation data.
+func (w *workable) transformJob(ac *ATSConnector, board Board, company string, wj workableJob) models.JobPost {
+	locations := []string{}
+	for _, l := range wj.Locations {
+		if l.Hidden {
+			continue
+		}
+		parts := []string{}
+		for _, p := range []string{l.City, l.Region, l.Country} {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+		if len(parts) > 0 {
+			locations = append(locations, strings.Join(parts, ", "))
+		}
+	}
+	if len(locations) == 0 {
+		parts := []string{}
+		for _, p := range []string{wj.City, wj.State, wj.Country} {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+		locations = append(locations, strings.Join(parts, ", "))
+	}
+	location := strings.Join(locations, "; ")
+	if location == "" && wj.Telecommuting {
+		location = "Remote"
+	}
+
+	posted := time.Now()
+	for _, s := range []string{wj.PublishedOn, wj.CreatedAt} {
+		if t, err := time.Parse("2006-01-02", s); err == nil {
+			posted = t
+			break
+		}
+	}
+
+	fields := ac.baseFields(board, wj.Shortcode, wj.URL)
+	fields["department"] = wj.Department
+	fields["offices"] = locations
+	fields["function"] = wj.Function
+	fields["industry"] = wj.Industry
+	fields["apply_url"] = wj.ApplicationURL
+
+	requirements := []string{}
+	if wj.Function != "" {
+		requirements = append(requirements, wj.Function)
+	}
+
+	return models.JobPost{
+		ID:              ac.jobID(board, strings.ToLower(wj.Shortcode)),
+		Title:           strings.TrimSpace(wj.Title),
+		Company:         company,
+		Description:     plainText(wj.Description),
+		Location:        location,
+		IsRemote:        wj.Telecommuting || isRemoteText(location),
+		URL:             wj.URL,
+		EmploymentType:  wj.EmploymentType,
+		ExperienceLevel: wj.Experience,
+		PostedDate:      posted,
+		Requirements:    requirements,
+		Benefits:        []string{},
+		Fields:          fields,
+	}
+}
@@ -0,0 +1,235 @@
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a JSON job board API and how its items map into JobPost.
+// Config files can be written in JSON or YAML; both use the same keys.
+type Config struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Source      string                 `json:"source"` // Job ID prefix and fields.source
+	Request     RequestConfig          `json:"request"`
+	Auth        AuthConfig             `json:"auth"`
+	Pagination  PaginationConfig       `json:"pagination"`
+	ItemsPath   string                 `json:"items_path"`
+	Mapping     map[string]interface{} `json:"mapping"`
+	Fields      map[string]interface{} `json:"fields"`
+	DateFormats []string               `json:"date_formats"`
+	RateLimit   RateLimitConfig        `json:"rate_limit"`
+	Snapshot    bool                   `json:"snapshot"` // Every run returns the full listing; stored jobs missing from it are closed
+}
+
+// RequestConfig is the templated HTTP request sent for every page.
+// Templates support {{page}}, {{offset}}, {{limit}}, {{cursor}}, {{env:NAME}}
+// and {{since:<Go time layout>}} (high watermark of the committed checkpoint).
+type RequestConfig struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+	Timeout int               `json:"timeout_seconds"`
+}
+
+// AuthConfig configures credentials, always read from environment variables
+type AuthConfig struct {
+	Type        string `json:"type"` // none, bearer, header, query, basic
+	Name        string `json:"name"` // Header or query parameter name
+	ValueEnv    string `json:"value_env"`
+	UsernameEnv string `json:"username_env"`
+	PasswordEnv string `json:"password_env"`
+}
+
+// PaginationConfig selects the paging style of the API
+type PaginationConfig struct {
+	Type       string `json:"type"` // none, offset, page, cursor
+	PageSize   int    `json:"page_size"`
+	StartPage  int    `json:"start_page"` // First page number for "page" (default 1)
+	MaxPages   int    `json:"max_pages"`
+	CursorPath string `json:"cursor_path"` // JSONPath to the next cursor for "cursor"
+	TotalPath  string `json:"total_path"`  // Optional JSONPath to the total result count
+}
+
+// RateLimitConfig throttles requests against the upstream API
+type RateLimitConfig struct {
+	RequestsPerMinute int `json:"requests_per_minute"`
+	DelayMillis       int `json:"delay_ms"`
+}
+
+// mappingKeys are the JobPost fields a mapping file may set
+var mappingKeys = map[string]bool{
+	"id": true, "title": true, "company": true, "description": true, "location": true,
+	"salary": true, "salary_min": true, "salary_max": true, "salary_currency": true,
+	"is_remote": true, "url": true, "employment_type": true, "experience_level": true,
+	"posted_date": true, "expires_date": true, "requirements": true, "benefits": true,
+}
+
+// LoadConfig reads a connector config from a .json, .yaml or .yml file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// Decode YAML generically and re-encode as JSON so one set of tags covers both
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+		}
+		data, err = json.Marshal(generic)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert YAML config %s: %w", path, err)
+		}
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// LoadConfigDir loads every JSON/YAML connector config in a directory
+func LoadConfigDir(dir string) ([]*Config, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+	}
+	sort.Strings(names)
+
+	configs := []*Config{}
+	for _, name := range names {
+		config, err := LoadConfig(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// Validate checks required settings and fills in defaults
+func (c *Config) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if c.Request.URL == "" {
+		return fmt.Errorf("request.url is required")
+	}
+	if c.Name == "" {
+		c.Name = c.ID
+	}
+	if c.Source == "" {
+		c.Source = c.ID
+	}
+	if c.Request.Method == "" {
+		c.Request.Method = "GET"
+	}
+	c.Request.Method = strings.ToUpper(c.Request.Method)
+	if c.ItemsPath == "" {
+		c.ItemsPath = "$"
+	}
+
+	switch c.Auth.Type {
+	case "", "none":
+		c.Auth.Type = "none"
+	case "bearer", "header", "query":
+		if c.Auth.ValueEnv == "" {
+			return fmt.Errorf("auth.value_env is required for %s auth", c.Auth.Type)
+		}
+		if c.Auth.Type != "bearer" && c.Auth.Name == "" {
+			return fmt.Errorf("auth.name is required for %s auth", c.Auth.Type)
+		}
+	case "basic":
+		if c.Auth.UsernameEnv == "" {
+			return fmt.Errorf("auth.username_env is required for basic auth")
+		}
+	default:
+		return fmt.Errorf("unknown auth type %q", c.Auth.Type)
+	}
+
+	switch c.Pagination.Type {
+	case "", "none":
+		c.Pagination.Type = "none"
+		c.Pagination.MaxPages = 1
+	case "offset", "page":
+		if c.Pagination.PageSize <= 0 {
+			return fmt.Errorf("pagination.page_size is required for %s pagination", c.Pagination.Type)
+		}
+	case "cursor":
+		if c.Pagination.CursorPath == "" {
+			return fmt.Errorf("pagination.cursor_path is required for cursor pagination")
+		}
+	default:
+		return fmt.Errorf("unknown pagination type %q", c.Pagination.Type)
+	}
+	if c.Pagination.Type == "page" && c.Pagination.StartPage == 0 {
+		c.Pagination.StartPage = 1
+	}
+	if c.Pagination.MaxPages <= 0 {
+		c.Pagination.MaxPages = 5
+	}
+	if c.Snapshot && strings.Contains(c.Request.URL+c.Request.Body, "{{since") {
+		return fmt.Errorf("snapshot needs the full listing, so the request cannot filter on {{since}}")
+	}
+
+	if _, ok := c.Mapping["title"]; !ok {
+		return fmt.Errorf("mapping.title is required")
+	}
+	_, hasID := c.Mapping["id"]
+	_, hasURL := c.Mapping["url"]
+	if !hasID && !hasURL {
+		return fmt.Errorf("mapping needs id or url to build stable job IDs")
+	}
+	for key := range c.Mapping {
+		if !mappingKeys[key] {
+			return fmt.Errorf("mapping.%s is not a JobPost field (use fields for extra data)", key)
+		}
+	}
+
+	// Compile every path once so typos fail at load time, not mid-sync
+	paths := []string{c.ItemsPath, c.Pagination.CursorPath, c.Pagination.TotalPath}
+	for _, expr := range c.Mapping {
+		if s, ok := expr.(string); ok {
+			paths = append(paths, s)
+		}
+	}
+	for _, expr := range c.Fields {
+		if s, ok := expr.(string); ok {
+			paths = append(paths, s)
+		}
+	}
+	for _, p := range paths {
+		if strings.HasPrefix(strings.TrimSpace(p), "$") {
+			if _, err := compilePath(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,644 @@
+package declarative
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+)
+
+// DeclarativeConnector implements a connector for any JSON job API described by a Config
+type DeclarativeConnector struct {
+	store      *storage.JobStore
+	config     *Config
+	userAgent  string
+	httpClient *http.Client
+}
+
+// defaultDateFormats are tried after the layouts listed in the config
+var defaultDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+}
+
+// templateVar matches {{name}} and {{name:arg}} placeholders in request templates
+var templateVar = regexp.MustCompile(`\{\{\s*([a-z_]+)(?::([^}]*))?\s*\}\}`)
+
+// NewDeclarativeConnector creates a new connector from a validated config
+func NewDeclarativeConnector(store *storage.JobStore, config *Config) *DeclarativeConnector {
+	timeout := 30 * time.Second
+	if config.Request.Timeout > 0 {
+		timeout = time.Duration(config.Request.Timeout) * time.Second
+	}
+
+	// requests_per_minute and delay_ms become the per-host interval; the slower wins
+	interval := time.Duration(config.RateLimit.DelayMillis) * time.Millisecond
+	if rpm := config.RateLimit.RequestsPerMinute; rpm > 0 {
+		if perRequest := time.Minute / time.Duration(rpm); perRequest > interval {
+			interval = perRequest
+		}
+	}
+
+	return &DeclarativeConnector{
+		store:      store,
+		config:     config,
+		userAgent:  httpclient.UserAgent("OpenJobs-Declarative-Connector/1.0"),
+		httpClient: httpclient.New(httpclient.Options{Timeout: timeout, Interval: interval}),
+	}
+}
+
+// GetID returns the connector ID
+func (dc *DeclarativeConnector) GetID() string {
+	return dc.config.ID
+}
+
+// GetName returns the connector name
+func (dc *DeclarativeConnector) GetName() string {
+	return dc.config.Name
+}
+
+// FetchJobs walks all configured pages of the API and maps every item to a JobPost
+func (dc *DeclarativeConnector) FetchJobs() ([]models.JobPost, error) {
+	jobs, _, err := dc.fetchListing()
+	return jobs, err
+}
+
+// fetchListing returns the jobs newer than the checkpoint and, for snapshot configs,
+// the IDs of every item in the listing. listed is nil unless every page was read.
+func (dc *DeclarativeConnector) fetchListing() (jobs []models.JobPost, listed []string, err error) {
+	cfg := dc.config
+	since := dc.getLastSyncTime()
+	if !since.IsZero() {
+		fmt.Printf("📅 %s: incremental sync from %s\n", cfg.ID, since.Format("2006-01-02 15:04:05"))
+	}
+
+	jobs = []models.JobPost{}
+	all := []string{}
+	complete := false
+	skipped := 0
+	page := cfg.Pagination.StartPage
+	offset := 0
+	cursor := ""
+
+	for pageNum := 0; pageNum < cfg.Pagination.MaxPages; pageNum++ {
+		vars := map[string]string{
+			"page":   strconv.Itoa(page),
+			"offset": strconv.Itoa(offset),
+			"limit":  strconv.Itoa(cfg.Pagination.PageSize),
+			"cursor": cursor,
+		}
+
+		doc, err := dc.fetchPage(vars, since)
+		if err != nil {
+			if len(jobs) > 0 {
+				// Keep what we already have rather than losing the whole run
+				fmt.Printf("⚠️  %s: stopping at page %d: %v\n", cfg.ID, pageNum+1, err)
+				break
+			}
+			return nil, nil, err
+		}
+
+		items, err := dc.extractItems(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Printf("📄 %s page %d: %d items\n", cfg.ID, pageNum+1, len(items))
+
+		for _, item := range items {
+			job, ok := dc.mapItem(item)
+			if !ok {
+				skipped++
+				continue
+			}
+			all = append(all, job.ID)
+			if !since.IsZero() && !job.PostedDate.After(since) {
+				continue
+			}
+			jobs = append(jobs, job)
+		}
+
+		// Decide whether there is another page
+		if len(items) == 0 || cfg.Pagination.Type == "none" {
+			complete = true
+			break
+		}
+		switch cfg.Pagination.Type {
+		case "offset", "page":
+			offset += len(items)
+			page++
+			if len(items) < cfg.Pagination.PageSize {
+				complete = true
+			}
+			if total, ok := dc.totalResults(doc); ok && offset >= total {
+				complete = true
+			}
+		case "cursor":
+			next := ""
+			if values, _ := lookup(cfg.Pagination.CursorPath, doc); len(values) > 0 {
+				next = stringValue(values[0])
+			}
+			if next == "" || next == cursor {
+				complete = true
+			}
+			cursor = next
+		}
+		if complete {
+			break
+		}
+	}
+
+	if skipped > 0 {
+		fmt.Printf("⚠️  %s: skipped %d items without title or stable ID\n", cfg.ID, skipped)
+	}
+	if cfg.Snapshot {
+		if complete {
+			listed = all
+		} else {
+			fmt.Printf("⚠️  %s: listing incomplete - not closing missing jobs\n", cfg.ID)
+		}
+	}
+	return jobs, listed, nil
+}
+
+// fetchPage renders the request templates, sends the request and decodes the JSON body
+func (dc *DeclarativeConnector) fetchPage(vars map[string]string, since time.Time) (interface{}, error) {
+	cfg := dc.config
+
+	reqURL, err := renderTemplate(cfg.Request.URL, vars, since, true)
+	if err != nil {
+		return nil, err
+	}
+	body, err := renderTemplate(cfg.Request.Body, vars, since, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var bodyReader io.Reader
+	if body != "" {
+		bodyReader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(cfg.Request.Method, reqURL, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", dc.userAgent)
+	req.Header.Set("Accept", "application/json")
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range cfg.Request.Headers {
+		rendered, err := renderTemplate(value, vars, since, false)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set(name, rendered)
+	}
+
+	if err := dc.applyAuth(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := dc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s API error %d: %s", cfg.ID, resp.StatusCode, truncate(string(respBody), 200))
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(respBody, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return doc, nil
+}
+
+// applyAuth adds credentials from the environment to the request
+func (dc *DeclarativeConnector) applyAuth(req *http.Request) error {
+	auth := dc.config.Auth
+	if auth.Type == "none" {
+		return nil
+	}
+
+	value := ""
+	if auth.ValueEnv != "" {
+		value = os.Getenv(auth.ValueEnv)
+		if value == "" {
+			return fmt.Errorf("%s not set (required by %s)", auth.ValueEnv, dc.config.ID)
+		}
+	}
+
+	switch auth.Type {
+	case "bearer":
+		req.Header.Set("Authorization", "Bearer "+value)
+	case "header":
+		req.Header.Set(auth.Name, value)
+	case "query":
+		query := req.URL.Query()
+		query.Set(auth.Name, value)
+		req.URL.RawQuery = query.Encode()
+	case "basic":
+		username := os.Getenv(auth.UsernameEnv)
+		if username == "" {
+			return fmt.Errorf("%s not set (required by %s)", auth.UsernameEnv, dc.config.ID)
+		}
+		req.SetBasicAuth(username, os.Getenv(auth.PasswordEnv))
+	}
+	return nil
+}
+
+// extractItems returns the job items selected by items_path
+func (dc *DeclarativeConnector) extractItems(doc interface{}) ([]interface{}, error) {
+	values, err := lookup(dc.config.ItemsPath, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// "$.jobs" selects the array itself; "$.jobs[*]" selects its elements
+	if len(values) == 1 {
+		if arr, ok := values[0].([]interface{}); ok {
+			return arr, nil
+		}
+	}
+	return values, nil
+}
+
+// totalResults reads the optional total count from the response
+func (dc *DeclarativeConnector) totalResults(doc interface{}) (int, bool) {
+	if dc.config.Pagination.TotalPath == "" {
+		return 0, false
+	}
+	values, err := lookup(dc.config.Pagination.TotalPath, doc)
+	if err != nil || len(values) == 0 {
+		return 0, false
+	}
+	total := intValue(values[0])
+	if total == nil {
+		return 0, false
+	}
+	return *total, true
+}
+
+// mapItem converts one API item to a JobPost using the mapping.
+// Items without a title or a stable ID are rejected.
+func (dc *DeclarativeConnector) mapItem(item interface{}) (models.JobPost, bool) {
+	cfg := dc.config
+	get := func(key string) interface{} {
+		expr, ok := cfg.Mapping[key]
+		if !ok {
+			return nil
+		}
+		values, err := lookup(expr, item)
+		if err != nil || len(values) == 0 {
+			return nil
+		}
+		if len(values) == 1 {
+			return values[0]
+		}
+		return values
+	}
+
+	job := models.JobPost{
+		Title:           stringValue(get("title")),
+		Company:         stringValue(get("company")),
+		Description:     stringValue(get("description")),
+		Location:        stringValue(get("location")),
+		Salary:          stringValue(get("salary")),
+		SalaryMin:       intValue(get("salary_min")),
+		SalaryMax:       intValue(get("salary_max")),
+		SalaryCurrency:  stringValue(get("salary_currency")),
+		IsRemote:        boolValue(get("is_remote")),
+		URL:             stringValue(get("url")),
+		EmploymentType:  stringValue(get("employment_type")),
+		ExperienceLevel: stringValue(get("experience_level")),
+		Requirements:    stringsValue(get("requirements")),
+		Benefits:        stringsValue(get("benefits")),
+	}
+	if job.Title == "" {
+		return job, false
+	}
+
+	originalID := stringValue(get("id"))
+	if originalID == "" && job.URL != "" {
+		// No upstream ID: hash the URL so the ID stays stable across syncs
+		originalID = jobid.URLHash(job.URL)
+	}
+	if originalID == "" {
+		return job, false
+	}
+	job.ID = jobid.New(cfg.Source, originalID)
+
+	posted, hasPosted := dc.parseDate(get("posted_date"))
+	if !hasPosted {
+		posted = time.Now()
+	}
+	job.PostedDate = posted
+	if expires, ok := dc.parseDate(get("expires_date")); ok {
+		job.ExpiresDate = expires
+	}
+
+	job.Fields = map[string]interface{}{}
+	for name, expr := range cfg.Fields {
+		values, err := lookup(expr, item)
+		if err != nil || len(values) == 0 {
+			continue
+		}
+		if len(values) == 1 {
+			job.Fields[name] = values[0]
+		} else {
+			job.Fields[name] = values
+		}
+	}
+	job.Fields["source"] = cfg.Source
+	job.Fields["source_url"] = job.URL
+	job.Fields["original_id"] = originalID
+	job.Fields["connector"] = cfg.ID
+	job.Fields["fetched_at"] = time.Now()
+	if !hasPosted {
+		job.Fields["posted_date_estimated"] = true
+	}
+
+	// Mapped salary_min and salary_max win over the amounts in the salary text
+	pay := salary.Parse(job.Salary, job.SalaryCurrency, "")
+	if job.SalaryMin != nil || job.SalaryMax != nil {
+		pay.Min, pay.Max = job.SalaryMin, job.SalaryMax
+	}
+	pay.Apply(&job)
+
+	return job, true
+}
+
+// parseDate parses a mapped date using the configured formats, then common defaults.
+// The special formats "unix" and "unix_ms" read epoch timestamps.
+func (dc *DeclarativeConnector) parseDate(value interface{}) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+
+	formats := append(append([]string{}, dc.config.DateFormats...), defaultDateFormats...)
+	for _, format := range formats {
+		switch format {
+		case "unix", "unix_ms":
+			n, err := strconv.ParseFloat(stringValue(value), 64)
+			if err != nil {
+				continue
+			}
+			if format == "unix_ms" {
+				return time.UnixMilli(int64(n)).UTC(), true
+			}
+			return time.Unix(int64(n), 0).UTC(), true
+		default:
+			s := stringValue(value)
+			if s == "" {
+				return time.Time{}, false
+			}
+			if t, err := time.Parse(format, s); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// getLastSyncTime returns the high watermark committed by the last successful sync
+func (dc *DeclarativeConnector) getLastSyncTime() time.Time {
+	if dc.store == nil {
+		return time.Time{}
+	}
+	checkpoint, err := dc.store.GetCheckpoint(dc.GetID(), "")
+	if err != nil {
+		fmt.Printf("⚠️  %s: failed to read checkpoint: %v\n", dc.GetID(), err)
+		return time.Time{}
+	}
+	return checkpoint.HighWatermark
+}
+
+// SyncJobs fetches jobs from the configured API and stores them
+func (dc *DeclarativeConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Printf("🔄 Starting %s sync...\n", dc.GetName())
+
+	jobs, listed, err := dc.fetchListing()
+	if err != nil {
+		dc.store.LogSync(&models.SyncLog{
+			ConnectorName: dc.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "failed",
+		})
+		return fmt.Errorf("failed to fetch jobs from %s: %w", dc.GetName(), err)
+	}
+
+	fmt.Printf("📥 Fetched %d jobs from %s\n", len(jobs), dc.GetName())
+
+	stored := 0
+	duplicates := 0
+	failed := 0
+	for _, job := range jobs {
+		existing, err := dc.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := dc.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	// Commit the checkpoint only once every job is stored, so failed jobs are retried.
+	// Snapshot sources first close the jobs that dropped off the listing.
+	removed := 0
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else {
+		if len(listed) > 0 {
+			closed, err := dc.store.CloseMissingJobs(dc.GetID(), listed, time.Now())
+			removed = len(closed)
+			if err != nil {
+				fmt.Printf("⚠️  Failed to close jobs missing from the listing: %v\n", err)
+			}
+		}
+		if err := dc.store.AdvanceCheckpoint(dc.GetID(), "", jobs); err != nil {
+			fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+		}
+	}
+
+	if err := dc.store.LogSync(&models.SyncLog{
+		ConnectorName:  dc.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		JobsRemoved:    removed,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 %s sync complete! Fetched: %d, Inserted: %d, Duplicates: %d, Closed: %d\n", dc.GetName(), len(jobs), stored, duplicates, removed)
+	return nil
+}
+
+// renderTemplate replaces {{...}} placeholders; values are query-escaped in URLs
+func renderTemplate(tmpl string, vars map[string]string, since time.Time, escape bool) (string, error) {
+	var renderErr error
+	out := templateVar.ReplaceAllStringFunc(tmpl, func(match string) string {
+		parts := templateVar.FindStringSubmatch(match)
+		name, arg := parts[1], strings.TrimSpace(parts[2])
+
+		value := ""
+		switch name {
+		case "env":
+			value = os.Getenv(arg)
+			if value == "" {
+				renderErr = fmt.Errorf("environment variable %s is not set", arg)
+			}
+		case "since":
+			if arg == "" {
+				arg = time.RFC3339
+			}
+			if !since.IsZero() {
+				value = since.UTC().Format(arg)
+			}
+		default:
+			v, ok := vars[name]
+			if !ok {
+				renderErr = fmt.Errorf("unknown template variable %q", name)
+			}
+			value = v
+		}
+
+		if escape {
+			return url.QueryEscape(value)
+		}
+		return value
+	})
+	return out, renderErr
+}
+
+// stringValue renders a decoded JSON value as a string
+func stringValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case []interface{}:
+		parts := []string{}
+		for _, item := range val {
+			if s := stringValue(item); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// intValue converts numbers and numeric strings to *int
+func intValue(v interface{}) *int {
+	var n float64
+	switch val := v.(type) {
+	case float64:
+		n = val
+	case int:
+		n = float64(val)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(val), ",", ""), 64)
+		if err != nil {
+			return nil
+		}
+		n = parsed
+	default:
+		return nil
+	}
+	i := int(n)
+	return &i
+}
+
+// boolValue accepts booleans and common truthy strings ("true", "yes", "remote")
+func boolValue(v interface{}) bool {
+	switch val := v.(type) {
+	case bool:
+		return val
+	case string:
+		switch strings.ToLower(strings.TrimSpace(val)) {
+		case "true", "yes", "1", "remote":
+			return true
+		}
+	case float64:
+		return val != 0
+	}
+	return false
+}
+
+// stringsValue converts an array or single value to a string slice; never nil, so
+// the array columns get [] instead of null
+func stringsValue(v interface{}) []string {
+	switch val := v.(type) {
+	case nil:
+		return []string{}
+	case []interface{}:
+		result := []string{}
+		for _, item := range val {
+			if s := stringValue(item); s != "" {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		if s := stringValue(val); s != "" {
+			return []string{s}
+		}
+		return []string{}
+	}
+}
+
+// truncate shortens error bodies for logging
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,129 @@
+package declarative
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathStep is one segment of a compiled JSONPath expression
+type pathStep struct {
+	key      string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// compilePath parses the JSONPath subset used in mapping files:
+// $, .key, ['key'], [n], [*] and .* (e.g. "$.results[*].company.display_name")
+func compilePath(expr string) ([]pathStep, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("JSONPath %q must start with $", expr)
+	}
+
+	steps := []pathStep{}
+	rest := expr[1:]
+	for len(rest) > 0 {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("JSONPath %q has an empty key", expr)
+			}
+			if key == "*" {
+				steps = append(steps, pathStep{wildcard: true})
+			} else {
+				steps = append(steps, pathStep{key: key})
+			}
+			rest = rest[end:]
+
+		case '[':
+			end := strings.Index(rest, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("JSONPath %q has an unclosed [", expr)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+
+			switch {
+			case inner == "*":
+				steps = append(steps, pathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, pathStep{key: inner[1 : len(inner)-1]})
+			default:
+				n, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("JSONPath %q has an invalid index %q", expr, inner)
+				}
+				steps = append(steps, pathStep{index: n, isIndex: true})
+			}
+
+		default:
+			return nil, fmt.Errorf("JSONPath %q has unexpected character %q", expr, rest[0])
+		}
+	}
+
+	return steps, nil
+}
+
+// evalPath returns every value matched by the path in a decoded JSON document
+func evalPath(steps []pathStep, doc interface{}) []interface{} {
+	current := []interface{}{doc}
+
+	for _, step := range steps {
+		next := []interface{}{}
+		for _, node := range current {
+			switch {
+			case step.wildcard:
+				switch v := node.(type) {
+				case []interface{}:
+					next = append(next, v...)
+				case map[string]interface{}:
+					for _, child := range v {
+						next = append(next, child)
+					}
+				}
+			case step.isIndex:
+				if arr, ok := node.([]interface{}); ok {
+					i := step.index
+					if i < 0 {
+						i += len(arr)
+					}
+					if i >= 0 && i < len(arr) {
+						next = append(next, arr[i])
+					}
+				}
+			default:
+				if obj, ok := node.(map[string]interface{}); ok {
+					if child, exists := obj[step.key]; exists {
+						next = append(next, child)
+					}
+				}
+			}
+		}
+		current = next
+	}
+
+	return current
+}
+
+// lookup evaluates a mapping value against a document. Strings starting with $
+// are JSONPath expressions; anything else is returned as a literal constant.
+func lookup(expr interface{}, doc interface{}) ([]interface{}, error) {
+	path, ok := expr.(string)
+	if !ok || !strings.HasPrefix(strings.TrimSpace(path), "$") {
+		return []interface{}{expr}, nil
+	}
+
+	steps, err := compilePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return evalPath(steps, doc), nil
+}
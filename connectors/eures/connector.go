@@ -1,53 +1,130 @@
 package eures
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 )
 
-// EURESConnector implements the connector interface for EURES
+// EURESConnector implements the connector interface for the European Commission's
+// EURES job mobility portal (vacancies published by the EU/EEA public employment services)
 type EURESConnector struct {
 	store      *storage.JobStore
 	baseURL    string
+	portalURL  string
 	userAgent  string
-	appID      string
-	appKey     string
+	keywords   string
+	countries  []string
+	language   string
+	maxPages   int
+	pageSize   int
 	httpClient *http.Client
 }
 
-// AdzunaJob represents a job from the Adzuna API
-type AdzunaJob struct {
-	ID          string `json:"id"` // Changed from int to string
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Company     struct {
-		DisplayName string `json:"display_name"`
-	} `json:"company"`
-	Location struct {
-		Area []string `json:"area"`
-	} `json:"location"`
-	SalaryMin    float64 `json:"salary_min,omitempty"`
-	SalaryMax    float64 `json:"salary_max,omitempty"`
-	ContractType string  `json:"contract_type"`
-	ContractTime string  `json:"contract_time"`
-	Created      string  `json:"created"`
-	RedirectURL  string  `json:"redirect_url"`
+// euresSearchRequest is the body of the EURES vacancy search
+type euresSearchRequest struct {
+	ResultsPerPage    int            `json:"resultsPerPage"`
+	Page              int            `json:"page"`
+	SortSearch        string         `json:"sortSearch"`
+	Keywords          []euresKeyword `json:"keywords"`
+	PublicationPeriod string         `json:"publicationPeriod,omitempty"`
+	LocationCodes     []string       `json:"locationCodes"`
+	OccupationUris    []string       `json:"occupationUris"`
+	RequiredLanguages []string       `json:"requiredLanguages"`
 }
 
-// AdzunaResponse represents the API response structure
-type AdzunaResponse struct {
-	Results []AdzunaJob `json:"results"`
-	Count   int         `json:"count"`
+type euresKeyword struct {
+	Keyword            string `json:"keyword"`
+	SpecificSearchCode string `json:"specificSearchCode"`
 }
 
+// euresSearchResponse is one page of vacancy summaries
+type euresSearchResponse struct {
+	NumberRecords int            `json:"numberRecords"`
+	JVs           []euresSummary `json:"jvs"`
+}
+
+// euresSummary is a vacancy as listed in search results
+type euresSummary struct {
+	ID                    string              `json:"id"`
+	Title                 string              `json:"title"`
+	Description           string              `json:"description"`
+	CreationDate          int64               `json:"creationDate"` // Unix milliseconds
+	LastModificationDate  int64               `json:"lastModificationDate"`
+	NumberOfPosts         int                 `json:"numberOfPosts"`
+	LocationMap           map[string][]string `json:"locationMap"` // Country code → NUTS codes
+	PositionScheduleCodes []string            `json:"positionScheduleCodes"`
+	Employer              struct {
+		Name string `json:"name"`
+	} `json:"employer"`
+}
+
+// euresDetail is the full vacancy with one profile per published language
+type euresDetail struct {
+	ID                string                  `json:"id"`
+	PreferredLanguage string                  `json:"preferredLanguage"`
+	JVProfiles        map[string]euresProfile `json:"jvProfiles"`
+}
+
+type euresProfile struct {
+	Title          string `json:"title"`
+	JobDescription string `json:"jobDescription"`
+	Employer       struct {
+		Name    string `json:"name"`
+		Website string `json:"website"`
+	} `json:"employer"`
+	Locations []struct {
+		CountryCode string `json:"countryCode"`
+		Region      string `json:"region"` // NUTS code, e.g. SE110
+		CityName    string `json:"cityName"`
+		PostalCode  string `json:"postalCode"`
+	} `json:"locations"`
+	Occupations []struct {
+		Code   string `json:"occupationCode"`
+		Scheme string `json:"occupationScheme"` // ESCO_OCCUPATIONS or ISCO
+	} `json:"occupations"`
+	RequiredLanguages []struct {
+		Code  string `json:"languageCode"`
+		Level string `json:"proficiencyLevel"` // CEFR A1-C2
+	} `json:"requiredLanguages"`
+	PositionScheduleCodes []string `json:"positionScheduleCodes"`
+	PositionOfferingCode  string   `json:"positionOfferingCode"`
+	LastApplicationDate   int64    `json:"lastApplicationDate"`
+	ApplicationURL        string   `json:"applicationUrl"`
+}
+
+// countryNames maps EURES country codes (NUTS prefixes) to names
+var countryNames = map[string]string{
+	"AT": "Austria", "BE": "Belgium", "BG": "Bulgaria", "CH": "Switzerland", "CY": "Cyprus",
+	"CZ": "Czechia", "DE": "Germany", "DK": "Denmark", "EE": "Estonia", "EL": "Greece",
+	"ES": "Spain", "FI": "Finland", "FR": "France", "HR": "Croatia", "HU": "Hungary",
+	"IE": "Ireland", "IS": "Iceland", "IT": "Italy", "LI": "Liechtenstein", "LT": "Lithuania",
+	"LU": "Luxembourg", "LV": "Latvia", "MT": "Malta", "NL": "Netherlands", "NO": "Norway",
+	"PL": "Poland", "PT": "Portugal", "RO": "Romania", "SE": "Sweden", "SI": "Slovenia",
+	"SK": "Slovakia",
+}
+
+var (
+	htmlTags = regexp.MustCompile(`<[^>]*>`)
+	iscoCode = regexp.MustCompile(`(?:isco/C)?(\d{4})$`)
+)
+
 // GetID returns the connector ID
 func (ec *EURESConnector) GetID() string {
 	return "eures"
@@ -58,304 +135,279 @@ func (ec *EURESConnector) GetName() string {
 	return "EURES Connector"
 }
 
-// NewEURESConnector creates a new EURES connector
+// NewEURESConnector creates a new EURES connector.
+// EURES_KEYWORDS, EURES_COUNTRIES (e.g. "se,no,dk"), EURES_LANGUAGE and
+// EURES_MAX_PAGES narrow the search; by default all recent vacancies are read.
 func NewEURESConnector(store *storage.JobStore) *EURESConnector {
+	countries := []string{}
+	for _, c := range strings.Split(os.Getenv("EURES_COUNTRIES"), ",") {
+		if c = strings.ToLower(strings.TrimSpace(c)); c != "" {
+			countries = append(countries, c)
+		}
+	}
+
+	language := os.Getenv("EURES_LANGUAGE")
+	if language == "" {
+		language = "en"
+	}
+
+	maxPages := 10
+	if n, err := strconv.Atoi(os.Getenv("EURES_MAX_PAGES")); err == nil && n > 0 {
+		maxPages = n
+	}
+
 	return &EURESConnector{
 		store:      store,
-		baseURL:    "https://api.adzuna.com/v1/api/jobs", // Adzuna base URL
-		userAgent:  "OpenJobs-EURES-Connector/1.0",
-		appID:      os.Getenv("ADZUNA_APP_ID"),
-		appKey:     os.Getenv("ADZUNA_APP_KEY"),
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    "https://europa.eu/eures/eures-apps/searchengine/page",
+		portalURL:  "https://europa.eu/eures/portal/jv-se/jv-details",
+		userAgent:  httpclient.UserAgent("OpenJobs-EURES-Connector/1.0"),
+		keywords:   os.Getenv("EURES_KEYWORDS"),
+		countries:  countries,
+		language:   language,
+		maxPages:   maxPages,
+		pageSize:   50,
+		httpClient: httpclient.New(httpclient.Options{Interval: 500 * time.Millisecond}),
 	}
 }
 
-// FetchJobs fetches job listings from Adzuna API
+// FetchJobs searches EURES newest first and fetches the full record of every new vacancy
 func (ec *EURESConnector) FetchJobs() ([]models.JobPost, error) {
+	lastSync := ec.getLastSyncTime()
+	period := publicationPeriod(lastSync)
 
-	// If credentials not configured, return demo data
-	if ec.appID == "" || ec.appKey == "" {
-		fmt.Println("⚠️  Adzuna credentials not configured, using demo data")
-		return ec.fetchDemoJobs(), nil
-	}
-
-	// Fetch from multiple European countries
-	// Note: Adzuna API only supports specific countries
-	// Supported: at, au, be, br, ca, ch, de, es, fr, gb, in, it, mx, nl, nz, pl, sg, us, za
-	countries := []string{
-		"de", // Germany
-		"nl", // Netherlands
-		"at", // Austria
-		"ch", // Switzerland
-		"be", // Belgium
-		"fr", // France
-		"es", // Spain
-		"it", // Italy
-		"pl", // Poland
-		"gb", // United Kingdom
-	}
-
-	allJobs := []models.JobPost{}
-	for _, country := range countries {
-		countryJobs, err := ec.fetchJobsFromCountry(country)
+	jobs := []models.JobPost{}
+	for page := 1; page <= ec.maxPages; page++ {
+		response, err := ec.search(page, period)
 		if err != nil {
-			fmt.Printf("⚠️  Error fetching jobs from %s: %v\n", country, err)
-			continue // Try next country
+			if page == 1 {
+				return nil, err
+			}
+			fmt.Printf("⚠️  EURES page %d failed: %v\n", page, err)
+			break
 		}
-		allJobs = append(allJobs, countryJobs...)
-		fmt.Printf("   ✅ Fetched %d jobs from %s\n", len(countryJobs), country)
-		
-		// Rate limiting between countries
-		time.Sleep(1 * time.Second)
-	}
 
-	if len(allJobs) == 0 {
-		fmt.Println("⚠️  No jobs fetched from any country, using demo data")
-		return ec.fetchDemoJobs(), nil
+		reachedKnown := false
+		for _, summary := range response.JVs {
+			created := fromMillis(summary.CreationDate)
+			if !lastSync.IsZero() && !created.After(lastSync) {
+				reachedKnown = true
+				continue
+			}
+
+			detail, err := ec.fetchDetail(summary.ID)
+			if err != nil {
+				fmt.Printf("   ⚠️  Using summary for %s: %v\n", summary.ID, err)
+			}
+			jobs = append(jobs, ec.transformVacancy(summary, detail))
+		}
+
+		fmt.Printf("📄 EURES page %d: %d vacancies (total so far: %d)\n", page, len(response.JVs), len(jobs))
+
+		if reachedKnown || len(response.JVs) < ec.pageSize || page*ec.pageSize >= response.NumberRecords {
+			break
+		}
 	}
 
-	return allJobs, nil
+	return jobs, nil
 }
 
-// fetchJobsFromCountry fetches jobs from a specific country
-func (ec *EURESConnector) fetchJobsFromCountry(country string) ([]models.JobPost, error) {
-	// Get last sync time for incremental sync
-	lastSync := ec.getLastSyncTime()
-	
-	// Build API URL with credentials - Adzuna API format
-	url := fmt.Sprintf("%s/%s/search/1?app_id=%s&app_key=%s&results_per_page=100&what=developer+OR+programmer+OR+software",
-		ec.baseURL, country, ec.appID, ec.appKey)
-	
-	// Add date filter if we have a last sync time
-	if !lastSync.IsZero() {
-		// Calculate days since last sync
-		daysSince := int(time.Since(lastSync).Hours() / 24)
-		if daysSince < 1 {
-			daysSince = 1 // Minimum 1 day
-		}
-		url += fmt.Sprintf("&max_days_old=%d", daysSince)
-		fmt.Printf("📅 Fetching jobs from last %d days\n", daysSince)
+// search fetches one page of vacancy summaries, most recent first
+func (ec *EURESConnector) search(page int, period string) (*euresSearchResponse, error) {
+	request := euresSearchRequest{
+		ResultsPerPage:    ec.pageSize,
+		Page:              page,
+		SortSearch:        "MOST_RECENT",
+		Keywords:          []euresKeyword{},
+		PublicationPeriod: period,
+		LocationCodes:     ec.countries,
+		OccupationUris:    []string{},
+		RequiredLanguages: []string{},
+	}
+	if ec.keywords != "" {
+		request.Keywords = append(request.Keywords, euresKeyword{Keyword: ec.keywords, SpecificSearchCode: "EVERYWHERE"})
 	}
 
-	fmt.Printf("🔍 Fetching jobs from Adzuna (%s)...\n", country)
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search: %w", err)
+	}
 
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("POST", ec.baseURL+"/jv-search/search", bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
+	var response euresSearchResponse
+	if err := ec.do(req, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// fetchDetail fetches the full vacancy record with all language profiles
+func (ec *EURESConnector) fetchDetail(id string) (*euresDetail, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/jv/id/%s?lang=%s", ec.baseURL, url.PathEscape(id), ec.language), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var detail euresDetail
+	if err := ec.do(req, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+func (ec *EURESConnector) do(req *http.Request, v interface{}) error {
 	req.Header.Set("User-Agent", ec.userAgent)
+	req.Header.Set("Accept", "application/json")
 
 	resp, err := ec.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("adzuna API error %d: %s", resp.StatusCode, string(body))
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("EURES API error %d: %s", resp.StatusCode, string(body))
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
 	}
+	return nil
+}
 
-	var adzunaResponse AdzunaResponse
-	err = json.Unmarshal(body, &adzunaResponse)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// transformVacancy converts a EURES vacancy to our JobPost format. The detail record
+// (nil when it couldn't be fetched) supplies ESCO occupations, NUTS regions and languages.
+func (ec *EURESConnector) transformVacancy(summary euresSummary, detail *euresDetail) models.JobPost {
+	sourceURL := fmt.Sprintf("%s/%s?lang=%s", ec.portalURL, url.PathEscape(summary.ID), ec.language)
+
+	profile, profileLang, languages := ec.pickProfile(detail)
+
+	title := firstNonEmpty(profile.Title, summary.Title)
+	company := firstNonEmpty(profile.Employer.Name, summary.Employer.Name)
+	description := plainText(firstNonEmpty(profile.JobDescription, summary.Description))
+
+	// Regions: detail locations, falling back to the summary's country → NUTS map
+	countries := []string{}
+	nuts := []string{}
+	cities := []string{}
+	for _, l := range profile.Locations {
+		countries = appendUnique(countries, strings.ToUpper(l.CountryCode))
+		nuts = appendUnique(nuts, strings.ToUpper(l.Region))
+		cities = appendUnique(cities, l.CityName)
 	}
-
-	// Transform to our JobPost format
-	jobs := make([]models.JobPost, 0, len(adzunaResponse.Results))
-	for _, adzunaJob := range adzunaResponse.Results {
-		job := ec.transformAdzunaJob(adzunaJob)
-		jobs = append(jobs, job)
+	if len(countries) == 0 {
+		for country, codes := range summary.LocationMap {
+			countries = appendUnique(countries, strings.ToUpper(country))
+			for _, code := range codes {
+				nuts = appendUnique(nuts, strings.ToUpper(code))
+			}
+		}
 	}
 
-	fmt.Printf("   ✅ Fetched %d jobs from %s\n", len(jobs), country)
-	return jobs, nil
-}
+	escoURIs := []string{}
+	iscoCodes := []string{}
+	for _, o := range profile.Occupations {
+		switch {
+		case strings.Contains(o.Code, "/esco/occupation/"):
+			escoURIs = appendUnique(escoURIs, o.Code)
+		case strings.EqualFold(o.Scheme, "ISCO") || strings.Contains(o.Code, "/esco/isco/"):
+			if m := iscoCode.FindStringSubmatch(o.Code); m != nil {
+				iscoCodes = appendUnique(iscoCodes, m[1])
+			}
+		}
+	}
 
-// fetchDemoJobs returns demo data as fallback
-func (ec *EURESConnector) fetchDemoJobs() []models.JobPost {
-	jobs := []models.JobPost{
-		{
-			ID:              "demo-001",
-			Title:           "Senior Software Engineer",
-			Company:         "TechCorp Sweden AB",
-			Description:     "We are looking for an experienced software engineer to join our innovative team in Stockholm. You will work on cutting-edge technologies and contribute to our mission of digital transformation.",
-			Location:        "Stockholm, Sweden",
-			Salary:          "SEK 45,000 - 65,000/month",
-			EmploymentType:  "Full-time",
-			ExperienceLevel: "Senior",
-			PostedDate:      time.Now().Add(-24 * time.Hour),
-			ExpiresDate:     time.Now().AddDate(0, 1, 0),
-			Requirements:    []string{"5+ years experience", "Go, Python, or Java", "Cloud platforms (AWS/GCP)"},
-			Benefits:        []string{"Health insurance", "Flexible hours", "Remote work options"},
-			Fields: map[string]interface{}{
-				"source":      "demo-eures",
-				"source_url":  "https://example.com/job/001",
-				"original_id": "001",
-				"country":     "Sweden",
-				"city":        "Stockholm",
-				"connector":   "eures",
-				"fetched_at":  time.Now(),
-			},
-		},
-		{
-			ID:              "demo-002",
-			Title:           "Full Stack Developer",
-			Company:         "Nordic Startup GmbH",
-			Description:     "Join our fast-growing startup as a full stack developer. We're building the future of Nordic fintech and need talented developers to help us scale.",
-			Location:        "Copenhagen, Denmark",
-			Salary:          "DKK 35,000 - 50,000/month",
-			EmploymentType:  "Full-time",
-			ExperienceLevel: "Mid-level",
-			PostedDate:      time.Now().Add(-48 * time.Hour),
-			ExpiresDate:     time.Now().AddDate(0, 1, 0),
-			Requirements:    []string{"3+ years experience", "React, Node.js", "PostgreSQL"},
-			Benefits:        []string{"Stock options", "Learning budget", "Team events"},
-			Fields: map[string]interface{}{
-				"source":      "demo-eures",
-				"source_url":  "https://example.com/job/002",
-				"original_id": "002",
-				"country":     "Denmark",
-				"city":        "Copenhagen",
-				"connector":   "eures",
-				"fetched_at":  time.Now(),
-			},
-		},
-		{
-			ID:              "demo-003",
-			Title:           "DevOps Engineer",
-			Description:     "We're seeking a DevOps engineer to help us build and maintain our cloud infrastructure. Experience with Kubernetes, Docker, and CI/CD pipelines is essential.",
-			Location:        "Helsinki, Finland",
-			Salary:          "EUR 4,500 - 6,500/month",
-			EmploymentType:  "Full-time",
-			ExperienceLevel: "Senior",
-			PostedDate:      time.Now().Add(-72 * time.Hour),
-			ExpiresDate:     time.Now().AddDate(0, 1, 0),
-			Requirements:    []string{"4+ years DevOps experience", "Kubernetes, Docker", "AWS/Azure/GCP"},
-			Benefits:        []string{"Health insurance", "Professional development", "Flexible work"},
-			Fields: map[string]interface{}{
-				"source":      "demo-eures",
-				"source_url":  "https://example.com/job/003",
-				"original_id": "003",
-				"country":     "Finland",
-				"city":        "Helsinki",
-				"connector":   "eures",
-				"fetched_at":  time.Now(),
-			},
-		},
-	}
-
-	return jobs
-}
+	requiredLanguages := []map[string]string{}
+	requirements := []string{}
+	for _, l := range profile.RequiredLanguages {
+		code := strings.ToLower(l.Code)
+		requiredLanguages = append(requiredLanguages, map[string]string{"language": code, "level": strings.ToUpper(l.Level)})
+		requirements = append(requirements, strings.TrimSpace(fmt.Sprintf("Language: %s %s", code, strings.ToUpper(l.Level))))
+	}
 
-// transformAdzunaJob converts Adzuna job format to our JobPost format
-func (ec *EURESConnector) transformAdzunaJob(aj AdzunaJob) models.JobPost {
-	job := models.JobPost{
-		ID:              fmt.Sprintf("adzuna-%s", aj.ID),
-		Title:           aj.Title,
-		Company:         aj.Company.DisplayName,
-		Description:     aj.Description,
-		Location:        strings.Join(aj.Location.Area, ", "),
-		URL:             aj.RedirectURL, // Direct application link
-		EmploymentType:  ec.mapEmploymentType(aj.ContractTime),
-		ExperienceLevel: "Mid-level", // Adzuna doesn't provide experience level
-		PostedDate:      ec.parseAdzunaDate(aj.Created),
-		ExpiresDate:     ec.parseAdzunaDate(aj.Created).AddDate(0, 1, 0), // Default 1 month expiry
-		Requirements:    ec.extractRequirementsFromText(aj.Title, aj.Description), // Extract from text
-		Fields: map[string]interface{}{
-			"source":        "adzuna",
-			"source_url":    aj.RedirectURL,
-			"original_id":   aj.ID,
-			"contract_type": aj.ContractType,
-			"contract_time": aj.ContractTime,
-			"location_area": aj.Location.Area,
-			"connector":     "eures-adzuna",
-			"fetched_at":    time.Now(),
-		},
-	}
-
-	// Handle salary - Adzuna provides structured salary data!
-	if aj.SalaryMin > 0 || aj.SalaryMax > 0 {
-		// Populate structured fields for LazyJobs matching
-		if aj.SalaryMin > 0 {
-			salaryMin := int(aj.SalaryMin)
-			job.SalaryMin = &salaryMin
-		}
-		if aj.SalaryMax > 0 {
-			salaryMax := int(aj.SalaryMax)
-			job.SalaryMax = &salaryMax
-		}
-		job.SalaryCurrency = "EUR"
-		
-		// Also create human-readable string
-		if aj.SalaryMin > 0 && aj.SalaryMax > 0 {
-			job.Salary = fmt.Sprintf("€%.0f - €%.0f", aj.SalaryMin, aj.SalaryMax)
-		} else if aj.SalaryMin > 0 {
-			job.Salary = fmt.Sprintf("€%.0f+", aj.SalaryMin)
-		} else {
-			job.Salary = fmt.Sprintf("Up to €%.0f", aj.SalaryMax)
-		}
+	schedule := profile.PositionScheduleCodes
+	if len(schedule) == 0 {
+		schedule = summary.PositionScheduleCodes
 	}
 
-	return job
-}
+	posted := fromMillis(summary.CreationDate)
+	if posted.IsZero() {
+		posted = time.Now()
+	}
 
-// parseAdzunaDate parses Adzuna date string
-func (ec *EURESConnector) parseAdzunaDate(dateStr string) time.Time {
-	// Try parsing different date formats
-	formats := []string{
-		"2006-01-02T15:04:05Z",
-		"2006-01-02T15:04:05",
-		"2006-01-02",
+	fields := map[string]interface{}{
+		"source":                 "eures",
+		"source_url":             sourceURL,
+		"original_id":            summary.ID,
+		"connector":              "eures",
+		"fetched_at":             time.Now(),
+		"countries":              countries,
+		"nuts_regions":           nuts,
+		"cities":                 cities,
+		"esco_occupation_uris":   escoURIs,
+		"isco_codes":             iscoCodes,
+		"required_languages":     requiredLanguages,
+		"profile_languages":      languages,
+		"description_language":   profileLang,
+		"number_of_posts":        summary.NumberOfPosts,
+		"position_schedule":      schedule,
+		"position_offering":      profile.PositionOfferingCode,
+		"last_modification_date": fromMillis(summary.LastModificationDate),
+	}
+	if profile.Employer.Website != "" {
+		fields["employer_url"] = profile.Employer.Website
+	}
+	if profile.ApplicationURL != "" {
+		fields["apply_url"] = profile.ApplicationURL
 	}
 
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t
-		}
+	job := models.JobPost{
+		ID:             jobid.New("eures", summary.ID),
+		Title:          strings.TrimSpace(title),
+		Company:        strings.TrimSpace(company),
+		Description:    description,
+		Location:       formatLocation(cities, countries),
+		URL:            sourceURL,
+		EmploymentType: mapEmploymentType(schedule, profile.PositionOfferingCode),
+		PostedDate:     posted,
+		ExpiresDate:    fromMillis(profile.LastApplicationDate),
+		Requirements:   requirements,
+		Benefits:       []string{},
+		Fields:         fields,
+	}
+	// Vacancies have no salary field; employers state it in the description, if at all
+	if pay, text := salary.Find(description, ""); text != "" {
+		pay.Apply(&job)
+		job.Salary = text
 	}
 
-	// Default to current time if parsing fails
-	return time.Now()
+	return job
 }
 
-// mapEmploymentType converts EURES employment type to our format
-func (ec *EURESConnector) mapEmploymentType(euresType string) string {
-	switch strings.ToLower(euresType) {
-	case "full-time", "full time":
-		return "Full-time"
-	case "part-time", "part time":
-		return "Part-time"
-	case "contract", "temporary":
-		return "Contract"
-	case "internship":
-		return "Internship"
-	default:
-		return "Full-time" // Default
+// pickProfile returns the profile in the configured language, else the preferred one,
+// plus the profile's language and every published language
+func (ec *EURESConnector) pickProfile(detail *euresDetail) (euresProfile, string, []string) {
+	if detail == nil || len(detail.JVProfiles) == 0 {
+		return euresProfile{}, "", []string{}
 	}
-}
 
-// mapExperienceLevel converts EURES experience to our format
-func (ec *EURESConnector) mapExperienceLevel(euresExp string) string {
-	switch strings.ToLower(euresExp) {
-	case "entry", "entry-level", "junior":
-		return "Entry-level"
-	case "mid", "mid-level", "experienced":
-		return "Mid-level"
-	case "senior", "expert":
-		return "Senior"
-	case "executive", "management":
-		return "Executive"
-	default:
-		return "Mid-level" // Default
+	languages := make([]string, 0, len(detail.JVProfiles))
+	for lang := range detail.JVProfiles {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	for _, lang := range []string{ec.language, detail.PreferredLanguage} {
+		if p, ok := detail.JVProfiles[lang]; ok {
+			return p, lang, languages
+		}
 	}
+	return detail.JVProfiles[languages[0]], languages[0], languages
 }
 
 // SyncJobs fetches jobs from EURES and stores them in the database
@@ -385,11 +437,13 @@ func (ec *EURESConnector) SyncJobs() error {
 
 	stored := 0
 	duplicates := 0
+	failed := 0
 	for _, job := range jobs {
 		// Check if job already exists (by source ID)
 		existing, err := ec.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -403,6 +457,7 @@ func (ec *EURESConnector) SyncJobs() error {
 		err = ec.store.CreateJob(&job)
 		if err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -410,6 +465,13 @@ func (ec *EURESConnector) SyncJobs() error {
 		fmt.Printf("✅ Stored job: %s\n", job.Title)
 	}
 
+	// Commit the checkpoint only once every job is stored, so failed jobs are retried
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if err := ec.store.AdvanceCheckpoint(ec.GetID(), "", jobs); err != nil {
+		fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+	}
+
 	// Log successful sync
 	if err := ec.store.LogSync(&models.SyncLog{
 		ConnectorName:  ec.GetID(),
@@ -427,71 +489,106 @@ func (ec *EURESConnector) SyncJobs() error {
 	return nil
 }
 
-// extractRequirementsFromText extracts basic keywords from title and description
-// This is a simple keyword-based extraction for APIs that don't provide structured requirements
-func (ec *EURESConnector) extractRequirementsFromText(title, description string) []string {
-	requirements := []string{}
-	seen := make(map[string]bool)
-	
-	// Combine title and description for searching
-	text := strings.ToLower(title + " " + description)
-	
-	// Common tech skills and keywords
-	keywords := []string{
-		// Programming languages
-		"Java", "Python", "JavaScript", "TypeScript", "C++", "C#", ".NET", "PHP", "Ruby", "Go", "Rust", "Swift", "Kotlin",
-		// Frameworks & Libraries
-		"React", "Angular", "Vue", "Node.js", "Spring", "Django", "Flask", "Express", "Laravel",
-		// DevOps & Cloud
-		"Docker", "Kubernetes", "AWS", "Azure", "GCP", "CI/CD", "Jenkins", "Git", "Linux",
-		// Databases
-		"SQL", "PostgreSQL", "MySQL", "MongoDB", "Redis", "Elasticsearch",
-		// Other skills
-		"API", "REST", "GraphQL", "Microservices", "Agile", "Scrum",
-	}
-	
-	// Extract keywords found in text
-	for _, keyword := range keywords {
-		if strings.Contains(text, strings.ToLower(keyword)) {
-			if !seen[keyword] {
-				requirements = append(requirements, keyword)
-				seen[keyword] = true
-			}
-		}
+// getLastSyncTime returns the high watermark committed by the last successful sync
+func (ec *EURESConnector) getLastSyncTime() time.Time {
+	if ec.store == nil {
+		return time.Time{}
 	}
-	
-	// Extract experience level from description
-	if strings.Contains(text, "senior") || strings.Contains(text, "lead") {
-		if !seen["Senior level"] {
-			requirements = append(requirements, "Senior level")
-			seen["Senior level"] = true
-		}
-	} else if strings.Contains(text, "junior") || strings.Contains(text, "entry") {
-		if !seen["Junior level"] {
-			requirements = append(requirements, "Junior level")
-			seen["Junior level"] = true
+	checkpoint, err := ec.store.GetCheckpoint(ec.GetID(), "")
+	if err != nil {
+		fmt.Printf("⚠️  Failed to read EURES checkpoint, fetching all jobs: %v\n", err)
+		return time.Time{}
+	}
+	if checkpoint.HighWatermark.IsZero() {
+		fmt.Println("📅 No EURES checkpoint yet - fetching all jobs")
+		return time.Time{}
+	}
+
+	fmt.Printf("📅 EURES checkpoint: jobs posted up to %s\n", checkpoint.HighWatermark.Format("2006-01-02"))
+	return checkpoint.HighWatermark
+}
+
+// publicationPeriod picks the narrowest EURES publication filter covering lastSync
+func publicationPeriod(lastSync time.Time) string {
+	if lastSync.IsZero() {
+		return ""
+	}
+	switch age := time.Since(lastSync); {
+	case age < 24*time.Hour:
+		return "LAST_DAY"
+	case age < 3*24*time.Hour:
+		return "LAST_THREE_DAYS"
+	case age < 7*24*time.Hour:
+		return "LAST_WEEK"
+	case age < 30*24*time.Hour:
+		return "LAST_MONTH"
+	default:
+		return ""
+	}
+}
+
+// mapEmploymentType converts EURES schedule and offering codes to our format
+func mapEmploymentType(schedule []string, offering string) string {
+	switch strings.ToLower(offering) {
+	case "temporary", "contracttohire", "seasonal":
+		return "Contract"
+	case "internship", "apprenticeship":
+		return "Internship"
+	}
+	for _, s := range schedule {
+		switch strings.ToLower(s) {
+		case "fulltime", "full-time":
+			return "Full-time"
+		case "parttime", "part-time":
+			return "Part-time"
 		}
 	}
-	
-	// Extract years of experience
-	if strings.Contains(text, "years experience") || strings.Contains(text, "years of experience") {
-		if !seen["Experience required"] {
-			requirements = append(requirements, "Experience required")
-			seen["Experience required"] = true
+	return ""
+}
+
+// formatLocation joins cities and country names, e.g. "Stockholm, Sweden"
+func formatLocation(cities, countries []string) string {
+	names := []string{}
+	for _, c := range countries {
+		if name, ok := countryNames[c]; ok {
+			names = append(names, name)
+		} else {
+			names = append(names, c)
 		}
 	}
-	
-	return requirements
+	return strings.Join(append(append([]string{}, cities...), names...), ", ")
 }
 
-// getLastSyncTime retrieves the timestamp of the most recent job in database
-func (ec *EURESConnector) getLastSyncTime() time.Time {
-	job, err := ec.store.GetMostRecentJob("adzuna-")
-	if err != nil {
-		fmt.Println("📅 No previous EURES jobs found - fetching all jobs")
+func fromMillis(ms int64) time.Time {
+	if ms <= 0 {
 		return time.Time{}
 	}
-	
-	fmt.Printf("📅 Last EURES job in database: %s (posted: %s)\n", job.Title, job.PostedDate.Format("2006-01-02"))
-	return job.PostedDate
+	return time.UnixMilli(ms).UTC()
+}
+
+// plainText strips the HTML EURES descriptions are published with
+func plainText(s string) string {
+	s = htmlTags.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func appendUnique(list []string, value string) []string {
+	if value == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == value {
+			return list
+		}
+	}
+	return append(list, value)
 }
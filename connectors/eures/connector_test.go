@@ -0,0 +1,176 @@
+package eures
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+func TestFetchJobs(t *testing.T) {
+	var search euresSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jv-search/search":
+			if err := json.NewDecoder(r.Body).Decode(&search); err != nil {
+				t.Errorf("Bad search body: %v", err)
+			}
+			fmt.Fprint(w, `{"numberRecords": 2, "jvs": [
+				{"id": "MTIz", "title": "Nurse", "creationDate": 1759305600000, "numberOfPosts": 3,
+				 "locationMap": {"se": ["SE110"]}, "employer": {"name": "Region Stockholm"}},
+				{"id": "NDU2 MQ==", "title": "Welder", "description": "<p>Welding &amp; more. Lønn: 550 000 NOK per år.</p>",
+				 "creationDate": 1759392000000, "locationMap": {"no": ["NO081"]},
+				 "positionScheduleCodes": ["parttime"], "employer": {"name": "Verft AS"}}
+			]}`)
+		case "/jv/id/MTIz":
+			fmt.Fprint(w, `{"id": "MTIz", "preferredLanguage": "sv", "jvProfiles": {
+				"sv": {"title": "Sjuksköterska", "jobDescription": "Svenska"},
+				"en": {"title": "Registered nurse", "jobDescription": "<p>Care for patients</p>",
+				 "employer": {"name": "Region Stockholm", "website": "https://regionstockholm.se"},
+				 "locations": [{"countryCode": "SE", "region": "SE110", "cityName": "Stockholm"}],
+				 "occupations": [
+				  {"occupationCode": "http://data.europa.eu/esco/occupation/abc", "occupationScheme": "ESCO_OCCUPATIONS"},
+				  {"occupationCode": "http://data.europa.eu/esco/isco/C2221", "occupationScheme": "ISCO"}],
+				 "requiredLanguages": [{"languageCode": "SV", "proficiencyLevel": "b2"}],
+				 "positionScheduleCodes": ["fulltime"], "positionOfferingCode": "directhire",
+				 "lastApplicationDate": 1761955200000}}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ec := NewEURESConnector(nil)
+	ec.baseURL = server.URL
+	ec.httpClient = httpclient.New(httpclient.Options{})
+	ec.countries = []string{"se", "no"}
+
+	jobs, err := ec.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if search.SortSearch != "MOST_RECENT" || len(search.LocationCodes) != 2 || search.PublicationPeriod != "" {
+		t.Errorf("Unexpected search: %+v", search)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(jobs))
+	}
+
+	nurse := jobs[0]
+	if nurse.ID != "eures:MTIz" || nurse.Title != "Registered nurse" || nurse.Location != "Stockholm, Sweden" {
+		t.Errorf("Unexpected nurse job: %s %q %q", nurse.ID, nurse.Title, nurse.Location)
+	}
+	if nurse.Description != "Care for patients" || nurse.EmploymentType != "Full-time" {
+		t.Errorf("Unexpected description/type: %q %q", nurse.Description, nurse.EmploymentType)
+	}
+	if nurse.ExpiresDate.Format("2006-01-02") != "2025-11-01" {
+		t.Errorf("Unexpected deadline: %v", nurse.ExpiresDate)
+	}
+	f := nurse.Fields
+	if uris := f["esco_occupation_uris"].([]string); len(uris) != 1 || uris[0] != "http://data.europa.eu/esco/occupation/abc" {
+		t.Errorf("Unexpected ESCO URIs: %v", uris)
+	}
+	if isco := f["isco_codes"].([]string); len(isco) != 1 || isco[0] != "2221" {
+		t.Errorf("Unexpected ISCO codes: %v", isco)
+	}
+	if nuts := f["nuts_regions"].([]string); len(nuts) != 1 || nuts[0] != "SE110" {
+		t.Errorf("Unexpected NUTS regions: %v", nuts)
+	}
+	langs := f["required_languages"].([]map[string]string)
+	if len(langs) != 1 || langs[0]["language"] != "sv" || langs[0]["level"] != "B2" {
+		t.Errorf("Unexpected languages: %v", langs)
+	}
+	if f["description_language"] != "en" || len(f["profile_languages"].([]string)) != 2 || f["number_of_posts"] != 3 {
+		t.Errorf("Unexpected fields: %+v", f)
+	}
+
+	// The detail request fails, so the summary is used and the unsafe ID is hashed
+	welder := jobs[1]
+	if welder.ID != jobid.New("eures", "NDU2 MQ==") || len(welder.ID) != len("eures:")+16 {
+		t.Errorf("Unexpected welder ID: %s", welder.ID)
+	}
+	if welder.Fields["original_id"] != "NDU2 MQ==" || welder.Location != "Norway" || welder.Description != "Welding & more. Lønn: 550 000 NOK per år." {
+		t.Errorf("Unexpected welder job: %+v", welder)
+	}
+	if welder.EmploymentType != "Part-time" {
+		t.Errorf("Expected Part-time, got %q", welder.EmploymentType)
+	}
+	if welder.Salary != "Lønn: 550 000 NOK per år" || *welder.SalaryMax != 550000 || welder.SalaryCurrency != "NOK" ||
+		welder.Fields["salary_period"] != "year" {
+		t.Errorf("Unexpected welder salary: %q %q %v", welder.Salary, welder.SalaryCurrency, welder.Fields)
+	}
+	if nurse.SalaryMin != nil || nurse.Salary != "" {
+		t.Errorf("Expected no nurse salary, got %q", nurse.Salary)
+	}
+}
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *EURESConnector {
+	rec := httpreplay.New(t, "testdata/search.json")
+
+	ec := NewEURESConnector(store)
+	ec.keywords = "developer"
+	ec.countries = []string{"se", "no"}
+	ec.language = "en"
+	ec.maxPages = 1
+	ec.httpClient = rec.Client()
+	return ec
+}
+
+// TestFetchJobsGolden replays recorded search and detail responses, including a detail
+// request that failed. Re-record with HTTPREPLAY=record go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	ec := newReplayConnector(t, nil)
+
+	jobs, err := ec.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs)
+}
+
+func TestFetchJobsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	ec := NewEURESConnector(nil)
+	ec.baseURL = server.URL
+	if _, err := ec.FetchJobs(); err == nil {
+		t.Fatal("Expected error instead of demo jobs")
+	}
+}
+
+func TestPublicationPeriod(t *testing.T) {
+	tests := map[time.Duration]string{
+		2 * time.Hour:       "LAST_DAY",
+		48 * time.Hour:      "LAST_THREE_DAYS",
+		5 * 24 * time.Hour:  "LAST_WEEK",
+		20 * 24 * time.Hour: "LAST_MONTH",
+		90 * 24 * time.Hour: "",
+	}
+	for age, want := range tests {
+		if got := publicationPeriod(time.Now().Add(-age)); got != want {
+			t.Errorf("publicationPeriod(-%v) = %q, want %q", age, got, want)
+		}
+	}
+	if publicationPeriod(time.Time{}) != "" {
+		t.Error("Expected no period without a previous sync")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
@@ -0,0 +1,136 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config lists the RSS/Atom feeds handled by one feed connector
+type Config struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Source    string        `json:"source"` // Job ID prefix and fields.source
+	UserAgent string        `json:"user_agent"`
+	DelayMs   int           `json:"delay_ms"` // Minimum gap between requests to one host
+	Feeds     []*FeedConfig `json:"feeds"`
+}
+
+// FeedConfig describes a single feed. Namespace keeps GUIDs from different
+// feeds apart in job IDs, so it must stay the same once jobs are stored.
+type FeedConfig struct {
+	URL       string `json:"url"`
+	Namespace string `json:"namespace"`
+	Company   string `json:"company"`  // Fixed company (e.g. a recruiter's own feed)
+	Location  string `json:"location"` // Fixed location
+	IsRemote  bool   `json:"is_remote"`
+
+	// Optional regexes; the named group "company"/"location"/"title" or the
+	// first capture group is used. Each is matched against ExtractFrom.
+	CompanyRegex  string `json:"company_regex"`
+	LocationRegex string `json:"location_regex"`
+	TitleRegex    string `json:"title_regex"`
+	ExtractFrom   string `json:"extract_from"` // title (default), description or both
+
+	companyRe  *regexp.Regexp
+	locationRe *regexp.Regexp
+	titleRe    *regexp.Regexp
+}
+
+// LoadConfig reads a feed connector config from a .json, .yaml or .yml file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+		}
+		if data, err = json.Marshal(generic); err != nil {
+			return nil, fmt.Errorf("failed to convert YAML config %s: %w", path, err)
+		}
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks the feeds, compiles regexes and fills in defaults
+func (c *Config) Validate() error {
+	if len(c.Feeds) == 0 {
+		return fmt.Errorf("at least one feed is required")
+	}
+	if c.ID == "" {
+		c.ID = "feeds"
+	}
+	if c.Name == "" {
+		c.Name = "RSS/Atom Job Feeds"
+	}
+	if c.Source == "" {
+		c.Source = "feed"
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = "OpenJobs-Feed-Connector/1.0"
+	}
+
+	namespaces := map[string]bool{}
+	for i, feed := range c.Feeds {
+		u, err := url.Parse(feed.URL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("feed %d: invalid url %q", i, feed.URL)
+		}
+		if feed.Namespace == "" {
+			// Host-based default, e.g. weworkremotely.com → weworkremotely
+			feed.Namespace = strings.Split(strings.TrimPrefix(u.Hostname(), "www."), ".")[0]
+		}
+		if namespaces[feed.Namespace] {
+			return fmt.Errorf("feed %s: namespace %q is used by another feed, set namespace explicitly", feed.URL, feed.Namespace)
+		}
+		namespaces[feed.Namespace] = true
+
+		switch feed.ExtractFrom {
+		case "":
+			feed.ExtractFrom = "title"
+		case "title", "description", "both":
+		default:
+			return fmt.Errorf("feed %s: extract_from must be title, description or both", feed.URL)
+		}
+
+		for _, r := range []struct {
+			expr   string
+			target **regexp.Regexp
+			name   string
+		}{
+			{feed.CompanyRegex, &feed.companyRe, "company_regex"},
+			{feed.LocationRegex, &feed.locationRe, "location_regex"},
+			{feed.TitleRegex, &feed.titleRe, "title_regex"},
+		} {
+			if r.expr == "" {
+				continue
+			}
+			re, err := regexp.Compile(r.expr)
+			if err != nil {
+				return fmt.Errorf("feed %s: invalid %s: %w", feed.URL, r.name, err)
+			}
+			*r.target = re
+		}
+	}
+
+	return nil
+}
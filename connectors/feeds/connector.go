@@ -0,0 +1,305 @@
+package feeds
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+)
+
+// FeedConnector implements a connector for RSS and Atom job feeds
+type FeedConnector struct {
+	store      *storage.JobStore
+	config     *Config
+	httpClient *http.Client
+}
+
+// NewFeedConnector creates a new feed connector from a validated config
+func NewFeedConnector(store *storage.JobStore, config *Config) *FeedConnector {
+	return &FeedConnector{
+		store:      store,
+		config:     config,
+		httpClient: httpclient.New(httpclient.Options{
+			UserAgent: config.UserAgent,
+			Interval:  time.Duration(config.DelayMs) * time.Millisecond,
+		}),
+	}
+}
+
+// GetID returns the connector ID
+func (fc *FeedConnector) GetID() string {
+	return fc.config.ID
+}
+
+// GetName returns the connector name
+func (fc *FeedConnector) GetName() string {
+	return fc.config.Name
+}
+
+// FetchJobs fetches every feed, skipping feeds that are unchanged since the last committed sync
+func (fc *FeedConnector) FetchJobs() ([]models.JobPost, error) {
+	return fc.fetchFeeds(fc.getCheckpoint())
+}
+
+// fetchFeeds fetches every feed, conditionally when the checkpoint has validators for
+// it. The responses' validators are kept on the checkpoint for SyncJobs to commit.
+func (fc *FeedConnector) fetchFeeds(checkpoint *models.Checkpoint) ([]models.JobPost, error) {
+	jobs := []models.JobPost{}
+	failed := 0
+
+	for _, feed := range fc.config.Feeds {
+		items, notModified, err := fc.fetchFeed(feed, checkpoint)
+		if err != nil {
+			fmt.Printf("⚠️  Feed %s failed: %v\n", feed.URL, err)
+			failed++
+			continue
+		}
+		if notModified {
+			fmt.Printf("💤 Feed %s not modified since last fetch\n", feed.Namespace)
+			continue
+		}
+
+		mapped := 0
+		for _, item := range items {
+			if job, ok := fc.transformItem(feed, item); ok {
+				jobs = append(jobs, job)
+				mapped++
+			}
+		}
+		fmt.Printf("📰 Feed %s: %d items, %d jobs\n", feed.Namespace, len(items), mapped)
+	}
+
+	if failed == len(fc.config.Feeds) {
+		return nil, fmt.Errorf("all %d feeds failed", failed)
+	}
+	return jobs, nil
+}
+
+// fetchFeed downloads one feed with If-None-Match/If-Modified-Since
+func (fc *FeedConnector) fetchFeed(feed *FeedConfig, checkpoint *models.Checkpoint) ([]FeedItem, bool, error) {
+	req, err := http.NewRequest("GET", feed.URL, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", fc.config.UserAgent)
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	checkpoint.Conditional(req)
+
+	resp, err := fc.httpClient.Do(req)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, false, fmt.Errorf("feed returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	items, err := ParseFeed(body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Only keep validators once the feed parsed, so a bad response is refetched
+	checkpoint.KeepValidators(req.URL.String(), resp.Header)
+
+	return items, false, nil
+}
+
+// transformItem converts a feed item to our JobPost format
+func (fc *FeedConnector) transformItem(feed *FeedConfig, item FeedItem) (models.JobPost, bool) {
+	// The GUID is stable per feed; fall back to the link for feeds without one
+	guid := item.GUID
+	if guid == "" {
+		guid = item.Link
+	}
+	if guid == "" || item.Title == "" {
+		return models.JobPost{}, false
+	}
+	sum := sha1.Sum([]byte(guid))
+
+	text := item.Title
+	switch feed.ExtractFrom {
+	case "description":
+		text = item.Description
+	case "both":
+		text = item.Title + "\n" + item.Description
+	}
+
+	company := feed.Company
+	if company == "" {
+		company = extract(feed.companyRe, "company", text)
+	}
+	if company == "" {
+		company = item.Author
+	}
+	location := feed.Location
+	if location == "" {
+		location = extract(feed.locationRe, "location", text)
+	}
+	title := item.Title
+	if cleaned := extract(feed.titleRe, "title", item.Title); cleaned != "" {
+		title = cleaned
+	}
+
+	postedDate := item.Published
+	if postedDate.IsZero() {
+		postedDate = time.Now()
+	}
+
+	isRemote := feed.IsRemote || strings.Contains(strings.ToLower(location), "remote") ||
+		strings.Contains(strings.ToLower(location), "distans")
+	if location == "" && feed.IsRemote {
+		location = "Remote"
+	}
+
+	job := models.JobPost{
+		ID:           jobid.New(fc.config.Source, feed.Namespace, hex.EncodeToString(sum[:])[:16]),
+		Title:        title,
+		Company:      company,
+		Description:  item.Description,
+		Location:     location,
+		IsRemote:     isRemote,
+		URL:          item.Link,
+		PostedDate:   postedDate,
+		Requirements: item.Categories,
+		Benefits:     []string{},
+		Fields: map[string]interface{}{
+			"source":      fc.config.Source,
+			"source_url":  item.Link,
+			"original_id": guid,
+			"feed":        feed.Namespace,
+			"feed_url":    feed.URL,
+			"categories":  item.Categories,
+			"connector":   fc.config.ID,
+			"fetched_at":  time.Now(),
+		},
+	}
+	// Feed items carry no salary field; some boards put it in the title or description
+	if pay, text := salary.Find(item.Title+"\n"+item.Description, ""); text != "" {
+		pay.Apply(&job)
+		job.Salary = text
+	}
+
+	return job, true
+}
+
+// extract returns the named group (or first group, or whole match) of re in text
+func extract(re *regexp.Regexp, name, text string) string {
+	if re == nil {
+		return ""
+	}
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	if i := re.SubexpIndex(name); i > 0 {
+		return strings.TrimSpace(match[i])
+	}
+	if len(match) > 1 {
+		return strings.TrimSpace(match[1])
+	}
+	return strings.TrimSpace(match[0])
+}
+
+// SyncJobs fetches all feeds and stores new jobs
+func (fc *FeedConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Printf("🔄 Starting %s sync...\n", fc.GetName())
+
+	checkpoint := fc.getCheckpoint()
+	jobs, err := fc.fetchFeeds(checkpoint)
+	if err != nil {
+		fc.store.LogSync(&models.SyncLog{
+			ConnectorName: fc.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "failed",
+		})
+		return fmt.Errorf("failed to fetch job feeds: %w", err)
+	}
+
+	fmt.Printf("📥 Fetched %d jobs from %d feeds\n", len(jobs), len(fc.config.Feeds))
+
+	stored := 0
+	duplicates := 0
+	failed := 0
+	for _, job := range jobs {
+		existing, err := fc.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := fc.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	// Commit the feeds' validators only once every item is stored, so the feeds are
+	// fetched in full again (not answered 304) while any of their items failed
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - feed validators not committed\n", failed)
+	} else if err := fc.store.SaveCheckpoint(checkpoint); err != nil {
+		fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+	}
+
+	if err := fc.store.LogSync(&models.SyncLog{
+		ConnectorName:  fc.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 %s sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", fc.GetName(), len(jobs), stored, duplicates)
+	return nil
+}
+
+// getCheckpoint returns the checkpoint committed by the last successful sync, or an
+// empty one (unconditional requests)
+func (fc *FeedConnector) getCheckpoint() *models.Checkpoint {
+	empty := &models.Checkpoint{ConnectorID: fc.GetID()}
+	if fc.store == nil {
+		return empty
+	}
+	checkpoint, err := fc.store.GetCheckpoint(fc.GetID(), "")
+	if err != nil {
+		fmt.Printf("⚠️  Failed to read %s checkpoint, fetching all feeds: %v\n", fc.GetName(), err)
+		return empty
+	}
+	return checkpoint
+}
@@ -0,0 +1,186 @@
+package feeds
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+const wwrFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"><channel><title>Remote jobs</title>
+<item>
+  <title>Acme AB: Senior Go Developer</title>
+  <link>https://weworkremotely.com/jobs/1</link>
+  <guid>https://weworkremotely.com/jobs/1</guid>
+  <pubDate>Wed, 01 Oct 2025 10:00:00 +0000</pubDate>
+  <description>&lt;p&gt;&lt;strong&gt;Headquarters:&lt;/strong&gt; Stockholm &lt;/p&gt; Build APIs. Salary: 55 000 - 65 000 SEK per month</description>
+  <category>Programming</category>
+</item>
+<item><title>No GUID or link</title></item>
+</channel></rss>`
+
+// Atom feed in ISO-8859-1, as published by many Swedish municipalities
+var kommunFeed = "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n" +
+	`<feed xmlns="http://www.w3.org/2005/Atom"><title>Lediga jobb</title>
+<entry>
+  <id>urn:kommun:job:42</id>
+  <title>Sjuksk` + "\xf6" + `terska - G` + "\xf6" + `teborg</title>
+  <link rel="alternate" href="https://kommun.example.se/jobb/42"/>
+  <published>2025-10-02T08:00:00Z</published>
+  <summary type="html">&lt;p&gt;Vi s` + "\xf6" + `ker...&lt;/p&gt;</summary>
+  <category term="vard" label="V` + "\xe5" + `rd"/>
+</entry></feed>`
+
+func TestFetchJobsWithConditionalGet(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/remote.rss":
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte(wwrFeed))
+		case "/jobb.atom":
+			w.Write([]byte(kommunFeed))
+		}
+	}))
+	defer server.Close()
+
+	config := &Config{
+		Source: "feed",
+		Feeds: []*FeedConfig{
+			{
+				URL:           server.URL + "/remote.rss",
+				Namespace:     "wwr",
+				IsRemote:      true,
+				CompanyRegex:  `^(?P<company>[^:]+):`,
+				TitleRegex:    `^[^:]+:\s*(.+)$`,
+				LocationRegex: `Headquarters:\s*(\w+)`,
+				ExtractFrom:   "both",
+			},
+			{
+				URL:           server.URL + "/jobb.atom",
+				Namespace:     "kommun",
+				Company:       "Exempel kommun",
+				LocationRegex: `- (?P<location>[^-]+)$`,
+			},
+		},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+
+	db := storagetest.NewServer(t)
+	connector := NewFeedConnector(db.Store(), config)
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs() failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d: %+v", len(jobs), jobs)
+	}
+
+	wwr := jobs[0]
+	if wwr.Company != "Acme AB" || wwr.Title != "Senior Go Developer" || wwr.Location != "Stockholm" || !wwr.IsRemote {
+		t.Errorf("Unexpected RSS mapping: %+v", wwr)
+	}
+	if !strings.HasPrefix(wwr.ID, "feed:wwr-") || wwr.PostedDate.Day() != 1 || wwr.Requirements[0] != "Programming" {
+		t.Errorf("Unexpected RSS ID/date/categories: %s %v %v", wwr.ID, wwr.PostedDate, wwr.Requirements)
+	}
+	if wwr.Salary != "Salary: 55 000 - 65 000 SEK per month" || *wwr.SalaryMin != 55000 || wwr.SalaryCurrency != "SEK" ||
+		wwr.Fields["salary_annual_max"] != 780000 {
+		t.Errorf("Unexpected RSS salary: %q %v %q %v", wwr.Salary, *wwr.SalaryMin, wwr.SalaryCurrency, wwr.Fields)
+	}
+
+	kommun := jobs[1]
+	if kommun.Title != "Sjuksköterska - Göteborg" || kommun.Location != "Göteborg" || kommun.Company != "Exempel kommun" {
+		t.Errorf("Unexpected Atom mapping: %+v", kommun)
+	}
+	if kommun.Description != "Vi söker..." || kommun.Requirements[0] != "Vård" || kommun.URL != "https://kommun.example.se/jobb/42" {
+		t.Errorf("Unexpected Atom description/categories/link: %q %v %q", kommun.Description, kommun.Requirements, kommun.URL)
+	}
+
+	// FetchJobs commits nothing, so the sync still gets the full RSS feed and stores both jobs
+	if err := connector.SyncJobs(); err != nil {
+		t.Fatalf("SyncJobs() failed: %v", err)
+	}
+	if stored := db.Jobs(); len(stored) != 2 {
+		t.Errorf("Expected 2 stored jobs, got %d", len(stored))
+	}
+
+	// After the sync the RSS feed answers 304 and is skipped, the Atom feed is refetched
+	again, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("Third FetchJobs() failed: %v", err)
+	}
+	if len(again) != 1 || again[0].ID != kommun.ID {
+		t.Errorf("Expected only the Atom job with a stable ID, got %+v", again)
+	}
+	if requests != 6 {
+		t.Errorf("Expected 6 requests, got %d", requests)
+	}
+}
+
+func TestValidateRejectsDuplicateNamespaces(t *testing.T) {
+	config := &Config{Feeds: []*FeedConfig{
+		{URL: "https://jobs.example.com/a.rss"},
+		{URL: "https://jobs.example.com/b.rss"},
+	}}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an error for two feeds with the same default namespace")
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	if _, err := LoadConfig("examples/feeds.yaml"); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+}
+
+// newExampleConnector reads the first example feed from its recorded fixture
+func newExampleConnector(t *testing.T, store *storage.JobStore) *FeedConnector {
+	rec := httpreplay.New(t, "testdata/wwr.json")
+
+	config, err := LoadConfig("examples/feeds.yaml")
+	if err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+	config.Feeds = config.Feeds[:1] // The other feeds are placeholders
+
+	connector := NewFeedConnector(store, config)
+	connector.httpClient = rec.Client()
+	return connector
+}
+
+// TestExampleFeedGolden runs the We Work Remotely feed of the example config against a
+// recorded fetch and a 304 refetch. Re-record with HTTPREPLAY=record go test -run Golden
+func TestExampleFeedGolden(t *testing.T) {
+	connector := newExampleConnector(t, nil)
+
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs() failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/wwr.golden.json", jobs)
+
+	again, err := connector.FetchJobs()
+	if err != nil || len(again) != 0 {
+		t.Errorf("Expected the unchanged feed to be skipped, got %d jobs (%v)", len(again), err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newExampleConnector(t, store)
+	}, conformance.Options{})
+}
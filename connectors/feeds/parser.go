@@ -0,0 +1,194 @@
+package feeds
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// FeedItem is an RSS item or Atom entry reduced to the fields we map
+type FeedItem struct {
+	Title       string
+	Link        string
+	GUID        string
+	Published   time.Time
+	Description string
+	Categories  []string
+	Author      string
+}
+
+// feedDocument decodes RSS 2.0, RSS 1.0 (RDF) and Atom with one struct
+type feedDocument struct {
+	XMLName xml.Name
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"`  // RSS 1.0 items live next to the channel
+	Entries []atomEntry `xml:"entry"` // Atom
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        string   `xml:"guid"`
+	PubDate     string   `xml:"pubDate"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Categories  []string `xml:"category"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	ID    string `xml:"id"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Published  string `xml:"published"`
+	Updated    string `xml:"updated"`
+	Summary    string `xml:"summary"`
+	Content    string `xml:"content"`
+	Categories []struct {
+		Term  string `xml:"term,attr"`
+		Label string `xml:"label,attr"`
+	} `xml:"category"`
+	Author struct {
+		Name string `xml:"name"`
+	} `xml:"author"`
+}
+
+// feedDateFormats covers RFC 822 variants seen in the wild plus Atom's RFC 3339
+var feedDateFormats = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// ParseFeed decodes an RSS or Atom document into feed items
+func ParseFeed(data []byte) ([]FeedItem, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReaderLabel // Many Swedish feeds are ISO-8859-1
+	decoder.Strict = false
+
+	var doc feedDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse feed: %w", err)
+	}
+
+	items := []FeedItem{}
+	switch strings.ToLower(doc.XMLName.Local) {
+	case "rss", "rdf":
+		for _, it := range append(doc.Channel.Items, doc.Items...) {
+			description := it.Description
+			if description == "" {
+				description = it.Content
+			}
+			published := it.PubDate
+			if published == "" {
+				published = it.Date
+			}
+			items = append(items, FeedItem{
+				Title:       cleanText(it.Title),
+				Link:        strings.TrimSpace(it.Link),
+				GUID:        strings.TrimSpace(it.GUID),
+				Published:   parseFeedDate(published),
+				Description: cleanText(description),
+				Categories:  cleanList(it.Categories),
+				Author:      cleanText(it.Creator),
+			})
+		}
+
+	case "feed":
+		for _, entry := range doc.Entries {
+			link := ""
+			for _, l := range entry.Links {
+				if l.Rel == "" || l.Rel == "alternate" {
+					link = l.Href
+					break
+				}
+			}
+			if link == "" && len(entry.Links) > 0 {
+				link = entry.Links[0].Href
+			}
+
+			description := entry.Summary
+			if description == "" {
+				description = entry.Content
+			}
+			published := entry.Published
+			if published == "" {
+				published = entry.Updated
+			}
+			categories := []string{}
+			for _, c := range entry.Categories {
+				if c.Label != "" {
+					categories = append(categories, c.Label)
+				} else {
+					categories = append(categories, c.Term)
+				}
+			}
+
+			items = append(items, FeedItem{
+				Title:       cleanText(entry.Title),
+				Link:        strings.TrimSpace(link),
+				GUID:        strings.TrimSpace(entry.ID),
+				Published:   parseFeedDate(published),
+				Description: cleanText(description),
+				Categories:  cleanList(categories),
+				Author:      cleanText(entry.Author.Name),
+			})
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported feed root element <%s>", doc.XMLName.Local)
+	}
+
+	return items, nil
+}
+
+// parseFeedDate parses pubDate/published values, returning zero time if unknown
+func parseFeedDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedDateFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// cleanText strips HTML tags and entities (descriptions are often escaped HTML)
+func cleanText(s string) string {
+	s = html.UnescapeString(s)
+	s = html.UnescapeString(htmlTags.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// cleanList cleans and drops empty entries
+func cleanList(values []string) []string {
+	result := []string{}
+	for _, v := range values {
+		if v = cleanText(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,465 @@
+package hackernews
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+)
+
+// HackerNewsConnector reads the monthly "Ask HN: Who is hiring?" thread through the Algolia HN API
+type HackerNewsConnector struct {
+	store      *storage.JobStore
+	baseURL    string
+	userAgent  string
+	threadID   string // Fixed thread; empty finds the latest one
+	httpClient *http.Client
+}
+
+// hnItem is an Algolia HN item: a story or a comment with its replies
+type hnItem struct {
+	ID        int      `json:"id"`
+	CreatedAt string   `json:"created_at"`
+	Author    string   `json:"author"`
+	Title     string   `json:"title"`
+	Text      string   `json:"text"`
+	Children  []hnItem `json:"children"`
+}
+
+// hnSearchResponse is an Algolia search result page
+type hnSearchResponse struct {
+	Hits []struct {
+		ObjectID  string `json:"objectID"`
+		Title     string `json:"title"`
+		CreatedAt string `json:"created_at"`
+	} `json:"hits"`
+}
+
+// header is the parsed "Company | Role | Location | REMOTE | Salary" first line of a posting
+type header struct {
+	company        string
+	title          string
+	roles          []string
+	location       string
+	remote         bool
+	remoteScope    string
+	salary         string
+	employmentType string
+	companyURL     string
+	extra          []string
+}
+
+var (
+	htmlTags = regexp.MustCompile(`<[^>]*>`)
+	salaryRe = regexp.MustCompile(`(?i)[$€£]\s?\d|\b\d+(?:\.\d+)?\s?k\b|\b(?:usd|eur|gbp|cad|chf|sek)\b`)
+	urlRe    = regexp.MustCompile(`(?i)^(?:https?://|www\.)\S+$|^[a-z0-9-]+(?:\.[a-z0-9-]+)*\.(?:com|io|ai|co|dev|org|net|app|tech|so|xyz)(?:/\S*)?$`)
+	roleRe   = regexp.MustCompile(`(?i)\b(?:engineers?|developers?|designers?|managers?|scientists?|architects?|analysts?|researchers?|leads?|sre|devops|founding|head of|director|cto|vp|product|marketing|sales|recruiter|technician|administrator|consultant|specialist|intern)\b`)
+	typeRe   = regexp.MustCompile(`(?i)\b(full[- ]?time|part[- ]?time|contract(?:or)?|freelance|intern(?:ship)?)\b`)
+	onsiteRe = regexp.MustCompile(`(?i)\b(onsite|on-site|hybrid|in[- ]office)\b`)
+	remoteRe = regexp.MustCompile(`(?i)\bremote\b(?:\s*\(([^)]*)\))?`)
+)
+
+// NewHackerNewsConnector creates a new Hacker News "Who is hiring" connector.
+// HN_THREAD_ID pins a specific thread instead of the latest one.
+func NewHackerNewsConnector(store *storage.JobStore) *HackerNewsConnector {
+	return &HackerNewsConnector{
+		store:      store,
+		baseURL:    "https://hn.algolia.com/api/v1",
+		userAgent:  httpclient.UserAgent("OpenJobs-HackerNews-Connector/1.0"),
+		threadID:   os.Getenv("HN_THREAD_ID"),
+		httpClient: httpclient.New(httpclient.Options{Timeout: 60 * time.Second}),
+	}
+}
+
+// GetID returns the connector ID
+func (hc *HackerNewsConnector) GetID() string {
+	return "hackernews"
+}
+
+// GetName returns the connector name
+func (hc *HackerNewsConnector) GetName() string {
+	return "Hacker News Who is Hiring Connector"
+}
+
+// FetchJobs parses every top-level comment of the latest "Who is hiring?" thread
+func (hc *HackerNewsConnector) FetchJobs() ([]models.JobPost, error) {
+	thread, err := hc.fetchThread()
+	if err != nil {
+		return nil, err
+	}
+	return hc.threadJobs(thread), nil
+}
+
+// fetchThread finds the thread (unless pinned) and loads it with all comments
+func (hc *HackerNewsConnector) fetchThread() (*hnItem, error) {
+	threadID := hc.threadID
+	if threadID == "" {
+		id, err := hc.latestThreadID()
+		if err != nil {
+			return nil, err
+		}
+		threadID = id
+	}
+
+	var thread hnItem
+	if err := hc.get(fmt.Sprintf("%s/items/%s", hc.baseURL, threadID), &thread); err != nil {
+		return nil, fmt.Errorf("failed to fetch thread %s: %w", threadID, err)
+	}
+	fmt.Printf("🧵 %s: %d top-level comments\n", thread.Title, len(thread.Children))
+	return &thread, nil
+}
+
+// latestThreadID returns the newest "Who is hiring?" story posted by the whoishiring account
+func (hc *HackerNewsConnector) latestThreadID() (string, error) {
+	var response hnSearchResponse
+	url := fmt.Sprintf("%s/search_by_date?tags=story,author_whoishiring&hitsPerPage=10", hc.baseURL)
+	if err := hc.get(url, &response); err != nil {
+		return "", fmt.Errorf("failed to search threads: %w", err)
+	}
+
+	// The account also posts "Who wants to be hired?" and "Freelancer?" threads
+	for _, hit := range response.Hits {
+		if strings.Contains(strings.ToLower(hit.Title), "who is hiring") {
+			return hit.ObjectID, nil
+		}
+	}
+	return "", fmt.Errorf("no \"Who is hiring?\" thread found")
+}
+
+func (hc *HackerNewsConnector) get(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", hc.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := hc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("algolia HN API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// threadJobs converts the thread's live top-level comments; deleted comments and
+// comments without a "Company | ..." header are skipped
+func (hc *HackerNewsConnector) threadJobs(thread *hnItem) []models.JobPost {
+	jobs := []models.JobPost{}
+	skipped := 0
+	for _, comment := range thread.Children {
+		if isDeleted(comment) {
+			continue
+		}
+		job, ok := hc.transformComment(thread, comment)
+		if !ok {
+			skipped++
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	if skipped > 0 {
+		fmt.Printf("⏭️  Skipped %d comments without a job header\n", skipped)
+	}
+	return jobs
+}
+
+// transformComment converts a top-level comment to our JobPost format
+func (hc *HackerNewsConnector) transformComment(thread *hnItem, comment hnItem) (models.JobPost, bool) {
+	first, rest := splitFirstParagraph(comment.Text)
+	h, ok := parseHeader(first)
+	if !ok {
+		return models.JobPost{}, false
+	}
+
+	sourceURL := fmt.Sprintf("https://news.ycombinator.com/item?id=%d", comment.ID)
+	posted, err := time.Parse(time.RFC3339, comment.CreatedAt)
+	if err != nil {
+		posted = time.Now()
+	}
+
+	description := plainText(rest)
+	if description == "" {
+		description = plainText(first)
+	}
+
+	job := models.JobPost{
+		ID:             jobid.New("hackernews", strconv.Itoa(comment.ID)),
+		Title:          h.title,
+		Company:        h.company,
+		Description:    description,
+		Location:       h.location,
+		IsRemote:       h.remote,
+		URL:            sourceURL,
+		Salary:         h.salary,
+		EmploymentType: h.employmentType,
+		PostedDate:     posted,
+		Requirements:   []string{},
+		Benefits:       []string{},
+		Fields: map[string]interface{}{
+			"source":       "hackernews",
+			"source_url":   sourceURL,
+			"original_id":  strconv.Itoa(comment.ID),
+			"thread_id":    strconv.Itoa(thread.ID),
+			"thread_title": thread.Title,
+			"author":       comment.Author,
+			"header":       plainText(first),
+			"roles":        h.roles,
+			"text_hash":    textHash(comment.Text),
+			"connector":    "hackernews",
+			"fetched_at":   time.Now(),
+		},
+	}
+	if h.remoteScope != "" {
+		job.Fields["remote_scope"] = h.remoteScope
+	}
+	if h.companyURL != "" {
+		job.Fields["company_url"] = h.companyURL
+	}
+	if len(h.extra) > 0 {
+		job.Fields["header_extra"] = h.extra
+	}
+	// Header salaries are yearly unless stated; the currency is only set when the text
+	// names one, so bare numbers aren't mistaken for salaries
+	if pay := salary.Parse(h.salary, "", salary.Year); pay.Currency != "" {
+		pay.Apply(&job)
+	}
+
+	return job, true
+}
+
+// SyncJobs stores new postings, updates edited ones and closes postings whose comment
+// was deleted or removed from the thread since the last run
+func (hc *HackerNewsConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Println("🔄 Starting Hacker News Who is hiring sync...")
+
+	thread, err := hc.fetchThread()
+	if err != nil {
+		hc.store.LogSync(&models.SyncLog{
+			ConnectorName: hc.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+		})
+		return fmt.Errorf("failed to fetch Who is hiring thread: %w", err)
+	}
+	jobs := hc.threadJobs(thread)
+	fmt.Printf("📥 Parsed %d postings from %s\n", len(jobs), thread.Title)
+
+	stored, err := hc.store.GetJobsByField("thread_id", strconv.Itoa(thread.ID))
+	if err != nil {
+		hc.store.LogSync(&models.SyncLog{
+			ConnectorName: hc.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			JobsFetched:   len(jobs),
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+		})
+		return fmt.Errorf("failed to load stored Who is hiring jobs: %w", err)
+	}
+	existing := map[string]*models.JobPost{}
+	for _, job := range stored {
+		existing[job.ID] = job
+	}
+
+	inserted, updated, duplicates := 0, 0, 0
+	active := map[string]bool{}
+	for _, job := range jobs {
+		active[job.ID] = true
+		old, ok := existing[job.ID]
+		if !ok {
+			if err := hc.store.CreateJob(&job); err != nil {
+				fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+				continue
+			}
+			inserted++
+			fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+			continue
+		}
+
+		if old.Fields["text_hash"] == job.Fields["text_hash"] {
+			duplicates++
+			continue
+		}
+		job.Fields["edited"] = true
+		job.Fields["edited_at"] = time.Now()
+		if err := hc.store.UpdateJob(&job); err != nil {
+			fmt.Printf("❌ Error updating edited job %s: %v\n", job.ID, err)
+			continue
+		}
+		updated++
+		fmt.Printf("✏️  Updated edited job: %s at %s\n", job.Title, job.Company)
+	}
+
+	removed := 0
+	for _, id := range removedJobs(stored, active) {
+		closed, err := hc.store.CloseJob(id, time.Now(), "removed")
+		if err != nil {
+			fmt.Printf("⚠️  Error closing removed job %s: %v\n", id, err)
+			continue
+		}
+		if closed {
+			removed++
+			fmt.Printf("🗑️  Closed removed job %s\n", id)
+		}
+	}
+
+	if err := hc.store.LogSync(&models.SyncLog{
+		ConnectorName:  hc.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   inserted,
+		JobsUpdated:    updated,
+		JobsRemoved:    removed,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 Hacker News sync complete! Fetched: %d, Inserted: %d, Updated: %d, Removed: %d, Duplicates: %d\n",
+		len(jobs), inserted, updated, removed, duplicates)
+	return nil
+}
+
+// removedJobs returns the stored, still open jobs whose comment is no longer a live posting
+func removedJobs(stored []*models.JobPost, active map[string]bool) []string {
+	ids := []string{}
+	for _, job := range stored {
+		if active[job.ID] {
+			continue
+		}
+		if closed, _ := job.Fields["closed"].(bool); closed {
+			continue
+		}
+		ids = append(ids, job.ID)
+	}
+	return ids
+}
+
+// parseHeader splits the conventional "Company | Role | Location | REMOTE | Salary" line.
+// Parts after the company are classified by content since their order varies.
+func parseHeader(line string) (header, bool) {
+	parts := []string{}
+	for _, p := range strings.Split(plainText(line), "|") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) < 2 {
+		return header{}, false
+	}
+
+	h := header{company: parts[0], roles: []string{}}
+	for _, part := range parts[1:] {
+		switch {
+		case urlRe.MatchString(part):
+			if h.companyURL == "" {
+				h.companyURL = part
+			}
+		case remoteRe.MatchString(part):
+			h.remote = true
+			if m := remoteRe.FindStringSubmatch(part); m[1] != "" && h.remoteScope == "" {
+				h.remoteScope = strings.TrimSpace(m[1])
+			}
+			if h.location == "" {
+				h.location = part
+			} else {
+				h.location += ", " + part
+			}
+		case roleRe.MatchString(part):
+			h.roles = append(h.roles, part)
+		case salaryRe.MatchString(part) && h.salary == "":
+			h.salary = part
+		case typeRe.MatchString(part) && h.employmentType == "":
+			h.employmentType = mapEmploymentType(typeRe.FindString(part))
+		case onsiteRe.MatchString(part) || h.location == "":
+			if h.location == "" {
+				h.location = part
+			} else {
+				h.location += ", " + part
+			}
+		default:
+			h.extra = append(h.extra, part)
+		}
+	}
+
+	switch {
+	case len(h.roles) > 0:
+		h.title = h.roles[0]
+	case len(h.extra) > 0:
+		h.title, h.extra = h.extra[0], h.extra[1:]
+	default:
+		h.title = "Multiple roles"
+	}
+	return h, true
+}
+
+// mapEmploymentType normalises the employment type named in a header
+func mapEmploymentType(s string) string {
+	switch s = strings.ToLower(s); {
+	case strings.HasPrefix(s, "full"):
+		return "Full-time"
+	case strings.HasPrefix(s, "part"):
+		return "Part-time"
+	case strings.HasPrefix(s, "intern"):
+		return "Internship"
+	default:
+		return "Contract"
+	}
+}
+
+// splitFirstParagraph separates the header line from the rest of an HN comment
+func splitFirstParagraph(text string) (string, string) {
+	if i := strings.Index(text, "<p>"); i >= 0 {
+		return text[:i], text[i:]
+	}
+	if i := strings.Index(text, "\n"); i >= 0 {
+		return text[:i], text[i:]
+	}
+	return text, ""
+}
+
+// isDeleted reports whether a comment was deleted (Algolia keeps it without author and text)
+func isDeleted(comment hnItem) bool {
+	return comment.Author == "" && strings.TrimSpace(comment.Text) == ""
+}
+
+func textHash(text string) string {
+	sum := sha1.Sum([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// plainText strips HN's comment HTML, keeping link targets readable
+func plainText(s string) string {
+	s = strings.ReplaceAll(s, "<p>", "\n")
+	s = htmlTags.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
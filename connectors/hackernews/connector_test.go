@@ -0,0 +1,137 @@
+package hackernews
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+const thread = `{"id": 100, "title": "Ask HN: Who is hiring? (October 2025)", "created_at": "2025-10-01T15:00:00.000Z",
+"children": [
+  {"id": 101, "author": "acme", "created_at": "2025-10-01T15:05:00.000Z",
+   "text": "Acme Corp | Senior Backend Engineer | San Francisco or REMOTE (US) | $150k-$200k | Full-time | <a href=\"https:&#x2F;&#x2F;acme.example\">https:&#x2F;&#x2F;acme.example</a><p>We build <i>rockets</i> &amp; more.<p>Apply: jobs@acme.example",
+   "children": [{"id": 111, "author": "bob", "text": "Is this still open?"}]},
+  {"id": 102, "author": null, "text": null, "created_at": "2025-10-01T15:06:00.000Z"},
+  {"id": 103, "author": "carol", "created_at": "2025-10-01T15:07:00.000Z", "text": "Great thread, thanks!"},
+  {"id": 104, "author": "beta", "created_at": "2025-10-01T16:00:00.000Z",
+   "text": "Beta GmbH | Berlin | ONSITE | Contract<p>Looking for people."}
+]}`
+
+func TestFetchJobs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search_by_date":
+			if r.URL.Query().Get("tags") != "story,author_whoishiring" {
+				t.Errorf("Unexpected tags: %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"hits": [
+				{"objectID": "99", "title": "Ask HN: Who wants to be hired? (October 2025)"},
+				{"objectID": "100", "title": "Ask HN: Who is hiring? (October 2025)"}
+			]}`)
+		case "/items/100":
+			fmt.Fprint(w, thread)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	hc := NewHackerNewsConnector(nil)
+	hc.baseURL = server.URL
+
+	jobs, err := hc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs (deleted and non-posting comments skipped), got %d", len(jobs))
+	}
+
+	acme := jobs[0]
+	if acme.ID != "hackernews:101" || acme.Company != "Acme Corp" || acme.Title != "Senior Backend Engineer" {
+		t.Errorf("Unexpected job: %s %q %q", acme.ID, acme.Company, acme.Title)
+	}
+	if acme.Location != "San Francisco or REMOTE (US)" || !acme.IsRemote || acme.Fields["remote_scope"] != "US" {
+		t.Errorf("Unexpected location: %q remote=%v scope=%v", acme.Location, acme.IsRemote, acme.Fields["remote_scope"])
+	}
+	if acme.SalaryMin == nil || *acme.SalaryMin != 150000 || *acme.SalaryMax != 200000 || acme.SalaryCurrency != "USD" {
+		t.Errorf("Unexpected salary: %q %v %v", acme.Salary, acme.SalaryMin, acme.SalaryMax)
+	}
+	if acme.EmploymentType != "Full-time" || acme.Fields["company_url"] != "https://acme.example" {
+		t.Errorf("Unexpected type/url: %q %v", acme.EmploymentType, acme.Fields["company_url"])
+	}
+	if acme.Description != "We build rockets & more. Apply: jobs@acme.example" {
+		t.Errorf("Unexpected description: %q", acme.Description)
+	}
+	if acme.Fields["thread_id"] != "100" || acme.URL != "https://news.ycombinator.com/item?id=101" {
+		t.Errorf("Unexpected thread/url: %v %s", acme.Fields["thread_id"], acme.URL)
+	}
+	if acme.PostedDate.Format("2006-01-02T15:04") != "2025-10-01T15:05" {
+		t.Errorf("Unexpected posted date: %v", acme.PostedDate)
+	}
+
+	beta := jobs[1]
+	if beta.Title != "Multiple roles" || beta.Location != "Berlin, ONSITE" || beta.EmploymentType != "Contract" || beta.IsRemote {
+		t.Errorf("Unexpected job: %q %q %q remote=%v", beta.Title, beta.Location, beta.EmploymentType, beta.IsRemote)
+	}
+}
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *HackerNewsConnector {
+	rec := httpreplay.New(t, "testdata/thread.json")
+
+	hc := NewHackerNewsConnector(store)
+	hc.threadID = ""
+	hc.httpClient = rec.Client()
+	return hc
+}
+
+// TestFetchJobsGolden replays a recorded thread search and thread. Re-record with
+// HTTPREPLAY=record go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	hc := newReplayConnector(t, nil)
+
+	jobs, err := hc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/thread.golden.json", jobs)
+}
+
+func TestParseHeader(t *testing.T) {
+	h, ok := parseHeader("Foo (YC W21) | Founding Engineer, Designer | Remote (EU) | €80,000 - €100,000 + equity")
+	if !ok || h.company != "Foo (YC W21)" || h.title != "Founding Engineer, Designer" || h.remoteScope != "EU" {
+		t.Errorf("Unexpected header: %+v", h)
+	}
+	if h.salary != "€80,000 - €100,000 + equity" {
+		t.Errorf("Unexpected salary: %q", h.salary)
+	}
+
+	if _, ok := parseHeader("Just a comment without separators"); ok {
+		t.Error("Expected comment without header to be rejected")
+	}
+}
+
+func TestRemovedJobs(t *testing.T) {
+	stored := []*models.JobPost{
+		{ID: "hackernews:1", Fields: map[string]interface{}{}},
+		{ID: "hackernews:2", Fields: map[string]interface{}{}},
+		{ID: "hackernews:3", Fields: map[string]interface{}{"closed": true}},
+	}
+	removed := removedJobs(stored, map[string]bool{"hackernews:1": true})
+	if len(removed) != 1 || removed[0] != "hackernews:2" {
+		t.Errorf("Expected only hackernews:2 to be closed, got %v", removed)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
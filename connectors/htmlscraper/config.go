@@ -0,0 +1,296 @@
+package htmlscraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"openjobs/pkg/storage"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes an HTML job board and the CSS selectors used to scrape it
+type Config struct {
+	ID             string                  `json:"id"`
+	Name           string                  `json:"name"`
+	Source         string                  `json:"source"` // Job ID prefix and fields.source
+	StartURLs      []string                `json:"start_urls"`
+	AllowedDomains []string                `json:"allowed_domains"`
+	UserAgent      string                  `json:"user_agent"`
+	Pagination     PaginationConfig        `json:"pagination"`
+	DelayMillis    int                     `json:"delay_ms"`
+	RandomDelayMs  int                     `json:"random_delay_ms"`
+	TimeoutSeconds int                     `json:"timeout_seconds"`
+	DateFormats    []string                `json:"date_formats"`
+	Defaults       map[string]string       `json:"defaults"` // Values used when a selector finds nothing
+	ActiveVersion  string                  `json:"active_version"`
+	SelectorSets   map[string]*SelectorSet `json:"selector_sets"`
+}
+
+// PaginationConfig controls how list pages are followed.
+// Start URLs containing {{page}} or {{offset}} are paged by number;
+// otherwise next_selector is followed when set.
+type PaginationConfig struct {
+	MaxPages     int    `json:"max_pages"`
+	PageSize     int    `json:"page_size"`  // Step for {{offset}}
+	StartPage    int    `json:"start_page"` // First {{page}} value (default 1)
+	NextSelector string `json:"next_selector"`
+}
+
+// SelectorSet is one version of a site's selectors. Keeping several versions in
+// the config lets a site change be handled by switching active_version.
+type SelectorSet struct {
+	Item   string                    `json:"item"`   // Repeated element on list pages
+	List   map[string]*FieldSelector `json:"list"`   // Fields read from each list item
+	Detail map[string]*FieldSelector `json:"detail"` // Fields read from the job's url page
+}
+
+// FieldSelector extracts a value from an element. In config files it can be a
+// plain string: "h2.title" (text), "a@href" (attribute) or "@data-jk" (attribute
+// of the item itself).
+type FieldSelector struct {
+	Selector string `json:"selector"`
+	Attr     string `json:"attr"`
+	Regex    string `json:"regex"` // First capture group (or whole match) is kept
+	All      bool   `json:"all"`   // Collect every match instead of the first
+
+	re *regexp.Regexp
+}
+
+// UnmarshalJSON accepts both the object form and the "selector@attr" shorthand
+func (fs *FieldSelector) UnmarshalJSON(data []byte) error {
+	var shorthand string
+	if err := json.Unmarshal(data, &shorthand); err == nil {
+		selector, attr, _ := strings.Cut(shorthand, "@")
+		fs.Selector = strings.TrimSpace(selector)
+		fs.Attr = strings.TrimSpace(attr)
+		return nil
+	}
+
+	type plain FieldSelector
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*fs = FieldSelector(p)
+	return nil
+}
+
+// ConfigLoader returns the current scraper config. It is called before every
+// sync so edited files or plugins.config rows take effect without a restart.
+type ConfigLoader func() (*Config, error)
+
+// FileConfig loads the config from a .json, .yaml or .yml file
+func FileConfig(path string) ConfigLoader {
+	return func() (*Config, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			var generic interface{}
+			if err := yaml.Unmarshal(data, &generic); err != nil {
+				return nil, fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+			}
+			if data, err = json.Marshal(generic); err != nil {
+				return nil, fmt.Errorf("failed to convert YAML config %s: %w", path, err)
+			}
+		}
+
+		return parseConfig(data)
+	}
+}
+
+// PluginTableConfig loads the config from the config JSONB of a plugins row
+func PluginTableConfig(store *storage.JobStore, pluginID string) ConfigLoader {
+	return func() (*Config, error) {
+		raw, err := store.GetPluginConfig(pluginID)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode plugin config: %w", err)
+		}
+
+		config, err := parseConfig(data)
+		if err != nil {
+			return nil, err
+		}
+		if config.ID == "" {
+			config.ID = pluginID
+		}
+		return config, config.Validate()
+	}
+}
+
+// ConfigFiles lists the JSON/YAML scraper configs in a directory
+func ConfigFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				paths = append(paths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// parseConfig decodes and validates a JSON config
+func parseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse scraper config: %w", err)
+	}
+	if config.ID == "" {
+		// Plugin table configs may leave the ID to the row
+		return &config, nil
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scraper config %s: %w", config.ID, err)
+	}
+	return &config, nil
+}
+
+// Validate checks required settings, compiles regexes and fills in defaults
+func (c *Config) Validate() error {
+	if c.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+	if len(c.StartURLs) == 0 {
+		return fmt.Errorf("start_urls is required")
+	}
+	if c.Name == "" {
+		c.Name = c.ID
+	}
+	if c.Source == "" {
+		c.Source = c.ID
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = "OpenJobs-HTML-Scraper/1.0"
+	}
+	if c.Pagination.MaxPages <= 0 {
+		c.Pagination.MaxPages = 1
+	}
+	if c.Pagination.StartPage == 0 {
+		c.Pagination.StartPage = 1
+	}
+	if c.Pagination.PageSize <= 0 {
+		c.Pagination.PageSize = 10
+	}
+
+	// Restrict scraping to the start URL hosts unless told otherwise
+	if len(c.AllowedDomains) == 0 {
+		for _, start := range c.StartURLs {
+			u, err := url.Parse(strings.NewReplacer("{{page}}", "1", "{{offset}}", "0").Replace(start))
+			if err != nil || u.Hostname() == "" {
+				return fmt.Errorf("invalid start URL %q", start)
+			}
+			c.AllowedDomains = append(c.AllowedDomains, u.Hostname())
+		}
+	}
+
+	if len(c.SelectorSets) == 0 {
+		return fmt.Errorf("selector_sets is required")
+	}
+	if c.ActiveVersion == "" {
+		// Default to the newest version (dates, or v1, v2 ... v10 in numeric order)
+		versions := c.Versions()
+		c.ActiveVersion = versions[len(versions)-1]
+	}
+	if _, ok := c.SelectorSets[c.ActiveVersion]; !ok {
+		return fmt.Errorf("active_version %q has no selector set", c.ActiveVersion)
+	}
+
+	for version, set := range c.SelectorSets {
+		if set == nil || set.Item == "" {
+			return fmt.Errorf("selector set %s: item is required", version)
+		}
+		if set.List["title"] == nil && set.Detail["title"] == nil {
+			return fmt.Errorf("selector set %s: a title selector is required", version)
+		}
+		if set.Detail != nil && set.List["url"] == nil {
+			return fmt.Errorf("selector set %s: detail selectors need a url list selector", version)
+		}
+		for _, fields := range []map[string]*FieldSelector{set.List, set.Detail} {
+			for name, fs := range fields {
+				if fs == nil {
+					return fmt.Errorf("selector set %s: field %s is empty", version, name)
+				}
+				if fs.Regex != "" {
+					re, err := regexp.Compile(fs.Regex)
+					if err != nil {
+						return fmt.Errorf("selector set %s: field %s: invalid regex: %w", version, name, err)
+					}
+					fs.re = re
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Versions returns the selector set versions oldest first. Numbers within versions
+// compare as numbers, so v10 comes after v9 and 2025-10-01 after 2025-9-30.
+func (c *Config) Versions() []string {
+	versions := make([]string, 0, len(c.SelectorSets))
+	for version := range c.SelectorSets {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return naturalLess(versions[i], versions[j]) })
+	return versions
+}
+
+// naturalLess compares strings run by run: digit runs by value, other runs as strings
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		ra, restA := leadingRun(a)
+		rb, restB := leadingRun(b)
+		if ra != rb {
+			if isDigit(ra[0]) && isDigit(rb[0]) {
+				na, nb := strings.TrimLeft(ra, "0"), strings.TrimLeft(rb, "0")
+				if len(na) != len(nb) {
+					return len(na) < len(nb)
+				}
+				if na != nb {
+					return na < nb
+				}
+			} else {
+				return ra < rb
+			}
+		}
+		a, b = restA, restB
+	}
+	return len(a) < len(b)
+}
+
+// leadingRun splits off the leading run of digits or of other characters
+func leadingRun(s string) (string, string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
@@ -0,0 +1,506 @@
+package htmlscraper
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// HTMLScraperConnector scrapes any HTML job board described by a Config
+type HTMLScraperConnector struct {
+	store     *storage.JobStore
+	loader    ConfigLoader
+	transport http.RoundTripper // Replaces the polite transport when set (tests)
+
+	mu     sync.Mutex
+	config *Config
+}
+
+// whitespace collapses runs of whitespace in scraped text
+var whitespace = regexp.MustCompile(`\s+`)
+
+// jobFields are the scraped keys mapped onto JobPost; all other keys go to Fields
+var jobFields = map[string]bool{
+	"id": true, "title": true, "company": true, "description": true, "location": true,
+	"salary": true, "url": true, "employment_type": true, "experience_level": true,
+	"posted_date": true, "expires_date": true, "requirements": true, "benefits": true,
+	"salary_currency": true, "is_remote": true,
+}
+
+// NewHTMLScraperConnector creates a new scraper, loading its config once up front
+func NewHTMLScraperConnector(store *storage.JobStore, loader ConfigLoader) (*HTMLScraperConnector, error) {
+	config, err := loader()
+	if err != nil {
+		return nil, err
+	}
+
+	return &HTMLScraperConnector{
+		store:  store,
+		loader: loader,
+		config: config,
+	}, nil
+}
+
+// GetID returns the connector ID
+func (hs *HTMLScraperConnector) GetID() string {
+	return hs.currentConfig().ID
+}
+
+// GetName returns the connector name
+func (hs *HTMLScraperConnector) GetName() string {
+	return hs.currentConfig().Name
+}
+
+// currentConfig returns the last successfully loaded config
+func (hs *HTMLScraperConnector) currentConfig() *Config {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.config
+}
+
+// reloadConfig picks up selector changes; a broken config keeps the previous one
+func (hs *HTMLScraperConnector) reloadConfig() *Config {
+	config, err := hs.loader()
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	if err != nil {
+		fmt.Printf("⚠️  %s: keeping previous config, reload failed: %v\n", hs.config.ID, err)
+		return hs.config
+	}
+	if config.ActiveVersion != hs.config.ActiveVersion {
+		fmt.Printf("🔁 %s: switched selector set %s → %s\n", config.ID, hs.config.ActiveVersion, config.ActiveVersion)
+	}
+	hs.config = config
+	return config
+}
+
+// FetchJobs scrapes all list pages (and detail pages) with the active selector set
+func (hs *HTMLScraperConnector) FetchJobs() ([]models.JobPost, error) {
+	cfg := hs.reloadConfig()
+	set := cfg.SelectorSets[cfg.ActiveVersion]
+	fmt.Printf("🕷️  Scraping %s with selector set %s\n", cfg.Name, cfg.ActiveVersion)
+
+	lastSync := hs.getLastSyncTime(cfg)
+	collector := hs.newCollector(cfg)
+
+	items := []map[string]interface{}{}
+	var visitErr error
+	for _, start := range cfg.StartURLs {
+		pageItems, err := hs.scrapeList(collector, cfg, set, start)
+		if err != nil {
+			fmt.Printf("⚠️  %s: failed to scrape %s: %v\n", cfg.ID, start, err)
+			visitErr = err
+			continue
+		}
+		items = append(items, pageItems...)
+	}
+
+	if len(items) == 0 {
+		if visitErr != nil {
+			return nil, fmt.Errorf("failed to scrape %s: %w", cfg.Name, visitErr)
+		}
+		fmt.Printf("⚠️  %s: selector set %s matched no jobs - the site markup may have changed\n", cfg.ID, cfg.ActiveVersion)
+		return []models.JobPost{}, nil
+	}
+
+	jobs := []models.JobPost{}
+	seen := map[string]bool{}
+	for _, item := range items {
+		if len(set.Detail) > 0 {
+			if link, _ := item["url"].(string); link != "" {
+				hs.scrapeDetail(collector, set, link, item)
+			}
+		}
+
+		job, ok := hs.mapItem(cfg, item)
+		if !ok || seen[job.ID] {
+			continue
+		}
+		seen[job.ID] = true
+
+		if !lastSync.IsZero() && !job.PostedDate.After(lastSync) {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+
+	fmt.Printf("📊 Scraped %d jobs from %s (%d items found)\n", len(jobs), cfg.Name, len(items))
+	return jobs, nil
+}
+
+// newCollector creates a colly collector with the configured domains and delays.
+// Requests go through the polite transport: delay_ms is the per-host interval and
+// robots.txt is honoured.
+func (hs *HTMLScraperConnector) newCollector(cfg *Config) *colly.Collector {
+	c := colly.NewCollector(
+		colly.UserAgent(cfg.UserAgent),
+		colly.AllowedDomains(cfg.AllowedDomains...),
+		colly.AllowURLRevisit(),
+	)
+	transport := hs.transport
+	if transport == nil {
+		transport = httpclient.NewTransport(httpclient.Options{
+			UserAgent:     cfg.UserAgent,
+			Interval:      time.Duration(cfg.DelayMillis) * time.Millisecond,
+			RespectRobots: true,
+		})
+	}
+	c.WithTransport(transport)
+
+	timeout := 30 * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	c.SetRequestTimeout(timeout)
+
+	c.Limit(&colly.LimitRule{
+		DomainGlob:  "*",
+		RandomDelay: time.Duration(cfg.RandomDelayMs) * time.Millisecond,
+		Parallelism: 1,
+	})
+
+	return c
+}
+
+// scrapeList walks the list pages of one start URL
+func (hs *HTMLScraperConnector) scrapeList(collector *colly.Collector, cfg *Config, set *SelectorSet, start string) ([]map[string]interface{}, error) {
+	items := []map[string]interface{}{}
+	numbered := strings.Contains(start, "{{page}}") || strings.Contains(start, "{{offset}}")
+	pageURL := start
+
+	for page := 0; page < cfg.Pagination.MaxPages; page++ {
+		if numbered {
+			pageURL = strings.NewReplacer(
+				"{{page}}", strconv.Itoa(cfg.Pagination.StartPage+page),
+				"{{offset}}", strconv.Itoa(page*cfg.Pagination.PageSize),
+			).Replace(start)
+		}
+
+		pageItems := []map[string]interface{}{}
+		nextURL := ""
+
+		c := collector.Clone()
+		c.OnHTML(set.Item, func(e *colly.HTMLElement) {
+			item := extractFields(e, set.List)
+			// Resolve relative links so detail visits and stored URLs are absolute
+			if link, ok := item["url"].(string); ok {
+				item["url"] = e.Request.AbsoluteURL(link)
+			}
+			pageItems = append(pageItems, item)
+		})
+		if !numbered && cfg.Pagination.NextSelector != "" {
+			next := &FieldSelector{}
+			next.UnmarshalJSON([]byte(strconv.Quote(cfg.Pagination.NextSelector)))
+			if next.Attr == "" {
+				next.Attr = "href"
+			}
+			c.OnHTML("html", func(e *colly.HTMLElement) {
+				if href := extractValue(e, next); href != "" {
+					nextURL = e.Request.AbsoluteURL(href)
+				}
+			})
+		}
+		var scrapeErr error
+		c.OnError(func(r *colly.Response, err error) {
+			scrapeErr = fmt.Errorf("%s: %w", r.Request.URL, err)
+		})
+
+		if err := c.Visit(pageURL); err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			break
+		}
+		if scrapeErr != nil {
+			if page == 0 {
+				return nil, scrapeErr
+			}
+			break
+		}
+
+		fmt.Printf("📄 %s page %d: %d items\n", cfg.ID, page+1, len(pageItems))
+		items = append(items, pageItems...)
+
+		if len(pageItems) == 0 {
+			break
+		}
+		if !numbered {
+			if nextURL == "" || nextURL == pageURL {
+				break
+			}
+			pageURL = nextURL
+		}
+	}
+
+	return items, nil
+}
+
+// scrapeDetail visits a job page and merges its fields into the item
+func (hs *HTMLScraperConnector) scrapeDetail(collector *colly.Collector, set *SelectorSet, link string, item map[string]interface{}) {
+	c := collector.Clone()
+	c.OnHTML("html", func(e *colly.HTMLElement) {
+		for name, value := range extractFields(e, set.Detail) {
+			item[name] = value
+		}
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		fmt.Printf("   ⚠️  Failed to fetch job page %s: %v\n", link, err)
+	})
+
+	if err := c.Visit(link); err != nil {
+		fmt.Printf("   ⚠️  Error visiting job page %s: %v\n", link, err)
+	}
+}
+
+// extractFields reads every configured field from an element, skipping empty values
+func extractFields(e *colly.HTMLElement, fields map[string]*FieldSelector) map[string]interface{} {
+	values := map[string]interface{}{}
+	for name, fs := range fields {
+		if fs.All {
+			if all := extractAll(e, fs); len(all) > 0 {
+				values[name] = all
+			}
+			continue
+		}
+		if value := extractValue(e, fs); value != "" {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// extractValue returns the first match of a field selector
+func extractValue(e *colly.HTMLElement, fs *FieldSelector) string {
+	all := extractAll(e, fs)
+	if len(all) == 0 {
+		return ""
+	}
+	return all[0]
+}
+
+// extractAll returns every non-empty match of a field selector
+func extractAll(e *colly.HTMLElement, fs *FieldSelector) []string {
+	selection := e.DOM
+	if fs.Selector != "" {
+		selection = e.DOM.Find(fs.Selector)
+	}
+
+	values := []string{}
+	for i := 0; i < selection.Length(); i++ {
+		node := selection.Eq(i)
+
+		value := ""
+		if fs.Attr != "" {
+			value, _ = node.Attr(fs.Attr)
+		} else {
+			value = node.Text()
+		}
+		value = strings.TrimSpace(whitespace.ReplaceAllString(value, " "))
+
+		if fs.re != nil {
+			match := fs.re.FindStringSubmatch(value)
+			switch {
+			case match == nil:
+				value = ""
+			case len(match) > 1:
+				value = match[1]
+			default:
+				value = match[0]
+			}
+		}
+
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// mapItem converts scraped values to a JobPost
+func (hs *HTMLScraperConnector) mapItem(cfg *Config, item map[string]interface{}) (models.JobPost, bool) {
+	get := func(key string) string {
+		switch v := item[key].(type) {
+		case string:
+			return v
+		case []string:
+			return strings.Join(v, ", ")
+		}
+		return cfg.Defaults[key]
+	}
+	list := func(key string) []string {
+		switch v := item[key].(type) {
+		case []string:
+			return v
+		case string:
+			return []string{v}
+		}
+		return []string{}
+	}
+
+	job := models.JobPost{
+		Title:           get("title"),
+		Company:         get("company"),
+		Description:     get("description"),
+		Location:        get("location"),
+		Salary:          get("salary"),
+		SalaryCurrency:  get("salary_currency"),
+		URL:             get("url"),
+		EmploymentType:  get("employment_type"),
+		ExperienceLevel: get("experience_level"),
+		Requirements:    list("requirements"),
+		Benefits:        list("benefits"),
+	}
+	if job.Title == "" {
+		return job, false
+	}
+
+	switch strings.ToLower(get("is_remote")) {
+	case "true", "yes", "remote", "1":
+		job.IsRemote = true
+	}
+
+	originalID := get("id")
+	if originalID == "" && job.URL != "" {
+		// No ID on the page: hash the URL so the ID stays stable across syncs
+		originalID = jobid.URLHash(job.URL)
+	}
+	if originalID == "" {
+		return job, false
+	}
+	job.ID = jobid.New(cfg.Source, originalID)
+
+	posted, hasPosted := parseDate(cfg, get("posted_date"))
+	job.PostedDate = posted
+	if !hasPosted {
+		job.PostedDate = time.Now()
+	}
+	if expires, ok := parseDate(cfg, get("expires_date")); ok {
+		job.ExpiresDate = expires
+	}
+
+	job.Fields = map[string]interface{}{}
+	for key, value := range item {
+		if !jobFields[key] {
+			job.Fields[key] = value
+		}
+	}
+	job.Fields["source"] = cfg.Source
+	job.Fields["source_url"] = job.URL
+	job.Fields["original_id"] = originalID
+	job.Fields["connector"] = cfg.ID
+	job.Fields["fetched_at"] = time.Now()
+	job.Fields["method"] = "web_scraping"
+	job.Fields["selector_version"] = cfg.ActiveVersion
+	if !hasPosted {
+		job.Fields["posted_date_estimated"] = true
+	}
+	salary.Parse(job.Salary, job.SalaryCurrency, "").Apply(&job)
+
+	return job, true
+}
+
+// parseDate parses a scraped date with the configured layouts, then RFC3339 and YYYY-MM-DD
+func parseDate(cfg *Config, value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range append(append([]string{}, cfg.DateFormats...), time.RFC3339, "2006-01-02") {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// getLastSyncTime returns the high watermark committed by the last successful sync
+func (hs *HTMLScraperConnector) getLastSyncTime(cfg *Config) time.Time {
+	if hs.store == nil {
+		return time.Time{}
+	}
+	checkpoint, err := hs.store.GetCheckpoint(cfg.ID, "")
+	if err != nil {
+		fmt.Printf("⚠️  %s: failed to read checkpoint: %v\n", cfg.ID, err)
+		return time.Time{}
+	}
+	return checkpoint.HighWatermark
+}
+
+// SyncJobs scrapes the site and stores new jobs
+func (hs *HTMLScraperConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Printf("🔄 Starting %s sync...\n", hs.GetName())
+
+	jobs, err := hs.FetchJobs()
+	if err != nil {
+		hs.store.LogSync(&models.SyncLog{
+			ConnectorName: hs.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "failed",
+		})
+		return fmt.Errorf("failed to scrape jobs from %s: %w", hs.GetName(), err)
+	}
+
+	fmt.Printf("📥 Scraped %d jobs from %s\n", len(jobs), hs.GetName())
+
+	stored := 0
+	duplicates := 0
+	failed := 0
+	for _, job := range jobs {
+		existing, err := hs.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := hs.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	// Commit the checkpoint only once every job is stored, so failed jobs are retried
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if err := hs.store.AdvanceCheckpoint(hs.GetID(), "", jobs); err != nil {
+		fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+	}
+
+	if err := hs.store.LogSync(&models.SyncLog{
+		ConnectorName:  hs.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 %s sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", hs.GetName(), len(jobs), stored, duplicates)
+	return nil
+}
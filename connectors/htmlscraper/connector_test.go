@@ -0,0 +1,203 @@
+package htmlscraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+// newJobBoard serves two list pages linked by a "next" link, plus detail pages
+func newJobBoard() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `<html><body>
+				<div class="card" data-id="3"><h2>Data Engineer</h2><a href="/job/3">view</a></div>
+			</body></html>`)
+			return
+		}
+		fmt.Fprint(w, `<html><body>
+			<div class="card" data-id="1"><h2> Go Developer </h2><span class="co">Acme AB</span><a href="/job/1">view</a>
+				<article class="v2"><h3>Go Developer</h3></article></div>
+			<div class="card" data-id="2"><h2>SRE</h2><span class="co">Volvo</span><a href="/job/2">view</a></div>
+			<a class="next" href="/jobs?page=2">Next</a>
+		</body></html>`)
+	})
+	mux.HandleFunc("/job/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/job/")
+		fmt.Fprintf(w, `<html><body><div id="desc">Full description %s</div>
+			<ul class="skills"><li>Go</li><li>SQL</li></ul><time datetime="2025-10-0%sT08:00:00Z"></time></body></html>`, id, id)
+	})
+	return httptest.NewServer(mux)
+}
+
+func writeConfig(t *testing.T, path, serverURL, version string) {
+	t.Helper()
+	config := `{
+		"id": "testboard",
+		"source": "tb",
+		"start_urls": ["` + serverURL + `/jobs"],
+		"pagination": {"max_pages": 5, "next_selector": "a.next"},
+		"defaults": {"location": "Sweden"},
+		"active_version": "` + version + `",
+		"selector_sets": {
+			"v1": {
+				"item": "div.card",
+				"list": {"id": "@data-id", "title": "h2", "company": "span.co", "url": "a@href"},
+				"detail": {
+					"description": "#desc",
+					"requirements": {"selector": "ul.skills li", "all": true},
+					"posted_date": "time@datetime"
+				}
+			},
+			"v2": {
+				"item": "article.v2",
+				"list": {"title": "h3"}
+			}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScrapeListAndDetailPages(t *testing.T) {
+	server := newJobBoard()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "board.json")
+	writeConfig(t, path, server.URL, "v1")
+
+	connector, err := NewHTMLScraperConnector(nil, FileConfig(path))
+	if err != nil {
+		t.Fatalf("NewHTMLScraperConnector() failed: %v", err)
+	}
+
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs() failed: %v", err)
+	}
+	if len(jobs) != 3 {
+		t.Fatalf("Expected 3 jobs across 2 pages, got %d", len(jobs))
+	}
+
+	first := jobs[0]
+	if first.ID != "tb:1" || first.Title != "Go Developer" || first.Company != "Acme AB" {
+		t.Errorf("Unexpected first job: %+v", first)
+	}
+	if first.URL != server.URL+"/job/1" || first.Description != "Full description 1" {
+		t.Errorf("Detail page not merged: url=%q description=%q", first.URL, first.Description)
+	}
+	if len(first.Requirements) != 2 || first.PostedDate.Day() != 1 || first.Location != "Sweden" {
+		t.Errorf("Unexpected requirements/date/location: %v %v %q", first.Requirements, first.PostedDate, first.Location)
+	}
+	if first.Fields["selector_version"] != "v1" {
+		t.Errorf("Expected selector_version v1, got %v", first.Fields["selector_version"])
+	}
+}
+
+func TestSelectorSetHotSwap(t *testing.T) {
+	server := newJobBoard()
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "board.json")
+	writeConfig(t, path, server.URL, "v1")
+
+	connector, err := NewHTMLScraperConnector(nil, FileConfig(path))
+	if err != nil {
+		t.Fatalf("NewHTMLScraperConnector() failed: %v", err)
+	}
+
+	// Switching active_version takes effect on the next fetch without a restart
+	writeConfig(t, path, server.URL, "v2")
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs() failed: %v", err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("v2 items have no id or url, expected 0 jobs, got %d", len(jobs))
+	}
+	if connector.currentConfig().ActiveVersion != "v2" {
+		t.Errorf("Expected v2 to be active")
+	}
+
+	// A broken config keeps the last good one
+	os.WriteFile(path, []byte(`{"id": "testboard", "active_version": "v9"}`), 0o644)
+	connector.FetchJobs()
+	if connector.currentConfig().ActiveVersion != "v2" {
+		t.Errorf("Broken config should not replace the active one")
+	}
+}
+
+func TestVersionsOrder(t *testing.T) {
+	config := &Config{SelectorSets: map[string]*SelectorSet{}}
+	for _, version := range []string{"v10", "v9", "v2", "2025-10-01", "2025-9-30", "v1"} {
+		config.SelectorSets[version] = &SelectorSet{}
+	}
+	want := "2025-9-30 2025-10-01 v1 v2 v9 v10"
+	if got := strings.Join(config.Versions(), " "); got != want {
+		t.Errorf("Versions() = %s, want %s", got, want)
+	}
+}
+
+func TestExampleConfigsLoad(t *testing.T) {
+	paths, err := ConfigFiles("examples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if _, err := FileConfig(path)(); err != nil {
+			t.Errorf("%s: %v", path, err)
+		}
+	}
+}
+
+// newExampleConnector runs the Indeed example config against its recorded fixture
+func newExampleConnector(t *testing.T, store *storage.JobStore) *HTMLScraperConnector {
+	rec := httpreplay.New(t, "testdata/indeed-se.json")
+
+	// One start URL and page keep the fixture small
+	loader := func() (*Config, error) {
+		cfg, err := FileConfig("examples/indeed-se.json")()
+		if err != nil {
+			return nil, err
+		}
+		cfg.StartURLs = cfg.StartURLs[:1]
+		cfg.Pagination.MaxPages = 1
+		cfg.RandomDelayMs = 0
+		return cfg, nil
+	}
+	connector, err := NewHTMLScraperConnector(store, loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	connector.transport = rec
+	return connector
+}
+
+// TestExampleConfigGolden replays a recorded Indeed search through the example config.
+// Re-record with HTTPREPLAY=record go test -run Golden
+func TestExampleConfigGolden(t *testing.T) {
+	connector := newExampleConnector(t, nil)
+
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/indeed-se.golden.json", jobs)
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newExampleConnector(t, store)
+	}, conformance.Options{})
+}
@@ -1,74 +1,118 @@
 package indeed
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"openjobs/pkg/browser"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 )
 
-// IndeedConnector implements connector for Indeed.se (Sweden)
-type IndeedConnector struct {
-	store       *storage.JobStore
-	baseURL     string
-	publisherID string
-	userAgent   string
-	country     string // "se" for Sweden
+// pageSize is the number of results per search page for every strategy
+const pageSize = 10
+
+// Countries maps the supported Indeed country codes to their salary currency
+var Countries = map[string]string{
+	"at": "EUR", "au": "AUD", "be": "EUR", "ca": "CAD", "ch": "CHF",
+	"de": "EUR", "dk": "DKK", "es": "EUR", "fi": "EUR", "fr": "EUR",
+	"ie": "EUR", "in": "INR", "it": "EUR", "nl": "EUR", "no": "NOK",
+	"pl": "PLN", "se": "SEK", "uk": "GBP", "us": "USD",
 }
 
-// IndeedResponse represents the API response from Indeed
-type IndeedResponse struct {
-	Version       int          `json:"version"`
-	Query         string       `json:"query"`
-	Location      string       `json:"location"`
-	TotalResults  int          `json:"totalResults"`
-	Start         int          `json:"start"`
-	End           int          `json:"end"`
-	PageNumber    int          `json:"pageNumber"`
-	Results       []IndeedJob  `json:"results"`
+// site is one Indeed country domain
+type site struct {
+	country  string
+	baseURL  string
+	currency string
+	location string // Optional "l" search parameter
 }
 
-// IndeedJob represents a job from the Indeed API
-type IndeedJob struct {
-	JobTitle             string    `json:"jobtitle"`
-	Company              string    `json:"company"`
-	City                 string    `json:"city"`
-	State                string    `json:"state"`
-	Country              string    `json:"country"`
-	FormattedLocation    string    `json:"formattedLocation"`
-	Source               string    `json:"source"`
-	Date                 string    `json:"date"`
-	Snippet              string    `json:"snippet"`
-	URL                  string    `json:"url"`
-	Latitude             float64   `json:"latitude"`
-	Longitude            float64   `json:"longitude"`
-	JobKey               string    `json:"jobkey"`
-	Sponsored            bool      `json:"sponsored"`
-	Expired              bool      `json:"expired"`
-	FormattedRelativeTime string   `json:"formattedRelativeTime"`
+func (s site) searchURL(query string, start int) string {
+	q := url.Values{}
+	q.Set("q", query)
+	if s.location != "" {
+		q.Set("l", s.location)
+	}
+	q.Set("start", strconv.Itoa(start))
+	return fmt.Sprintf("%s/jobs?%s", s.baseURL, q.Encode())
+}
+
+func (s site) viewURL(jobKey string) string {
+	return fmt.Sprintf("%s/viewjob?jk=%s", s.baseURL, url.QueryEscape(jobKey))
 }
 
-// NewIndeedConnector creates a new Indeed connector
+// IndeedConnector fetches Indeed jobs with the cheapest strategy that works: the
+// publisher API, then plain HTTP, then headless Chrome. A strategy that is blocked
+// or comes back empty hands the rest of the run to the next one.
+type IndeedConnector struct {
+	store       *storage.JobStore
+	countries   []string
+	baseURLs    map[string]string // Country → https://<domain>
+	queries     []string
+	location    string
+	maxPages    int
+	rateLimit   time.Duration
+	userAgent   string
+	strategyIDs []string
+	publisherID string
+	apiURL      string
+	browser     *browser.Pool
+	transport   http.RoundTripper // Replaces the polite transports when set (tests)
+}
+
+// NewIndeedConnector creates a new Indeed connector.
+// INDEED_COUNTRIES (default "se"), INDEED_QUERIES, INDEED_LOCATION and INDEED_MAX_PAGES
+// configure the search; INDEED_STRATEGIES overrides the api,http,headless_chrome order.
 func NewIndeedConnector(store *storage.JobStore) *IndeedConnector {
-	publisherID := os.Getenv("INDEED_PUBLISHER_ID")
-	if publisherID == "" {
-		publisherID = "demo" // Demo mode for testing
-		fmt.Println("⚠️  INDEED_PUBLISHER_ID not set, using demo mode (limited results)")
+	countries := splitList(os.Getenv("INDEED_COUNTRIES"))
+	if len(countries) == 0 {
+		countries = []string{"se"}
+	}
+
+	baseURLs := map[string]string{}
+	for _, c := range countries {
+		baseURLs[c] = domainURL(c)
+	}
+
+	queries := splitList(os.Getenv("INDEED_QUERIES"))
+	if len(queries) == 0 {
+		queries = []string{"developer", "engineer", "designer", "manager", "sales", "marketing"}
+	}
+
+	maxPages := 5
+	if n, err := strconv.Atoi(os.Getenv("INDEED_MAX_PAGES")); err == nil && n > 0 {
+		maxPages = n
+	}
+
+	strategyIDs := splitList(os.Getenv("INDEED_STRATEGIES"))
+	if len(strategyIDs) == 0 {
+		strategyIDs = []string{"api", "http", "headless_chrome"}
 	}
 
 	return &IndeedConnector{
 		store:       store,
-		baseURL:     "http://api.indeed.com/ads/apisearch",
-		publisherID: publisherID,
-		userAgent:   "OpenJobs-Indeed-Connector/1.0",
-		country:     "se", // Sweden
+		countries:   countries,
+		baseURLs:    baseURLs,
+		queries:     queries,
+		location:    os.Getenv("INDEED_LOCATION"),
+		maxPages:    maxPages,
+		rateLimit:   2 * time.Second,
+		userAgent:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		strategyIDs: strategyIDs,
+		publisherID: os.Getenv("INDEED_PUBLISHER_ID"),
+		apiURL:      "http://api.indeed.com/ads/apisearch",
+		browser:     browser.Shared(),
 	}
 }
 
@@ -79,333 +123,281 @@ func (ic *IndeedConnector) GetID() string {
 
 // GetName returns the connector name
 func (ic *IndeedConnector) GetName() string {
-	return "Indeed Sweden Connector"
+	return "Indeed Connector"
 }
 
-// FetchJobs fetches job listings from Indeed API
-func (ic *IndeedConnector) FetchJobs() ([]models.JobPost, error) {
-	allJobs := []models.JobPost{}
-	
-	// Search queries to get diverse jobs
-	queries := []string{
-		"",                    // All jobs
-		"developer",           // Tech jobs
-		"engineer",            // Engineering jobs
-		"manager",             // Management jobs
-		"sales",               // Sales jobs
-		"customer service",    // Service jobs
-	}
-
-	// Get last sync time for incremental sync
-	lastSync := ic.getLastSyncTime()
-	
-	for _, query := range queries {
-		fmt.Printf("🔍 Searching Indeed for: '%s'\n", query)
-		
-		// Fetch multiple pages (up to 100 results per query)
-		for start := 0; start < 100; start += 25 {
-			jobs, err := ic.fetchJobsPage(query, start)
-			if err != nil {
-				fmt.Printf("⚠️  Error fetching page %d for query '%s': %v\n", start/25+1, query, err)
-				continue
+// strategies builds the configured fetch strategies in escalation order; the API
+// needs a publisher ID and Chrome a configured browser pool, otherwise they are left out
+func (ic *IndeedConnector) strategies() []fetchStrategy {
+	strategies := []fetchStrategy{}
+	for _, id := range ic.strategyIDs {
+		switch id {
+		case "api":
+			if ic.publisherID != "" {
+				api := &apiStrategy{
+					baseURL:     ic.apiURL,
+					publisherID: ic.publisherID,
+					userAgent:   httpclient.UserAgent("OpenJobs-Indeed-Connector/1.0"),
+					httpClient:  httpclient.New(httpclient.Options{Interval: ic.rateLimit}),
+				}
+				if ic.transport != nil {
+					api.httpClient.Transport = ic.transport
+				}
+				strategies = append(strategies, api)
 			}
-			
-			if len(jobs) == 0 {
-				break // No more results
+		case "http":
+			transport := ic.transport
+			if transport == nil {
+				transport = httpclient.NewTransport(httpclient.Options{
+					UserAgent:     ic.userAgent,
+					Interval:      ic.rateLimit,
+					RespectRobots: true,
+				})
 			}
-			
-			// Filter to only new jobs
-			for _, job := range jobs {
-				if lastSync.IsZero() || job.PostedDate.After(lastSync) {
-					allJobs = append(allJobs, job)
-				}
+			strategies = append(strategies, &httpStrategy{userAgent: ic.userAgent, transport: transport})
+		case "headless_chrome", "chrome":
+			if ic.browser.Enabled() {
+				strategies = append(strategies, &chromeStrategy{pool: ic.browser, userAgent: ic.userAgent, interval: ic.rateLimit})
 			}
-			
-			// Rate limiting - be nice to Indeed API
-			time.Sleep(1 * time.Second)
+		default:
+			fmt.Printf("⚠️  Unknown Indeed strategy %q, skipping\n", id)
 		}
 	}
-	
-	// Deduplicate by job key
-	uniqueJobs := ic.deduplicateJobs(allJobs)
-	
-	fmt.Printf("📊 Fetched %d unique jobs from Indeed (filtered from %d total)\n", len(uniqueJobs), len(allJobs))
-	
-	return uniqueJobs, nil
+	return strategies
 }
 
-// fetchJobsPage fetches a single page of jobs
-func (ic *IndeedConnector) fetchJobsPage(query string, start int) ([]models.JobPost, error) {
-	// Build API URL
-	params := url.Values{}
-	params.Set("publisher", ic.publisherID)
-	params.Set("v", "2")
-	params.Set("format", "json")
-	params.Set("co", ic.country)
-	params.Set("limit", "25")
-	params.Set("start", fmt.Sprintf("%d", start))
-	
-	if query != "" {
-		params.Set("q", query)
-	}
-	
-	// Add user IP and user agent if available
-	params.Set("useragent", ic.userAgent)
-	
-	apiURL := fmt.Sprintf("%s?%s", ic.baseURL, params.Encode())
-	
-	req, err := http.NewRequest("GET", apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	
-	req.Header.Set("User-Agent", ic.userAgent)
-	req.Header.Set("Accept", "application/json")
-	
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch jobs from Indeed: %w", err)
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("indeed API error %d: %s", resp.StatusCode, string(body))
-	}
-	
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// FetchJobs searches every configured country and query, escalating strategies as needed
+func (ic *IndeedConnector) FetchJobs() ([]models.JobPost, error) {
+	strategies := ic.strategies()
+	if len(strategies) == 0 {
+		return nil, fmt.Errorf("no usable Indeed strategy in %v", ic.strategyIDs)
 	}
-	
-	var indeedResp IndeedResponse
-	err = json.Unmarshal(body, &indeedResp)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
-	}
-	
-	// Transform to our JobPost format
-	jobs := make([]models.JobPost, 0, len(indeedResp.Results))
-	for _, indeedJob := range indeedResp.Results {
-		if indeedJob.Expired {
-			continue // Skip expired jobs
+	defer func() {
+		for _, s := range strategies {
+			s.close()
+		}
+	}()
+
+	level := 0
+	jobs := []models.JobPost{}
+	seen := map[string]bool{}
+
+	for _, country := range ic.countries {
+		s, ok := ic.site(country)
+		if !ok {
+			fmt.Printf("⚠️  Indeed country %q is not supported, skipping\n", country)
+			continue
+		}
+
+		for _, query := range ic.queries {
+			fmt.Printf("🔍 Searching Indeed %s for '%s'\n", country, query)
+
+			for page := 0; page < ic.maxPages; page++ {
+				if level >= len(strategies) {
+					return jobs, ic.exhausted(strategies, jobs)
+				}
+
+				cards, strategy, err := ic.searchPage(strategies, &level, s, query, page*pageSize)
+				if err != nil {
+					fmt.Printf("⚠️  %v\n", err)
+					break
+				}
+				if len(cards) == 0 {
+					break
+				}
+
+				newOnPage := 0
+				for _, c := range cards {
+					id := jobid.New("indeed", c.JobKey)
+					if seen[id] {
+						continue
+					}
+					seen[id] = true
+					if ic.isStored(id) {
+						continue
+					}
+
+					description := ""
+					if desc, err := strategy.description(s, c.JobKey); err != nil {
+						fmt.Printf("   ⚠️  Using snippet for %s: %v\n", c.JobKey, err)
+					} else {
+						description = desc
+					}
+					jobs = append(jobs, ic.transformCard(s, c, description, strategy.name()))
+					newOnPage++
+				}
+
+				fmt.Printf("   📄 Page %d via %s: %d results, %d new\n", page+1, strategy.name(), len(cards), newOnPage)
+
+				// A page of only known jobs means we've caught up with this query
+				if newOnPage == 0 || len(cards) < pageSize {
+					break
+				}
+			}
 		}
-		job := ic.transformIndeedJob(indeedJob)
-		jobs = append(jobs, job)
 	}
-	
+
+	if level >= len(strategies) {
+		return jobs, ic.exhausted(strategies, jobs)
+	}
+
+	fmt.Printf("📊 Fetched %d new Indeed jobs\n", len(jobs))
 	return jobs, nil
 }
 
-// transformIndeedJob converts Indeed job format to our JobPost format
-func (ic *IndeedConnector) transformIndeedJob(ij IndeedJob) models.JobPost {
-	job := models.JobPost{
-		ID:              fmt.Sprintf("indeed-%s", ij.JobKey),
-		Title:           ij.JobTitle,
-		Company:         ij.Company,
-		Description:     ic.cleanSnippet(ij.Snippet),
-		Location:        ic.formatLocation(ij),
-		Salary:          "", // Indeed doesn't provide salary in API
-		SalaryMin:       nil,
-		SalaryMax:       nil,
-		SalaryCurrency:  "SEK", // Sweden
-		IsRemote:        ic.detectRemote(ij),
-		URL:             ij.URL,
-		EmploymentType:  "Full-time", // Indeed doesn't specify in API
-		ExperienceLevel: "Mid-level",
-		PostedDate:      ic.parseIndeedDate(ij.Date),
-		ExpiresDate:     ic.parseIndeedDate(ij.Date).AddDate(0, 1, 0), // 1 month expiration
-		Requirements:    ic.extractRequirements(ij),
-		Benefits:        []string{},
-		Fields: map[string]interface{}{
-			"source":                  "indeed",
-			"source_url":              ij.URL,
-			"original_id":             ij.JobKey,
-			"city":                    ij.City,
-			"state":                   ij.State,
-			"country":                 ij.Country,
-			"formatted_location":      ij.FormattedLocation,
-			"sponsored":               ij.Sponsored,
-			"formatted_relative_time": ij.FormattedRelativeTime,
-			"latitude":                ij.Latitude,
-			"longitude":               ij.Longitude,
-			"connector":               "indeed",
-			"fetched_at":              time.Now(),
-		},
-	}
-	
-	return job
-}
+// searchPage fetches one results page with the current strategy. A blocked strategy,
+// or one that finds nothing on a query's first page, is replaced by the next for the
+// rest of the run; the same page is then retried.
+func (ic *IndeedConnector) searchPage(strategies []fetchStrategy, level *int, s site, query string, start int) ([]card, fetchStrategy, error) {
+	for *level < len(strategies) {
+		strategy := strategies[*level]
+		cards, err := strategy.search(s, query, start)
 
-// cleanSnippet removes HTML tags and cleans up the snippet
-func (ic *IndeedConnector) cleanSnippet(snippet string) string {
-	// Remove <b> tags (Indeed uses them for highlighting)
-	snippet = strings.ReplaceAll(snippet, "<b>", "")
-	snippet = strings.ReplaceAll(snippet, "</b>", "")
-	
-	// Trim whitespace
-	snippet = strings.TrimSpace(snippet)
-	
-	return snippet
-}
+		switch {
+		case err != nil && !errors.Is(err, errBlocked) && start > 0:
+			return nil, strategy, fmt.Errorf("indeed %s page at %d failed: %w", strategy.name(), start, err)
+		case err != nil:
+			fmt.Printf("🚧 Indeed %s strategy failed (%v), escalating\n", strategy.name(), err)
+		case len(cards) == 0 && start == 0:
+			fmt.Printf("🚧 Indeed %s strategy returned nothing, escalating\n", strategy.name())
+		default:
+			return cards, strategy, nil
+		}
 
-// formatLocation formats the location string
-func (ic *IndeedConnector) formatLocation(ij IndeedJob) string {
-	if ij.FormattedLocation != "" {
-		return ij.FormattedLocation
-	}
-	
-	parts := []string{}
-	if ij.City != "" {
-		parts = append(parts, ij.City)
+		strategy.close()
+		*level++
 	}
-	if ij.State != "" {
-		parts = append(parts, ij.State)
-	}
-	if ij.Country != "" {
-		parts = append(parts, ij.Country)
+	return nil, nil, fmt.Errorf("all Indeed strategies failed")
+}
+
+// exhausted reports a run where every strategy gave up; jobs fetched before that are kept
+func (ic *IndeedConnector) exhausted(strategies []fetchStrategy, jobs []models.JobPost) error {
+	if len(jobs) > 0 {
+		fmt.Printf("⚠️  All Indeed strategies exhausted after %d jobs\n", len(jobs))
+		return nil
 	}
-	
-	if len(parts) > 0 {
-		return strings.Join(parts, ", ")
+	names := []string{}
+	for _, s := range strategies {
+		names = append(names, s.name())
 	}
-	
-	return "Sweden"
+	return fmt.Errorf("indeed blocked or empty for every strategy (%s)", strings.Join(names, ", "))
 }
 
-// detectRemote checks if job is remote
-func (ic *IndeedConnector) detectRemote(ij IndeedJob) bool {
-	text := strings.ToLower(ij.JobTitle + " " + ij.Snippet + " " + ij.FormattedLocation)
-	
-	remoteKeywords := []string{
-		"remote", "distans", "hemarbete", "hemifrån",
-		"work from home", "wfh", "anywhere",
+func (ic *IndeedConnector) site(country string) (site, bool) {
+	currency, ok := Countries[country]
+	if !ok {
+		return site{}, false
 	}
-	
-	for _, keyword := range remoteKeywords {
-		if strings.Contains(text, keyword) {
-			return true
-		}
+	baseURL := ic.baseURLs[country]
+	if baseURL == "" {
+		baseURL = domainURL(country)
 	}
-	
-	return false
+	return site{country: country, baseURL: baseURL, currency: currency, location: ic.location}, true
 }
 
-// parseIndeedDate parses Indeed date format
-func (ic *IndeedConnector) parseIndeedDate(dateStr string) time.Time {
-	if dateStr == "" {
-		return time.Now()
-	}
-	
-	// Indeed uses various formats, try common ones
-	formats := []string{
-		time.RFC3339,
-		"Mon, 02 Jan 2006 15:04:05 MST",
-		"2006-01-02",
-	}
-	
-	for _, format := range formats {
-		if t, err := time.Parse(format, dateStr); err == nil {
-			return t
-		}
+// isStored reports whether a job is already in the database, so its page isn't fetched again
+func (ic *IndeedConnector) isStored(id string) bool {
+	if ic.store == nil {
+		return false
 	}
-	
-	return time.Now()
+	existing, err := ic.store.GetJob(id)
+	return err == nil && existing != nil
 }
 
-// extractRequirements extracts keywords from title and snippet
-func (ic *IndeedConnector) extractRequirements(ij IndeedJob) []string {
-	requirements := []string{}
-	seen := make(map[string]bool)
-	
-	text := strings.ToLower(ij.JobTitle + " " + ij.Snippet)
-	
-	// Common tech skills and keywords
-	keywords := []string{
-		"Java", "Python", "JavaScript", "TypeScript", "C++", "C#", ".NET", "PHP", "Ruby", "Go", "Rust", "Swift", "Kotlin",
-		"React", "Angular", "Vue", "Node.js", "Spring", "Django", "Flask", "Express", "Laravel",
-		"Docker", "Kubernetes", "AWS", "Azure", "GCP", "CI/CD", "Jenkins", "Git", "Linux",
-		"SQL", "PostgreSQL", "MySQL", "MongoDB", "Redis", "Elasticsearch",
-		"API", "REST", "GraphQL", "Microservices", "Agile", "Scrum",
-		"Swedish", "English", "B2B", "B2C", "SaaS",
+// transformCard converts a search result to our JobPost format
+func (ic *IndeedConnector) transformCard(s site, c card, description, method string) models.JobPost {
+	jobURL := s.viewURL(c.JobKey)
+	if description == "" {
+		description = cleanText(c.Snippet)
 	}
-	
-	for _, keyword := range keywords {
-		if strings.Contains(text, strings.ToLower(keyword)) && !seen[keyword] {
-			requirements = append(requirements, keyword)
-			seen[keyword] = true
-		}
+
+	posted := c.Posted
+	if posted.IsZero() {
+		posted = time.Now()
 	}
-	
-	return requirements
-}
 
-// deduplicateJobs removes duplicate jobs by job key
-func (ic *IndeedConnector) deduplicateJobs(jobs []models.JobPost) []models.JobPost {
-	seen := make(map[string]bool)
-	unique := []models.JobPost{}
-	
-	for _, job := range jobs {
-		if !seen[job.ID] {
-			seen[job.ID] = true
-			unique = append(unique, job)
-		}
+	fields := map[string]interface{}{
+		"source":      "indeed",
+		"source_url":  jobURL,
+		"original_id": c.JobKey,
+		"country":     s.country,
+		"domain":      s.baseURL,
+		"method":      method,
+		"connector":   "indeed",
+		"fetched_at":  time.Now(),
+	}
+	if c.Posted.IsZero() {
+		fields["posted_date_estimated"] = true
+	}
+	if c.Latitude != 0 || c.Longitude != 0 {
+		fields["coordinates"] = []float64{c.Longitude, c.Latitude}
+	}
+	if c.Sponsored {
+		fields["sponsored"] = true
 	}
-	
-	return unique
+
+	job := models.JobPost{
+		ID:           jobid.New("indeed", c.JobKey),
+		Title:        strings.TrimSpace(c.Title),
+		Company:      strings.TrimSpace(c.Company),
+		Description:  description,
+		Location:     strings.TrimSpace(c.Location),
+		Salary:       strings.TrimSpace(c.Salary),
+		IsRemote:     detectRemote(c.Title, description, c.Location),
+		URL:          jobURL,
+		PostedDate:   posted,
+		Requirements: extractRequirements(c.Title, description),
+		Benefits:     []string{},
+		Fields:       fields,
+	}
+	if job.Salary != "" {
+		job.SalaryCurrency = s.currency
+		salary.Parse(job.Salary, s.currency, "").Apply(&job)
+	}
+	return job
 }
 
 // SyncJobs fetches jobs from Indeed and stores them
 func (ic *IndeedConnector) SyncJobs() error {
 	startTime := time.Now()
-	fmt.Println("🔄 Starting Indeed Sweden jobs sync...")
-	
+	fmt.Printf("🔄 Starting Indeed sync (%s)...\n", strings.Join(ic.countries, ", "))
+
 	jobs, err := ic.FetchJobs()
 	if err != nil {
-		// Log failed sync
 		ic.store.LogSync(&models.SyncLog{
 			ConnectorName: ic.GetID(),
 			StartedAt:     startTime,
 			CompletedAt:   time.Now(),
-			JobsFetched:   0,
-			JobsInserted:  0,
-			JobsDuplicates: 0,
-			Status:        "failed",
+			Status:        "error",
+			ErrorMessage:  err.Error(),
 		})
 		return fmt.Errorf("failed to fetch jobs from Indeed: %w", err)
 	}
-	
-	fmt.Printf("📥 Fetched %d jobs from Indeed Sweden\n", len(jobs))
-	
+
+	fmt.Printf("📥 Fetched %d jobs from Indeed\n", len(jobs))
+
 	stored := 0
 	duplicates := 0
 	for _, job := range jobs {
-		// Check if job already exists
 		existing, err := ic.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
 			continue
 		}
-		
 		if existing != nil {
-			// Job already exists, skip
 			duplicates++
 			continue
 		}
-		
-		// Store new job
-		err = ic.store.CreateJob(&job)
-		if err != nil {
+
+		if err := ic.store.CreateJob(&job); err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
 			continue
 		}
-		
+
 		stored++
 		fmt.Printf("✅ Stored job: %s at %s (%s)\n", job.Title, job.Company, job.Location)
 	}
-	
-	// Log successful sync
+
 	if err := ic.store.LogSync(&models.SyncLog{
 		ConnectorName:  ic.GetID(),
 		StartedAt:      startTime,
@@ -417,19 +409,80 @@ func (ic *IndeedConnector) SyncJobs() error {
 	}); err != nil {
 		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
 	}
-	
+
 	fmt.Printf("🎉 Indeed sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
 	return nil
 }
 
-// getLastSyncTime retrieves the timestamp of the most recent job in database
-func (ic *IndeedConnector) getLastSyncTime() time.Time {
-	job, err := ic.store.GetMostRecentJob("indeed-")
-	if err != nil {
-		fmt.Println("📅 No previous Indeed jobs found - processing all jobs")
-		return time.Time{}
+// domainURL returns the Indeed site of a country (www.indeed.com for the US)
+func domainURL(country string) string {
+	if country == "us" {
+		return "https://www.indeed.com"
+	}
+	return fmt.Sprintf("https://%s.indeed.com", country)
+}
+
+func splitList(s string) []string {
+	list := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+var (
+	htmlTags   = regexp.MustCompile(`<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n\s*\n\s*\n+`)
+)
+
+// cleanText strips highlighting tags and excess blank lines
+func cleanText(text string) string {
+	text = htmlTags.ReplaceAllString(text, "")
+	text = blankLines.ReplaceAllString(text, "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// detectRemote checks if job is remote
+func detectRemote(title, description, location string) bool {
+	text := strings.ToLower(title + " " + description + " " + location)
+
+	remoteKeywords := []string{
+		"remote", "distans", "hemarbete", "hemifrån", "homeoffice", "home office",
+		"work from home", "wfh", "anywhere",
+	}
+
+	for _, keyword := range remoteKeywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractRequirements extracts keywords from title and description
+func extractRequirements(title, description string) []string {
+	requirements := []string{}
+	seen := make(map[string]bool)
+
+	text := strings.ToLower(title + " " + description)
+
+	// Common tech skills
+	keywords := []string{
+		"Java", "Python", "JavaScript", "TypeScript", "C++", "C#", ".NET", "PHP", "Ruby", "Go", "Rust", "Swift", "Kotlin",
+		"React", "Angular", "Vue", "Node.js", "Spring", "Django", "Flask", "Express", "Laravel",
+		"Docker", "Kubernetes", "AWS", "Azure", "GCP", "CI/CD", "Jenkins", "Git", "Linux",
+		"SQL", "PostgreSQL", "MySQL", "MongoDB", "Redis", "Elasticsearch",
+		"API", "REST", "GraphQL", "Microservices", "Agile", "Scrum",
+		"Swedish", "English", "B2B", "B2C", "SaaS",
 	}
-	
-	fmt.Printf("📅 Last Indeed job in database: %s (posted: %s)\n", job.Title, job.PostedDate.Format("2006-01-02"))
-	return job.PostedDate
+
+	for _, keyword := range keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) && !seen[keyword] {
+			requirements = append(requirements, keyword)
+			seen[keyword] = true
+		}
+	}
+	return requirements
 }
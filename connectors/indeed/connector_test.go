@@ -0,0 +1,236 @@
+package indeed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/browser"
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+const searchPage = `<html><body><div id="mosaic">
+<div class="job_seen_beacon"><table><tr><td class="resultContent">
+  <h2 class="jobTitle"><a data-jk="abc123" href="/rc/clk?jk=abc123"><span title="Backend Developer">Backend Developer</span></a></h2>
+  <span data-testid="company-name">Acme AB</span>
+  <div data-testid="text-location">Stockholm</div>
+  <div class="salary-snippet">45 000 - 55 000 kr per månad</div>
+  <div class="job-snippet">Go and <b>Kubernetes</b></div>
+  <span class="date">Publicerad för 3 dagar sedan</span>
+</td></tr></table></div>
+<div class="job_seen_beacon"><td class="resultContent">
+  <h2 class="jobTitle"><a href="/viewjob?jk=def456"><span>Distans Designer</span></a></h2>
+  <span class="companyName">Beta</span>
+</td></div>
+</div></body></html>`
+
+const viewPage = `<html><body><div id="jobDescriptionText"><p>Full description with Python.</p></div></body></html>`
+
+func newTestConnector(handler http.Handler) (*IndeedConnector, func()) {
+	server := httptest.NewServer(handler)
+	ic := NewIndeedConnector(nil)
+	ic.baseURLs["se"] = server.URL
+	ic.apiURL = server.URL + "/ads/apisearch"
+	ic.queries = []string{"developer"}
+	ic.maxPages = 2
+	ic.rateLimit = 0
+	ic.browser = nil
+	return ic, server.Close
+}
+
+func TestEscalatesFromBlockedAPIToHTTP(t *testing.T) {
+	hits := map[string]int{}
+	ic, done := newTestConnector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		switch r.URL.Path {
+		case "/ads/apisearch":
+			w.WriteHeader(http.StatusForbidden)
+		case "/jobs":
+			if r.URL.Query().Get("start") != "0" {
+				fmt.Fprint(w, `<html><body>No more jobs</body></html>`)
+				return
+			}
+			fmt.Fprint(w, searchPage)
+		case "/viewjob":
+			if r.URL.Query().Get("jk") == "def456" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, viewPage)
+		}
+	}))
+	defer done()
+	ic.publisherID = "pub"
+
+	jobs, err := ic.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if hits["/ads/apisearch"] != 1 {
+		t.Errorf("Expected one API attempt before escalating, got %d", hits["/ads/apisearch"])
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(jobs))
+	}
+
+	dev := jobs[0]
+	if dev.ID != "indeed:abc123" || dev.Title != "Backend Developer" || dev.Company != "Acme AB" || dev.Location != "Stockholm" {
+		t.Errorf("Unexpected job: %s %q %q %q", dev.ID, dev.Title, dev.Company, dev.Location)
+	}
+	if dev.Description != "Full description with Python." || dev.Fields["method"] != "http" || dev.Fields["country"] != "se" {
+		t.Errorf("Unexpected description/fields: %q %+v", dev.Description, dev.Fields)
+	}
+	if dev.SalaryCurrency != "SEK" || !strings.HasPrefix(dev.Salary, "45 000") {
+		t.Errorf("Unexpected salary: %q %q", dev.Salary, dev.SalaryCurrency)
+	}
+	if days := int(time.Since(dev.PostedDate).Hours() / 24); days != 3 || dev.Fields["posted_date_estimated"] != nil {
+		t.Errorf("Expected posted 3 days ago, got %v", dev.PostedDate)
+	}
+	if !strings.HasSuffix(dev.URL, "/viewjob?jk=abc123") {
+		t.Errorf("Unexpected URL: %s", dev.URL)
+	}
+
+	// The view page failed, so the snippet is used; "distans" marks it remote
+	designer := jobs[1]
+	if designer.ID != "indeed:def456" || designer.Description != "" || !designer.IsRemote || designer.Fields["posted_date_estimated"] != true {
+		t.Errorf("Unexpected fallback job: %+v", designer)
+	}
+}
+
+func TestAPIStrategy(t *testing.T) {
+	ic, done := newTestConnector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ads/apisearch" || r.URL.Query().Get("co") != "se" || r.URL.Query().Get("publisher") != "pub" {
+			t.Errorf("Unexpected request: %s", r.URL)
+		}
+		fmt.Fprint(w, `{"totalResults": 2, "results": [
+			{"jobkey": "k1", "jobtitle": "Engineer", "company": "Gamma", "formattedLocation": "Göteborg",
+			 "snippet": "Build <b>things</b>", "date": "Wed, 01 Oct 2025 10:00:00 GMT", "latitude": 57.7, "longitude": 11.97},
+			{"jobkey": "k2", "jobtitle": "Old", "expired": true}
+		]}`)
+	}))
+	defer done()
+	ic.publisherID = "pub"
+
+	jobs, err := ic.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].ID != "indeed:k1" || jobs[0].Description != "Build things" || jobs[0].Fields["method"] != "api" {
+		t.Fatalf("Unexpected jobs: %+v", jobs)
+	}
+	if jobs[0].PostedDate.Format("2006-01-02") != "2025-10-01" {
+		t.Errorf("Unexpected posted date: %v", jobs[0].PostedDate)
+	}
+}
+
+func TestAllStrategiesBlocked(t *testing.T) {
+	ic, done := newTestConnector(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><title>Just a moment...</title></head><body class="cf-challenge"></body></html>`)
+	}))
+	defer done()
+
+	if _, err := ic.FetchJobs(); err == nil || !strings.Contains(err.Error(), "every strategy") {
+		t.Fatalf("Expected exhausted error, got %v", err)
+	}
+}
+
+func TestStrategiesFromConfig(t *testing.T) {
+	ic := NewIndeedConnector(nil)
+	ic.publisherID = ""
+	ic.browser = nil
+	if s := ic.strategies(); len(s) != 1 || s[0].name() != "http" {
+		t.Errorf("Expected only http without publisher ID and Chrome, got %d strategies", len(s))
+	}
+
+	t.Setenv("INDEED_COUNTRIES", "us, UK")
+	ic = NewIndeedConnector(nil)
+	if ic.baseURLs["us"] != "https://www.indeed.com" || ic.baseURLs["uk"] != "https://uk.indeed.com" {
+		t.Errorf("Unexpected domains: %v", ic.baseURLs)
+	}
+}
+
+// newReplayConnector returns a connector that replays the recorded API and search pages
+func newReplayConnector(t *testing.T, store *storage.JobStore) *IndeedConnector {
+	t.Setenv("HTTP_USER_AGENT", "") // The API sends it as a query parameter
+	rec := httpreplay.New(t, "testdata/search.json")
+
+	ic := NewIndeedConnector(store)
+	if ic.publisherID == "" {
+		ic.publisherID = "test"
+	}
+	rec.Redact(ic.publisherID)
+	ic.countries = []string{"se"}
+	ic.queries = []string{"developer"}
+	ic.location = ""
+	ic.maxPages = 1
+	ic.browser = nil
+	ic.transport = rec
+	return ic
+}
+
+// TestFetchJobsGolden replays a recorded run where the retired publisher API refuses
+// the request and the connector escalates to the search pages.
+// Re-record with HTTPREPLAY=record INDEED_PUBLISHER_ID=... go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	ic := newReplayConnector(t, nil)
+
+	jobs, err := ic.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	// Cards only say "3 days ago", so posted dates move with the clock
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs, "posted_date")
+}
+
+// TestFetchJobsChromeGolden renders the recorded search pages in headless Chrome and
+// expects the jobs plain HTTP produced. It needs a local Chrome and is skipped without one.
+func TestFetchJobsChromeGolden(t *testing.T) {
+	path := browser.LookPath()
+	if path == "" {
+		t.Skip("no Chrome binary installed")
+	}
+	t.Setenv("HTTP_RESPECT_ROBOTS", "false") // The fixture has no robots.txt
+
+	rec := httpreplay.New(t, "testdata/search.json")
+	server := rec.Server()
+
+	pool := browser.NewPool(browser.Config{ExecPath: path, MaxTabs: 1, PageTimeout: 30 * time.Second})
+	t.Cleanup(pool.Close)
+
+	ic := NewIndeedConnector(nil)
+	ic.countries = []string{"se"}
+	ic.baseURLs["se"] = server.URL
+	ic.queries = []string{"developer"}
+	ic.location = ""
+	ic.maxPages = 1
+	ic.rateLimit = 0
+	ic.strategyIDs = []string{"headless_chrome"}
+	ic.browser = pool
+
+	jobs, err := ic.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	for i := range jobs {
+		if jobs[i].Fields["method"] != "headless_chrome" {
+			t.Errorf("%s: expected method headless_chrome, got %v", jobs[i].ID, jobs[i].Fields["method"])
+		}
+		jobs[i].Fields["method"] = "http"
+		jobs[i].Fields["domain"] = "https://se.indeed.com"
+		jobs[i].Fields["source_url"] = strings.Replace(jobs[i].Fields["source_url"].(string), server.URL, "https://se.indeed.com", 1)
+		jobs[i].URL = strings.Replace(jobs[i].URL, server.URL, "https://se.indeed.com", 1)
+	}
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs, "posted_date")
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
@@ -0,0 +1,363 @@
+package indeed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+
+	"openjobs/pkg/browser"
+	"openjobs/pkg/httpclient"
+)
+
+// errBlocked means the strategy was refused (bot wall, rate limit, bad credentials)
+// and the connector should escalate to the next one
+var errBlocked = errors.New("blocked")
+
+// card is a search result, whichever strategy produced it
+type card struct {
+	JobKey    string
+	Title     string
+	Company   string
+	Location  string
+	Snippet   string
+	Salary    string
+	Posted    time.Time // Zero when the result only shows a relative age we couldn't read
+	URL       string
+	Latitude  float64
+	Longitude float64
+	Sponsored bool
+}
+
+// fetchStrategy is one way of reading Indeed, from cheapest to most expensive:
+// the publisher API, plain HTTP (colly) and headless Chrome
+type fetchStrategy interface {
+	name() string
+	search(s site, query string, start int) ([]card, error)
+	// description returns the full ad text ("" when the strategy can't fetch it)
+	description(s site, jobKey string) (string, error)
+	close()
+}
+
+// apiStrategy uses the legacy Indeed Publisher API (needs INDEED_PUBLISHER_ID)
+type apiStrategy struct {
+	baseURL     string
+	publisherID string
+	userAgent   string
+	httpClient  *http.Client
+}
+
+type apiResponse struct {
+	TotalResults int      `json:"totalResults"`
+	Results      []apiJob `json:"results"`
+}
+
+type apiJob struct {
+	JobTitle          string  `json:"jobtitle"`
+	Company           string  `json:"company"`
+	FormattedLocation string  `json:"formattedLocation"`
+	Date              string  `json:"date"`
+	Snippet           string  `json:"snippet"`
+	URL               string  `json:"url"`
+	Latitude          float64 `json:"latitude"`
+	Longitude         float64 `json:"longitude"`
+	JobKey            string  `json:"jobkey"`
+	Sponsored         bool    `json:"sponsored"`
+	Expired           bool    `json:"expired"`
+}
+
+func (as *apiStrategy) name() string { return "api" }
+
+func (as *apiStrategy) search(s site, query string, start int) ([]card, error) {
+	params := url.Values{}
+	params.Set("publisher", as.publisherID)
+	params.Set("v", "2")
+	params.Set("format", "json")
+	params.Set("co", s.country)
+	params.Set("limit", strconv.Itoa(pageSize))
+	params.Set("start", strconv.Itoa(start))
+	params.Set("useragent", as.userAgent)
+	if query != "" {
+		params.Set("q", query)
+	}
+	if s.location != "" {
+		params.Set("l", s.location)
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s?%s", as.baseURL, params.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", as.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := as.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs from Indeed API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%w: indeed API error %d: %s", errBlocked, resp.StatusCode, string(body))
+	}
+
+	var response apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	cards := make([]card, 0, len(response.Results))
+	for _, j := range response.Results {
+		if j.Expired || j.JobKey == "" {
+			continue
+		}
+		cards = append(cards, card{
+			JobKey:    j.JobKey,
+			Title:     j.JobTitle,
+			Company:   j.Company,
+			Location:  j.FormattedLocation,
+			Snippet:   j.Snippet,
+			Posted:    parseAPIDate(j.Date),
+			URL:       j.URL,
+			Latitude:  j.Latitude,
+			Longitude: j.Longitude,
+			Sponsored: j.Sponsored,
+		})
+	}
+	return cards, nil
+}
+
+// The API only returns snippets
+func (as *apiStrategy) description(s site, jobKey string) (string, error) { return "", nil }
+
+func (as *apiStrategy) close() {}
+
+// httpStrategy fetches the public search pages with colly
+type httpStrategy struct {
+	userAgent string
+	transport http.RoundTripper // Polite transport: per-host interval, robots.txt, Retry-After
+}
+
+func (hs *httpStrategy) name() string { return "http" }
+
+func (hs *httpStrategy) search(s site, query string, start int) ([]card, error) {
+	body, err := hs.fetch(s.searchURL(query, start))
+	if err != nil {
+		return nil, err
+	}
+	return parseSearchPage(body)
+}
+
+func (hs *httpStrategy) description(s site, jobKey string) (string, error) {
+	body, err := hs.fetch(s.viewURL(jobKey))
+	if err != nil {
+		return "", err
+	}
+	return parseDescription(body)
+}
+
+func (hs *httpStrategy) close() {}
+
+func (hs *httpStrategy) fetch(pageURL string) (string, error) {
+	c := colly.NewCollector(colly.UserAgent(hs.userAgent), colly.AllowURLRevisit())
+	c.WithTransport(hs.transport)
+	c.SetRequestTimeout(30 * time.Second)
+
+	var body []byte
+	status := 0
+	c.OnResponse(func(r *colly.Response) {
+		body, status = r.Body, r.StatusCode
+	})
+	c.OnError(func(r *colly.Response, err error) {
+		if r != nil {
+			status = r.StatusCode
+		}
+	})
+
+	err := c.Visit(pageURL)
+	switch {
+	case status == http.StatusForbidden || status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable:
+		return "", fmt.Errorf("%w: HTTP %d from %s", errBlocked, status, pageURL)
+	case err != nil:
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	case isChallenge(string(body)):
+		return "", fmt.Errorf("%w: bot challenge at %s", errBlocked, pageURL)
+	}
+	return string(body), nil
+}
+
+// chromeStrategy renders pages in the process-wide headless browser pool
+type chromeStrategy struct {
+	pool      *browser.Pool
+	userAgent string
+	interval  time.Duration
+}
+
+func (cs *chromeStrategy) name() string { return "headless_chrome" }
+
+func (cs *chromeStrategy) search(s site, query string, start int) ([]card, error) {
+	body, err := cs.render(s.searchURL(query, start), searchSelector)
+	if err != nil {
+		return nil, err
+	}
+	return parseSearchPage(body)
+}
+
+func (cs *chromeStrategy) description(s site, jobKey string) (string, error) {
+	body, err := cs.render(s.viewURL(jobKey), descriptionSelector)
+	if err != nil {
+		return "", err
+	}
+	return parseDescription(body)
+}
+
+func (cs *chromeStrategy) render(pageURL, waitSelector string) (string, error) {
+	ctx := context.Background()
+	if !httpclient.Allowed(ctx, pageURL, cs.userAgent) {
+		return "", fmt.Errorf("%w: %s", httpclient.ErrDisallowed, pageURL)
+	}
+	if err := httpclient.Wait(ctx, pageURL, cs.interval); err != nil {
+		return "", err
+	}
+
+	body, err := cs.pool.Render(ctx, pageURL, waitSelector, 2*time.Second)
+	if err != nil {
+		return "", err
+	}
+	if isChallenge(body) {
+		return "", fmt.Errorf("%w: bot challenge at %s", errBlocked, pageURL)
+	}
+	return body, nil
+}
+
+// close is a no-op: the pool keeps Chrome for other connectors and shuts it down when idle
+func (cs *chromeStrategy) close() {}
+
+// Selectors shared by the HTTP and Chrome strategies (Indeed changes its markup often)
+const (
+	searchSelector      = `div.job_seen_beacon, td.resultContent, div.jobsearch-SerpJobCard`
+	descriptionSelector = `#jobDescriptionText, div.jobsearch-jobDescriptionText, div[id*='jobDesc']`
+)
+
+var (
+	jobKeyRe   = regexp.MustCompile(`jk=([a-zA-Z0-9]+)`)
+	relativeRe = regexp.MustCompile(`(\d+)\+?\s*(?:days?|dagar?|tagen?|jours?|dagen)`)
+	todayRe    = regexp.MustCompile(`(?i)\b(?:today|just posted|idag|nyss|heute|aujourd'hui|vandaag)\b`)
+)
+
+// parseSearchPage extracts the job cards of a search results page
+func parseSearchPage(body string) ([]card, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse search page: %w", err)
+	}
+
+	cards := []card{}
+	seen := map[string]bool{}
+	doc.Find(searchSelector).Each(func(_ int, e *goquery.Selection) {
+		jobKey := cardJobKey(e)
+		// td.resultContent is nested inside div.job_seen_beacon
+		if jobKey == "" || seen[jobKey] {
+			return
+		}
+
+		title := firstText(e, "h2.jobTitle span[title]", "h2.jobTitle", "a[data-jk] span")
+		if t, ok := e.Find("h2.jobTitle span[title]").Attr("title"); ok && t != "" {
+			title = t
+		}
+		if title == "" {
+			return
+		}
+		seen[jobKey] = true
+
+		cards = append(cards, card{
+			JobKey:   jobKey,
+			Title:    title,
+			Company:  firstText(e, "span.companyName", "span[data-testid='company-name']"),
+			Location: firstText(e, "div.companyLocation", "div[data-testid='text-location']"),
+			Snippet:  firstText(e, "div.job-snippet", "div[class*='snippet']", "ul li"),
+			Salary:   firstText(e, "div.salary-snippet", "div[class*='salary']", "span[class*='salary']"),
+			Posted:   parseRelativeDate(firstText(e, "span.date", "span[data-testid='myJobsStateDate']")),
+		})
+	})
+	return cards, nil
+}
+
+// parseDescription extracts the ad text of a viewjob page
+func parseDescription(body string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse job page: %w", err)
+	}
+	return cleanText(doc.Find(descriptionSelector).First().Text()), nil
+}
+
+func cardJobKey(e *goquery.Selection) string {
+	if jk, ok := e.Attr("data-jk"); ok && jk != "" {
+		return jk
+	}
+	if jk, ok := e.Find("a[data-jk]").Attr("data-jk"); ok && jk != "" {
+		return jk
+	}
+	if href, ok := e.Find("a[href*='jk=']").Attr("href"); ok {
+		if m := jobKeyRe.FindStringSubmatch(href); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func firstText(e *goquery.Selection, selectors ...string) string {
+	for _, sel := range selectors {
+		if text := strings.TrimSpace(e.Find(sel).First().Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// isChallenge spots Cloudflare and captcha interstitials served with a 200
+func isChallenge(body string) bool {
+	lower := strings.ToLower(body)
+	return strings.Contains(lower, "cf-challenge") || strings.Contains(lower, "challenge-platform") ||
+		strings.Contains(lower, "<title>just a moment") || strings.Contains(lower, "hcaptcha")
+}
+
+// parseRelativeDate reads "Posted 3 days ago" / "Publicerad för 30+ dagar sedan" style
+// ages. They only give the day, so the result is midnight UTC of that day.
+func parseRelativeDate(text string) time.Time {
+	if text == "" {
+		return time.Time{}
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	if todayRe.MatchString(text) {
+		return today
+	}
+	if m := relativeRe.FindStringSubmatch(strings.ToLower(text)); m != nil {
+		if days, err := strconv.Atoi(m[1]); err == nil {
+			return today.AddDate(0, 0, -days)
+		}
+	}
+	return time.Time{}
+}
+
+// parseAPIDate parses the publisher API's RFC1123 dates
+func parseAPIDate(dateStr string) time.Time {
+	for _, format := range []string{time.RFC1123, time.RFC1123Z, time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(format, dateStr); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -11,7 +11,10 @@ import (
 	"strings"
 	"time"
 
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 )
 
@@ -67,35 +70,40 @@ func NewJoobleConnector(store *storage.JobStore) *JoobleConnector {
 		store:      store,
 		baseURL:    "https://jooble.org/api",
 		apiKey:     os.Getenv("JOOBLE_API_KEY"),
-		userAgent:  "OpenJobs-Jooble-Connector/1.0",
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  httpclient.UserAgent("OpenJobs-Jooble-Connector/1.0"),
+		httpClient: httpclient.New(httpclient.Options{Interval: 2 * time.Second}),
 	}
 }
 
+// searchQueries are the Jooble searches for diverse coverage; each keeps its own checkpoint
+var searchQueries = []string{
+	"developer",
+	"engineer",
+	"designer",
+	"manager",
+	"sales",
+	"marketing",
+}
+
 // FetchJobs fetches job listings from Jooble API
 func (jc *JoobleConnector) FetchJobs() ([]models.JobPost, error) {
+	_, jobs := jc.fetchQueries()
+	return jobs, nil
+}
+
+// fetchQueries runs every search and returns the new jobs of each query (posted since
+// its own checkpoint) and all of them deduplicated
+func (jc *JoobleConnector) fetchQueries() (map[string][]models.JobPost, []models.JobPost) {
+	byQuery := map[string][]models.JobPost{}
 	allJobs := []models.JobPost{}
 
 	// If API key not configured, return demo data
 	if jc.apiKey == "" {
 		fmt.Println("⚠️  JOOBLE_API_KEY not set - returning demo data")
-		return jc.getDemoJobs(), nil
-	}
-
-	// Get last sync time for incremental sync
-	lastSync := jc.getLastSyncTime()
-
-	// Search queries for diverse coverage
-	queries := []string{
-		"developer",
-		"engineer",
-		"designer",
-		"manager",
-		"sales",
-		"marketing",
+		return byQuery, jc.getDemoJobs()
 	}
 
-	for _, query := range queries {
+	for _, query := range searchQueries {
 		fmt.Printf("🔍 Fetching Jooble jobs for: '%s'\n", query)
 
 		jobs, err := jc.searchJobs(query, "Stockholm")
@@ -103,25 +111,23 @@ func (jc *JoobleConnector) FetchJobs() ([]models.JobPost, error) {
 			fmt.Printf("⚠️  Error fetching jobs for '%s': %v\n", query, err)
 			continue
 		}
-
 		fmt.Printf("   ✅ Found %d jobs for '%s'\n", len(jobs), query)
-		allJobs = append(allJobs, jobs...)
 
-		// Rate limiting - be respectful
-		time.Sleep(2 * time.Second)
-	}
+		// Filter by date if the query has a checkpoint (client-side filtering)
+		if lastSync := jc.getLastSyncTime(query); !lastSync.IsZero() {
+			jobs = jc.filterJobsByDate(jobs, lastSync)
+			fmt.Printf("📅 Filtered to %d jobs posted after %s\n", len(jobs), lastSync.Format("2006-01-02"))
+		}
 
-	// Filter by date if we have a last sync time (client-side filtering)
-	if !lastSync.IsZero() {
-		allJobs = jc.filterJobsByDate(allJobs, lastSync)
-		fmt.Printf("📅 Filtered to %d jobs posted after %s\n", len(allJobs), lastSync.Format("2006-01-02"))
+		byQuery[query] = jobs
+		allJobs = append(allJobs, jobs...)
 	}
 
 	// Deduplicate by ID
 	uniqueJobs := jc.deduplicateJobs(allJobs)
 	fmt.Printf("📊 Fetched %d unique jobs from Jooble (filtered from %d total)\n", len(uniqueJobs), len(allJobs))
 
-	return uniqueJobs, nil
+	return byQuery, uniqueJobs
 }
 
 // searchJobs performs a job search via Jooble API
@@ -175,6 +181,9 @@ func (jc *JoobleConnector) searchJobs(keywords, location string) ([]models.JobPo
 	// Transform to JobPost format
 	jobs := make([]models.JobPost, 0, len(joobleResp.Jobs))
 	for _, jJob := range joobleResp.Jobs {
+		if jJob.ID == 0 && strings.TrimSpace(jJob.Link) == "" {
+			continue // Nothing to derive a stable ID from
+		}
 		job := jc.transformJoobleJob(jJob)
 		jobs = append(jobs, job)
 	}
@@ -184,13 +193,10 @@ func (jc *JoobleConnector) searchJobs(keywords, location string) ([]models.JobPo
 
 // transformJoobleJob converts Jooble job format to JobPost
 func (jc *JoobleConnector) transformJoobleJob(jj JoobleJob) models.JobPost {
-	// Generate unique ID from numeric ID or fallback to link hash
-	var jobID string
+	// Jooble's numeric ID when it has one, otherwise a hash of the canonical link
+	jobID := jobid.URLHash(jj.Link)
 	if jj.ID != 0 {
 		jobID = fmt.Sprintf("%d", jj.ID)
-	} else {
-		// Fallback: hash of link
-		jobID = jc.generateJobID(jj.Link)
 	}
 
 	// Parse posted date from updated field
@@ -199,8 +205,8 @@ func (jc *JoobleConnector) transformJoobleJob(jj JoobleJob) models.JobPost {
 	// Clean description
 	description := jc.cleanText(jj.Snippet)
 
-	return models.JobPost{
-		ID:              fmt.Sprintf("jooble-%s", jobID),
+	job := models.JobPost{
+		ID:              jobid.New("jooble", jobID),
 		Title:           strings.TrimSpace(jj.Title),
 		Company:         strings.TrimSpace(jj.Company),
 		Description:     description,
@@ -227,24 +233,10 @@ func (jc *JoobleConnector) transformJoobleJob(jj JoobleJob) models.JobPost {
 			"fetched_at":     time.Now(),
 		},
 	}
-}
 
-// generateJobID creates a simple hash from URL
-func (jc *JoobleConnector) generateJobID(url string) string {
-	// Simple hash: take last part of URL or use timestamp
-	parts := strings.Split(url, "/")
-	if len(parts) > 0 {
-		lastPart := parts[len(parts)-1]
-		// Remove query params
-		if idx := strings.Index(lastPart, "?"); idx != -1 {
-			lastPart = lastPart[:idx]
-		}
-		if lastPart != "" {
-			return lastPart
-		}
-	}
-	// Fallback to timestamp
-	return fmt.Sprintf("%d", time.Now().Unix())
+	// Salary snippets of Swedish ads are monthly unless stated
+	salary.Parse(job.Salary, "SEK", salary.Month).Apply(&job)
+	return job
 }
 
 // parseJoobleDate parses Jooble date format
@@ -306,16 +298,23 @@ func (jc *JoobleConnector) cleanText(text string) string {
 	return text
 }
 
-// getLastSyncTime retrieves the timestamp of the most recent job in database
-func (jc *JoobleConnector) getLastSyncTime() time.Time {
-	job, err := jc.store.GetMostRecentJob("jooble-")
+// getLastSyncTime returns the high watermark committed for a query by the last successful sync
+func (jc *JoobleConnector) getLastSyncTime(query string) time.Time {
+	if jc.store == nil {
+		return time.Time{}
+	}
+	checkpoint, err := jc.store.GetCheckpoint(jc.GetID(), query)
 	if err != nil {
-		fmt.Println(" No previous Jooble jobs found - fetching all jobs")
+		fmt.Printf("⚠️  Failed to read Jooble '%s' checkpoint, keeping all jobs: %v\n", query, err)
 		return time.Time{}
 	}
-	
-	fmt.Printf(" Last Jooble job in database: %s (posted: %s)\n", job.Title, job.PostedDate.Format("2006-01-02"))
-	return job.PostedDate
+	if checkpoint.HighWatermark.IsZero() {
+		fmt.Printf("📅 No Jooble '%s' checkpoint yet - keeping all jobs\n", query)
+		return time.Time{}
+	}
+
+	fmt.Printf("📅 Jooble '%s' checkpoint: jobs posted up to %s\n", query, checkpoint.HighWatermark.Format("2006-01-02"))
+	return checkpoint.HighWatermark
 }
 
 // filterJobsByDate filters jobs to only include those posted after the given date
@@ -411,7 +410,7 @@ func (jc *JoobleConnector) deduplicateJobs(jobs []models.JobPost) []models.JobPo
 func (jc *JoobleConnector) getDemoJobs() []models.JobPost {
 	return []models.JobPost{
 		{
-			ID:              "jooble-demo-1",
+			ID:              jobid.New("demo-jooble", "demo-1"),
 			Title:           "Senior Full Stack Developer",
 			Company:         "Tech Company AB",
 			Description:     "We are looking for an experienced Full Stack Developer to join our team. You will work with React, Node.js, and PostgreSQL.",
@@ -435,7 +434,7 @@ func (jc *JoobleConnector) getDemoJobs() []models.JobPost {
 			},
 		},
 		{
-			ID:              "jooble-demo-2",
+			ID:              jobid.New("demo-jooble", "demo-2"),
 			Title:           "DevOps Engineer",
 			Company:         "Cloud Solutions AB",
 			Description:     "Join our DevOps team! Experience with Kubernetes, Docker, and AWS required.",
@@ -466,30 +465,19 @@ func (jc *JoobleConnector) SyncJobs() error {
 	startTime := time.Now()
 	fmt.Println("🔄 Starting Jooble job aggregator sync...")
 
-	jobs, err := jc.FetchJobs()
-	if err != nil {
-		// Log failed sync
-		jc.store.LogSync(&models.SyncLog{
-			ConnectorName:  jc.GetID(),
-			StartedAt:      startTime,
-			CompletedAt:    time.Now(),
-			JobsFetched:    0,
-			JobsInserted:   0,
-			JobsDuplicates: 0,
-			Status:         "failed",
-		})
-		return fmt.Errorf("failed to fetch jobs from Jooble: %w", err)
-	}
+	byQuery, jobs := jc.fetchQueries()
 
 	fmt.Printf("📥 Fetched %d jobs from Jooble\n", len(jobs))
 
 	stored := 0
 	duplicates := 0
+	failed := 0
 	for _, job := range jobs {
 		// Check if job already exists
 		existing, err := jc.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -502,6 +490,7 @@ func (jc *JoobleConnector) SyncJobs() error {
 		err = jc.store.CreateJob(&job)
 		if err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -509,6 +498,20 @@ func (jc *JoobleConnector) SyncJobs() error {
 		fmt.Printf("✅ Stored job: %s at %s (%s)\n", job.Title, job.Company, job.Location)
 	}
 
+	// Commit each query's checkpoint only once every job is stored, so failed jobs are
+	// retried. Demo data (no API key) has no queries, so it commits nothing.
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else {
+		for _, query := range searchQueries {
+			if queryJobs, ok := byQuery[query]; ok {
+				if err := jc.store.AdvanceCheckpoint(jc.GetID(), query, queryJobs); err != nil {
+					fmt.Printf("⚠️  Failed to commit '%s' checkpoint: %v\n", query, err)
+				}
+			}
+		}
+	}
+
 	// Log successful sync
 	if err := jc.store.LogSync(&models.SyncLog{
 		ConnectorName:  jc.GetID(),
@@ -0,0 +1,93 @@
+package jooble
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *JoobleConnector {
+	rec := httpreplay.New(t, "testdata/search.json")
+
+	jc := NewJoobleConnector(store)
+	if jc.apiKey == "" {
+		jc.apiKey = "test"
+	}
+	rec.Redact(jc.apiKey) // The key is part of the URL path
+	jc.httpClient = rec.Client()
+	return jc
+}
+
+// TestFetchJobsGolden replays recorded searches, including duplicates across queries and
+// a rate-limited query. Re-record with HTTPREPLAY=record JOOBLE_API_KEY=... go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	jc := newReplayConnector(t, nil)
+
+	jobs, err := jc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs)
+}
+
+// TestSyncCheckpointPerQuery checks that every query keeps its own watermark, so a
+// newer ad from one query doesn't filter out the older new ads of another
+func TestSyncCheckpointPerQuery(t *testing.T) {
+	results := map[string]string{
+		"developer": `{"id": 1, "title": "Go Developer", "updated": "2025-10-05T08:00:00Z"}`,
+		"sales":     `{"id": 2, "title": "Account Executive", "updated": "2025-10-01T08:00:00Z"}`,
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req JoobleRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"totalCount": 1, "jobs": [%s]}`, results[req.Keywords])
+	}))
+	defer api.Close()
+
+	server := storagetest.NewServer(t)
+	jc := NewJoobleConnector(server.Store())
+	jc.baseURL, jc.apiKey = api.URL, "test"
+	jc.httpClient = httpclient.New(httpclient.Options{})
+
+	if err := jc.SyncJobs(); err != nil {
+		t.Fatalf("First SyncJobs failed: %v", err)
+	}
+	if got := jc.getLastSyncTime("sales"); !got.Equal(time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("sales checkpoint = %v, want its own newest ad", got)
+	}
+
+	// Older than the newest developer ad, but new for sales
+	results["sales"] = `{"id": 3, "title": "Sales Manager", "updated": "2025-10-03T08:00:00Z"}`
+	if err := jc.SyncJobs(); err != nil {
+		t.Fatalf("Second SyncJobs failed: %v", err)
+	}
+	if got := len(server.Jobs()); got != 3 {
+		t.Errorf("Expected 3 stored jobs, got %d", got)
+	}
+}
+
+func TestDemoJobsWithoutAPIKey(t *testing.T) {
+	jc := NewJoobleConnector(nil)
+	jc.apiKey = ""
+	jobs, err := jc.FetchJobs()
+	if err != nil || len(jobs) == 0 {
+		t.Fatalf("Expected demo jobs without an API key, got %d (%v)", len(jobs), err)
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
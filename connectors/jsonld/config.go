@@ -0,0 +1,115 @@
+package jsonld
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config lists the career pages and sitemaps to crawl for JobPosting JSON-LD
+type Config struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Source         string   `json:"source"` // Job ID prefix and fields.source
+	SeedURLs       []string `json:"seed_urls"`
+	Sitemaps       []string `json:"sitemaps"`
+	AllowedDomains []string `json:"allowed_domains"`
+	FollowLinks    string   `json:"follow_links"`   // Regex of links to follow from seed pages
+	SitemapFilter  string   `json:"sitemap_filter"` // Regex of sitemap URLs to visit
+	MaxDepth       int      `json:"max_depth"`
+	MaxPages       int      `json:"max_pages"`
+	DelayMillis    int      `json:"delay_ms"`
+	RandomDelayMs  int      `json:"random_delay_ms"`
+	UserAgent      string   `json:"user_agent"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+
+	followRe  *regexp.Regexp
+	sitemapRe *regexp.Regexp
+}
+
+// LoadConfig reads a crawler config from a .json, .yaml or .yml file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML config %s: %w", path, err)
+		}
+		if data, err = json.Marshal(generic); err != nil {
+			return nil, fmt.Errorf("failed to convert YAML config %s: %w", path, err)
+		}
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Validate checks required settings, compiles regexes and fills in defaults
+func (c *Config) Validate() error {
+	if len(c.SeedURLs) == 0 && len(c.Sitemaps) == 0 {
+		return fmt.Errorf("seed_urls or sitemaps is required")
+	}
+	if c.ID == "" {
+		c.ID = "jsonld"
+	}
+	if c.Name == "" {
+		c.Name = "Schema.org JobPosting Crawler"
+	}
+	if c.Source == "" {
+		c.Source = c.ID
+	}
+	if c.UserAgent == "" {
+		c.UserAgent = "OpenJobs-JSONLD-Crawler/1.0 (+https://github.com/magnusfroste/openjobs)"
+	}
+	if c.MaxDepth <= 0 {
+		c.MaxDepth = 2
+	}
+	if c.MaxPages <= 0 {
+		c.MaxPages = 500
+	}
+	if c.DelayMillis <= 0 {
+		c.DelayMillis = 1000 // Employer sites are small - keep it gentle
+	}
+
+	// Stay on the seed and sitemap hosts unless told otherwise
+	if len(c.AllowedDomains) == 0 {
+		for _, raw := range append(append([]string{}, c.SeedURLs...), c.Sitemaps...) {
+			u, err := url.Parse(raw)
+			if err != nil || u.Hostname() == "" {
+				return fmt.Errorf("invalid URL %q", raw)
+			}
+			c.AllowedDomains = append(c.AllowedDomains, u.Hostname())
+		}
+	}
+
+	var err error
+	if c.FollowLinks != "" {
+		if c.followRe, err = regexp.Compile(c.FollowLinks); err != nil {
+			return fmt.Errorf("invalid follow_links regex: %w", err)
+		}
+	}
+	if c.SitemapFilter != "" {
+		if c.sitemapRe, err = regexp.Compile(c.SitemapFilter); err != nil {
+			return fmt.Errorf("invalid sitemap_filter regex: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package jsonld
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// JSONLDConnector crawls employer career pages for schema.org JobPosting JSON-LD
+type JSONLDConnector struct {
+	store     *storage.JobStore
+	config    *Config
+	transport http.RoundTripper // Replaces the polite transport when set (tests)
+}
+
+// NewJSONLDConnector creates a new crawler connector from a validated config
+func NewJSONLDConnector(store *storage.JobStore, config *Config) *JSONLDConnector {
+	return &JSONLDConnector{
+		store:  store,
+		config: config,
+	}
+}
+
+// GetID returns the connector ID
+func (jc *JSONLDConnector) GetID() string {
+	return jc.config.ID
+}
+
+// GetName returns the connector name
+func (jc *JSONLDConnector) GetName() string {
+	return jc.config.Name
+}
+
+// FetchJobs crawls seed pages and sitemaps and extracts every JobPosting found
+func (jc *JSONLDConnector) FetchJobs() ([]models.JobPost, error) {
+	cfg := jc.config
+
+	var mu sync.Mutex
+	jobs := []models.JobPost{}
+	seen := map[string]bool{}
+	pages := 0
+
+	c := colly.NewCollector(
+		colly.UserAgent(cfg.UserAgent),
+		colly.AllowedDomains(cfg.AllowedDomains...),
+		colly.MaxDepth(cfg.MaxDepth),
+	)
+	// Employer sites are crawled directly, so honour their robots.txt
+	transport := jc.transport
+	if transport == nil {
+		transport = httpclient.NewTransport(httpclient.Options{
+			UserAgent:     cfg.UserAgent,
+			Interval:      time.Duration(cfg.DelayMillis) * time.Millisecond,
+			RespectRobots: true,
+		})
+	}
+	c.WithTransport(transport)
+
+	timeout := 30 * time.Second
+	if cfg.TimeoutSeconds > 0 {
+		timeout = time.Duration(cfg.TimeoutSeconds) * time.Second
+	}
+	c.SetRequestTimeout(timeout)
+	c.Limit(&colly.LimitRule{
+		DomainGlob:  "*",
+		RandomDelay: time.Duration(cfg.RandomDelayMs) * time.Millisecond,
+		Parallelism: 1,
+	})
+
+	c.OnRequest(func(r *colly.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if pages >= cfg.MaxPages {
+			r.Abort()
+			return
+		}
+		pages++
+	})
+
+	c.OnError(func(r *colly.Response, err error) {
+		fmt.Printf("⚠️  Failed to crawl %s: %v\n", r.Request.URL, err)
+	})
+
+	// Sitemap index → nested sitemaps, urlset → pages
+	c.OnXML("//sitemapindex/sitemap/loc", func(e *colly.XMLElement) {
+		e.Request.Visit(strings.TrimSpace(e.Text))
+	})
+	c.OnXML("//urlset/url/loc", func(e *colly.XMLElement) {
+		loc := strings.TrimSpace(e.Text)
+		if cfg.sitemapRe == nil || cfg.sitemapRe.MatchString(loc) {
+			e.Request.Visit(loc)
+		}
+	})
+
+	c.OnHTML(`script[type="application/ld+json"]`, func(e *colly.HTMLElement) {
+		pageURL := e.Request.URL.String()
+		for _, posting := range extractPostings(e.Text) {
+			job, ok := postingToJob(cfg, posting, pageURL)
+			if !ok {
+				continue
+			}
+
+			mu.Lock()
+			if !seen[job.ID] {
+				seen[job.ID] = true
+				jobs = append(jobs, job)
+			}
+			mu.Unlock()
+		}
+	})
+
+	// Follow career page links that match follow_links
+	if cfg.followRe != nil {
+		c.OnHTML("a[href]", func(e *colly.HTMLElement) {
+			link := e.Request.AbsoluteURL(e.Attr("href"))
+			if link != "" && cfg.followRe.MatchString(link) {
+				e.Request.Visit(link)
+			}
+		})
+	}
+
+	visited := 0
+	for _, start := range append(append([]string{}, cfg.Sitemaps...), cfg.SeedURLs...) {
+		if err := c.Visit(start); err != nil {
+			fmt.Printf("⚠️  Could not crawl %s: %v\n", start, err)
+			continue
+		}
+		visited++
+	}
+	c.Wait()
+
+	if visited == 0 {
+		return nil, fmt.Errorf("none of the %d seed URLs and sitemaps could be crawled", len(cfg.SeedURLs)+len(cfg.Sitemaps))
+	}
+
+	// Postings past validThrough are already closed
+	open := []models.JobPost{}
+	for _, job := range jobs {
+		if job.ExpiresDate.IsZero() || job.ExpiresDate.After(time.Now()) {
+			open = append(open, job)
+		}
+	}
+
+	fmt.Printf("📊 Found %d open JobPostings on %d pages (%d expired)\n", len(open), pages, len(jobs)-len(open))
+	return open, nil
+}
+
+// SyncJobs crawls the configured sites and stores new jobs
+func (jc *JSONLDConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Printf("🔄 Starting %s sync...\n", jc.GetName())
+
+	jobs, err := jc.FetchJobs()
+	if err != nil {
+		jc.store.LogSync(&models.SyncLog{
+			ConnectorName: jc.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "failed",
+		})
+		return fmt.Errorf("failed to crawl JobPostings: %w", err)
+	}
+
+	fmt.Printf("📥 Extracted %d jobs from JSON-LD\n", len(jobs))
+
+	stored := 0
+	duplicates := 0
+	for _, job := range jobs {
+		existing, err := jc.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := jc.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	if err := jc.store.LogSync(&models.SyncLog{
+		ConnectorName:  jc.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 %s sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", jc.GetName(), len(jobs), stored, duplicates)
+	return nil
+}
@@ -0,0 +1,162 @@
+package jsonld
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+// newCareerSite serves a sitemap, a careers page and job pages with JSON-LD
+func newCareerSite() *httptest.Server {
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>%[1]s/jobs/1</loc></url>
+  <url><loc>%[1]s/jobs/2</loc></url>
+  <url><loc>%[1]s/about</loc></url>
+</urlset>`, server.URL)
+	})
+	mux.HandleFunc("/careers", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><a href="/jobs/3">Backend</a><a href="/blog">Blog</a></body></html>`)
+	})
+	mux.HandleFunc("/jobs/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><script type="application/ld+json">{
+			"@context": "https://schema.org", "@type": "JobPosting",
+			"title": "Go Developer", "description": "<p>Build &amp; run APIs</p>",
+			"identifier": {"@type": "PropertyValue", "name": "Acme", "value": "A-1"},
+			"hiringOrganization": {"@type": "Organization", "name": "Acme AB"},
+			"datePosted": "2025-10-01", "validThrough": "2099-01-01T00:00",
+			"employmentType": ["FULL_TIME"],
+			"jobLocation": {"@type": "Place", "address": {"addressLocality": "Göteborg", "addressCountry": "SE"}},
+			"baseSalary": {"@type": "MonetaryAmount", "currency": "sek",
+				"value": {"@type": "QuantitativeValue", "minValue": 45000, "maxValue": "55000", "unitText": "MONTH"}}
+		}</script></head><body></body></html>`)
+	})
+	mux.HandleFunc("/jobs/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><script type="application/ld+json">{"@graph": [
+			{"@type": "WebPage", "name": "Jobs"},
+			{"@type": "JobPosting", "title": "Expired role", "validThrough": "2020-01-01"}
+		]}</script></head></html>`)
+	})
+	mux.HandleFunc("/jobs/3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><head><script type="application/ld+json">[{
+			"@type": "JobPosting", "title": "Backend Engineer", "hiringOrganization": "Acme AB",
+			"jobLocationType": "TELECOMMUTE", "employmentType": "CONTRACTOR",
+			"baseSalary": {"currency": "EUR", "value": 70000}
+		}]</script></head></html>`)
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	server = httptest.NewServer(mux)
+	return server
+}
+
+func TestCrawlExtractsJobPostings(t *testing.T) {
+	server := newCareerSite()
+	defer server.Close()
+
+	config := &Config{
+		ID:            "acme-careers",
+		Source:        "acme",
+		SeedURLs:      []string{server.URL + "/careers"},
+		Sitemaps:      []string{server.URL + "/sitemap.xml"},
+		FollowLinks:   `/jobs/`,
+		SitemapFilter: `/jobs/`,
+		DelayMillis:   1,
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() failed: %v", err)
+	}
+
+	jobs, err := NewJSONLDConnector(nil, config).FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs() failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 open jobs (expired one dropped), got %d: %+v", len(jobs), jobs)
+	}
+
+	byTitle := map[string]int{}
+	for i, job := range jobs {
+		byTitle[job.Title] = i
+	}
+
+	dev := jobs[byTitle["Go Developer"]]
+	if dev.Company != "Acme AB" || dev.Location != "Göteborg, SE" || dev.EmploymentType != "Full-time" {
+		t.Errorf("Unexpected mapping: %+v", dev)
+	}
+	if dev.SalaryMin == nil || *dev.SalaryMin != 45000 || dev.SalaryMax == nil || *dev.SalaryMax != 55000 || dev.SalaryCurrency != "SEK" {
+		t.Errorf("Unexpected salary: %v %v %q", dev.SalaryMin, dev.SalaryMax, dev.SalaryCurrency)
+	}
+	if dev.Salary != "45000-55000 SEK/MONTH" || dev.Description != "Build & run APIs" {
+		t.Errorf("Unexpected salary text/description: %q %q", dev.Salary, dev.Description)
+	}
+	if dev.ExpiresDate.Year() != 2099 || dev.PostedDate.Day() != 1 || dev.Fields["original_id"] != "A-1" {
+		t.Errorf("Unexpected dates/ID fields: %v %v %v", dev.ExpiresDate, dev.PostedDate, dev.Fields)
+	}
+
+	backend, ok := byTitle["Backend Engineer"]
+	if !ok {
+		t.Fatal("Expected the job linked from the careers page")
+	}
+	if job := jobs[backend]; !job.IsRemote || job.Location != "Remote" || job.EmploymentType != "Contract" || *job.SalaryMin != 70000 {
+		t.Errorf("Unexpected remote job mapping: %+v", job)
+	}
+
+	// IDs are stable across crawls
+	again, _ := NewJSONLDConnector(nil, config).FetchJobs()
+	for _, job := range again {
+		if jobs[byTitle[job.Title]].ID != job.ID {
+			t.Errorf("ID for %q changed between crawls", job.Title)
+		}
+	}
+}
+
+func TestExampleConfigLoads(t *testing.T) {
+	if _, err := LoadConfig("examples/employers.yaml"); err != nil {
+		t.Fatalf("LoadConfig() failed: %v", err)
+	}
+}
+
+// newExampleConnector crawls the example employer sites from the fixture
+func newExampleConnector(t *testing.T, store *storage.JobStore) *JSONLDConnector {
+	rec := httpreplay.New(t, "testdata/employers.json")
+
+	cfg, err := LoadConfig("examples/employers.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.RandomDelayMs = 0
+	connector := NewJSONLDConnector(store, cfg)
+	connector.transport = rec
+	return connector
+}
+
+// TestExampleConfigGolden replays a crawl of the example employer sites
+func TestExampleConfigGolden(t *testing.T) {
+	connector := newExampleConnector(t, nil)
+
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/employers.golden.json", jobs)
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newExampleConnector(t, store)
+	}, conformance.Options{})
+}
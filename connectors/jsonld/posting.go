@@ -0,0 +1,352 @@
+package jsonld
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+)
+
+// htmlTags strips markup from JSON-LD descriptions
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// postingDateFormats are the date layouts seen in datePosted/validThrough
+var postingDateFormats = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// extractPostings returns every JobPosting object in a JSON-LD script body.
+// Handles single objects, arrays and @graph containers.
+func extractPostings(raw string) []map[string]interface{} {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &doc); err != nil {
+		return nil
+	}
+
+	postings := []map[string]interface{}{}
+	var walk func(node interface{})
+	walk = func(node interface{}) {
+		switch v := node.(type) {
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		case map[string]interface{}:
+			if isType(v, "JobPosting") {
+				postings = append(postings, v)
+				return
+			}
+			if graph, ok := v["@graph"]; ok {
+				walk(graph)
+			}
+		}
+	}
+	walk(doc)
+	return postings
+}
+
+// isType reports whether a JSON-LD node has the given @type (string or array)
+func isType(node map[string]interface{}, typeName string) bool {
+	for _, t := range strs(node["@type"]) {
+		if t == typeName || strings.HasSuffix(t, "/"+typeName) {
+			return true
+		}
+	}
+	return false
+}
+
+// postingToJob maps a JobPosting to our JobPost format
+func postingToJob(cfg *Config, posting map[string]interface{}, pageURL string) (models.JobPost, bool) {
+	title := cleanText(str(posting["title"]))
+	if title == "" {
+		return models.JobPost{}, false
+	}
+
+	jobURL := str(posting["url"])
+	if jobURL == "" {
+		jobURL = pageURL
+	}
+
+	// Identifiers are only unique per employer, so they are hashed with the site host
+	host := ""
+	if u, err := url.Parse(jobURL); err == nil {
+		host = u.Hostname()
+	}
+	originalID := identifier(posting["identifier"])
+	var hash string
+	if originalID != "" {
+		sum := sha1.Sum([]byte(host + "|" + originalID))
+		hash = hex.EncodeToString(sum[:])[:16]
+	} else {
+		hash = jobid.URLHash(jobURL)
+		originalID = hash // No identifier: the URL hash is the only stable ID
+	}
+
+	pay, salaryText := baseSalary(posting["baseSalary"])
+	location, country, remote := jobLocation(posting)
+
+	job := models.JobPost{
+		ID:             jobid.New(cfg.Source, hash),
+		Title:          title,
+		Company:        organization(posting["hiringOrganization"]),
+		Description:    cleanText(str(posting["description"])),
+		Location:       location,
+		Salary:         salaryText,
+		SalaryCurrency: pay.Currency,
+		IsRemote:       remote,
+		URL:            jobURL,
+		EmploymentType: employmentType(posting["employmentType"]),
+		PostedDate:     time.Now(),
+		Requirements:   strs(posting["skills"]),
+		Benefits:       strs(posting["jobBenefits"]),
+		Fields: map[string]interface{}{
+			"source":      cfg.Source,
+			"source_url":  pageURL,
+			"original_id": originalID,
+			"connector":   cfg.ID,
+			"fetched_at":  time.Now(),
+			"method":      "json_ld",
+		},
+	}
+	pay.Apply(&job)
+
+	if posted, ok := parsePostingDate(str(posting["datePosted"])); ok {
+		job.PostedDate = posted
+	} else {
+		job.Fields["posted_date_estimated"] = true
+	}
+	if expires, ok := parsePostingDate(str(posting["validThrough"])); ok {
+		job.ExpiresDate = expires
+	}
+	if country != "" {
+		job.Fields["location_country"] = country
+	}
+	if industry := str(posting["industry"]); industry != "" {
+		job.Fields["industry"] = industry
+	}
+	if level := str(posting["experienceRequirements"]); level != "" {
+		job.ExperienceLevel = cleanText(level)
+	}
+
+	return job, true
+}
+
+// baseSalary reads a MonetaryAmount: value may be a number or a QuantitativeValue,
+// whose unitText (HOUR, MONTH, YEAR...) is the pay period
+func baseSalary(v interface{}) (salary.Salary, string) {
+	amount, ok := v.(map[string]interface{})
+	if !ok {
+		return salary.Salary{}, ""
+	}
+	currency := strings.ToUpper(str(amount["currency"]))
+
+	var minValue, maxValue *int
+	unit := ""
+	switch value := amount["value"].(type) {
+	case map[string]interface{}:
+		minValue = num(value["minValue"])
+		maxValue = num(value["maxValue"])
+		if exact := num(value["value"]); exact != nil {
+			if minValue == nil {
+				minValue = exact
+			}
+			if maxValue == nil {
+				maxValue = exact
+			}
+		}
+		unit = strings.ToUpper(str(value["unitText"]))
+	default:
+		minValue = num(value)
+		maxValue = minValue
+	}
+
+	pay := salary.Salary{Min: minValue, Max: maxValue, Currency: currency, Period: salary.ParsePeriod(unit)}
+	if !pay.HasAmount() {
+		return pay, ""
+	}
+
+	text := ""
+	switch {
+	case minValue != nil && maxValue != nil && *minValue != *maxValue:
+		text = fmt.Sprintf("%d-%d %s", *minValue, *maxValue, currency)
+	case minValue != nil:
+		text = fmt.Sprintf("%d %s", *minValue, currency)
+	default:
+		text = fmt.Sprintf("%d %s", *maxValue, currency)
+	}
+	if unit != "" {
+		text += "/" + unit
+	}
+	return pay, strings.TrimSpace(text)
+}
+
+// jobLocation formats jobLocation addresses and detects TELECOMMUTE postings
+func jobLocation(posting map[string]interface{}) (string, string, bool) {
+	remote := strings.EqualFold(str(posting["jobLocationType"]), "TELECOMMUTE")
+
+	places := []interface{}{posting["jobLocation"]}
+	if list, ok := posting["jobLocation"].([]interface{}); ok {
+		places = list
+	}
+
+	locations := []string{}
+	country := ""
+	for _, p := range places {
+		place, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		address, ok := place["address"].(map[string]interface{})
+		if !ok {
+			if s := str(place["address"]); s != "" {
+				locations = append(locations, s)
+			}
+			continue
+		}
+
+		placeCountry := organization(address["addressCountry"])
+		if country == "" {
+			country = placeCountry
+		}
+		parts := []string{}
+		for _, part := range []string{str(address["addressLocality"]), str(address["addressRegion"]), placeCountry} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			locations = append(locations, strings.Join(parts, ", "))
+		}
+	}
+
+	location := strings.Join(locations, "; ")
+	if location == "" && remote {
+		location = "Remote"
+	}
+	return location, country, remote
+}
+
+// employmentType maps schema.org employment types to our format
+func employmentType(v interface{}) string {
+	types := strs(v)
+	if len(types) == 0 {
+		return ""
+	}
+	switch strings.ToUpper(strings.ReplaceAll(types[0], "-", "_")) {
+	case "FULL_TIME":
+		return "Full-time"
+	case "PART_TIME":
+		return "Part-time"
+	case "CONTRACTOR", "CONTRACT":
+		return "Contract"
+	case "TEMPORARY":
+		return "Temporary"
+	case "INTERN", "INTERNSHIP":
+		return "Internship"
+	default:
+		return types[0]
+	}
+}
+
+// organization returns the name of an Organization/Country node or a plain string
+func organization(v interface{}) string {
+	if node, ok := v.(map[string]interface{}); ok {
+		return cleanText(str(node["name"]))
+	}
+	return cleanText(str(v))
+}
+
+// identifier reads a PropertyValue identifier or a plain string/number
+func identifier(v interface{}) string {
+	if node, ok := v.(map[string]interface{}); ok {
+		return str(node["value"])
+	}
+	return str(v)
+}
+
+// parsePostingDate parses datePosted/validThrough values
+func parsePostingDate(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range postingDateFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// cleanText strips HTML tags and entities and collapses whitespace
+func cleanText(s string) string {
+	s = html.UnescapeString(htmlTags.ReplaceAllString(s, " "))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// str renders a JSON value as a string
+func str(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case []interface{}:
+		if len(val) > 0 {
+			return str(val[0])
+		}
+	}
+	return ""
+}
+
+// strs renders a string or array of strings as a slice
+func strs(v interface{}) []string {
+	result := []string{}
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if s := cleanText(str(item)); s != "" {
+				result = append(result, s)
+			}
+		}
+	case string:
+		for _, s := range strings.Split(val, ",") {
+			if s = cleanText(s); s != "" {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+// num reads a number or numeric string as *int
+func num(v interface{}) *int {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case string:
+		parsed, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(val), " ", ""), 64)
+		if err != nil {
+			return nil
+		}
+		f = parsed
+	default:
+		return nil
+	}
+	i := int(f)
+	return &i
+}
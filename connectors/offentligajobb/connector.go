@@ -2,30 +2,89 @@ package offentligajobb
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
+	"openjobs/pkg/browser"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 
-	"github.com/chromedp/chromedp"
+	"github.com/PuerkitoBio/goquery"
 )
 
-// OffentligaJobbConnector implements headless Chrome scraping for Offentliga Jobb
+// politeInterval is the minimum gap between requests to the site - it is a public
+// sector service, so keep it gentle
+const politeInterval = time.Second
+
+// OffentligaJobbConnector implements scraping of Swedish public sector jobs from offentligajobb.se.
+// Pages are fetched over plain HTTP; headless Chrome is only used when the site
+// blocks plain requests or renders the list client-side.
 type OffentligaJobbConnector struct {
-	store     *storage.JobStore
-	baseURL   string
-	rateLimit time.Duration
+	store      *storage.JobStore
+	baseURL    string
+	listPath   string
+	maxPages   int
+	userAgent  string
+	httpClient *http.Client
+
+	browser   *browser.Pool // Nil or disabled turns off the chromedp fallback
+	mu        sync.Mutex
+	useChrome bool // Set once plain HTTP has been blocked during a run
+}
+
+// listing is one job card on a search result page
+type listing struct {
+	id       string
+	url      string
+	title    string
+	employer string
+	location string
+	deadline time.Time
 }
 
-// NewOffentligaJobbConnector creates a new Chrome-based scraper connector
+var (
+	// Job ads live under /jobb/<slug>-<numeric id> (older ads: /annons/<id>)
+	jobPathRe  = regexp.MustCompile(`^/(?:jobb|annons|lediga-jobb)/(?:[^/?#]*?-)?(\d{4,})/?$`)
+	deadlineRe = regexp.MustCompile(`(?i)sista\s+ans[öo]kningsdag(?:en)?\s*:?\s*([0-9]{4}-[0-9]{2}-[0-9]{2}|[0-9]{1,2}\s+[a-zåäö]+\.?\s+[0-9]{4})`)
+	spacesRe   = regexp.MustCompile(`[ \t]+`)
+	newlinesRe = regexp.MustCompile(`\n{3,}`)
+)
+
+var swedishMonths = map[string]time.Month{
+	"jan": time.January, "januari": time.January,
+	"feb": time.February, "februari": time.February,
+	"mar": time.March, "mars": time.March,
+	"apr": time.April, "april": time.April,
+	"maj": time.May,
+	"jun": time.June, "juni": time.June,
+	"jul": time.July, "juli": time.July,
+	"aug": time.August, "augusti": time.August,
+	"sep": time.September, "sept": time.September, "september": time.September,
+	"okt": time.October, "oktober": time.October,
+	"nov": time.November, "november": time.November,
+	"dec": time.December, "december": time.December,
+}
+
+// NewOffentligaJobbConnector creates a new Offentliga Jobb connector
 func NewOffentligaJobbConnector(store *storage.JobStore) *OffentligaJobbConnector {
 	return &OffentligaJobbConnector{
-		store:     store,
-		baseURL:   "https://www.offentligajobb.se",
-		rateLimit: 3 * time.Second, // Be extra respectful with Chrome
+		store:      store,
+		baseURL:    "https://www.offentligajobb.se",
+		listPath:   "/lediga-jobb",
+		maxPages:   20,
+		userAgent:  httpclient.UserAgent("Mozilla/5.0 (compatible; OpenJobs/1.0)"),
+		httpClient: httpclient.New(httpclient.Options{Interval: politeInterval, RespectRobots: true}),
+		browser:    browser.Shared(),
 	}
 }
 
@@ -36,464 +95,406 @@ func (ojc *OffentligaJobbConnector) GetID() string {
 
 // GetName returns the connector name
 func (ojc *OffentligaJobbConnector) GetName() string {
-	return "Offentliga Jobb Chrome Scraper (Swedish Public Sector)"
+	return "Offentliga Jobb (Swedish Public Sector)"
 }
 
-// FetchJobs scrapes job listings from Offentliga Jobb using headless Chrome
+// FetchJobs walks the newest-first search pages and fetches the detail page of every new ad
 func (ojc *OffentligaJobbConnector) FetchJobs() ([]models.JobPost, error) {
-	allJobs := []models.JobPost{}
-	
-	// Search queries for diverse coverage
-	queries := []string{
-		"developer",
-		"engineer",
-		"designer",
-		"manager",
-		"sales",
-		"marketing",
-	}
-	
-	// Get existing job IDs for incremental sync (database-based)
-	existingIDs := icc.getExistingJobIDs()
-	
-	// Create shared Chrome context for all pages (reuse to save memory)
-	opts := []chromedp.ExecAllocatorOption{
-		chromedp.NoSandbox,
-		chromedp.DisableGPU,
-		chromedp.Flag("disable-dev-shm-usage", true),
-		chromedp.Flag("disable-setuid-sandbox", true),
-		chromedp.Flag("headless", true),
-		chromedp.ExecPath("/usr/bin/chromium-browser"),
-	}
-	
-	allocCtx, allocCancel := chromedp.NewExecAllocator(context.Background(), opts...)
-	defer allocCancel()
-	
-	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
-	defer browserCancel()
-	
-	for _, query := range queries {
-		fmt.Printf("🔍 Scraping Indeed with Chrome for: '%s'\n", query)
-		
-		duplicateCount := 0
-		maxDuplicatesBeforeStop := 20 // Stop if we see 20 duplicates in a row
-		
-		// Scrape first 10 pages (0-90) = 100 jobs per query
-		// Since we run once per day, maximize coverage
-		for start := 0; start < 100; start += 10 {
-			jobs, err := icc.scrapePage(browserCtx, query, start)
-			if err != nil {
-				fmt.Printf("⚠️  Error scraping page %d for query '%s': %v\n", start/10+1, query, err)
+	defer ojc.resetChrome()
+
+	jobs := []models.JobPost{}
+	seen := make(map[string]bool)
+
+	for page := 1; page <= ojc.maxPages; page++ {
+		listings, err := ojc.fetchListPage(page)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+			fmt.Printf("⚠️  Error fetching page %d: %v\n", page, err)
+			break
+		}
+		if len(listings) == 0 {
+			fmt.Printf("   ℹ️  No more results at page %d\n", page)
+			break
+		}
+
+		newOnPage := 0
+		known := 0
+		for _, l := range listings {
+			if seen[l.id] {
 				continue
 			}
-			
-			if len(jobs) == 0 {
-				fmt.Printf("   ℹ️  No more results for '%s' at page %d\n", query, start/10+1)
-				break // No more results
+			seen[l.id] = true
+			newOnPage++
+
+			if ojc.isStored(l.id) {
+				known++
+				continue
 			}
-			
-			newJobsOnPage := 0
-			// Filter to only new jobs (check against existing IDs)
-			for _, job := range jobs {
-				if !existingIDs[job.ID] {
-					// This is a new job - fetch full description
-					fullJob := icc.createJobPost(browserCtx, map[string]string{
-						"jobKey":  strings.TrimPrefix(job.ID, "indeed-chrome-"),
-						"title":   job.Title,
-						"company": job.Company,
-						"location": job.Location,
-						"snippet": job.Description,
-						"salary":  job.Salary,
-					}, true) // true = fetch full description
-					
-					if fullJob != nil {
-						allJobs = append(allJobs, *fullJob)
-						newJobsOnPage++
-					}
-				}
+
+			job, err := ojc.fetchDetail(l)
+			if err != nil {
+				fmt.Printf("   ⚠️  Failed to fetch ad %s: %v\n", l.id, err)
+				continue
 			}
-			
-			// Early stopping: if we found no new jobs on this page, increment duplicate counter
-			if newJobsOnPage == 0 {
-				duplicateCount += len(jobs)
-				fmt.Printf("   ℹ️  Page %d: All %d jobs already seen (total duplicates: %d)\n", start/10+1, len(jobs), duplicateCount)
-				
-				// Stop if we've seen too many duplicates (means we've caught up)
-				if duplicateCount >= maxDuplicatesBeforeStop {
-					fmt.Printf("   ✅ Stopping '%s' - caught up with existing jobs\n", query)
-					break
-				}
-			} else {
-				duplicateCount = 0 // Reset counter if we found new jobs
-				fmt.Printf("   ✅ Page %d: Found %d new jobs\n", start/10+1, newJobsOnPage)
+			if !job.ExpiresDate.IsZero() && job.ExpiresDate.Before(time.Now().Truncate(24*time.Hour)) {
+				continue // Application deadline has passed
 			}
-			
-			// Rate limiting - be respectful!
-			time.Sleep(icc.rateLimit)
+			jobs = append(jobs, job)
+		}
+
+		fmt.Printf("   📄 Page %d: %d ads, %d already stored\n", page, len(listings), known)
+
+		// Results are newest first: a page of only known ads means we've caught up
+		if newOnPage == 0 || known == newOnPage {
+			break
 		}
 	}
-	
-	// Deduplicate by job key
-	uniqueJobs := icc.deduplicateJobs(allJobs)
-	
-	fmt.Printf("📊 Scraped %d unique jobs from Indeed (filtered from %d total)\n", len(uniqueJobs), len(allJobs))
-	
-	return uniqueJobs, nil
+
+	fmt.Printf("📊 Fetched %d new public sector jobs from Offentliga Jobb\n", len(jobs))
+	return jobs, nil
 }
 
-// scrapePage scrapes a single search results page using Chrome
-func (ojc *OffentligaJobbConnector) scrapePage(browserCtx context.Context, query string, start int) ([]models.JobPost, error) {
-	jobs := []models.JobPost{}
-	
-	// Build search URL
-	searchURL := fmt.Sprintf("%s/jobs?q=%s&l=Sverige&start=%d",
-		icc.baseURL,
-		strings.ReplaceAll(query, " ", "+"),
-		start,
-	)
-	
-	// Create timeout context for this page
-	ctx, timeoutCancel := context.WithTimeout(browserCtx, 120*time.Second)
-	defer timeoutCancel()
-	
-	// Variables to store scraped data
-	var jobCards []map[string]string
-	
-	// Run Chrome automation
-	err := chromedp.Run(ctx,
-		// Navigate to search page
-		chromedp.Navigate(searchURL),
-		
-		// Wait for job cards to load
-		chromedp.WaitVisible(`div.job_seen_beacon, td.resultContent`, chromedp.ByQuery),
-		
-		// Wait a bit for dynamic content
-		chromedp.Sleep(2*time.Second),
-		
-		// Extract job data using JavaScript
-		chromedp.Evaluate(`
-			(() => {
-				const jobs = [];
-				
-				// Try multiple selectors
-				const cards = document.querySelectorAll('div.job_seen_beacon, td.resultContent, div[class*="jobsearch"]');
-				
-				cards.forEach(card => {
-					// Extract job key
-					let jobKey = card.getAttribute('data-jk');
-					if (!jobKey) {
-						const link = card.querySelector('a[data-jk]');
-						if (link) jobKey = link.getAttribute('data-jk');
-					}
-					if (!jobKey) {
-						const href = card.querySelector('a[href*="jk="]');
-						if (href) {
-							const match = href.href.match(/jk=([a-zA-Z0-9]+)/);
-							if (match) jobKey = match[1];
-						}
-					}
-					
-					// Extract title
-					let title = '';
-					const titleEl = card.querySelector('h2.jobTitle span[title], h2.jobTitle, a[data-jk] span');
-					if (titleEl) title = titleEl.textContent.trim();
-					
-					// Extract company
-					let company = '';
-					const companyEl = card.querySelector('span.companyName, span[data-testid="company-name"]');
-					if (companyEl) company = companyEl.textContent.trim();
-					
-					// Extract location
-					let location = '';
-					const locationEl = card.querySelector('div.companyLocation, div[data-testid="text-location"]');
-					if (locationEl) location = locationEl.textContent.trim();
-					
-					// Extract snippet
-					let snippet = '';
-					const snippetEl = card.querySelector('div.job-snippet, div[class*="snippet"], ul li');
-					if (snippetEl) snippet = snippetEl.textContent.trim();
-					
-					// Extract salary
-					let salary = '';
-					const salaryEl = card.querySelector('div.salary-snippet, span[class*="salary"]');
-					if (salaryEl) salary = salaryEl.textContent.trim();
-					
-					// Only add if we have minimum data
-					if (title && jobKey) {
-						jobs.push({
-							jobKey: jobKey,
-							title: title,
-							company: company,
-							location: location,
-							snippet: snippet,
-							salary: salary
-						});
-					}
-				});
-				
-				return jobs;
-			})()
-		`, &jobCards),
-	)
-	
+// fetchListPage fetches and parses one search result page
+func (ojc *OffentligaJobbConnector) fetchListPage(page int) ([]listing, error) {
+	pageURL := fmt.Sprintf("%s%s?page=%d", ojc.baseURL, ojc.listPath, page)
+
+	doc, err := ojc.fetchDocument(pageURL, "a[href]")
 	if err != nil {
-		return nil, fmt.Errorf("failed to scrape page with Chrome: %w", err)
+		return nil, err
 	}
-	
-	fmt.Printf("   📄 Found %d job cards on page\n", len(jobCards))
-	
-	// Convert to JobPost objects (without full descriptions yet)
-	for _, card := range jobCards {
-		job := icc.createJobPost(browserCtx, card, false) // false = don't fetch description yet
-		if job != nil {
-			jobs = append(jobs, *job)
+	listings := ojc.parseListPage(doc)
+
+	// A list without job links over plain HTTP is most likely rendered client-side
+	if len(listings) == 0 && page == 1 && !ojc.chromeActive() && ojc.browser.Enabled() {
+		fmt.Println("🌐 No ads in server-rendered HTML, retrying with headless Chrome")
+		ojc.setChrome()
+		if doc, err = ojc.fetchDocument(pageURL, "a[href]"); err != nil {
+			return nil, err
 		}
+		listings = ojc.parseListPage(doc)
 	}
-	
-	return jobs, nil
+	return listings, nil
 }
 
-// createJobPost creates a JobPost from scraped data
-func (ojc *OffentligaJobbConnector) createJobPost(browserCtx context.Context, card map[string]string, fetchDescription bool) *models.JobPost {
-	jobKey := card["jobKey"]
-	title := card["title"]
-	company := card["company"]
-	location := card["location"]
-	snippet := card["snippet"]
-	salary := card["salary"]
-	
-	if title == "" || jobKey == "" {
-		return nil
-	}
-	
-	// Build job URL
-	jobURL := fmt.Sprintf("%s/viewjob?jk=%s", icc.baseURL, jobKey)
-	
-	// Optionally fetch full description from job page
-	description := snippet
-	if fetchDescription {
-		fullDescription := icc.scrapeJobDescription(browserCtx, jobURL, jobKey)
-		if fullDescription != "" {
-			description = fullDescription
-			fmt.Printf("   ✅ Fetched full description for: %s\n", title)
-		}
-	}
-	
-	// Estimate posted date (Indeed doesn't always show exact date)
-	// Use a heuristic: jobs are likely posted within last 30 days
-	// For incremental sync, we'll check against database ID instead
-	postedDate := time.Now().AddDate(0, 0, -7) // Assume posted within last week
-	
-	// Create JobPost
-	job := models.JobPost{
-		ID:              fmt.Sprintf("indeed-chrome-%s", jobKey),
-		Title:           strings.TrimSpace(title),
-		Company:         strings.TrimSpace(company),
-		Description:     icc.cleanText(description),
-		Location:        icc.formatLocation(location),
-		Salary:          strings.TrimSpace(salary),
-		SalaryMin:       nil,
-		SalaryMax:       nil,
-		SalaryCurrency:  "SEK",
-		IsRemote:        icc.detectRemote(title, description, location),
-		URL:             jobURL,
-		EmploymentType:  "Full-time",
-		ExperienceLevel: "Mid-level",
-		PostedDate:      postedDate,
-		ExpiresDate:     time.Now().AddDate(0, 1, 0),
-		Requirements:    icc.extractRequirements(title, description),
-		Benefits:        []string{},
-		Fields: map[string]interface{}{
-			"source":      "indeed-chrome",
-			"source_url":  jobURL,
-			"original_id": jobKey,
-			"connector":   "indeed-chrome",
-			"fetched_at":  time.Now(),
-			"method":      "headless_chrome",
-		},
-	}
-	
-	return &job
-}
-
-// scrapeJobDescription fetches the full job description from individual job page using Chrome
-func (ojc *OffentligaJobbConnector) scrapeJobDescription(browserCtx context.Context, jobURL, jobKey string) string {
-	description := ""
-	
-	// Create timeout context for this job page
-	ctx, timeoutCancel := context.WithTimeout(browserCtx, 60*time.Second)
-	defer timeoutCancel()
-	
-	// Run Chrome automation
-	err := chromedp.Run(ctx,
-		// Navigate to job page
-		chromedp.Navigate(jobURL),
-		
-		// Wait for description to load
-		chromedp.WaitVisible(`div#jobDescriptionText, div.jobsearch-jobDescriptionText`, chromedp.ByQuery),
-		
-		// Extract description text
-		chromedp.Evaluate(`
-			(() => {
-				const descEl = document.querySelector('div#jobDescriptionText, div.jobsearch-jobDescriptionText, div[id*="jobDesc"]');
-				return descEl ? descEl.textContent : '';
-			})()
-		`, &description),
-	)
-	
+// parseListPage extracts job cards from a search result page
+func (ojc *OffentligaJobbConnector) parseListPage(doc *goquery.Document) []listing {
+	listings := []listing{}
+	seen := make(map[string]bool)
+
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		jobURL, id := ojc.jobURL(href)
+		if id == "" || seen[id] {
+			return
+		}
+		seen[id] = true
+
+		// The card is the closest list element around the link
+		card := a.Closest("article, li, [class*=job], [class*=Job], [class*=ad-]")
+		if card.Length() == 0 {
+			card = a.Parent()
+		}
+
+		title := cleanText(a.Find("h2, h3").First().Text())
+		if title == "" {
+			title = cleanText(a.Text())
+		}
+		if title == "" {
+			title = cleanText(card.Find("h2, h3").First().Text())
+		}
+
+		l := listing{
+			id:       id,
+			url:      jobURL,
+			title:    title,
+			employer: cleanText(card.Find(".employer, .company, [class*=employer], [class*=Employer], [class*=organization]").First().Text()),
+			location: cleanText(card.Find(".location, [class*=location], [class*=Location], [class*=municipality]").First().Text()),
+		}
+		if m := deadlineRe.FindStringSubmatch(card.Text()); m != nil {
+			l.deadline = parseSwedishDate(m[1])
+		}
+		listings = append(listings, l)
+	})
+
+	return listings
+}
+
+// fetchDetail fetches an ad page and merges it with the list card
+func (ojc *OffentligaJobbConnector) fetchDetail(l listing) (models.JobPost, error) {
+	doc, err := ojc.fetchDocument(l.url, "h1")
 	if err != nil {
-		fmt.Printf("   ⚠️  Failed to fetch job page %s: %v\n", jobKey, err)
-		return ""
+		return models.JobPost{}, err
+	}
+	return ojc.parseDetailPage(doc, l), nil
+}
+
+// parseDetailPage builds a JobPost from an ad page. The schema.org JobPosting block
+// is preferred; labelled facts ("Arbetsgivare", "Sista ansökningsdag") fill the gaps.
+func (ojc *OffentligaJobbConnector) parseDetailPage(doc *goquery.Document, l listing) models.JobPost {
+	posting := findJobPosting(doc)
+	facts := labelledFacts(doc)
+
+	title := firstNonEmpty(posting.Title, cleanText(doc.Find("h1").First().Text()), l.title)
+	employer := firstNonEmpty(posting.HiringOrganization.Name, facts["arbetsgivare"], facts["myndighet"],
+		facts["organisation"], facts["kommun"], l.employer)
+	location := firstNonEmpty(posting.location(), facts["ort"], facts["placeringsort"], facts["kommun"], facts["län"], l.location)
+
+	description := htmlToText(cleanText(posting.Description))
+	if description == "" {
+		description = cleanText(doc.Find(".job-description, .description, [class*=description], [class*=Description], article").First().Text())
+	}
+
+	deadline := parseSwedishDate(posting.ValidThrough)
+	if deadline.IsZero() {
+		deadline = parseSwedishDate(firstNonEmpty(facts["sista ansökningsdag"], facts["sista ansökningsdagen"]))
+	}
+	if deadline.IsZero() {
+		if m := deadlineRe.FindStringSubmatch(doc.Text()); m != nil {
+			deadline = parseSwedishDate(m[1])
+		}
+	}
+	if deadline.IsZero() {
+		deadline = l.deadline
+	}
+
+	posted := parseSwedishDate(firstNonEmpty(posting.DatePosted, facts["publicerad"], facts["publiceringsdatum"]))
+	postedEstimated := posted.IsZero()
+	if postedEstimated {
+		posted = time.Now()
+	}
+
+	employmentType := firstNonEmpty(facts["anställningsform"], facts["tjänstgöringsgrad"], posting.employmentType())
+	reference := firstNonEmpty(facts["referensnummer"], facts["diarienummer"], facts["ref"])
+	method := "http"
+	if ojc.chromeActive() {
+		method = "headless_chrome"
+	}
+
+	fields := map[string]interface{}{
+		"source":        "offentligajobb",
+		"source_url":    l.url,
+		"original_id":   l.id,
+		"connector":     "offentligajobb",
+		"fetched_at":    time.Now(),
+		"method":        method,
+		"employer_type": employerType(employer),
+		"sector":        "public",
+	}
+	if postedEstimated {
+		fields["posted_date_estimated"] = true
+	}
+	if !deadline.IsZero() {
+		fields["application_deadline"] = deadline.Format("2006-01-02")
+	}
+	if reference != "" {
+		fields["reference_number"] = reference
 	}
-	
-	return description
-}
-
-// cleanText removes extra whitespace and cleans text
-func (ojc *OffentligaJobbConnector) cleanText(text string) string {
-	// Remove HTML tags
-	re := regexp.MustCompile(`<[^>]*>`)
-	text = re.ReplaceAllString(text, "")
-	
-	// Trim whitespace
-	text = strings.TrimSpace(text)
-	
-	// Remove excessive newlines
-	re = regexp.MustCompile(`\n{3,}`)
-	text = re.ReplaceAllString(text, "\n\n")
-	
-	return text
-}
-
-// formatLocation formats location string
-func (ojc *OffentligaJobbConnector) formatLocation(location string) string {
-	location = strings.TrimSpace(location)
+	if region := firstNonEmpty(facts["län"], facts["region"]); region != "" {
+		fields["region"] = region
+	}
+	if apply := applyURL(doc, l.url); apply != "" {
+		fields["apply_url"] = apply
+	}
+
 	if location == "" {
-		return "Sweden"
+		location = "Sweden"
+	} else if !strings.Contains(strings.ToLower(location), "sverige") && !strings.Contains(strings.ToLower(location), "sweden") {
+		location += ", Sweden"
 	}
-	
-	// Add Sweden if not present
-	if !strings.Contains(strings.ToLower(location), "sweden") &&
-		!strings.Contains(strings.ToLower(location), "sverige") {
-		location = location + ", Sweden"
+
+	job := models.JobPost{
+		ID:             jobid.New("offentligajobb", l.id),
+		Title:          title,
+		Company:        employer,
+		Description:    description,
+		Location:       location,
+		SalaryCurrency: "SEK",
+		Salary:         firstNonEmpty(facts["lön"], facts["lönevillkor"], facts["löneform"]),
+		IsRemote:       detectRemote(title, description, location),
+		URL:            l.url,
+		EmploymentType: employmentType,
+		PostedDate:     posted,
+		ExpiresDate:    deadline,
+		Requirements:   []string{},
+		Benefits:       []string{},
+		Fields:         fields,
 	}
-	
-	return location
+	salary.Parse(job.Salary, "SEK", salary.Month).Apply(&job)
+	return job
 }
 
-// detectRemote checks if job is remote
-func (ojc *OffentligaJobbConnector) detectRemote(title, description, location string) bool {
-	text := strings.ToLower(title + " " + description + " " + location)
-	
-	remoteKeywords := []string{
-		"remote", "distans", "hemarbete", "hemifrån",
-		"work from home", "wfh", "anywhere",
+// jobURL resolves a link and returns the absolute ad URL and its numeric ad ID
+func (ojc *OffentligaJobbConnector) jobURL(href string) (string, string) {
+	base, err := url.Parse(ojc.baseURL)
+	if err != nil {
+		return "", ""
 	}
-	
-	for _, keyword := range remoteKeywords {
-		if strings.Contains(text, keyword) {
-			return true
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return "", ""
+	}
+	abs := base.ResolveReference(ref)
+	if abs.Host != base.Host {
+		return "", ""
+	}
+	m := jobPathRe.FindStringSubmatch(abs.Path)
+	if m == nil {
+		return "", ""
+	}
+	abs.RawQuery = ""
+	abs.Fragment = ""
+	return abs.String(), m[1]
+}
+
+// fetchDocument fetches a page over plain HTTP, switching to headless Chrome for the
+// rest of the run when the site answers with a block or bot challenge
+func (ojc *OffentligaJobbConnector) fetchDocument(pageURL, waitSelector string) (*goquery.Document, error) {
+	if !ojc.chromeActive() {
+		body, blocked, err := ojc.fetchHTTP(pageURL)
+		if err != nil {
+			return nil, err
 		}
+		if !blocked {
+			return goquery.NewDocumentFromReader(strings.NewReader(body))
+		}
+		if !ojc.browser.Enabled() {
+			return nil, fmt.Errorf("blocked by %s and chrome fallback is disabled", pageURL)
+		}
+		fmt.Println("🛡️  Plain HTTP blocked, switching to headless Chrome")
+		ojc.setChrome()
 	}
-	
-	return false
+
+	body, err := ojc.fetchChrome(pageURL, waitSelector)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(strings.NewReader(body))
 }
 
-// extractRequirements extracts keywords from title and description
-func (ojc *OffentligaJobbConnector) extractRequirements(title, description string) []string {
-	requirements := []string{}
-	seen := make(map[string]bool)
-	
-	text := strings.ToLower(title + " " + description)
-	
-	// Common tech skills
-	keywords := []string{
-		"Java", "Python", "JavaScript", "TypeScript", "C++", "C#", ".NET", "PHP", "Ruby", "Go", "Rust", "Swift", "Kotlin",
-		"React", "Angular", "Vue", "Node.js", "Spring", "Django", "Flask", "Express", "Laravel",
-		"Docker", "Kubernetes", "AWS", "Azure", "GCP", "CI/CD", "Jenkins", "Git", "Linux",
-		"SQL", "PostgreSQL", "MySQL", "MongoDB", "Redis", "Elasticsearch",
-		"API", "REST", "GraphQL", "Microservices", "Agile", "Scrum",
-		"Swedish", "English", "B2B", "B2C", "SaaS",
-	}
-	
-	for _, keyword := range keywords {
-		if strings.Contains(text, strings.ToLower(keyword)) && !seen[keyword] {
-			requirements = append(requirements, keyword)
-			seen[keyword] = true
-		}
-	}
-	
-	return requirements
-}
-
-// deduplicateJobs removes duplicate jobs by ID
-func (ojc *OffentligaJobbConnector) deduplicateJobs(jobs []models.JobPost) []models.JobPost {
-	seen := make(map[string]bool)
-	unique := []models.JobPost{}
-	
-	for _, job := range jobs {
-		if !seen[job.ID] {
-			seen[job.ID] = true
-			unique = append(unique, job)
+// fetchHTTP returns the page body, or blocked=true for bot protection responses
+func (ojc *OffentligaJobbConnector) fetchHTTP(pageURL string) (string, bool, error) {
+	req, err := http.NewRequest("GET", pageURL, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", ojc.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("Accept-Language", "sv-SE,sv;q=0.9,en;q=0.5")
+
+	resp, err := ojc.httpClient.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		lower := strings.ToLower(string(body))
+		if strings.Contains(lower, "cf-challenge") || strings.Contains(lower, "just a moment...") {
+			return "", true, nil
 		}
+		return string(body), false, nil
+	case http.StatusForbidden, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return "", true, nil
+	default:
+		return "", false, fmt.Errorf("%s returned %d", pageURL, resp.StatusCode)
+	}
+}
+
+// fetchChrome renders a page in the shared headless browser pool and returns its HTML
+func (ojc *OffentligaJobbConnector) fetchChrome(pageURL, waitSelector string) (string, error) {
+	ctx := context.Background()
+	if !httpclient.Allowed(ctx, pageURL, ojc.userAgent) {
+		return "", fmt.Errorf("%w: %s", httpclient.ErrDisallowed, pageURL)
+	}
+	if err := httpclient.Wait(ctx, pageURL, politeInterval); err != nil {
+		return "", err
+	}
+
+	body, err := ojc.browser.Render(ctx, pageURL, waitSelector, time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to render page with Chrome: %w", err)
+	}
+	return body, nil
+}
+
+// resetChrome sends the next run back to plain HTTP; the pool keeps Chrome until it is idle
+func (ojc *OffentligaJobbConnector) resetChrome() {
+	ojc.mu.Lock()
+	ojc.useChrome = false
+	ojc.mu.Unlock()
+}
+
+func (ojc *OffentligaJobbConnector) setChrome() {
+	ojc.mu.Lock()
+	ojc.useChrome = true
+	ojc.mu.Unlock()
+}
+
+func (ojc *OffentligaJobbConnector) chromeActive() bool {
+	ojc.mu.Lock()
+	defer ojc.mu.Unlock()
+	return ojc.useChrome
+}
+
+// isStored reports whether an ad is already in the database, so its detail page is skipped
+func (ojc *OffentligaJobbConnector) isStored(id string) bool {
+	if ojc.store == nil {
+		return false
 	}
-	
-	return unique
+	existing, err := ojc.store.GetJob(jobid.New("offentligajobb", id))
+	return err == nil && existing != nil
 }
 
-// SyncJobs scrapes jobs from Indeed using Chrome and stores them
+// SyncJobs fetches new public sector jobs and stores them
 func (ojc *OffentligaJobbConnector) SyncJobs() error {
 	startTime := time.Now()
-	fmt.Println("🔄 Starting Indeed Sweden Chrome scraping sync...")
-	fmt.Println("🌐 Using headless Chrome - bypasses Cloudflare!")
-	
-	jobs, err := icc.FetchJobs()
+	fmt.Println("🔄 Starting Offentliga Jobb sync...")
+
+	jobs, err := ojc.FetchJobs()
 	if err != nil {
-		// Log failed sync
-		icc.store.LogSync(&models.SyncLog{
-			ConnectorName: icc.GetID(),
+		ojc.store.LogSync(&models.SyncLog{
+			ConnectorName: ojc.GetID(),
 			StartedAt:     startTime,
 			CompletedAt:   time.Now(),
-			JobsFetched:   0,
-			JobsInserted:  0,
-			JobsDuplicates: 0,
 			Status:        "failed",
 		})
-		return fmt.Errorf("failed to scrape jobs from Indeed: %w", err)
+		return fmt.Errorf("failed to fetch jobs from Offentliga Jobb: %w", err)
 	}
-	
-	fmt.Printf("📥 Scraped %d jobs from Indeed Sweden\n", len(jobs))
-	
+
+	fmt.Printf("📥 Fetched %d jobs from Offentliga Jobb\n", len(jobs))
+
 	stored := 0
 	duplicates := 0
 	for _, job := range jobs {
-		// Check if job already exists
-		existing, err := icc.store.GetJob(job.ID)
+		existing, err := ojc.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
 			continue
 		}
-		
 		if existing != nil {
-			// Job already exists, skip
 			duplicates++
 			continue
 		}
-		
-		// Store new job
-		err = icc.store.CreateJob(&job)
-		if err != nil {
+
+		if err := ojc.store.CreateJob(&job); err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
 			continue
 		}
-		
+
 		stored++
 		fmt.Printf("✅ Stored job: %s at %s (%s)\n", job.Title, job.Company, job.Location)
 	}
-	
-	// Log successful sync
-	if err := icc.store.LogSync(&models.SyncLog{
-		ConnectorName:  icc.GetID(),
+
+	if err := ojc.store.LogSync(&models.SyncLog{
+		ConnectorName:  ojc.GetID(),
 		StartedAt:      startTime,
 		CompletedAt:    time.Now(),
 		JobsFetched:    len(jobs),
@@ -503,21 +504,255 @@ func (ojc *OffentligaJobbConnector) SyncJobs() error {
 	}); err != nil {
 		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
 	}
-	
-	fmt.Printf("🎉 Indeed Chrome scraping sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
+
+	fmt.Printf("🎉 Offentliga Jobb sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
 	return nil
 }
 
-// getExistingJobIDs retrieves all existing job IDs for incremental sync
-func (ojc *OffentligaJobbConnector) getExistingJobIDs() map[string]bool {
-	// This would need a new method in storage to get all IDs efficiently
-	// For now, use a simple approach
-	existingIDs := make(map[string]bool)
-	
-	// Note: In production, you'd want to add a method to JobStore like:
-	// jobs, err := icc.store.GetJobIDsByPrefix("indeed-chrome-")
-	// For now, we'll rely on the database check in SyncJobs
-	
-	fmt.Println("📅 Using database-based incremental sync")
-	return existingIDs
+// jobPosting is the subset of a schema.org JobPosting block used on ad pages
+type jobPosting struct {
+	Title              string          `json:"title"`
+	Description        string          `json:"description"`
+	DatePosted         string          `json:"datePosted"`
+	ValidThrough       string          `json:"validThrough"`
+	EmploymentType     json.RawMessage `json:"employmentType"`
+	HiringOrganization struct {
+		Name string `json:"name"`
+	} `json:"hiringOrganization"`
+	JobLocation json.RawMessage `json:"jobLocation"`
+}
+
+type jobPlace struct {
+	Address struct {
+		Locality string `json:"addressLocality"`
+		Region   string `json:"addressRegion"`
+	} `json:"address"`
+}
+
+// location joins the locality and region of the first job location
+func (p jobPosting) location() string {
+	var places []jobPlace
+	if err := json.Unmarshal(p.JobLocation, &places); err != nil {
+		var place jobPlace
+		if err := json.Unmarshal(p.JobLocation, &place); err != nil {
+			return ""
+		}
+		places = []jobPlace{place}
+	}
+	for _, place := range places {
+		parts := []string{}
+		for _, s := range []string{place.Address.Locality, place.Address.Region} {
+			if s = strings.TrimSpace(s); s != "" && !containsFold(parts, s) {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, ", ")
+		}
+	}
+	return ""
+}
+
+// employmentType returns the employment type, which may be a string or a list
+func (p jobPosting) employmentType() string {
+	var single string
+	if err := json.Unmarshal(p.EmploymentType, &single); err == nil {
+		return single
+	}
+	var list []string
+	if err := json.Unmarshal(p.EmploymentType, &list); err == nil {
+		return strings.Join(list, ", ")
+	}
+	return ""
+}
+
+// findJobPosting returns the first JobPosting JSON-LD block on the page (zero value if none)
+func findJobPosting(doc *goquery.Document) jobPosting {
+	var found jobPosting
+	doc.Find(`script[type="application/ld+json"]`).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		raw := []byte(s.Text())
+
+		var candidates []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &candidates); err != nil {
+			var single map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &single); err != nil {
+				return true
+			}
+			candidates = []map[string]json.RawMessage{single}
+			if graph, ok := single["@graph"]; ok {
+				var nodes []map[string]json.RawMessage
+				if json.Unmarshal(graph, &nodes) == nil {
+					candidates = append(candidates, nodes...)
+				}
+			}
+		}
+
+		for _, c := range candidates {
+			var typ string
+			json.Unmarshal(c["@type"], &typ)
+			if typ != "JobPosting" {
+				continue
+			}
+			encoded, _ := json.Marshal(c)
+			if json.Unmarshal(encoded, &found) == nil {
+				return false
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// labelledFacts collects "Label: value" facts from definition lists and label/value rows,
+// keyed by lowercase label without the trailing colon
+func labelledFacts(doc *goquery.Document) map[string]string {
+	facts := make(map[string]string)
+	add := func(label, value string) {
+		label = strings.ToLower(strings.TrimSuffix(cleanText(label), ":"))
+		value = cleanText(value)
+		if label != "" && value != "" && facts[label] == "" {
+			facts[label] = value
+		}
+	}
+
+	doc.Find("dt").Each(func(_ int, dt *goquery.Selection) {
+		add(dt.Text(), dt.NextFiltered("dd").Text())
+	})
+	doc.Find("th").Each(func(_ int, th *goquery.Selection) {
+		add(th.Text(), th.NextFiltered("td").Text())
+	})
+	doc.Find("li, p").Each(func(_ int, s *goquery.Selection) {
+		if label := s.Find("strong, b, .label").First(); label.Length() > 0 {
+			add(label.Text(), strings.TrimPrefix(cleanText(s.Text()), cleanText(label.Text())))
+		}
+	})
+	return facts
+}
+
+// applyURL returns the external application link if the ad has one
+func applyURL(doc *goquery.Document, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	apply := ""
+	doc.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		text := strings.ToLower(a.Text())
+		if !strings.Contains(text, "ansök") && !strings.Contains(text, "apply") {
+			return true
+		}
+		href, _ := a.Attr("href")
+		if ref, err := url.Parse(href); err == nil && !strings.HasPrefix(href, "#") {
+			apply = base.ResolveReference(ref).String()
+			return false
+		}
+		return true
+	})
+	return apply
+}
+
+// employerType classifies public sector employers by their name
+func employerType(employer string) string {
+	lower := strings.ToLower(employer)
+	switch {
+	case lower == "":
+		return ""
+	case strings.Contains(lower, "kommun") || strings.HasSuffix(lower, " stad"):
+		return "municipality"
+	case strings.HasPrefix(lower, "region ") || strings.Contains(lower, "landsting"):
+		return "region"
+	case strings.Contains(lower, "myndighet") || strings.Contains(lower, "verket") ||
+		strings.Contains(lower, "styrelse") || strings.Contains(lower, "inspektion") ||
+		strings.Contains(lower, "polisen") || strings.Contains(lower, "domstol") ||
+		strings.Contains(lower, "universitet") || strings.Contains(lower, "högskola") ||
+		strings.Contains(lower, "försäkringskassan") || strings.Contains(lower, "arbetsförmedlingen"):
+		return "agency"
+	default:
+		return "other"
+	}
+}
+
+// parseSwedishDate parses ISO dates and Swedish "31 oktober 2025" style dates
+func parseSwedishDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	if len(s) > 10 {
+		if t, err := time.Parse("2006-01-02", s[:10]); err == nil {
+			return t
+		}
+	}
+
+	parts := strings.Fields(strings.ToLower(s))
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	month, ok := swedishMonths[strings.TrimSuffix(parts[1], ".")]
+	if !ok {
+		return time.Time{}
+	}
+	var day, year int
+	if _, err := fmt.Sscanf(parts[0]+" "+parts[2], "%d %d", &day, &year); err != nil {
+		return time.Time{}
+	}
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// cleanText collapses whitespace while keeping paragraph breaks
+func cleanText(text string) string {
+	text = strings.ReplaceAll(text, "\r", "")
+	text = spacesRe.ReplaceAllString(text, " ")
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text = newlinesRe.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text)
+}
+
+// htmlToText strips markup from descriptions that embed HTML
+func htmlToText(text string) string {
+	if !strings.Contains(text, "<") {
+		return text
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(text))
+	if err != nil {
+		return text
+	}
+	return cleanText(doc.Text())
+}
+
+// detectRemote checks if the ad offers remote work
+func detectRemote(title, description, location string) bool {
+	text := strings.ToLower(title + " " + description + " " + location)
+	for _, keyword := range []string{"distans", "hemarbete", "remote", "på distans"} {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
 }
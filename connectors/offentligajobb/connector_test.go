@@ -0,0 +1,262 @@
+package offentligajobb
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/browser"
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+const listPage = `<html><body>
+<nav><a href="/om-oss">Om oss</a><a href="/lediga-jobb?page=2">Nästa</a></nav>
+<ul class="results">
+  <li class="job-item">
+    <a href="/jobb/handlaggare-till-skatteverket-123456?ref=list"><h3>Handläggare</h3></a>
+    <span class="employer">Skatteverket</span>
+    <span class="location">Solna</span>
+  </li>
+  <li class="job-item">
+    <a href="/jobb/sjukskoterska-234567"><h3>Sjuksköterska</h3></a>
+    <span class="employer">Region Uppsala</span>
+    <span class="location">Uppsala</span>
+    <p>Sista ansökningsdag: 15 december 2099</p>
+  </li>
+  <li class="job-item">
+    <a href="https://www.offentligajobb.se/jobb/old-ad-345678"><h3>Old ad</h3></a>
+  </li>
+  <li class="job-item">
+    <a href="/jobb/expired-456789"><h3>Expired</h3></a>
+  </li>
+</ul>
+</body></html>`
+
+const jsonLDAd = `<html><head>
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "JobPosting",
+  "title": "Handläggare inom skatt", "description": "<p>Du utreder <b>skatteärenden</b>.</p>",
+  "datePosted": "2025-10-01", "validThrough": "2099-11-30T23:59:59+01:00", "employmentType": ["FULL_TIME"],
+  "hiringOrganization": {"@type": "Organization", "name": "Skatteverket"},
+  "jobLocation": {"@type": "Place", "address": {"addressLocality": "Solna", "addressRegion": "Stockholms län"}}}</script>
+</head><body><h1>Handläggare inom skatt</h1><a href="https://skatteverket.varbi.com/ansok/1">Ansök här</a></body></html>`
+
+const factsAd = `<html><body>
+<h1>Sjuksköterska till akuten</h1>
+<dl>
+  <dt>Arbetsgivare</dt><dd>Region Uppsala</dd>
+  <dt>Ort</dt><dd>Uppsala</dd>
+  <dt>Anställningsform</dt><dd>Tillsvidareanställning</dd>
+  <dt>Publicerad</dt><dd>2025-10-05</dd>
+  <dt>Referensnummer</dt><dd>RS 2025-1234</dd>
+</dl>
+<div class="job-description">Vi söker en sjuksköterska. Delvis distansarbete med journalföring.</div>
+</body></html>`
+
+const expiredAd = `<html><body><h1>Expired</h1><p><strong>Sista ansökningsdag:</strong> 2020-01-31</p></body></html>`
+
+func newTestConnector(t *testing.T, handler http.Handler) *OffentligaJobbConnector {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	ojc := NewOffentligaJobbConnector(nil)
+	ojc.baseURL = server.URL
+	ojc.httpClient = httpclient.New(httpclient.Options{})
+	ojc.browser = nil
+	return ojc
+}
+
+func TestFetchJobs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/lediga-jobb", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") != "1" {
+			fmt.Fprint(w, `<html><body><p>Inga fler annonser</p></body></html>`)
+			return
+		}
+		fmt.Fprint(w, listPage)
+	})
+	mux.HandleFunc("/jobb/handlaggare-till-skatteverket-123456", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, jsonLDAd)
+	})
+	mux.HandleFunc("/jobb/sjukskoterska-234567", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, factsAd)
+	})
+	mux.HandleFunc("/jobb/expired-456789", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, expiredAd)
+	})
+	// old-ad-345678 links to another host and must be ignored
+
+	jobs, err := newTestConnector(t, mux).FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d: %+v", len(jobs), jobs)
+	}
+
+	tax := jobs[0]
+	if tax.ID != "offentligajobb:123456" {
+		t.Errorf("Unexpected ID: %s", tax.ID)
+	}
+	if tax.Title != "Handläggare inom skatt" || tax.Company != "Skatteverket" {
+		t.Errorf("Unexpected title/company: %q / %q", tax.Title, tax.Company)
+	}
+	if tax.Location != "Solna, Stockholms län, Sweden" {
+		t.Errorf("Unexpected location: %q", tax.Location)
+	}
+	if tax.Description != "Du utreder skatteärenden." {
+		t.Errorf("Unexpected description: %q", tax.Description)
+	}
+	if tax.ExpiresDate.Format("2006-01-02") != "2099-11-30" || tax.Fields["application_deadline"] != "2099-11-30" {
+		t.Errorf("Unexpected deadline: %v / %v", tax.ExpiresDate, tax.Fields["application_deadline"])
+	}
+	if tax.EmploymentType != "FULL_TIME" || tax.Fields["employer_type"] != "agency" {
+		t.Errorf("Unexpected employment/employer type: %q / %v", tax.EmploymentType, tax.Fields["employer_type"])
+	}
+	if !strings.HasSuffix(tax.URL, "/jobb/handlaggare-till-skatteverket-123456") {
+		t.Errorf("Query string should be dropped from the ad URL: %s", tax.URL)
+	}
+	if tax.Fields["apply_url"] != "https://skatteverket.varbi.com/ansok/1" || tax.Fields["method"] != "http" {
+		t.Errorf("Unexpected fields: %+v", tax.Fields)
+	}
+
+	nurse := jobs[1]
+	if nurse.ID != "offentligajobb:234567" || nurse.Company != "Region Uppsala" || nurse.Fields["employer_type"] != "region" {
+		t.Errorf("Unexpected nurse job: %+v", nurse)
+	}
+	if nurse.PostedDate.Format("2006-01-02") != "2025-10-05" || nurse.EmploymentType != "Tillsvidareanställning" {
+		t.Errorf("Unexpected posted date/employment type: %v / %q", nurse.PostedDate, nurse.EmploymentType)
+	}
+	// The deadline is only on the list card
+	if nurse.ExpiresDate.Format("2006-01-02") != "2099-12-15" {
+		t.Errorf("Expected deadline from list card, got %v", nurse.ExpiresDate)
+	}
+	if nurse.Fields["reference_number"] != "RS 2025-1234" || !nurse.IsRemote {
+		t.Errorf("Unexpected reference/remote: %v / %v", nurse.Fields["reference_number"], nurse.IsRemote)
+	}
+}
+
+func TestBlockedWithoutChrome(t *testing.T) {
+	ojc := newTestConnector(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	if _, err := ojc.FetchJobs(); err == nil || !strings.Contains(err.Error(), "chrome fallback is disabled") {
+		t.Fatalf("Expected blocked error, got %v", err)
+	}
+}
+
+func TestParseSwedishDate(t *testing.T) {
+	tests := map[string]string{
+		"2025-10-31":                "2025-10-31",
+		"2025-10-31T23:59:59+01:00": "2025-10-31",
+		"31 oktober 2025":           "2025-10-31",
+		"1 maj 2026":                "2026-05-01",
+		"3 sept. 2025":              "2025-09-03",
+	}
+	for in, want := range tests {
+		if got := parseSwedishDate(in); got.Format("2006-01-02") != want {
+			t.Errorf("parseSwedishDate(%q) = %v, want %s", in, got, want)
+		}
+	}
+	if !parseSwedishDate("snarast").Equal(time.Time{}) {
+		t.Error("Expected zero time for non-date")
+	}
+}
+
+func TestEmployerType(t *testing.T) {
+	tests := map[string]string{
+		"Göteborgs Stad":      "municipality",
+		"Lunds kommun":        "municipality",
+		"Region Skåne":        "region",
+		"Trafikverket":        "agency",
+		"Uppsala universitet": "agency",
+		"Stiftelse X":         "other",
+	}
+	for in, want := range tests {
+		if got := employerType(in); got != want {
+			t.Errorf("employerType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// newReplayConnector returns a plain HTTP connector that replays the recorded crawl
+func newReplayConnector(t *testing.T, store *storage.JobStore) *OffentligaJobbConnector {
+	rec := httpreplay.New(t, "testdata/lediga-jobb.json")
+
+	ojc := NewOffentligaJobbConnector(store)
+	ojc.httpClient = rec.Client()
+	ojc.browser = nil
+	ojc.maxPages = 2
+	return ojc
+}
+
+// TestFetchJobsGolden replays a recorded crawl over plain HTTP.
+// Re-record with HTTPREPLAY=record go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	ojc := newReplayConnector(t, nil)
+
+	jobs, err := ojc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/lediga-jobb.golden.json", jobs)
+}
+
+// TestFetchJobsChromeGolden renders the same fixture in headless Chrome and expects
+// the jobs plain HTTP produced. It needs a local Chrome and is skipped without one.
+func TestFetchJobsChromeGolden(t *testing.T) {
+	path := browser.LookPath()
+	if path == "" {
+		t.Skip("no Chrome binary installed")
+	}
+	t.Setenv("HTTP_RESPECT_ROBOTS", "false") // The fixture has no robots.txt
+
+	rec := httpreplay.New(t, "testdata/lediga-jobb.json")
+	server := rec.Server()
+
+	pool := browser.NewPool(browser.Config{ExecPath: path, MaxTabs: 1, PageTimeout: 30 * time.Second})
+	t.Cleanup(pool.Close)
+
+	ojc := NewOffentligaJobbConnector(nil)
+	ojc.baseURL = server.URL
+	ojc.browser = pool
+	ojc.maxPages = 2
+	ojc.setChrome()
+
+	jobs, err := ojc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	for i := range jobs {
+		if jobs[i].Fields["method"] != "headless_chrome" {
+			t.Errorf("%s: expected method headless_chrome, got %v", jobs[i].ID, jobs[i].Fields["method"])
+		}
+		jobs[i].Fields["method"] = "http"
+		jobs[i] = recordedOrigin(jobs[i], server.URL, "https://www.offentligajobb.se")
+	}
+	httpreplay.AssertGolden(t, "testdata/lediga-jobb.golden.json", jobs)
+}
+
+// recordedOrigin points a job replayed through a local server back at the recorded site
+func recordedOrigin(job models.JobPost, serverURL, origin string) models.JobPost {
+	job.URL = strings.Replace(job.URL, serverURL, origin, 1)
+	for _, key := range []string{"source_url", "apply_url"} {
+		if link, ok := job.Fields[key].(string); ok {
+			job.Fields[key] = strings.Replace(link, serverURL, origin, 1)
+		}
+	}
+	return job
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
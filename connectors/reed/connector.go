@@ -0,0 +1,423 @@
+package reed
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
+	"openjobs/pkg/storage"
+)
+
+// ReedConnector implements a connector for the Reed.co.uk jobseeker API
+type ReedConnector struct {
+	store         *storage.JobStore
+	baseURL       string
+	userAgent     string
+	apiKey        string
+	keywords      string
+	location      string
+	distance      int // Miles from location
+	resultsToTake int
+	maxResults    int
+	httpClient    *http.Client
+}
+
+// ReedSearchResult is a job as listed in /search results
+type ReedSearchResult struct {
+	JobID          int     `json:"jobId"`
+	EmployerID     int     `json:"employerId"`
+	EmployerName   string  `json:"employerName"`
+	JobTitle       string  `json:"jobTitle"`
+	LocationName   string  `json:"locationName"`
+	MinimumSalary  float64 `json:"minimumSalary"`
+	MaximumSalary  float64 `json:"maximumSalary"`
+	Currency       string  `json:"currency"`
+	ExpirationDate string  `json:"expirationDate"` // dd/mm/yyyy
+	Date           string  `json:"date"`           // dd/mm/yyyy
+	JobDescription string  `json:"jobDescription"` // Truncated snippet
+	Applications   int     `json:"applications"`
+	JobURL         string  `json:"jobUrl"`
+}
+
+// ReedSearchResponse represents a page of search results
+type ReedSearchResponse struct {
+	Results      []ReedSearchResult `json:"results"`
+	TotalResults int                `json:"totalResults"`
+}
+
+// ReedJobDetail is the full job from /jobs/{id}
+type ReedJobDetail struct {
+	JobID               int     `json:"jobId"`
+	EmployerName        string  `json:"employerName"`
+	JobTitle            string  `json:"jobTitle"`
+	LocationName        string  `json:"locationName"`
+	MinimumSalary       float64 `json:"minimumSalary"`
+	MaximumSalary       float64 `json:"maximumSalary"`
+	YearlyMinimumSalary float64 `json:"yearlyMinimumSalary"`
+	YearlyMaximumSalary float64 `json:"yearlyMaximumSalary"`
+	Currency            string  `json:"currency"`
+	SalaryType          string  `json:"salaryType"` // e.g. "per annum", "per day"
+	Salary              string  `json:"salary"`
+	DatePosted          string  `json:"datePosted"`
+	ExpirationDate      string  `json:"expirationDate"`
+	ExternalURL         string  `json:"externalUrl"`
+	JobURL              string  `json:"jobUrl"`
+	PartTime            bool    `json:"partTime"`
+	FullTime            bool    `json:"fullTime"`
+	ContractType        string  `json:"contractType"` // Permanent, Contract, Temporary
+	JobDescription      string  `json:"jobDescription"`
+}
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+// NewReedConnector creates a new Reed connector.
+// REED_API_KEY is required; REED_KEYWORDS, REED_LOCATION, REED_DISTANCE (miles)
+// and REED_MAX_RESULTS configure the search.
+func NewReedConnector(store *storage.JobStore) *ReedConnector {
+	distance := 10
+	if n, err := strconv.Atoi(os.Getenv("REED_DISTANCE")); err == nil && n >= 0 {
+		distance = n
+	}
+
+	maxResults := 500
+	if n, err := strconv.Atoi(os.Getenv("REED_MAX_RESULTS")); err == nil && n > 0 {
+		maxResults = n
+	}
+
+	return &ReedConnector{
+		store:         store,
+		baseURL:       "https://www.reed.co.uk/api/1.0",
+		userAgent:     httpclient.UserAgent("OpenJobs-Reed-Connector/1.0"),
+		apiKey:        os.Getenv("REED_API_KEY"),
+		keywords:      os.Getenv("REED_KEYWORDS"),
+		location:      os.Getenv("REED_LOCATION"),
+		distance:      distance,
+		resultsToTake: 100, // API maximum
+		maxResults:    maxResults,
+		httpClient:    httpclient.New(httpclient.Options{Interval: 500 * time.Millisecond}),
+	}
+}
+
+// GetID returns the connector ID
+func (rc *ReedConnector) GetID() string {
+	return "reed"
+}
+
+// GetName returns the connector name
+func (rc *ReedConnector) GetName() string {
+	return "Reed.co.uk Connector"
+}
+
+// FetchJobs pages through Reed search results and fetches the full description of every new job
+func (rc *ReedConnector) FetchJobs() ([]models.JobPost, error) {
+	if rc.apiKey == "" {
+		return nil, fmt.Errorf("REED_API_KEY must be set")
+	}
+
+	jobs := []models.JobPost{}
+	known := 0
+	for skip := 0; skip < rc.maxResults; skip += rc.resultsToTake {
+		response, err := rc.search(skip)
+		if err != nil {
+			if skip == 0 {
+				return nil, err
+			}
+			// Keep what earlier pages returned
+			fmt.Printf("⚠️  Reed page at offset %d failed: %v\n", skip, err)
+			break
+		}
+
+		for _, result := range response.Results {
+			if rc.isStored(result.JobID) {
+				known++
+				continue
+			}
+
+			detail, err := rc.fetchDetail(result.JobID)
+			if err != nil {
+				fmt.Printf("   ⚠️  Using search snippet for %d: %v\n", result.JobID, err)
+			}
+			jobs = append(jobs, rc.transformJob(result, detail))
+		}
+
+		fmt.Printf("📄 Reed offset %d: %d results (new so far: %d)\n", skip, len(response.Results), len(jobs))
+
+		if len(response.Results) < rc.resultsToTake || skip+rc.resultsToTake >= response.TotalResults {
+			break
+		}
+	}
+
+	if known > 0 {
+		fmt.Printf("⏭️  Skipped %d Reed jobs already in database\n", known)
+	}
+	return jobs, nil
+}
+
+// search fetches one page of results starting at resultsToSkip
+func (rc *ReedConnector) search(skip int) (*ReedSearchResponse, error) {
+	q := url.Values{}
+	if rc.keywords != "" {
+		q.Set("keywords", rc.keywords)
+	}
+	if rc.location != "" {
+		q.Set("locationName", rc.location)
+		q.Set("distanceFromLocation", strconv.Itoa(rc.distance))
+	}
+	q.Set("resultsToTake", strconv.Itoa(rc.resultsToTake))
+	q.Set("resultsToSkip", strconv.Itoa(skip))
+
+	var response ReedSearchResponse
+	if err := rc.get(fmt.Sprintf("%s/search?%s", rc.baseURL, q.Encode()), &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// fetchDetail fetches the full job, including the complete description
+func (rc *ReedConnector) fetchDetail(jobID int) (*ReedJobDetail, error) {
+	var detail ReedJobDetail
+	if err := rc.get(fmt.Sprintf("%s/jobs/%d", rc.baseURL, jobID), &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// get performs an authenticated GET; Reed uses the API key as basic auth username
+func (rc *ReedConnector) get(url string, v interface{}) error {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(rc.apiKey, "")
+	req.Header.Set("User-Agent", rc.userAgent)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := rc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("reed API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// isStored reports whether the job is already in the database, so its detail isn't re-fetched
+func (rc *ReedConnector) isStored(jobID int) bool {
+	if rc.store == nil {
+		return false
+	}
+	existing, err := rc.store.GetJob(jobid.New("reed", strconv.Itoa(jobID)))
+	return err == nil && existing != nil
+}
+
+// transformJob converts a Reed job to our JobPost format; detail is nil when it couldn't be fetched
+func (rc *ReedConnector) transformJob(result ReedSearchResult, detail *ReedJobDetail) models.JobPost {
+	description := result.JobDescription
+	minSalary, maxSalary := result.MinimumSalary, result.MaximumSalary
+	currency := result.Currency
+	employmentType := ""
+	expires := parseReedDate(result.ExpirationDate)
+	jobURL := result.JobURL
+
+	fields := map[string]interface{}{
+		"source":       "reed",
+		"source_url":   result.JobURL,
+		"original_id":  strconv.Itoa(result.JobID),
+		"employer_id":  result.EmployerID,
+		"applications": result.Applications,
+		"connector":    "reed",
+		"fetched_at":   time.Now(),
+	}
+
+	if detail != nil {
+		if detail.JobDescription != "" {
+			description = detail.JobDescription
+		}
+		if detail.MinimumSalary > 0 || detail.MaximumSalary > 0 {
+			minSalary, maxSalary = detail.MinimumSalary, detail.MaximumSalary
+		}
+		if detail.Currency != "" {
+			currency = detail.Currency
+		}
+		if t := parseReedDate(detail.ExpirationDate); !t.IsZero() {
+			expires = t
+		}
+		if detail.JobURL != "" {
+			jobURL = detail.JobURL
+		}
+		employmentType = mapEmploymentType(detail)
+		fields["contract_type"] = detail.ContractType
+		fields["salary_type"] = detail.SalaryType
+		if detail.YearlyMinimumSalary > 0 {
+			fields["yearly_salary_min"] = detail.YearlyMinimumSalary
+		}
+		if detail.YearlyMaximumSalary > 0 {
+			fields["yearly_salary_max"] = detail.YearlyMaximumSalary
+		}
+		if detail.ExternalURL != "" {
+			fields["apply_url"] = detail.ExternalURL
+		}
+	}
+
+	job := models.JobPost{
+		ID:             jobid.New("reed", strconv.Itoa(result.JobID)),
+		Title:          result.JobTitle,
+		Company:        result.EmployerName,
+		Description:    plainText(description),
+		Location:       result.LocationName,
+		IsRemote:       strings.Contains(strings.ToLower(result.JobTitle+" "+result.LocationName), "remote"),
+		URL:            jobURL,
+		EmploymentType: employmentType,
+		PostedDate:     parseReedDate(result.Date),
+		ExpiresDate:    expires,
+		Requirements:   []string{},
+		Benefits:       []string{},
+		Fields:         fields,
+	}
+	if job.PostedDate.IsZero() {
+		job.PostedDate = time.Now()
+		fields["posted_date_estimated"] = true
+	}
+
+	// Reed salaries are GBP unless stated otherwise
+	if minSalary > 0 || maxSalary > 0 {
+		if currency == "" {
+			currency = "GBP"
+		}
+		pay := salary.Salary{Currency: currency}
+		if detail != nil {
+			pay.Period = salary.ParsePeriod(detail.SalaryType)
+		}
+		if minSalary > 0 {
+			min := int(minSalary)
+			pay.Min = &min
+		}
+		if maxSalary > 0 {
+			max := int(maxSalary)
+			pay.Max = &max
+		}
+		pay.Apply(&job)
+		switch {
+		case minSalary > 0 && maxSalary > 0 && minSalary != maxSalary:
+			job.Salary = fmt.Sprintf("%.0f - %.0f %s", minSalary, maxSalary, currency)
+		case minSalary > 0:
+			job.Salary = fmt.Sprintf("%.0f %s", minSalary, currency)
+		default:
+			job.Salary = fmt.Sprintf("Up to %.0f %s", maxSalary, currency)
+		}
+		if detail != nil && detail.SalaryType != "" {
+			job.Salary += " " + detail.SalaryType
+		}
+	}
+
+	return job
+}
+
+// SyncJobs fetches jobs from Reed and stores them in the database
+func (rc *ReedConnector) SyncJobs() error {
+	startTime := time.Now()
+	fmt.Println("🔄 Starting Reed.co.uk job sync...")
+
+	jobs, err := rc.FetchJobs()
+	if err != nil {
+		rc.store.LogSync(&models.SyncLog{
+			ConnectorName: rc.GetID(),
+			StartedAt:     startTime,
+			CompletedAt:   time.Now(),
+			Status:        "error",
+			ErrorMessage:  err.Error(),
+		})
+		return fmt.Errorf("failed to fetch jobs from Reed: %w", err)
+	}
+
+	fmt.Printf("📥 Fetched %d new jobs from Reed\n", len(jobs))
+
+	stored := 0
+	duplicates := 0
+	for _, job := range jobs {
+		existing, err := rc.store.GetJob(job.ID)
+		if err != nil && err.Error() != "sql: no rows in result set" {
+			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			continue
+		}
+		if existing != nil {
+			duplicates++
+			continue
+		}
+
+		if err := rc.store.CreateJob(&job); err != nil {
+			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			continue
+		}
+
+		stored++
+		fmt.Printf("✅ Stored job: %s at %s\n", job.Title, job.Company)
+	}
+
+	if err := rc.store.LogSync(&models.SyncLog{
+		ConnectorName:  rc.GetID(),
+		StartedAt:      startTime,
+		CompletedAt:    time.Now(),
+		JobsFetched:    len(jobs),
+		JobsInserted:   stored,
+		JobsDuplicates: duplicates,
+		Status:         "success",
+	}); err != nil {
+		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
+	}
+
+	fmt.Printf("🎉 Reed sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
+	return nil
+}
+
+// parseReedDate parses Reed's dd/mm/yyyy dates (zero time when empty or invalid)
+func parseReedDate(dateStr string) time.Time {
+	for _, format := range []string{"02/01/2006", time.RFC3339, "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(format, dateStr); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// mapEmploymentType converts Reed's contract type and hours to our format
+func mapEmploymentType(detail *ReedJobDetail) string {
+	switch strings.ToLower(detail.ContractType) {
+	case "contract", "temporary", "temp":
+		return "Contract"
+	}
+	switch {
+	case detail.PartTime && !detail.FullTime:
+		return "Part-time"
+	case detail.FullTime:
+		return "Full-time"
+	default:
+		return ""
+	}
+}
+
+// plainText strips the HTML Reed descriptions are published with
+func plainText(s string) string {
+	s = htmlTags.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
@@ -0,0 +1,170 @@
+package reed
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+func TestFetchJobs(t *testing.T) {
+	skips := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "secret" || pass != "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/search":
+			q := r.URL.Query()
+			if q.Get("keywords") != "golang" || q.Get("locationName") != "London" || q.Get("distanceFromLocation") != "15" {
+				t.Errorf("Unexpected search query: %s", r.URL.RawQuery)
+			}
+			skips = append(skips, q.Get("resultsToSkip"))
+			if q.Get("resultsToSkip") == "0" {
+				fmt.Fprint(w, `{"totalResults": 3, "results": [
+					{"jobId": 101, "employerName": "Acme Ltd", "jobTitle": "Go Developer", "locationName": "London",
+					 "minimumSalary": 60000, "maximumSalary": 75000, "currency": "GBP",
+					 "date": "01/10/2025", "expirationDate": "12/11/2025", "jobDescription": "Short...",
+					 "jobUrl": "https://www.reed.co.uk/jobs/go-developer/101"},
+					{"jobId": 102, "employerName": "Beta", "jobTitle": "Contract SRE", "locationName": "Croydon",
+					 "minimumSalary": 500, "maximumSalary": 600, "date": "02/10/2025", "jobDescription": "Snippet"}
+				]}`)
+				return
+			}
+			fmt.Fprint(w, `{"totalResults": 3, "results": [
+				{"jobId": 103, "employerName": "Gamma", "jobTitle": "Remote Tester", "date": ""}
+			]}`)
+		case "/jobs/101":
+			fmt.Fprint(w, `{"jobId": 101, "jobDescription": "<p>Full <b>description</b> &amp; more</p>",
+				"minimumSalary": 60000, "maximumSalary": 75000, "yearlyMinimumSalary": 60000, "currency": "GBP",
+				"salaryType": "per annum", "expirationDate": "12/11/2025", "fullTime": true, "contractType": "Permanent",
+				"externalUrl": "https://acme.example/apply", "jobUrl": "https://www.reed.co.uk/jobs/go-developer/101"}`)
+		case "/jobs/102":
+			fmt.Fprint(w, `{"jobId": 102, "jobDescription": "Day rate contract", "minimumSalary": 500,
+				"maximumSalary": 600, "salaryType": "per day", "contractType": "Contract", "fullTime": true}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	rc := NewReedConnector(nil)
+	rc.baseURL = server.URL
+	rc.apiKey = "secret"
+	rc.keywords, rc.location, rc.distance = "golang", "London", 15
+	rc.resultsToTake = 2
+	rc.httpClient = httpclient.New(httpclient.Options{})
+
+	jobs, err := rc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	if len(jobs) != 3 || strings.Join(skips, ",") != "0,2" {
+		t.Fatalf("Expected 3 jobs over offsets 0,2; got %d jobs, offsets %v", len(jobs), skips)
+	}
+
+	dev := jobs[0]
+	if dev.ID != "reed:101" || dev.Company != "Acme Ltd" || dev.Description != "Full description & more" {
+		t.Errorf("Unexpected job: %s %q %q", dev.ID, dev.Company, dev.Description)
+	}
+	if *dev.SalaryMin != 60000 || *dev.SalaryMax != 75000 || dev.SalaryCurrency != "GBP" || dev.Salary != "60000 - 75000 GBP per annum" {
+		t.Errorf("Unexpected salary: %v %v %q %q", *dev.SalaryMin, *dev.SalaryMax, dev.SalaryCurrency, dev.Salary)
+	}
+	if dev.PostedDate.Format("2006-01-02") != "2025-10-01" || dev.ExpiresDate.Format("2006-01-02") != "2025-11-12" {
+		t.Errorf("Unexpected dates: %v %v", dev.PostedDate, dev.ExpiresDate)
+	}
+	if dev.EmploymentType != "Full-time" || dev.Fields["apply_url"] != "https://acme.example/apply" {
+		t.Errorf("Unexpected type/fields: %q %+v", dev.EmploymentType, dev.Fields)
+	}
+
+	if jobs[1].EmploymentType != "Contract" || jobs[1].SalaryCurrency != "GBP" || jobs[1].Fields["salary_type"] != "per day" {
+		t.Errorf("Unexpected contract job: %+v", jobs[1])
+	}
+
+	// Detail lookup fails, so the search result is used as is
+	tester := jobs[2]
+	if tester.ID != "reed:103" || !tester.IsRemote || tester.SalaryMin != nil || tester.EmploymentType != "" {
+		t.Errorf("Unexpected fallback job: %+v", tester)
+	}
+
+	// The undated job gets the fetch time, marked as estimated so it can't move a
+	// checkpoint past the dated jobs
+	if tester.Fields["posted_date_estimated"] != true || dev.Fields["posted_date_estimated"] != nil {
+		t.Errorf("Expected only the undated job to be estimated: %v %v", tester.Fields, dev.Fields)
+	}
+	checkpoint := models.Checkpoint{}
+	checkpoint.Advance(jobs)
+	if checkpoint.HighWatermark.Format("2006-01-02") != "2025-10-02" {
+		t.Errorf("Expected the checkpoint at the newest dated job, got %v", checkpoint.HighWatermark)
+	}
+}
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *ReedConnector {
+	rec := httpreplay.New(t, "testdata/search.json")
+
+	rc := NewReedConnector(store)
+	if rc.apiKey == "" {
+		rc.apiKey = "test" // Sent as basic auth, which fixtures don't keep
+	}
+	rc.keywords = "golang"
+	rc.location = "London"
+	rc.distance = 10
+	rc.maxResults = 100
+	rc.httpClient = rec.Client()
+	return rc
+}
+
+// TestFetchJobsGolden replays recorded API responses, including a detail request that
+// failed. Re-record with HTTPREPLAY=record REED_API_KEY=... go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	rc := newReplayConnector(t, nil)
+
+	jobs, err := rc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/search.golden.json", jobs)
+}
+
+func TestFetchJobsErrors(t *testing.T) {
+	rc := NewReedConnector(nil)
+	rc.apiKey = ""
+	if _, err := rc.FetchJobs(); err == nil {
+		t.Error("Expected error without API key")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	rc.baseURL = server.URL
+	rc.apiKey = "wrong"
+	if _, err := rc.FetchJobs(); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("Expected 401 error, got %v", err)
+	}
+}
+
+func TestParseReedDate(t *testing.T) {
+	if got := parseReedDate("05/03/2026").Format("2006-01-02"); got != "2026-03-05" {
+		t.Errorf("Expected day/month order, got %s", got)
+	}
+	if !parseReedDate("").IsZero() {
+		t.Error("Expected zero time for empty date")
+	}
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
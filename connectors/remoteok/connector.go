@@ -8,15 +8,19 @@ import (
 	"strings"
 	"time"
 
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 )
 
 // RemoteOKConnector implements connector for RemoteOK.com
 type RemoteOKConnector struct {
-	store     *storage.JobStore
-	baseURL   string
-	userAgent string
+	store      *storage.JobStore
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
 }
 
 // RemoteOKJob represents a job from the RemoteOK API
@@ -32,14 +36,17 @@ type RemoteOKJob struct {
 	Date        string   `json:"date"`
 	URL         string   `json:"url"`
 	ApplyURL    string   `json:"apply_url"`
+	SalaryMin   int      `json:"salary_min"` // Yearly USD, 0 when not given
+	SalaryMax   int      `json:"salary_max"`
 }
 
 // NewRemoteOKConnector creates a new connector
 func NewRemoteOKConnector(store *storage.JobStore) *RemoteOKConnector {
 	return &RemoteOKConnector{
-		store:     store,
-		baseURL:   "https://remoteok.com/api",
-		userAgent: "OpenJobs-RemoteOK-Connector/1.0",
+		store:      store,
+		baseURL:    "https://remoteok.com/api",
+		userAgent:  httpclient.UserAgent("OpenJobs-RemoteOK-Connector/1.0"),
+		httpClient: httpclient.New(httpclient.Options{}),
 	}
 }
 
@@ -53,8 +60,25 @@ func (rc *RemoteOKConnector) GetName() string {
 	return "RemoteOK Connector"
 }
 
+// feed is one download of the full RemoteOK listing
+type feed struct {
+	jobs        []models.JobPost // Jobs posted after the checkpoint
+	listed      []string         // IDs of every job in the listing, for snapshot diffing
+	notModified bool             // 304: unchanged since the committed validators
+}
+
 // FetchJobs fetches job listings from RemoteOK API
 func (rc *RemoteOKConnector) FetchJobs() ([]models.JobPost, error) {
+	result, err := rc.fetchFeed(rc.getCheckpoint())
+	if err != nil {
+		return nil, err
+	}
+	return result.jobs, nil
+}
+
+// fetchFeed downloads the whole listing, conditionally when the checkpoint has
+// validators. The response's validators are kept on the checkpoint for SyncJobs to commit.
+func (rc *RemoteOKConnector) fetchFeed(checkpoint *models.Checkpoint) (*feed, error) {
 	url := rc.baseURL
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -64,14 +88,18 @@ func (rc *RemoteOKConnector) FetchJobs() ([]models.JobPost, error) {
 
 	req.Header.Set("User-Agent", rc.userAgent)
 	req.Header.Set("Accept", "application/json")
+	checkpoint.Conditional(req)
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := rc.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch jobs from RemoteOK: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		fmt.Println("📭 RemoteOK listing not modified since the last sync")
+		return &feed{jobs: []models.JobPost{}, notModified: true}, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("remoteOK API error %d: %s", resp.StatusCode, string(body))
@@ -87,34 +115,26 @@ func (rc *RemoteOKConnector) FetchJobs() ([]models.JobPost, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
+	checkpoint.KeepValidators(url, resp.Header)
 
 	// First item is metadata, skip it
 	if len(remoteOKJobs) > 0 {
 		remoteOKJobs = remoteOKJobs[1:]
 	}
 
-	// Get last sync time for incremental sync
-	lastSync := rc.getLastSyncTime()
-	
-	// Filter jobs to only new ones (posted after last sync)
-	filteredJobs := []RemoteOKJob{}
+	// Transform only jobs posted after the checkpoint; the listing keeps every ID
+	lastSync := checkpoint.HighWatermark
+	result := &feed{jobs: []models.JobPost{}, listed: make([]string, 0, len(remoteOKJobs))}
 	for _, remoteOKJob := range remoteOKJobs {
+		result.listed = append(result.listed, jobid.New("remoteok", remoteOKJob.ID))
 		jobDate := rc.parseRemoteOKDate(remoteOKJob.Date)
 		if lastSync.IsZero() || jobDate.After(lastSync) {
-			filteredJobs = append(filteredJobs, remoteOKJob)
+			result.jobs = append(result.jobs, rc.transformRemoteOKJob(remoteOKJob))
 		}
 	}
-	
-	fmt.Printf("📊 Filtered %d jobs from %d total (only new jobs)\n", len(filteredJobs), len(remoteOKJobs))
-
-	// Transform to our JobPost format
-	jobs := make([]models.JobPost, 0, len(filteredJobs))
-	for _, remoteOKJob := range filteredJobs {
-		job := rc.transformRemoteOKJob(remoteOKJob)
-		jobs = append(jobs, job)
-	}
 
-	return jobs, nil
+	fmt.Printf("📊 Filtered %d jobs from %d total (only new jobs)\n", len(result.jobs), len(remoteOKJobs))
+	return result, nil
 }
 
 // transformRemoteOKJob converts RemoteOK job format to our JobPost format
@@ -123,14 +143,11 @@ func (rc *RemoteOKConnector) transformRemoteOKJob(rj RemoteOKJob) models.JobPost
 	url := rc.extractURL(rj)
 	
 	job := models.JobPost{
-		ID:              fmt.Sprintf("remoteok-%s", rj.ID),
+		ID:              jobid.New("remoteok", rj.ID),
 		Title:           rj.Position,
 		Company:         rj.Company,
 		Description:     rc.extractDescription(rj),
 		Location:        rc.formatLocation(rj),
-		Salary:          "", // RemoteOK doesn't provide salary
-		SalaryMin:       nil,
-		SalaryMax:       nil,
 		SalaryCurrency:  "USD",
 		IsRemote:        true, // ⭐ All RemoteOK jobs are remote
 		URL:             url,  // ⭐ Direct application URL
@@ -153,6 +170,25 @@ func (rc *RemoteOKConnector) transformRemoteOKJob(rj RemoteOKJob) models.JobPost
 		},
 	}
 
+	if rj.SalaryMin > 0 || rj.SalaryMax > 0 {
+		pay := salary.Salary{Currency: "USD", Period: salary.Year}
+		if rj.SalaryMin > 0 {
+			pay.Min = &rj.SalaryMin
+		}
+		if rj.SalaryMax > 0 {
+			pay.Max = &rj.SalaryMax
+		}
+		pay.Apply(&job)
+		switch {
+		case pay.Min != nil && pay.Max != nil:
+			job.Salary = fmt.Sprintf("%d - %d USD per year", rj.SalaryMin, rj.SalaryMax)
+		case pay.Min != nil:
+			job.Salary = fmt.Sprintf("From %d USD per year", rj.SalaryMin)
+		default:
+			job.Salary = fmt.Sprintf("Up to %d USD per year", rj.SalaryMax)
+		}
+	}
+
 	return job
 }
 
@@ -207,7 +243,8 @@ func (rc *RemoteOKConnector) SyncJobs() error {
 	startTime := time.Now()
 	fmt.Println("🔄 Starting RemoteOK remote jobs sync...")
 
-	jobs, err := rc.FetchJobs()
+	checkpoint := rc.getCheckpoint()
+	result, err := rc.fetchFeed(checkpoint)
 	if err != nil {
 		// Log failed sync
 		rc.store.LogSync(&models.SyncLog{
@@ -221,16 +258,19 @@ func (rc *RemoteOKConnector) SyncJobs() error {
 		})
 		return fmt.Errorf("failed to fetch jobs from RemoteOK: %w", err)
 	}
+	jobs := result.jobs
 
 	fmt.Printf("📥 Fetched %d remote jobs from RemoteOK\n", len(jobs))
 
 	stored := 0
 	duplicates := 0
+	failed := 0
 	for _, job := range jobs {
 		// Check if job already exists
 		existing, err := rc.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -244,6 +284,7 @@ func (rc *RemoteOKConnector) SyncJobs() error {
 		err = rc.store.CreateJob(&job)
 		if err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -251,6 +292,24 @@ func (rc *RemoteOKConnector) SyncJobs() error {
 		fmt.Printf("✅ Stored remote job: %s at %s\n", job.Title, job.Company)
 	}
 
+	// Close jobs that dropped off the listing and commit the checkpoint, only once every
+	// job is stored so failed jobs are retried. A 304 has nothing to diff or commit.
+	removed := 0
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if !result.notModified {
+		closed, err := rc.store.CloseMissingJobs(rc.GetID(), result.listed, time.Now())
+		removed = len(closed)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to close jobs missing from the listing - checkpoint not advanced: %v\n", err)
+		} else {
+			checkpoint.Advance(jobs)
+			if err := rc.store.SaveCheckpoint(checkpoint); err != nil {
+				fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+			}
+		}
+	}
+
 	// Log successful sync
 	if err := rc.store.LogSync(&models.SyncLog{
 		ConnectorName:  rc.GetID(),
@@ -259,12 +318,13 @@ func (rc *RemoteOKConnector) SyncJobs() error {
 		JobsFetched:    len(jobs),
 		JobsInserted:   stored,
 		JobsDuplicates: duplicates,
+		JobsRemoved:    removed,
 		Status:         "success",
 	}); err != nil {
 		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
 	}
 
-	fmt.Printf("🎉 RemoteOK sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
+	fmt.Printf("🎉 RemoteOK sync complete! Fetched: %d, Inserted: %d, Duplicates: %d, Closed: %d\n", len(jobs), stored, duplicates, removed)
 	return nil
 }
 
@@ -303,14 +363,23 @@ seen[keyword] = true
 return requirements
 }
 
-// getLastSyncTime retrieves the timestamp of the most recent job in database
-func (rc *RemoteOKConnector) getLastSyncTime() time.Time {
-	job, err := rc.store.GetMostRecentJob("remoteok-")
+// getCheckpoint returns the checkpoint committed by the last successful sync, or an
+// empty one (process all jobs, unconditional request)
+func (rc *RemoteOKConnector) getCheckpoint() *models.Checkpoint {
+	empty := &models.Checkpoint{ConnectorID: rc.GetID()}
+	if rc.store == nil {
+		return empty
+	}
+	checkpoint, err := rc.store.GetCheckpoint(rc.GetID(), "")
 	if err != nil {
-		fmt.Println("📅 No previous RemoteOK jobs found - processing all jobs")
-		return time.Time{}
+		fmt.Printf("⚠️  Failed to read RemoteOK checkpoint, processing all jobs: %v\n", err)
+		return empty
 	}
-	
-	fmt.Printf("📅 Last RemoteOK job in database: %s (posted: %s)\n", job.Title, job.PostedDate.Format("2006-01-02"))
-	return job.PostedDate
+	if checkpoint.HighWatermark.IsZero() {
+		fmt.Println("📅 No RemoteOK checkpoint yet - processing all jobs")
+		return checkpoint
+	}
+
+	fmt.Printf("📅 RemoteOK checkpoint: jobs posted up to %s\n", checkpoint.HighWatermark.Format("2006-01-02"))
+	return checkpoint
 }
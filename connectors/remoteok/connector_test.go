@@ -0,0 +1,120 @@
+package remoteok
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *RemoteOKConnector {
+	rec := httpreplay.New(t, "testdata/api.json")
+
+	rc := NewRemoteOKConnector(store)
+	rc.httpClient = rec.Client()
+	return rc
+}
+
+// TestFetchJobsGolden replays a recorded API response. Re-record with
+// HTTPREPLAY=record go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	rc := newReplayConnector(t, nil)
+
+	jobs, err := rc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/api.golden.json", jobs)
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
+
+// TestSyncJobsConditionalAndSnapshotDiff checks that an unchanged feed is answered with
+// 304 and skipped, and that jobs which drop off the listing are closed
+func TestSyncJobsConditionalAndSnapshotDiff(t *testing.T) {
+	var mu sync.Mutex
+	etag, listing := `"v1"`, []string{"101", "102"}
+	ifNoneMatch := []string{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, `[{"legal": "metadata"}`)
+		for _, id := range listing {
+			fmt.Fprintf(w, `, {"id": %q, "position": "Go Engineer", "company": "Acme", "date": "2025-10-01T08:00:00Z"}`, id)
+		}
+		fmt.Fprint(w, `]`)
+	}))
+	defer api.Close()
+
+	server := storagetest.NewServer(t)
+	runSync := func() {
+		t.Helper()
+		rc := NewRemoteOKConnector(server.Store())
+		rc.baseURL = api.URL
+		rc.httpClient = api.Client()
+		if err := rc.SyncJobs(); err != nil {
+			t.Fatalf("SyncJobs failed: %v", err)
+		}
+	}
+
+	runSync()
+	runSync() // Unchanged: 304
+	mu.Lock()
+	etag, listing = `"v2"`, []string{"102"}
+	mu.Unlock()
+	runSync()
+
+	if want := []string{"", `"v1"`, `"v1"`}; fmt.Sprint(ifNoneMatch) != fmt.Sprint(want) {
+		t.Errorf("If-None-Match headers = %q, want %q", ifNoneMatch, want)
+	}
+
+	logs := server.SyncLogs()
+	if len(logs) != 3 || logs[1].JobsFetched != 0 || logs[2].JobsRemoved != 1 {
+		t.Errorf("Unexpected sync logs: %+v", logs)
+	}
+	for _, job := range server.Jobs() {
+		closed := job.Fields["closed_reason"] == "missing_from_feed"
+		if closed != (job.ID == "remoteok:101") {
+			t.Errorf("%s: closed = %v, only remoteok:101 dropped off the listing", job.ID, closed)
+		}
+	}
+	if checkpoint, _ := server.Store().GetCheckpoint("remoteok", ""); checkpoint.ETags[api.URL] != `"v2"` {
+		t.Errorf("Committed ETags = %v, want the v2 ETag", checkpoint.ETags)
+	}
+}
+
+func TestSalaryTextFromPresentBounds(t *testing.T) {
+	rc := NewRemoteOKConnector(nil)
+	for _, tt := range []struct {
+		min, max int
+		want     string
+	}{
+		{150000, 210000, "150000 - 210000 USD per year"},
+		{150000, 0, "From 150000 USD per year"},
+		{0, 210000, "Up to 210000 USD per year"},
+		{0, 0, ""},
+	} {
+		job := rc.transformRemoteOKJob(RemoteOKJob{ID: "1", SalaryMin: tt.min, SalaryMax: tt.max})
+		if job.Salary != tt.want {
+			t.Errorf("salary %d-%d: got %q, want %q", tt.min, tt.max, job.Salary, tt.want)
+		}
+	}
+}
@@ -9,15 +9,19 @@ import (
 	"strings"
 	"time"
 
+	"openjobs/pkg/httpclient"
+	"openjobs/pkg/jobid"
 	"openjobs/pkg/models"
+	"openjobs/pkg/salary"
 	"openjobs/pkg/storage"
 )
 
 // RemotiveConnector implements connector for Remotive remote job platform
 type RemotiveConnector struct {
-	store     *storage.JobStore
-	baseURL   string
-	userAgent string
+	store      *storage.JobStore
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
 }
 
 // RemotiveJob represents a job from the Remotive API
@@ -38,15 +42,17 @@ type RemotiveJob struct {
 
 // RemotiveResponse represents the API response
 type RemotiveResponse struct {
-	Jobs []RemotiveJob `json:"jobs"`
+	Jobs          []RemotiveJob `json:"jobs"`
+	TotalJobCount int           `json:"total-job-count"`
 }
 
 // NewRemotiveConnector creates a new connector
 func NewRemotiveConnector(store *storage.JobStore) *RemotiveConnector {
 	return &RemotiveConnector{
-		store:     store,
-		baseURL:   "https://remotive.com/api", // Changed from remotive.io to remotive.com (SSL issue on .io)
-		userAgent: "OpenJobs-Remotive-Connector/1.0",
+		store:      store,
+		baseURL:    "https://remotive.com/api", // Changed from remotive.io to remotive.com (SSL issue on .io)
+		userAgent:  httpclient.UserAgent("OpenJobs-Remotive-Connector/1.0"),
+		httpClient: httpclient.New(httpclient.Options{}),
 	}
 }
 
@@ -60,9 +66,27 @@ func (rc *RemotiveConnector) GetName() string {
 	return "Remotive Remote Jobs Connector"
 }
 
+// feed is one download of the full Remotive listing
+type feed struct {
+	jobs        []models.JobPost // Jobs posted after the checkpoint
+	listed      []string         // IDs of every job in the listing; nil unless it is complete
+	notModified bool             // 304: unchanged since the committed validators
+}
+
 // FetchJobs fetches job listings from Remotive API
 func (rc *RemotiveConnector) FetchJobs() ([]models.JobPost, error) {
-	url := fmt.Sprintf("%s/remote-jobs?limit=100", rc.baseURL) // Increased from 10 to 100
+	result, err := rc.fetchFeed(rc.getCheckpoint())
+	if err != nil {
+		return nil, err
+	}
+	return result.jobs, nil
+}
+
+// fetchFeed downloads the whole listing (no limit, so absent jobs can be closed),
+// conditionally when the checkpoint has validators. The response's validators are
+// kept on the checkpoint for SyncJobs to commit.
+func (rc *RemotiveConnector) fetchFeed(checkpoint *models.Checkpoint) (*feed, error) {
+	url := fmt.Sprintf("%s/remote-jobs", rc.baseURL)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -71,17 +95,22 @@ func (rc *RemotiveConnector) FetchJobs() ([]models.JobPost, error) {
 
 	req.Header.Set("User-Agent", rc.userAgent)
 	req.Header.Set("Accept", "application/json")
+	checkpoint.Conditional(req)
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := rc.httpClient.Do(req)
 	if err != nil {
 		// Remotive API often has SSL issues - return empty array instead of failing
 		fmt.Printf("⚠️  Remotive API unavailable (SSL/connection error): %v\n", err)
 		fmt.Println("   Skipping Remotive sync - will retry next cycle")
-		return []models.JobPost{}, nil // Return empty array, don't fail
+		return &feed{jobs: []models.JobPost{}}, nil // Return empty array, don't fail
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		fmt.Println("📭 Remotive listing not modified since the last sync")
+		return &feed{jobs: []models.JobPost{}, notModified: true}, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		// Check if it's a Cloudflare SSL error (526)
@@ -89,7 +118,7 @@ func (rc *RemotiveConnector) FetchJobs() ([]models.JobPost, error) {
 			fmt.Printf("⚠️  Remotive API has SSL certificate issues (Error 526)\n")
 			fmt.Println("   This is a Remotive server issue, not a problem with OpenJobs")
 			fmt.Println("   Skipping Remotive sync - will retry next cycle")
-			return []models.JobPost{}, nil // Return empty array, don't fail
+			return &feed{jobs: []models.JobPost{}}, nil // Return empty array, don't fail
 		}
 		return nil, fmt.Errorf("remotive API error %d: %s", resp.StatusCode, string(body))
 	}
@@ -105,45 +134,44 @@ func (rc *RemotiveConnector) FetchJobs() ([]models.JobPost, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	// Get last sync time for incremental sync (client-side filtering)
-	lastSync := rc.getLastSyncTime()
-	
-	// Filter jobs to only new ones (posted after last sync)
-	filteredJobs := []RemotiveJob{}
+	checkpoint.KeepValidators(url, resp.Header)
+
+	// Transform only jobs posted after the checkpoint (client-side filtering)
+	lastSync := checkpoint.HighWatermark
+	result := &feed{jobs: []models.JobPost{}}
+	listed := make([]string, 0, len(remotiveResponse.Jobs))
 	for _, remotiveJob := range remotiveResponse.Jobs {
+		listed = append(listed, jobid.New("remotive", strconv.Itoa(remotiveJob.ID)))
 		jobDate := rc.parseRemotiveDate(remotiveJob.PublicationDate)
 		if lastSync.IsZero() || jobDate.After(lastSync) {
-			filteredJobs = append(filteredJobs, remotiveJob)
+			result.jobs = append(result.jobs, rc.transformRemotiveJob(remotiveJob))
 		}
 	}
-	
-	fmt.Printf("📊 Filtered %d jobs from %d total (only new jobs)\n", len(filteredJobs), len(remotiveResponse.Jobs))
 
-	// Transform to our JobPost format
-	jobs := make([]models.JobPost, 0, len(filteredJobs))
-	for _, remotiveJob := range filteredJobs {
-		job := rc.transformRemotiveJob(remotiveJob)
-		jobs = append(jobs, job)
+	// A truncated listing says nothing about the jobs it left out
+	if remotiveResponse.TotalJobCount <= len(remotiveResponse.Jobs) {
+		result.listed = listed
+	} else {
+		fmt.Printf("⚠️  Remotive returned %d of %d jobs - not closing missing jobs\n", len(remotiveResponse.Jobs), remotiveResponse.TotalJobCount)
 	}
 
-	return jobs, nil
+	fmt.Printf("📊 Filtered %d jobs from %d total (only new jobs)\n", len(result.jobs), len(remotiveResponse.Jobs))
+	return result, nil
 }
 
 // transformRemotiveJob converts Remotive job format to our JobPost format
 func (rc *RemotiveConnector) transformRemotiveJob(rj RemotiveJob) models.JobPost {
-	// Parse salary if available
-	salaryMin, salaryMax, currency := rc.parseSalary(rj.Salary)
+	// Parse salary if available; Remotive salaries are yearly USD unless stated
+	pay := salary.Parse(rj.Salary, "USD", salary.Year)
 	
 	job := models.JobPost{
-		ID:              fmt.Sprintf("remotive-%d", rj.ID),
+		ID:              jobid.New("remotive", strconv.Itoa(rj.ID)),
 		Title:           rj.Title,
 		Company:         rj.CompanyName,
 		Description:     rc.extractDescription(rj),
 		Location:        rc.formatLocation(rj),
 		Salary:          rj.Salary,
-		SalaryMin:       salaryMin,
-		SalaryMax:       salaryMax,
-		SalaryCurrency:  currency,
+		SalaryCurrency:  pay.Currency,
 		IsRemote:        true, // All Remotive jobs are remote
 		URL:             rj.URL, // Direct application link
 		EmploymentType:  rc.mapEmploymentType(rj.JobType),
@@ -164,6 +192,7 @@ func (rc *RemotiveConnector) transformRemotiveJob(rj RemotiveJob) models.JobPost
 			"fetched_at":                  time.Now(),
 		},
 	}
+	pay.Apply(&job)
 
 	return job
 }
@@ -214,6 +243,11 @@ func (rc *RemotiveConnector) parseRemotiveDate(dateStr string) time.Time {
 		return t
 	}
 
+	// The API sends timestamps without a zone, e.g. 2025-10-14T08:12:33 (UTC)
+	if t, err := time.Parse("2006-01-02T15:04:05", dateStr); err == nil {
+		return t
+	}
+
 	return time.Now()
 }
 
@@ -222,7 +256,8 @@ func (rc *RemotiveConnector) SyncJobs() error {
 	startTime := time.Now()
 	fmt.Println("🔄 Starting Remotive remote jobs sync...")
 
-	jobs, err := rc.FetchJobs()
+	checkpoint := rc.getCheckpoint()
+	result, err := rc.fetchFeed(checkpoint)
 	if err != nil {
 		// Log failed sync
 		rc.store.LogSync(&models.SyncLog{
@@ -236,16 +271,19 @@ func (rc *RemotiveConnector) SyncJobs() error {
 		})
 		return fmt.Errorf("failed to fetch jobs from Remotive: %w", err)
 	}
+	jobs := result.jobs
 
 	fmt.Printf("📥 Fetched %d remote jobs from Remotive\n", len(jobs))
 
 	stored := 0
 	duplicates := 0
+	failed := 0
 	for _, job := range jobs {
 		// Check if job already exists
 		existing, err := rc.store.GetJob(job.ID)
 		if err != nil && err.Error() != "sql: no rows in result set" {
 			fmt.Printf("⚠️  Error checking existing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -259,6 +297,7 @@ func (rc *RemotiveConnector) SyncJobs() error {
 		err = rc.store.CreateJob(&job)
 		if err != nil {
 			fmt.Printf("❌ Error storing job %s: %v\n", job.ID, err)
+			failed++
 			continue
 		}
 
@@ -266,6 +305,24 @@ func (rc *RemotiveConnector) SyncJobs() error {
 		fmt.Printf("✅ Stored remote job: %s at %s\n", job.Title, job.Company)
 	}
 
+	// Close jobs that dropped off the listing and commit the checkpoint, only once every
+	// job is stored so failed jobs are retried. Skipped or incomplete listings are not diffed.
+	removed := 0
+	if failed > 0 {
+		fmt.Printf("⚠️  %d jobs failed - checkpoint not advanced\n", failed)
+	} else if len(result.listed) > 0 {
+		closed, err := rc.store.CloseMissingJobs(rc.GetID(), result.listed, time.Now())
+		removed = len(closed)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to close jobs missing from the listing - checkpoint not advanced: %v\n", err)
+		} else {
+			checkpoint.Advance(jobs)
+			if err := rc.store.SaveCheckpoint(checkpoint); err != nil {
+				fmt.Printf("⚠️  Failed to commit checkpoint: %v\n", err)
+			}
+		}
+	}
+
 	// Log successful sync
 	if err := rc.store.LogSync(&models.SyncLog{
 		ConnectorName:  rc.GetID(),
@@ -274,12 +331,13 @@ func (rc *RemotiveConnector) SyncJobs() error {
 		JobsFetched:    len(jobs),
 		JobsInserted:   stored,
 		JobsDuplicates: duplicates,
+		JobsRemoved:    removed,
 		Status:         "success",
 	}); err != nil {
 		fmt.Printf("⚠️  Failed to log sync: %v\n", err)
 	}
 
-	fmt.Printf("🎉 Remotive sync complete! Fetched: %d, Inserted: %d, Duplicates: %d\n", len(jobs), stored, duplicates)
+	fmt.Printf("🎉 Remotive sync complete! Fetched: %d, Inserted: %d, Duplicates: %d, Closed: %d\n", len(jobs), stored, duplicates, removed)
 	return nil
 }
 // extractRequirements extracts keywords from title, description, and tags
@@ -323,60 +381,23 @@ seen[rj.Category] = true
 return requirements
 }
 
-// parseSalary parses salary string to extract min, max, and currency
-func (rc *RemotiveConnector) parseSalary(salaryStr string) (*int, *int, string) {
-if salaryStr == "" {
-return nil, nil, "USD"
-}
-
-// Detect currency
-currency := "USD"
-if strings.Contains(salaryStr, "€") || strings.Contains(salaryStr, "EUR") {
-currency = "EUR"
-} else if strings.Contains(salaryStr, "£") || strings.Contains(salaryStr, "GBP") {
-currency = "GBP"
-}
-
-// Remove currency symbols and common words
-cleanStr := strings.ReplaceAll(salaryStr, "$", "")
-cleanStr = strings.ReplaceAll(cleanStr, "€", "")
-cleanStr = strings.ReplaceAll(cleanStr, "£", "")
-cleanStr = strings.ReplaceAll(cleanStr, "USD", "")
-cleanStr = strings.ReplaceAll(cleanStr, "EUR", "")
-cleanStr = strings.ReplaceAll(cleanStr, "GBP", "")
-cleanStr = strings.ReplaceAll(cleanStr, "k", "000")
-cleanStr = strings.ReplaceAll(cleanStr, "K", "000")
-cleanStr = strings.ReplaceAll(cleanStr, ",", "")
-cleanStr = strings.ReplaceAll(cleanStr, " ", "")
-
-// Try to find range (e.g., "50000-80000" or "50-80k")
-if strings.Contains(cleanStr, "-") {
-parts := strings.Split(cleanStr, "-")
-if len(parts) == 2 {
-var min, max int
-if m, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
-min = m
-}
-if m, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-max = m
-}
-if min > 0 && max > 0 {
-return &min, &max, currency
-}
-}
-}
-
-return nil, nil, currency
-}
-
-// getLastSyncTime retrieves the timestamp of the most recent job in database
-func (rc *RemotiveConnector) getLastSyncTime() time.Time {
-	job, err := rc.store.GetMostRecentJob("remotive-")
+// getCheckpoint returns the checkpoint committed by the last successful sync, or an
+// empty one (process all jobs, unconditional request)
+func (rc *RemotiveConnector) getCheckpoint() *models.Checkpoint {
+	empty := &models.Checkpoint{ConnectorID: rc.GetID()}
+	if rc.store == nil {
+		return empty
+	}
+	checkpoint, err := rc.store.GetCheckpoint(rc.GetID(), "")
 	if err != nil {
-		fmt.Println("📅 No previous Remotive jobs found - processing all jobs")
-		return time.Time{}
+		fmt.Printf("⚠️  Failed to read Remotive checkpoint, processing all jobs: %v\n", err)
+		return empty
 	}
-	
-	fmt.Printf("📅 Last Remotive job in database: %s (posted: %s)\n", job.Title, job.PostedDate.Format("2006-01-02"))
-	return job.PostedDate
+	if checkpoint.HighWatermark.IsZero() {
+		fmt.Println("📅 No Remotive checkpoint yet - processing all jobs")
+		return checkpoint
+	}
+
+	fmt.Printf("📅 Remotive checkpoint: jobs posted up to %s\n", checkpoint.HighWatermark.Format("2006-01-02"))
+	return checkpoint
 }
@@ -0,0 +1,70 @@
+package remotive
+
+import (
+	"testing"
+	"time"
+
+	"openjobs/pkg/conformance"
+	"openjobs/pkg/httpreplay"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+// newReplayConnector returns a connector that replays the recorded fixture
+func newReplayConnector(t *testing.T, store *storage.JobStore) *RemotiveConnector {
+	rec := httpreplay.New(t, "testdata/api.json")
+
+	rc := NewRemotiveConnector(store)
+	rc.httpClient = rec.Client()
+	return rc
+}
+
+// TestFetchJobsGolden replays a recorded API response. Re-record with
+// HTTPREPLAY=record go test -run Golden
+func TestFetchJobsGolden(t *testing.T) {
+	rc := newReplayConnector(t, nil)
+
+	jobs, err := rc.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	httpreplay.AssertGolden(t, "testdata/api.golden.json", jobs)
+}
+
+func TestConformance(t *testing.T) {
+	conformance.Run(t, func(t *testing.T, store *storage.JobStore) models.PluginConnector {
+		return newReplayConnector(t, store)
+	}, conformance.Options{})
+}
+
+// TestSyncJobsCheckpoint checks that incremental sync follows the committed checkpoint,
+// not the newest stored job: a job inserted by hand must not hide the feed
+func TestSyncJobsCheckpoint(t *testing.T) {
+	server := storagetest.NewServer(t)
+	manual := models.JobPost{ID: "remotive:manual", Title: "Manual", PostedDate: time.Now().AddDate(0, 1, 0)}
+	if err := server.Store().CreateJob(&manual); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := newReplayConnector(t, nil).FetchJobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := newReplayConnector(t, server.Store()).SyncJobs(); err != nil {
+		t.Fatalf("SyncJobs failed: %v", err)
+	}
+	if got := len(server.Jobs()); got != len(jobs)+1 {
+		t.Errorf("Expected %d stored jobs, got %d", len(jobs)+1, got)
+	}
+
+	checkpoint, err := server.Store().GetCheckpoint("remotive", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := models.Checkpoint{}
+	want.Advance(jobs)
+	if want.HighWatermark.IsZero() || !checkpoint.HighWatermark.Equal(want.HighWatermark) {
+		t.Errorf("HighWatermark = %v, want the newest fetched posted date %v", checkpoint.HighWatermark, want.HighWatermark)
+	}
+}
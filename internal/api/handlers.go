@@ -6,9 +6,13 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"openjobs/internal/scheduler"
+	"openjobs/pkg/dedup"
+	"openjobs/pkg/geo"
+	"openjobs/pkg/httpclient"
 	"openjobs/pkg/models"
 	"openjobs/pkg/storage"
 
@@ -514,10 +518,14 @@ func (s *Server) AnalyticsHandler(w http.ResponseWriter, r *http.Request) {
 		lastSyncTime = logs[0].StartedAt.Format(time.RFC3339)
 	}
 
+	// Salaries in euros per year; nil until migrations/010 is applied
+	salaries, _ := s.jobStore.GetSalarySummary()
+
 	// Return real analytics data
 	response := models.APIResponse{
 		Success: true,
 		Data: map[string]interface{}{
+			"salaries": salaries,
 			"summary": map[string]interface{}{
 				"total_jobs":        totalJobs,
 				"sources_count":     6,
@@ -650,7 +658,59 @@ func (s *Server) GetAllJobs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	jobs, err := s.jobStore.GetAllJobs(limit, offset)
+	// collapse=true shows one job per duplicate cluster, as its golden record (see pkg/dedup)
+	collapse := r.URL.Query().Get("collapse") == "true"
+	merged := collapse || r.URL.Query().Get("merged") == "true"
+	provenance := r.URL.Query().Get("provenance") == "true"
+
+	filter := storage.JobFilter{
+		Collapse:    collapse,
+		Country:     r.URL.Query().Get("country"),
+		Region:      r.URL.Query().Get("region"),
+		City:        r.URL.Query().Get("city"),
+		RemoteScope: r.URL.Query().Get("remote_scope"),
+	}
+
+	// min_salary_eur / max_salary_eur filter on the annual salary in euros (see pkg/currency)
+	for _, p := range []struct {
+		param string
+		bound *int
+	}{{"min_salary_eur", &filter.MinSalaryEUR}, {"max_salary_eur", &filter.MaxSalaryEUR}} {
+		param, bound := p.param, p.bound
+		if v := r.URL.Query().Get(param); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 0 {
+				http.Error(w, fmt.Sprintf(`{"success": false, "message": "Invalid %s"}`, param), http.StatusBadRequest)
+				return
+			}
+			*bound = parsed
+		}
+	}
+
+	// near=lat,lon (or a place name: near=Uppsala) with radius_km sorts by distance
+	if near := r.URL.Query().Get("near"); near != "" {
+		point, ok := geo.ParsePoint(near)
+		if !ok {
+			if loc := s.jobStore.Gazetteer().Resolve(near, false); loc.Lat != nil && loc.Lon != nil {
+				point, ok = geo.Point{Lat: *loc.Lat, Lon: *loc.Lon}, true
+			}
+		}
+		if !ok {
+			http.Error(w, `{"success": false, "message": "Invalid near, expected lat,lon or a place name"}`, http.StatusBadRequest)
+			return
+		}
+		filter.Near, filter.RadiusKm = &point, 25
+		if v := r.URL.Query().Get("radius_km"); v != "" {
+			radius, err := strconv.ParseFloat(v, 64)
+			if err != nil || radius <= 0 || radius > 500 {
+				http.Error(w, `{"success": false, "message": "Invalid radius_km, expected 0-500"}`, http.StatusBadRequest)
+				return
+			}
+			filter.RadiusKm = radius
+		}
+	}
+
+	jobs, err := s.jobStore.SearchJobs(filter, limit, offset)
 	if err != nil {
 		http.Error(w, `{"success": false, "message": "Failed to retrieve jobs"}`, http.StatusInternalServerError)
 		return
@@ -660,6 +720,9 @@ func (s *Server) GetAllJobs(w http.ResponseWriter, r *http.Request) {
 		Success: true,
 		Data:    jobs,
 	}
+	if merged || provenance {
+		response.Data = s.jobViews(jobs, merged, provenance)
+	}
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -705,7 +768,8 @@ func (s *Server) GetJobByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	job, err := s.jobStore.GetJob(id)
+	// Old IDs keep resolving after the job was renamed (see pkg/jobid)
+	job, err := s.jobStore.GetJobOrAlias(id)
 	if err != nil {
 		if err.Error() == "sql: no rows in result set" {
 			http.Error(w, `{"success": false, "message": "Job not found"}`, http.StatusNotFound)
@@ -719,6 +783,102 @@ func (s *Server) GetJobByID(w http.ResponseWriter, r *http.Request) {
 		Success: true,
 		Data:    job,
 	}
+	merged := r.URL.Query().Get("merged") == "true"
+	if provenance := r.URL.Query().Get("provenance") == "true"; merged || provenance {
+		response.Data = s.jobViews([]*models.JobPost{job}, merged, provenance)[0]
+	}
+
+	json.NewEncoder(w).Encode(response)
+}
+
+// jobViews returns jobs as the API shows them. merged replaces a job with the golden
+// record of its duplicate cluster when there is one; provenance adds the job each
+// field was taken from and the jobs the record was merged from.
+func (s *Server) jobViews(jobs []*models.JobPost, merged, provenance bool) []models.JobView {
+	records := map[string]*models.GoldenRecord{}
+	if merged {
+		clusterIDs := []string{}
+		for _, job := range jobs {
+			if clusterID, _ := job.Fields["cluster_id"].(string); clusterID != "" {
+				clusterIDs = append(clusterIDs, clusterID)
+			}
+		}
+		found, err := s.jobStore.GetGoldenRecords(clusterIDs)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to read golden records, showing source jobs: %v\n", err)
+		} else {
+			records = found
+		}
+	}
+
+	views := make([]models.JobView, 0, len(jobs))
+	for _, job := range jobs {
+		clusterID, _ := job.Fields["cluster_id"].(string)
+		record := records[clusterID]
+		if record == nil {
+			// A job on its own is its own record: every field comes from itself
+			record = dedup.Merge(job.ID, []*models.JobPost{job}, dedup.DefaultTrust)
+			record.Job = *job
+		}
+		view := models.JobView{JobPost: record.Job}
+		if km, ok := job.Fields["distance_km"]; ok {
+			// A radius search measured the listed job; keep its distance on the record
+			fields := map[string]interface{}{"distance_km": km}
+			for key, value := range view.Fields {
+				if key != "distance_km" {
+					fields[key] = value
+				}
+			}
+			view.Fields = fields
+		}
+		if provenance {
+			view.Provenance = record.Provenance
+			view.MergedFrom = record.JobIDs
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// GetJobDuplicates handles GET /jobs/{id}/duplicates: the other jobs of the job's
+// duplicate cluster, i.e. the same posting stored from other sources
+func (s *Server) GetJobDuplicates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	id := strings.TrimSuffix(r.URL.Path[len("/jobs/"):], "/duplicates")
+	if id == "" {
+		http.Error(w, `{"success": false, "message": "Job ID required"}`, http.StatusBadRequest)
+		return
+	}
+
+	job, err := s.jobStore.GetJobOrAlias(id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			http.Error(w, `{"success": false, "message": "Job not found"}`, http.StatusNotFound)
+			return
+		}
+		http.Error(w, `{"success": false, "message": "Failed to retrieve job"}`, http.StatusInternalServerError)
+		return
+	}
+
+	duplicates := []*models.JobPost{}
+	if clusterID, _ := job.Fields["cluster_id"].(string); clusterID != "" {
+		members, err := s.jobStore.GetClusterJobs(clusterID)
+		if err != nil {
+			http.Error(w, `{"success": false, "message": "Failed to retrieve duplicates"}`, http.StatusInternalServerError)
+			return
+		}
+		for _, member := range members {
+			if member.ID != job.ID {
+				duplicates = append(duplicates, member)
+			}
+		}
+	}
+
+	response := models.APIResponse{
+		Success: true,
+		Data:    duplicates,
+	}
 
 	json.NewEncoder(w).Encode(response)
 }
@@ -807,6 +967,69 @@ func (s *Server) SyncLogsHandler(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// CheckpointsHandler handles /sync/checkpoints - List (GET) or reset (DELETE) connector checkpoints
+// GET takes an optional ?connector=, DELETE requires it and resets one query when ?query= is given
+func (s *Server) CheckpointsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	connectorID := r.URL.Query().Get("connector")
+
+	switch r.Method {
+	case http.MethodGet:
+		checkpoints, err := s.jobStore.GetCheckpoints(connectorID)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.APIResponse{
+				Success: false,
+				Message: fmt.Sprintf("Failed to fetch checkpoints: %v", err),
+				Data:    []models.Checkpoint{},
+			})
+			return
+		}
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Data:    checkpoints,
+		})
+
+	case http.MethodDelete:
+		if connectorID == "" {
+			http.Error(w, `{"success": false, "message": "connector parameter required"}`, http.StatusBadRequest)
+			return
+		}
+		var queryKey *string
+		if r.URL.Query().Has("query") {
+			query := r.URL.Query().Get("query")
+			queryKey = &query
+		}
+		if err := s.jobStore.DeleteCheckpoints(connectorID, queryKey); err != nil {
+			http.Error(w, `{"success": false, "message": "Failed to reset checkpoints"}`, http.StatusInternalServerError)
+			return
+		}
+		fmt.Printf("🔁 Checkpoints reset for %s\n", connectorID)
+		json.NewEncoder(w).Encode(models.APIResponse{
+			Success: true,
+			Message: fmt.Sprintf("Checkpoints reset for %s; the next sync starts from scratch", connectorID),
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// HTTPMetricsHandler handles GET /metrics/http - Per-host outbound request counters and rate limits
+func (s *Server) HTTPMetricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := models.APIResponse{
+		Success: true,
+		Data:    httpclient.Snapshot(),
+	}
+	json.NewEncoder(w).Encode(response)
+}
+
 // PluginStatusHandler handles GET /plugins/status - Get plugin health status
 func (s *Server) PluginStatusHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -822,8 +1045,11 @@ func (s *Server) PluginStatusHandler(w http.ResponseWriter, r *http.Request) {
 		{"name": "EURES", "port": 8082, "id": "eures"},
 		{"name": "Remotive", "port": 8083, "id": "remotive"},
 		{"name": "RemoteOK", "port": 8084, "id": "remoteok"},
-		{"name": "Indeed Chrome", "port": 8087, "id": "indeed-chrome"},
+		{"name": "Indeed", "port": 8087, "id": "indeed"},
 		{"name": "Jooble", "port": 8088, "id": "jooble"},
+		{"name": "Adzuna", "port": 8095, "id": "adzuna"},
+		{"name": "Reed", "port": 8096, "id": "reed"},
+		{"name": "Hacker News", "port": 8097, "id": "hackernews"},
 	}
 
 	// Get plugin URLs from environment
@@ -832,8 +1058,11 @@ func (s *Server) PluginStatusHandler(w http.ResponseWriter, r *http.Request) {
 		"eures":              os.Getenv("PLUGIN_EURES_URL"),
 		"remotive":           os.Getenv("PLUGIN_REMOTIVE_URL"),
 		"remoteok":           os.Getenv("PLUGIN_REMOTEOK_URL"),
-		"indeed-chrome":      os.Getenv("PLUGIN_INDEED_CHROME_URL"),
+		"indeed":             scheduler.IndeedPluginURL(),
 		"jooble":             os.Getenv("PLUGIN_JOOBLE_URL"),
+		"adzuna":             os.Getenv("PLUGIN_ADZUNA_URL"),
+		"reed":               os.Getenv("PLUGIN_REED_URL"),
+		"hackernews":         os.Getenv("PLUGIN_HACKERNEWS_URL"),
 	}
 
 	// Check health of each plugin and get job count
@@ -5,12 +5,22 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
+	"openjobs/connectors/adzuna"
 	"openjobs/connectors/arbetsformedlingen"
+	"openjobs/connectors/ats"
+	"openjobs/connectors/declarative"
 	"openjobs/connectors/eures"
+	"openjobs/connectors/feeds"
+	"openjobs/connectors/hackernews"
+	"openjobs/connectors/htmlscraper"
+	"openjobs/connectors/jsonld"
+	"openjobs/connectors/reed"
 	"openjobs/connectors/remoteok"
 	"openjobs/connectors/remotive"
+	"openjobs/pkg/dedup"
 	"openjobs/pkg/models"
 	"openjobs/pkg/storage"
 	
@@ -19,10 +29,13 @@ import (
 
 // Scheduler manages periodic job data ingestion
 type Scheduler struct {
+	store        *storage.JobStore
+	trust        dedup.Trust
 	registry     *models.PluginRegistry
 	interval     time.Duration
 	cronSchedule string
 	stopChan     chan bool
+	execPlugins  []*models.ExecPluginConnector
 }
 
 // NewScheduler creates a new scheduler instance
@@ -35,6 +48,67 @@ func NewScheduler(store *storage.JobStore) *Scheduler {
 	registry.Register(eures.NewEURESConnector(store))
 	registry.Register(remoteok.NewRemoteOKConnector(store))
 	registry.Register(remotive.NewRemotiveConnector(store))
+	registry.Register(hackernews.NewHackerNewsConnector(store))
+
+	// Adzuna needs API credentials
+	if os.Getenv("ADZUNA_APP_ID") != "" {
+		registry.Register(adzuna.NewAdzunaConnector(store))
+	}
+
+	// Reed needs an API key
+	if os.Getenv("REED_API_KEY") != "" {
+		registry.Register(reed.NewReedConnector(store))
+	}
+
+	// Register declarative REST connectors from DECLARATIVE_CONFIG_DIR
+	if dir := os.Getenv("DECLARATIVE_CONFIG_DIR"); dir != "" {
+		configs, err := declarative.LoadConfigDir(dir)
+		if err != nil {
+			log.Printf("⚠️  Failed to load declarative connectors: %v", err)
+		}
+		for _, config := range configs {
+			registry.Register(declarative.NewDeclarativeConnector(store, config))
+			fmt.Printf("🧩 Registered declarative connector %s (%s)\n", config.ID, config.Name)
+		}
+	}
+
+	// Register HTML scrapers from config files and from plugins table rows
+	registerHTMLScrapers(registry, store)
+
+	// Register the schema.org JobPosting crawler from JSONLD_CONFIG
+	if configPath := os.Getenv("JSONLD_CONFIG"); configPath != "" {
+		if config, err := jsonld.LoadConfig(configPath); err != nil {
+			log.Printf("⚠️  Failed to load JSON-LD crawler config: %v", err)
+		} else {
+			registry.Register(jsonld.NewJSONLDConnector(store, config))
+		}
+	}
+
+	// Register RSS/Atom job feeds from FEEDS_CONFIG
+	if configPath := os.Getenv("FEEDS_CONFIG"); configPath != "" {
+		if config, err := feeds.LoadConfig(configPath); err != nil {
+			log.Printf("⚠️  Failed to load feed config: %v", err)
+		} else {
+			registry.Register(feeds.NewFeedConnector(store, config))
+		}
+	}
+
+	// Register ATS job board connectors for the configured company boards
+	if boards := ats.ParseBoards(os.Getenv("GREENHOUSE_BOARDS")); len(boards) > 0 {
+		registry.Register(ats.NewGreenhouseConnector(store, boards))
+	}
+	if boards := ats.ParseBoards(os.Getenv("LEVER_BOARDS")); len(boards) > 0 {
+		registry.Register(ats.NewLeverConnector(store, boards))
+	}
+	if boards := ats.ParseBoards(os.Getenv("WORKABLE_BOARDS")); len(boards) > 0 {
+		registry.Register(ats.NewWorkableConnector(store, boards))
+	}
+
+	// Register executable (stdin/stdout) plugins from EXEC_PLUGINS
+	execPlugins := loadExecPlugins(os.Getenv("EXEC_PLUGINS"))
+	for _, plugin := range execPlugins {
+		registry.Register(plugin)
+	}
 
 	// Check for cron schedule first (takes priority)
 	cronSchedule := os.Getenv("CRON_SCHEDULE")
@@ -47,14 +121,82 @@ func NewScheduler(store *storage.JobStore) *Scheduler {
 		}
 	}
 	
+	// Rank sources for golden records from TRUST_CONFIG, laid over the defaults
+	trust := dedup.DefaultTrust
+	if configPath := os.Getenv("TRUST_CONFIG"); configPath != "" {
+		if loaded, err := dedup.LoadTrust(configPath); err != nil {
+			log.Printf("⚠️  Failed to load trust config, using defaults: %v", err)
+		} else {
+			trust = loaded
+		}
+	}
+
 	return &Scheduler{
+		store:        store,
+		trust:        trust,
 		registry:     registry,
 		interval:     time.Hour * time.Duration(syncIntervalHours), // Configurable via SYNC_INTERVAL_HOURS
 		cronSchedule: cronSchedule,                                  // Configurable via CRON_SCHEDULE (takes priority)
 		stopChan:     make(chan bool),
+		execPlugins:  execPlugins,
 	}
 }
 
+// registerHTMLScrapers adds a scraper per file in HTML_SCRAPER_CONFIG_DIR and per
+// plugin ID in HTML_SCRAPER_PLUGINS (configs stored in plugins.config)
+func registerHTMLScrapers(registry *models.PluginRegistry, store *storage.JobStore) {
+	loaders := []htmlscraper.ConfigLoader{}
+
+	if dir := os.Getenv("HTML_SCRAPER_CONFIG_DIR"); dir != "" {
+		paths, err := htmlscraper.ConfigFiles(dir)
+		if err != nil {
+			log.Printf("⚠️  Failed to list HTML scraper configs: %v", err)
+		}
+		for _, path := range paths {
+			loaders = append(loaders, htmlscraper.FileConfig(path))
+		}
+	}
+	for _, pluginID := range strings.Split(os.Getenv("HTML_SCRAPER_PLUGINS"), ",") {
+		if pluginID = strings.TrimSpace(pluginID); pluginID != "" {
+			loaders = append(loaders, htmlscraper.PluginTableConfig(store, pluginID))
+		}
+	}
+
+	for _, loader := range loaders {
+		connector, err := htmlscraper.NewHTMLScraperConnector(store, loader)
+		if err != nil {
+			log.Printf("⚠️  Skipping HTML scraper: %v", err)
+			continue
+		}
+		registry.Register(connector)
+		fmt.Printf("🕷️  Registered HTML scraper %s (%s)\n", connector.GetID(), connector.GetName())
+	}
+}
+
+// loadExecPlugins parses EXEC_PLUGINS ("id=/path/to/binary [args...],id2=...")
+// into subprocess plugin connectors
+func loadExecPlugins(spec string) []*models.ExecPluginConnector {
+	plugins := []*models.ExecPluginConnector{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, command, found := strings.Cut(entry, "=")
+		parts := strings.Fields(command)
+		if !found || strings.TrimSpace(id) == "" || len(parts) == 0 {
+			log.Printf("⚠️  Ignoring invalid EXEC_PLUGINS entry: %q", entry)
+			continue
+		}
+
+		id = strings.TrimSpace(id)
+		plugins = append(plugins, models.NewExecPluginConnector(id, id+" Exec Plugin", parts[0], parts[1:]...))
+		fmt.Printf("🔌 Registered executable plugin %s (%s)\n", id, parts[0])
+	}
+	return plugins
+}
+
 // Start begins the scheduled job ingestion
 func (s *Scheduler) Start() {
 	// Check if cron schedule is set (takes priority)
@@ -111,9 +253,15 @@ func (s *Scheduler) startCronScheduler() {
 	// If you need to trigger manually, use: POST /sync/manual
 }
 
-// Stop halts the scheduled job ingestion
+// Stop halts the scheduled job ingestion and shuts down executable plugins
 func (s *Scheduler) Stop() {
 	s.stopChan <- true
+
+	for _, plugin := range s.execPlugins {
+		if err := plugin.Close(); err != nil {
+			log.Printf("⚠️  Failed to stop plugin %s: %v", plugin.GetID(), err)
+		}
+	}
 }
 
 // runSync executes the job synchronization for all connectors
@@ -140,6 +288,7 @@ func (s *Scheduler) runSync() {
 				fmt.Printf("✅ %s sync completed\n", connector.GetName())
 			}
 		}
+		s.clusterJobs()
 	}
 
 	fmt.Println("✅ All scheduled syncs completed")
@@ -155,8 +304,12 @@ func (s *Scheduler) RunManualSync() error {
 		"eures":              os.Getenv("PLUGIN_EURES_URL"),
 		"remotive":           os.Getenv("PLUGIN_REMOTIVE_URL"),
 		"remoteok":           os.Getenv("PLUGIN_REMOTEOK_URL"),
-		"indeed-chrome":      os.Getenv("PLUGIN_INDEED_CHROME_URL"),
+		"indeed":             IndeedPluginURL(),
 		"jooble":             os.Getenv("PLUGIN_JOOBLE_URL"),
+		"offentligajobb":     os.Getenv("PLUGIN_OFFENTLIGAJOBB_URL"),
+		"adzuna":             os.Getenv("PLUGIN_ADZUNA_URL"),
+		"reed":               os.Getenv("PLUGIN_REED_URL"),
+		"hackernews":         os.Getenv("PLUGIN_HACKERNEWS_URL"),
 	}
 
 	// Default URLs for local development (Docker Compose)
@@ -176,12 +329,24 @@ func (s *Scheduler) RunManualSync() error {
 		if pluginURLs["remoteok"] == "" {
 			pluginURLs["remoteok"] = "http://localhost:8084"
 		}
-		if pluginURLs["indeed-chrome"] == "" {
-			pluginURLs["indeed-chrome"] = "http://localhost:8087"
+		if pluginURLs["indeed"] == "" {
+			pluginURLs["indeed"] = "http://localhost:8087"
 		}
 		if pluginURLs["jooble"] == "" {
 			pluginURLs["jooble"] = "http://localhost:8088"
 		}
+		if pluginURLs["offentligajobb"] == "" {
+			pluginURLs["offentligajobb"] = "http://localhost:8094"
+		}
+		if pluginURLs["adzuna"] == "" {
+			pluginURLs["adzuna"] = "http://localhost:8095"
+		}
+		if pluginURLs["reed"] == "" {
+			pluginURLs["reed"] = "http://localhost:8096"
+		}
+		if pluginURLs["hackernews"] == "" {
+			pluginURLs["hackernews"] = "http://localhost:8097"
+		}
 	}
 	
 	// In production (Easypanel), only sync explicitly configured plugins
@@ -192,8 +357,12 @@ func (s *Scheduler) RunManualSync() error {
 		"eures":              "EURES",
 		"remotive":           "Remotive",
 		"remoteok":           "RemoteOK",
-		"indeed-chrome":      "Indeed Chrome",
+		"indeed":             "Indeed",
 		"jooble":             "Jooble",
+		"offentligajobb":     "Offentliga Jobb",
+		"adzuna":             "Adzuna",
+		"reed":               "Reed",
+		"hackernews":         "Hacker News",
 	}
 
 	for id, url := range pluginURLs {
@@ -209,9 +378,51 @@ func (s *Scheduler) RunManualSync() error {
 		}
 	}
 
+	// Executable plugins run as subprocesses of the core in both modes
+	for _, plugin := range s.execPlugins {
+		if err := plugin.SyncJobs(); err != nil {
+			log.Printf("❌ %s sync failed: %v", plugin.GetName(), err)
+		} else {
+			fmt.Printf("✅ %s sync completed\n", plugin.GetName())
+		}
+	}
+
+	s.clusterJobs()
+
 	// NOTE: Do NOT run local connectors here - they are already running as HTTP plugins
 	// Running both would cause duplicate sync logs and duplicate job entries
 	// The local connectors in the registry are only used for scheduled syncs in non-microservice mode
 
 	return nil
 }
+
+// clusterJobs groups the jobs the syncs stored with the same posting from other
+// sources and recomposes the golden records of the clusters that changed
+func (s *Scheduler) clusterJobs() {
+	if s.store == nil {
+		return
+	}
+	result, err := dedup.NewClusterer(s.store).Run()
+	if err != nil {
+		log.Printf("❌ Duplicate clustering failed: %v", err)
+		return
+	}
+	fmt.Printf("🧩 Clustered %d new jobs: %d duplicates of other sources, %d failed\n",
+		result.Clustered, result.Duplicates, result.Failed)
+
+	merged, err := dedup.NewMerger(s.store, s.trust).Refresh(result.Clusters)
+	if err != nil {
+		log.Printf("❌ Golden record rebuild failed: %v", err)
+		return
+	}
+	fmt.Printf("🏅 Rebuilt %d golden records\n", merged)
+}
+
+// IndeedPluginURL returns the unified Indeed plugin URL, falling back to the
+// pre-merge PLUGIN_INDEED_CHROME_URL so existing deployments keep syncing
+func IndeedPluginURL() string {
+	if url := os.Getenv("PLUGIN_INDEED_URL"); url != "" {
+		return url
+	}
+	return os.Getenv("PLUGIN_INDEED_CHROME_URL")
+}
@@ -0,0 +1,15 @@
+-- Persist a per-run checkpoint on sync logs so stream-based connectors
+-- (e.g. Arbetsförmedlingen JobStream) can resume where the last successful run stopped
+
+ALTER TABLE sync_logs ADD COLUMN IF NOT EXISTS checkpoint TEXT;
+ALTER TABLE sync_logs ADD COLUMN IF NOT EXISTS jobs_updated INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sync_logs ADD COLUMN IF NOT EXISTS jobs_removed INTEGER NOT NULL DEFAULT 0;
+
+-- Index for the "latest successful checkpoint" lookup
+CREATE INDEX IF NOT EXISTS idx_sync_logs_checkpoint
+ON sync_logs (connector_name, started_at DESC)
+WHERE checkpoint IS NOT NULL AND status = 'success';
+
+COMMENT ON COLUMN sync_logs.checkpoint IS 'Connector-specific resume position (e.g. JobStream timestamp) committed by this run';
+COMMENT ON COLUMN sync_logs.jobs_updated IS 'Existing jobs updated in place';
+COMMENT ON COLUMN sync_logs.jobs_removed IS 'Jobs closed because the source removed them';
@@ -0,0 +1,26 @@
+-- The Indeed scraper and Chrome connectors were merged into the single "indeed"
+-- connector, which stores every posting as indeed-<jk>. Rename the old prefixes and
+-- drop copies of postings that already exist under the new ID.
+
+DELETE FROM job_posts old
+USING job_posts kept
+WHERE old.id IN ('indeed-scraper-' || substring(kept.id FROM 8), 'indeed-chrome-' || substring(kept.id FROM 8))
+  AND kept.id LIKE 'indeed-%'
+  AND kept.id NOT LIKE 'indeed-scraper-%'
+  AND kept.id NOT LIKE 'indeed-chrome-%';
+
+-- A posting found by both old connectors: keep the Chrome copy (it has the full description)
+DELETE FROM job_posts scraper
+USING job_posts chrome
+WHERE scraper.id LIKE 'indeed-scraper-%'
+  AND chrome.id = 'indeed-chrome-' || substring(scraper.id FROM 16);
+
+UPDATE job_posts
+SET id = 'indeed-' || substring(id FROM 15),
+    fields = COALESCE(fields, '{}'::jsonb) || '{"source": "indeed", "connector": "indeed"}'::jsonb
+WHERE id LIKE 'indeed-chrome-%';
+
+UPDATE job_posts
+SET id = 'indeed-' || substring(id FROM 16),
+    fields = COALESCE(fields, '{}'::jsonb) || '{"source": "indeed", "connector": "indeed"}'::jsonb
+WHERE id LIKE 'indeed-scraper-%';
@@ -0,0 +1,28 @@
+-- Per-connector sync checkpoints. Incremental syncs used to resume from the newest
+-- posted_date stored under the connector's ID prefix, which missed jobs dated earlier
+-- and moved whenever a job was inserted by hand. Connectors now commit their position
+-- here after a successful store step, one row per connector and query.
+
+CREATE TABLE IF NOT EXISTS connector_checkpoints (
+    connector_id VARCHAR(100) NOT NULL,
+    query_key TEXT NOT NULL DEFAULT '',
+    cursor TEXT NOT NULL DEFAULT '',
+    high_watermark TIMESTAMP WITH TIME ZONE,
+    etags JSONB,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (connector_id, query_key)
+);
+
+-- Carry over the JobStream position committed on sync logs (see 004)
+INSERT INTO connector_checkpoints (connector_id, query_key, cursor, updated_at)
+SELECT DISTINCT ON (connector_name) connector_name, 'jobstream', checkpoint, started_at
+FROM sync_logs
+WHERE checkpoint IS NOT NULL AND status = 'success'
+ORDER BY connector_name, started_at DESC
+ON CONFLICT (connector_id, query_key) DO NOTHING;
+
+COMMENT ON TABLE connector_checkpoints IS 'Sync position committed by each connector query after its jobs were stored';
+COMMENT ON COLUMN connector_checkpoints.query_key IS 'Connector-specific query (search terms, board, country); empty for single-query connectors';
+COMMENT ON COLUMN connector_checkpoints.cursor IS 'Opaque resume position, e.g. the JobStream timestamp';
+COMMENT ON COLUMN connector_checkpoints.high_watermark IS 'Newest source posted date seen by a committed run';
+COMMENT ON COLUMN connector_checkpoints.etags IS 'Upstream ETag / Last-Modified validators by URL';
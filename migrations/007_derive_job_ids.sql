@@ -0,0 +1,74 @@
+-- Job IDs are now derived in one place (pkg/jobid) as "<source>:<upstream id>", or
+-- "<source>:<hash>" of the canonical URL when the source has no ID of its own. Each
+-- connector used to prefix IDs its own way ("af-", "hn-", "<source>-"). Rename the
+-- stored jobs to the new scheme and keep every old ID in job_id_aliases, so existing
+-- links to GET /jobs/{id} keep resolving.
+
+CREATE TABLE IF NOT EXISTS job_id_aliases (
+    alias VARCHAR(255) PRIMARY KEY,
+    job_id VARCHAR(255) NOT NULL REFERENCES job_posts(id) ON UPDATE CASCADE ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_id_aliases_job_id ON job_id_aliases(job_id);
+
+-- Plugin data follows its job when the ID changes
+DO $$
+BEGIN
+    IF to_regclass('job_posts_plugin_data') IS NOT NULL THEN
+        ALTER TABLE job_posts_plugin_data
+            DROP CONSTRAINT IF EXISTS job_posts_plugin_data_job_id_fkey,
+            ADD CONSTRAINT job_posts_plugin_data_job_id_fkey
+                FOREIGN KEY (job_id) REFERENCES job_posts(id) ON UPDATE CASCADE ON DELETE CASCADE;
+    END IF;
+END $$;
+
+-- Old ID -> new ID. Arbetsförmedlingen and Hacker News used a short prefix, the demo
+-- Jooble jobs a reversed one; every other connector prefixed fields.source and a dash
+-- (ATS board tokens and feed namespaces stay part of the upstream ID).
+CREATE TEMP TABLE job_id_renames AS
+SELECT id AS old_id,
+    CASE
+        WHEN id LIKE 'af-%' THEN 'arbetsformedlingen:' || substring(id FROM 4)
+        WHEN id LIKE 'hn-%' THEN 'hackernews:' || substring(id FROM 4)
+        WHEN id LIKE 'jooble-demo-%' THEN 'demo-jooble:' || substring(id FROM 8)
+        ELSE (fields->>'source') || ':' || substring(id FROM length(fields->>'source') + 2)
+    END AS new_id
+FROM job_posts
+WHERE position(':' IN id) = 0
+  AND (id LIKE 'af-%'
+       OR id LIKE 'hn-%'
+       OR id LIKE 'jooble-demo-%'
+       OR (COALESCE(fields->>'source', '') <> ''
+           AND left(id, length(fields->>'source') + 1) = (fields->>'source') || '-'
+           AND length(id) > length(fields->>'source') + 1));
+
+-- A posting already stored under its new ID (a sync ran before this migration) keeps
+-- that row; so does the first of several old IDs that map to the same new one
+DELETE FROM job_posts old
+USING job_id_renames r
+WHERE old.id = r.old_id
+  AND (EXISTS (SELECT 1 FROM job_posts kept WHERE kept.id = r.new_id)
+       OR EXISTS (SELECT 1 FROM job_id_renames first WHERE first.new_id = r.new_id AND first.old_id < r.old_id));
+
+UPDATE job_posts
+SET id = r.new_id
+FROM job_id_renames r
+WHERE job_posts.id = r.old_id;
+
+INSERT INTO job_id_aliases (alias, job_id)
+SELECT r.old_id, r.new_id
+FROM job_id_renames r
+JOIN job_posts kept ON kept.id = r.new_id
+ON CONFLICT (alias) DO NOTHING;
+
+DROP TABLE job_id_renames;
+
+-- Not migrated: the few Jooble jobs stored without a numeric Jooble ID used the last
+-- URL path segment (or a timestamp) and are now hashed from the URL, and URL-hashed IDs
+-- of non-canonical URLs (tracking parameters, trailing slashes) hash differently. Their
+-- next sync stores them under the new ID; the old rows expire as before.
+
+COMMENT ON TABLE job_id_aliases IS 'Retired job IDs and the job they now belong to, resolved by GET /jobs/{id}';
+COMMENT ON COLUMN job_id_aliases.alias IS 'Old job ID, e.g. af-30123456';
+COMMENT ON COLUMN job_id_aliases.job_id IS 'Current job ID, e.g. arbetsformedlingen:30123456';
@@ -0,0 +1,9 @@
+-- Cross-source duplicate clusters (pkg/dedup). After each sync every new job gets
+-- fields.company_key (normalized company) and fields.cluster_id (ID of the cluster's
+-- first job); the other jobs of a cluster get fields.duplicate = true. The clustering
+-- pass looks jobs up by these keys, and /jobs?collapse=true filters on duplicate.
+
+CREATE INDEX IF NOT EXISTS idx_job_posts_company_key ON job_posts ((fields->>'company_key'));
+CREATE INDEX IF NOT EXISTS idx_job_posts_cluster_id ON job_posts ((fields->>'cluster_id'));
+CREATE INDEX IF NOT EXISTS idx_job_posts_unclustered ON job_posts (posted_date) WHERE fields->>'cluster_id' IS NULL;
+CREATE INDEX IF NOT EXISTS idx_job_posts_representatives ON job_posts (posted_date DESC) WHERE fields->>'duplicate' IS NULL;
@@ -0,0 +1,17 @@
+-- Golden records: the canonical view of each duplicate cluster (see 008), composed
+-- field by field from the source trusted most for that field (pkg/dedup, TRUST_CONFIG).
+-- The per-source rows in job_posts stay as they are; a cluster of a single job has no
+-- golden record.
+
+CREATE TABLE IF NOT EXISTS job_golden_records (
+    cluster_id VARCHAR(255) PRIMARY KEY,
+    job JSONB NOT NULL,
+    provenance JSONB NOT NULL DEFAULT '{}'::jsonb,
+    job_ids JSONB NOT NULL DEFAULT '[]'::jsonb,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE job_golden_records IS 'Merged job per duplicate cluster, rebuilt after each sync';
+COMMENT ON COLUMN job_golden_records.job IS 'The merged JobPost; its id is the cluster ID';
+COMMENT ON COLUMN job_golden_records.provenance IS 'Per JobPost field: the job_id, source and connector it was taken from';
+COMMENT ON COLUMN job_golden_records.job_ids IS 'Jobs of the cluster, oldest first';
@@ -0,0 +1,128 @@
+-- Salaries normalized to euros per year (pkg/currency). The storage layer converts
+-- fields.salary_annual_min/max (pkg/salary) with the exchange-rate table loaded from
+-- EXCHANGE_RATES_FILE and records its version in fields.salary_rates_version.
+-- Existing rows are filled by cmd/backfill-salaries; /jobs?min_salary_eur= and the
+-- salary analytics read these columns.
+
+ALTER TABLE job_posts
+ADD COLUMN IF NOT EXISTS salary_annual_min_eur INTEGER,
+ADD COLUMN IF NOT EXISTS salary_annual_max_eur INTEGER;
+
+CREATE INDEX IF NOT EXISTS idx_job_posts_salary_annual_min_eur
+ON job_posts (salary_annual_min_eur)
+WHERE salary_annual_min_eur IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_job_posts_salary_annual_max_eur
+ON job_posts (salary_annual_max_eur)
+WHERE salary_annual_max_eur IS NOT NULL;
+
+-- 002 defaulted salary_currency to 'USD', which labels Swedish jobs without a salary
+-- as dollars. The connectors set the currency themselves.
+ALTER TABLE job_posts ALTER COLUMN salary_currency DROP DEFAULT;
+
+UPDATE job_posts
+SET salary_currency = NULL
+WHERE salary_currency = 'USD'
+  AND salary_min IS NULL
+  AND salary_max IS NULL
+  AND COALESCE(salary, '') = '';
+
+-- Every version of the rate table that was used, units per euro like the ECB rates
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    version VARCHAR(100) NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    per_eur NUMERIC NOT NULL,
+    loaded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (version, currency)
+);
+
+-- Salary analytics from the normalized columns: averages over mixed currencies and
+-- periods were meaningless
+DROP MATERIALIZED VIEW IF EXISTS job_analytics;
+
+CREATE MATERIALIZED VIEW job_analytics AS
+SELECT
+    jp.plugin_source,
+
+    -- Job counts and geographic spread
+    COUNT(*) as total_jobs,
+    COUNT(DISTINCT jp.location_country) as countries_covered,
+
+    -- Employment type distribution
+    COUNT(CASE WHEN jp.employment_type = 'Full-time' THEN 1 END) as fulltime_jobs,
+    COUNT(CASE WHEN jp.employment_type = 'Part-time' THEN 1 END) as parttime_jobs,
+    COUNT(CASE WHEN jp.remote_work = TRUE THEN 1 END) as remote_jobs,
+
+    -- Salary analytics in euros per year
+    ROUND(AVG(jp.salary_annual_min_eur)) as avg_min_salary_eur,
+    ROUND(AVG(jp.salary_annual_max_eur)) as avg_max_salary_eur,
+
+    -- Category diversity (plugin-specific)
+    COUNT(DISTINCT CASE
+        WHEN jp.plugin_source = 'arbetsformedlingen' THEN jpd.structured_data->>'occupation_group'
+        WHEN jp.plugin_source = 'eures' THEN jpd.structured_data->>'job_category'
+        WHEN jp.plugin_source = 'remotive' THEN jpd.structured_data->>'category'
+        ELSE jp.job_category
+    END) as categories_count,
+
+    -- Freshness and sync metrics
+    MAX(jp.created_at) as latest_job,
+    MIN(jp.created_at) as first_job,
+    ROUND(EXTRACT(EPOCH FROM (MAX(jp.created_at) - MIN(jp.created_at))) / 3600, 2) as hours_active,
+
+    -- Metadata richness tracking
+    COUNT(*) FILTER (WHERE jpd.structured_data IS NOT NULL) as jobs_with_metadata,
+    AVG(array_length(jp.tags, 1)) FILTER (WHERE array_length(jp.tags, 1) IS NOT NULL) as avg_tags_per_job
+
+FROM job_posts jp
+LEFT JOIN job_posts_plugin_data jpd ON jp.id = jpd.job_id AND jp.plugin_source = jpd.plugin_source
+WHERE jp.plugin_source IN ('arbetsformedlingen', 'eures', 'remotive')
+GROUP BY jp.plugin_source;
+
+GRANT SELECT ON job_analytics TO authenticated;
+
+CREATE OR REPLACE FUNCTION get_job_analytics_summary()
+RETURNS TABLE (
+    total_jobs bigint,
+    sources_count bigint,
+    countries_covered bigint,
+    avg_salary_range int,
+    remote_percentage decimal
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        SUM(ja.total_jobs) as total_jobs,
+        COUNT(*) as sources_count,
+        SUM(ja.countries_covered) as countries_covered,
+        ROUND(AVG(ja.avg_max_salary_eur - ja.avg_min_salary_eur))::int as avg_salary_range,
+        ROUND((SUM(ja.remote_jobs)::decimal / SUM(ja.total_jobs)) * 100, 2) as remote_percentage
+    FROM job_analytics ja;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Salary summary over all sources, for GET /analytics
+CREATE OR REPLACE FUNCTION get_salary_summary()
+RETURNS TABLE (
+    jobs_with_salary bigint,
+    avg_annual_min_eur int,
+    avg_annual_max_eur int,
+    rates_version text
+) AS $$
+BEGIN
+    RETURN QUERY
+    SELECT
+        COUNT(*) as jobs_with_salary,
+        ROUND(AVG(jp.salary_annual_min_eur))::int as avg_annual_min_eur,
+        ROUND(AVG(jp.salary_annual_max_eur))::int as avg_annual_max_eur,
+        (SELECT er.version::text FROM exchange_rates er ORDER BY er.loaded_at DESC LIMIT 1) as rates_version
+    FROM job_posts jp
+    WHERE jp.salary_annual_min_eur IS NOT NULL OR jp.salary_annual_max_eur IS NOT NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMENT ON COLUMN job_posts.salary_annual_min_eur IS 'Lower bound of the salary in euros per year';
+COMMENT ON COLUMN job_posts.salary_annual_max_eur IS 'Upper bound of the salary in euros per year';
+COMMENT ON TABLE exchange_rates IS 'Versions of the exchange-rate table used for salary_annual_*_eur';
+COMMENT ON MATERIALIZED VIEW job_analytics IS 'Pre-computed analytics for job platform dashboard (refresh periodically)';
+COMMENT ON FUNCTION get_salary_summary() IS 'Average annual salary in euros across all sources';
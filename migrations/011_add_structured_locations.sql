@@ -0,0 +1,25 @@
+-- Structured job locations (pkg/geo). The storage layer resolves the free-text
+-- location of every job it writes with the gazetteer (GAZETTEER_FILE, built from a
+-- GeoNames dump by cmd/build-gazetteer) into these columns; location_country from
+-- 002 now holds the ISO 3166-1 alpha-2 code. Existing rows are filled by
+-- cmd/backfill-locations. /jobs?country=&region=&city=&remote_scope= filter on them.
+
+ALTER TABLE job_posts
+ADD COLUMN IF NOT EXISTS location_country VARCHAR(100),
+ADD COLUMN IF NOT EXISTS location_region VARCHAR(100),
+ADD COLUMN IF NOT EXISTS location_city VARCHAR(100),
+ADD COLUMN IF NOT EXISTS latitude DOUBLE PRECISION,
+ADD COLUMN IF NOT EXISTS longitude DOUBLE PRECISION,
+ADD COLUMN IF NOT EXISTS remote_scope VARCHAR(20);
+
+CREATE INDEX IF NOT EXISTS idx_location_country ON job_posts (location_country);
+CREATE INDEX IF NOT EXISTS idx_job_posts_location_region ON job_posts (lower(location_region));
+CREATE INDEX IF NOT EXISTS idx_job_posts_location_city ON job_posts (lower(location_city));
+CREATE INDEX IF NOT EXISTS idx_job_posts_remote_scope ON job_posts (remote_scope) WHERE remote_scope IS NOT NULL;
+
+COMMENT ON COLUMN job_posts.location_country IS 'ISO 3166-1 alpha-2 country code resolved from location';
+COMMENT ON COLUMN job_posts.location_region IS 'First-level administrative region (GeoNames admin1), e.g. Västra Götaland';
+COMMENT ON COLUMN job_posts.location_city IS 'City name as in the gazetteer (its GeoNames name), e.g. Gothenburg';
+COMMENT ON COLUMN job_posts.latitude IS 'Latitude of the workplace, or of the city when the source gives no coordinates';
+COMMENT ON COLUMN job_posts.longitude IS 'Longitude of the workplace, or of the city when the source gives no coordinates';
+COMMENT ON COLUMN job_posts.remote_scope IS 'Where a remote job can be done from: worldwide, a continent (europe...), country or countries';
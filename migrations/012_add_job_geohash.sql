@@ -0,0 +1,16 @@
+-- Radius search (/jobs?near=lat,lon&radius_km=). Every job with coordinates (from the
+-- source, like AF's workplace_address.coordinates, or geocoded from its city by the
+-- gazetteer, see 011) gets the geohash of its latitude/longitude. A radius search
+-- fetches the jobs in the geohash cells covering the circle with prefix queries
+-- (geohash=like.u6sc*), then keeps and sorts those within the radius by distance.
+-- Existing rows get their geohash from cmd/backfill-locations.
+
+ALTER TABLE job_posts
+ADD COLUMN IF NOT EXISTS geohash VARCHAR(12);
+
+-- varchar_pattern_ops lets LIKE 'prefix%' use the index
+CREATE INDEX IF NOT EXISTS idx_job_posts_geohash
+ON job_posts (geohash varchar_pattern_ops)
+WHERE geohash IS NOT NULL;
+
+COMMENT ON COLUMN job_posts.geohash IS 'Geohash (9 characters, about 5 m) of latitude/longitude for radius search';
@@ -0,0 +1,18 @@
+-- Golden records (009) are rebuilt for every cluster with a job updated since the last
+-- rebuild, so a job closed or changed by a sync shows in its cluster's record. The
+-- connectors run in separate processes, so the rebuild finds those jobs by updated_at,
+-- which until now only had a default: keep it current on every update.
+
+CREATE OR REPLACE FUNCTION touch_job_posts_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS job_posts_touch_updated_at ON job_posts;
+CREATE TRIGGER job_posts_touch_updated_at
+BEFORE UPDATE ON job_posts
+FOR EACH ROW EXECUTE FUNCTION touch_job_posts_updated_at();
+
+CREATE INDEX IF NOT EXISTS idx_job_posts_clustered_updated_at ON job_posts (updated_at) WHERE fields->>'cluster_id' IS NOT NULL;
@@ -0,0 +1,21 @@
+-- Snapshot syncs (RemoteOK, Remotive, declarative feeds with snapshot: true, the AF
+-- JobStream snapshot) list a connector's open jobs by fields.connector and close those
+-- missing from the feed. Rows stored before the connectors set that key were never
+-- listed, so they stayed open. Derive it from the "<source>:" prefix of the job ID
+-- (007) for the connectors whose ID is their source; the demo Jooble jobs belong to
+-- the Jooble connector.
+--
+-- Not backfilled: jobs of configured connectors (declarative, feeds, ats, jsonld,
+-- htmlscraper), whose connector ID isn't part of the job ID. They set the key since
+-- they were added.
+
+UPDATE job_posts
+SET fields = COALESCE(fields, '{}'::jsonb) || jsonb_build_object('connector',
+    CASE split_part(id, ':', 1)
+        WHEN 'demo-jooble' THEN 'jooble'
+        ELSE split_part(id, ':', 1)
+    END)
+WHERE COALESCE(fields->>'connector', '') = ''
+  AND split_part(id, ':', 1) IN (
+      'adzuna', 'arbetsformedlingen', 'demo-jooble', 'eures', 'hackernews', 'indeed',
+      'jooble', 'offentligajobb', 'reed', 'remoteok', 'remotive');
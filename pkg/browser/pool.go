@@ -0,0 +1,299 @@
+// Package browser provides a headless Chrome pool shared by every chromedp-based
+// connector in a process, so overlapping scrapers reuse one browser and a capped
+// number of tabs instead of each starting their own Chrome.
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrDisabled is returned by Render when no Chrome binary or remote URL is configured
+var ErrDisabled = errors.New("headless browser is disabled")
+
+// blockedResources are URL patterns that never affect the HTML we parse
+var blockedResources = []string{
+	"*.png", "*.jpg", "*.jpeg", "*.gif", "*.webp", "*.svg", "*.ico",
+	"*.woff", "*.woff2", "*.ttf", "*.otf", "*.eot",
+	"*.mp4", "*.webm", "*.mp3",
+}
+
+// Config controls how the pool starts Chrome and how many tabs it keeps
+type Config struct {
+	ExecPath       string        // Local Chrome binary, used when RemoteURL is empty
+	RemoteURL      string        // DevTools websocket of an already running Chrome
+	MaxTabs        int           // Concurrent tabs across all connectors
+	PageTimeout    time.Duration // Upper bound for one Render call
+	IdleTimeout    time.Duration // Chrome is shut down after this long without renders
+	UserAgent      string
+	BlockResources bool // Skip images, fonts and media
+}
+
+// ConfigFromEnv reads CHROME_BIN, CHROME_WS_URL, CHROME_MAX_TABS, CHROME_PAGE_TIMEOUT,
+// CHROME_IDLE_TIMEOUT and CHROME_BLOCK_RESOURCES. Setting CHROME_BIN to an empty
+// string disables the local browser.
+func ConfigFromEnv() Config {
+	cfg := Config{
+		ExecPath:       "/usr/bin/chromium-browser",
+		RemoteURL:      os.Getenv("CHROME_WS_URL"),
+		MaxTabs:        2,
+		PageTimeout:    60 * time.Second,
+		IdleTimeout:    5 * time.Minute,
+		UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		BlockResources: true,
+	}
+	if path, ok := os.LookupEnv("CHROME_BIN"); ok {
+		cfg.ExecPath = path
+	}
+	if n, err := strconv.Atoi(os.Getenv("CHROME_MAX_TABS")); err == nil && n > 0 {
+		cfg.MaxTabs = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("CHROME_PAGE_TIMEOUT")); err == nil && d > 0 {
+		cfg.PageTimeout = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("CHROME_IDLE_TIMEOUT")); err == nil && d > 0 {
+		cfg.IdleTimeout = d
+	}
+	if os.Getenv("CHROME_BLOCK_RESOURCES") == "false" {
+		cfg.BlockResources = false
+	}
+	return cfg
+}
+
+// LookPath returns the path of an installed Chrome or Chromium, or "" when there is none
+func LookPath() string {
+	for _, name := range []string{"chromium-browser", "chromium", "google-chrome"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+var (
+	sharedOnce sync.Once
+	shared     *Pool
+)
+
+// Shared returns the process-wide pool configured from the environment
+func Shared() *Pool {
+	sharedOnce.Do(func() {
+		shared = NewPool(ConfigFromEnv())
+	})
+	return shared
+}
+
+// tab is one reusable browser target
+type tab struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	generation int
+}
+
+// Pool hands out tabs of a lazily started Chrome. Tabs are reused between renders,
+// dropped after a failure, and the browser is restarted if it has crashed.
+type Pool struct {
+	cfg   Config
+	slots chan struct{}
+
+	mu            sync.Mutex
+	browserCtx    context.Context
+	browserCancel context.CancelFunc
+	allocCancel   context.CancelFunc
+	generation    int
+	idle          []*tab
+	inUse         int
+	idleTimer     *time.Timer
+}
+
+// NewPool creates a pool; Chrome is not started until the first Render
+func NewPool(cfg Config) *Pool {
+	if cfg.MaxTabs <= 0 {
+		cfg.MaxTabs = 1
+	}
+	if cfg.PageTimeout <= 0 {
+		cfg.PageTimeout = 60 * time.Second
+	}
+	return &Pool{cfg: cfg, slots: make(chan struct{}, cfg.MaxTabs)}
+}
+
+// Enabled reports whether the pool has a browser to render with
+func (p *Pool) Enabled() bool {
+	return p != nil && (p.cfg.ExecPath != "" || p.cfg.RemoteURL != "")
+}
+
+// Render navigates to pageURL, waits for waitSelector to be ready plus settle for
+// client-side rendering, and returns the page HTML. It blocks while MaxTabs renders
+// are already running.
+func (p *Pool) Render(ctx context.Context, pageURL, waitSelector string, settle time.Duration) (string, error) {
+	if !p.Enabled() {
+		return "", ErrDisabled
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+	defer func() { <-p.slots }()
+
+	t, err := p.acquire()
+	if err != nil {
+		return "", err
+	}
+
+	runCtx, cancel := context.WithTimeout(t.ctx, p.cfg.PageTimeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	var body string
+	err = chromedp.Run(runCtx,
+		chromedp.Navigate(pageURL),
+		chromedp.WaitReady(waitSelector, chromedp.ByQuery),
+		chromedp.Sleep(settle),
+		chromedp.OuterHTML("html", &body, chromedp.ByQuery),
+	)
+	p.release(t, err == nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", pageURL, err)
+	}
+	return body, nil
+}
+
+// Close shuts Chrome down; the pool starts a new browser if it is used again
+func (p *Pool) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.shutdownLocked()
+}
+
+// acquire returns an idle tab of the live browser or opens a new one
+func (p *Pool) acquire() (*tab, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.idleTimer != nil {
+		p.idleTimer.Stop()
+		p.idleTimer = nil
+	}
+	if p.browserCtx != nil && p.browserCtx.Err() != nil {
+		fmt.Println("⚠️  Headless browser exited, restarting")
+		p.shutdownLocked()
+	}
+	if p.browserCtx == nil {
+		if err := p.startLocked(); err != nil {
+			return nil, err
+		}
+	}
+
+	p.inUse++
+	if n := len(p.idle); n > 0 {
+		t := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		return t, nil
+	}
+
+	ctx, cancel := chromedp.NewContext(p.browserCtx)
+	var setup chromedp.Tasks
+	if p.cfg.UserAgent != "" {
+		// Also applies to remote browsers, which ignore the launch flag
+		setup = append(setup, emulation.SetUserAgentOverride(p.cfg.UserAgent))
+	}
+	if p.cfg.BlockResources {
+		setup = append(setup, network.Enable(), network.SetBlockedURLS(blockedResources))
+	}
+	if err := chromedp.Run(ctx, setup); err != nil {
+		cancel()
+		p.inUse--
+		return nil, fmt.Errorf("failed to open browser tab: %w", err)
+	}
+	return &tab{ctx: ctx, cancel: cancel, generation: p.generation}, nil
+}
+
+// release returns a healthy tab to the idle list and closes a failed one
+func (p *Pool) release(t *tab, healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inUse--
+	if healthy && t.generation == p.generation && t.ctx.Err() == nil {
+		p.idle = append(p.idle, t)
+	} else {
+		t.cancel()
+	}
+
+	if p.inUse == 0 && p.cfg.IdleTimeout > 0 && p.browserCtx != nil {
+		p.idleTimer = time.AfterFunc(p.cfg.IdleTimeout, p.closeIfIdle)
+	}
+}
+
+func (p *Pool) closeIfIdle() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.inUse == 0 {
+		p.shutdownLocked()
+	}
+}
+
+func (p *Pool) startLocked() error {
+	var allocCtx context.Context
+	var allocCancel context.CancelFunc
+	if p.cfg.RemoteURL != "" {
+		allocCtx, allocCancel = chromedp.NewRemoteAllocator(context.Background(), p.cfg.RemoteURL)
+	} else {
+		opts := []chromedp.ExecAllocatorOption{
+			chromedp.NoSandbox,
+			chromedp.DisableGPU,
+			chromedp.Flag("disable-dev-shm-usage", true),
+			chromedp.Flag("disable-setuid-sandbox", true),
+			chromedp.Flag("headless", true),
+			chromedp.ExecPath(p.cfg.ExecPath),
+		}
+		if p.cfg.UserAgent != "" {
+			opts = append(opts, chromedp.UserAgent(p.cfg.UserAgent))
+		}
+		if p.cfg.BlockResources {
+			opts = append(opts, chromedp.Flag("blink-settings", "imagesEnabled=false"))
+		}
+		allocCtx, allocCancel = chromedp.NewExecAllocator(context.Background(), opts...)
+	}
+
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+	// Running with no actions starts the browser (or connects to the remote one)
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return fmt.Errorf("failed to start headless browser: %w", err)
+	}
+
+	p.browserCtx, p.browserCancel, p.allocCancel = browserCtx, browserCancel, allocCancel
+	p.generation++
+	return nil
+}
+
+func (p *Pool) shutdownLocked() {
+	if p.idleTimer != nil {
+		p.idleTimer.Stop()
+		p.idleTimer = nil
+	}
+	for _, t := range p.idle {
+		t.cancel()
+	}
+	p.idle = nil
+	if p.browserCancel != nil {
+		p.browserCancel()
+		p.allocCancel()
+	}
+	p.browserCtx, p.browserCancel, p.allocCancel = nil, nil, nil
+}
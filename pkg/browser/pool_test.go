@@ -0,0 +1,91 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("CHROME_BIN", "/opt/chrome")
+	t.Setenv("CHROME_WS_URL", "ws://chrome:9222/devtools/browser/abc")
+	t.Setenv("CHROME_MAX_TABS", "4")
+	t.Setenv("CHROME_PAGE_TIMEOUT", "15s")
+	t.Setenv("CHROME_BLOCK_RESOURCES", "false")
+
+	cfg := ConfigFromEnv()
+	if cfg.ExecPath != "/opt/chrome" || cfg.RemoteURL != "ws://chrome:9222/devtools/browser/abc" {
+		t.Errorf("Unexpected browser location: %+v", cfg)
+	}
+	if cfg.MaxTabs != 4 || cfg.PageTimeout != 15*time.Second || cfg.BlockResources {
+		t.Errorf("Unexpected limits: %+v", cfg)
+	}
+}
+
+func TestDisabledPool(t *testing.T) {
+	var nilPool *Pool
+	if nilPool.Enabled() {
+		t.Error("Expected nil pool to be disabled")
+	}
+
+	t.Setenv("CHROME_BIN", "")
+	t.Setenv("CHROME_WS_URL", "")
+	pool := NewPool(ConfigFromEnv())
+	if pool.Enabled() {
+		t.Fatal("Expected pool without Chrome path or remote URL to be disabled")
+	}
+	if _, err := pool.Render(context.Background(), "http://example.com", "body", 0); !errors.Is(err, ErrDisabled) {
+		t.Errorf("Expected ErrDisabled, got %v", err)
+	}
+}
+
+// TestRenderReusesTabs needs a local Chrome and is skipped when none is installed
+func TestRenderReusesTabs(t *testing.T) {
+	path := LookPath()
+	if path == "" {
+		t.Skip("no Chrome binary installed")
+	}
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		mu.Unlock()
+		if strings.HasSuffix(r.URL.Path, ".png") {
+			w.Header().Set("Content-Type", "image/png")
+			return
+		}
+		fmt.Fprintf(w, `<html><body><img src="/logo.png"><div id="jobs">page %s</div></body></html>`, r.URL.Path)
+	}))
+	defer server.Close()
+
+	pool := NewPool(Config{ExecPath: path, MaxTabs: 2, PageTimeout: 30 * time.Second, BlockResources: true})
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body, err := pool.Render(context.Background(), fmt.Sprintf("%s/p%d", server.URL, i), "#jobs", 0)
+			if err != nil || !strings.Contains(body, fmt.Sprintf("page /p%d", i)) {
+				t.Errorf("Render %d failed: %v", i, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if n := len(pool.idle); n > 2 {
+		t.Errorf("Expected at most 2 pooled tabs, got %d", n)
+	}
+	if requests["/logo.png"] != 0 {
+		t.Errorf("Expected images to be blocked, got %d requests", requests["/logo.png"])
+	}
+}
@@ -0,0 +1,285 @@
+// Package conformance checks that a PluginConnector follows the conventions every
+// connector shares: stable namespaced IDs, the required fields, sane dates and URLs,
+// and a SyncJobs that is idempotent and logs each run. Connector packages call Run
+// from a test, replaying their recorded fixtures (see pkg/httpreplay).
+package conformance
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/jobid"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+	"openjobs/pkg/storage/storagetest"
+)
+
+// Factory creates the connector for one run. Run calls it several times, so it must
+// start a fresh fixture replay each time. The store is nil for fetch-only runs.
+type Factory func(t *testing.T, store *storage.JobStore) models.PluginConnector
+
+// Options describes what the suite should expect from a connector
+type Options struct {
+	// IDPrefix every job ID starts with; defaults to fields.source + ":" (see pkg/jobid)
+	IDPrefix string
+	// SkipSync leaves out the SyncJobs checks, for connectors whose sync does more
+	// requests than their fixtures cover
+	SkipSync bool
+}
+
+// RequiredFields are the Fields keys every job carries
+var RequiredFields = []string{"source", "source_url", "original_id", "connector", "fetched_at"}
+
+var (
+	fieldKeyRe  = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+	currencyRe  = regexp.MustCompile(`^[A-Z]{3}$`)
+	oldestValid = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Run checks FetchJobs and SyncJobs of the connectors built by newConnector
+func Run(t *testing.T, newConnector Factory, opts Options) {
+	t.Run("FetchJobs", func(t *testing.T) {
+		start := time.Now()
+		first := fetch(t, newConnector)
+		end := time.Now()
+		if len(first) == 0 {
+			t.Fatal("FetchJobs returned no jobs; the fixture should produce at least one")
+		}
+
+		connectorID := newConnector(t, nil).GetID()
+		for _, job := range first {
+			for _, problem := range CheckJob(job, connectorID, opts.IDPrefix, start, end) {
+				t.Errorf("%s: %s", job.ID, problem)
+			}
+		}
+		if dup := duplicateIDs(first); len(dup) > 0 {
+			t.Errorf("Duplicate job IDs in one run: %v", dup)
+		}
+
+		second := fetch(t, newConnector)
+		if a, b := sortedIDs(first), sortedIDs(second); strings.Join(a, ",") != strings.Join(b, ",") {
+			t.Errorf("Job IDs are not stable across runs:\nfirst:  %v\nsecond: %v", a, b)
+		}
+	})
+
+	if opts.SkipSync {
+		return
+	}
+
+	t.Run("SyncJobs", func(t *testing.T) {
+		server := storagetest.NewServer(t)
+
+		connector := newConnector(t, server.Store())
+		start := time.Now()
+		if err := connector.SyncJobs(); err != nil {
+			t.Fatalf("First SyncJobs failed: %v", err)
+		}
+		end := time.Now()
+
+		stored := server.Jobs()
+		if len(stored) == 0 {
+			t.Fatal("First SyncJobs stored no jobs")
+		}
+		logs := server.SyncLogs()
+		checkSyncLogs(t, "first", logs, connector.GetID(), start, end)
+		if len(logs) > 0 && logs[len(logs)-1].JobsInserted != len(stored) {
+			t.Errorf("First sync log reports %d inserted, the store has %d jobs", logs[len(logs)-1].JobsInserted, len(stored))
+		}
+
+		connector = newConnector(t, server.Store())
+		start = time.Now()
+		if err := connector.SyncJobs(); err != nil {
+			t.Fatalf("Second SyncJobs failed: %v", err)
+		}
+		end = time.Now()
+
+		again := server.Jobs()
+		if a, b := sortedIDs(stored), sortedIDs(again); strings.Join(a, ",") != strings.Join(b, ",") {
+			t.Errorf("Second SyncJobs changed the stored jobs:\nbefore: %v\nafter:  %v", a, b)
+		}
+		secondLogs := server.SyncLogs()[len(logs):]
+		checkSyncLogs(t, "second", secondLogs, connector.GetID(), start, end)
+		if len(secondLogs) > 0 && secondLogs[len(secondLogs)-1].JobsInserted != 0 {
+			t.Errorf("Second sync inserted %d jobs, expected none", secondLogs[len(secondLogs)-1].JobsInserted)
+		}
+	})
+}
+
+// CheckJob returns the convention violations of one job fetched between start and
+// end by the connector with the given ID
+func CheckJob(job models.JobPost, connectorID, idPrefix string, start, end time.Time) []string {
+	problems := []string{}
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	// Fields
+	if job.Fields == nil {
+		return append(problems, "fields is nil")
+	}
+	for _, key := range RequiredFields {
+		if v, ok := job.Fields[key]; !ok || v == nil || v == "" {
+			add("missing fields.%s", key)
+		}
+	}
+	for key := range job.Fields {
+		if !fieldKeyRe.MatchString(key) {
+			add("fields key %q is not snake_case", key)
+		}
+	}
+	if connector, _ := job.Fields["connector"].(string); connector != "" && connector != connectorID {
+		add("fields.connector is %q, the connector ID is %q", connector, connectorID)
+	}
+	if fetched, ok := fieldTime(job.Fields["fetched_at"]); !ok {
+		add("fields.fetched_at is not a timestamp: %v", job.Fields["fetched_at"])
+	} else if fetched.Before(start.Add(-time.Second)) || fetched.After(end.Add(time.Second)) {
+		add("fields.fetched_at %v is outside the run", fetched)
+	}
+
+	// ID
+	source, _ := job.Fields["source"].(string)
+	prefix := idPrefix
+	if prefix == "" {
+		prefix = source + jobid.Separator
+	}
+	switch {
+	case job.ID == "":
+		add("empty ID")
+	case strings.TrimSpace(job.ID) != job.ID || strings.ContainsAny(job.ID, " \t\n/?#"):
+		add("ID %q contains whitespace or URL delimiters", job.ID)
+	case !strings.HasPrefix(job.ID, prefix) || len(job.ID) == len(prefix):
+		add("ID is not namespaced with %q", prefix)
+	}
+
+	// Required columns
+	if strings.TrimSpace(job.Title) == "" {
+		add("empty Title")
+	}
+	if strings.TrimSpace(job.Company) == "" {
+		add("empty Company")
+	}
+	if job.Requirements == nil || job.Benefits == nil {
+		add("Requirements and Benefits must be empty slices, not nil")
+	}
+
+	// URLs
+	if err := checkURL(job.URL); err != nil {
+		add("URL: %v", err)
+	}
+	if link, _ := job.Fields["source_url"].(string); link != "" {
+		if err := checkURL(link); err != nil {
+			add("fields.source_url: %v", err)
+		}
+	}
+
+	// Dates
+	estimated, _ := job.Fields["posted_date_estimated"].(bool)
+	switch {
+	case job.PostedDate.IsZero():
+		add("zero PostedDate")
+	case job.PostedDate.Before(oldestValid):
+		add("PostedDate %v is implausibly old", job.PostedDate)
+	case job.PostedDate.After(end.Add(24 * time.Hour)):
+		add("PostedDate %v is in the future", job.PostedDate)
+	case !estimated && !job.PostedDate.Before(start) && !job.PostedDate.After(end):
+		add("PostedDate is the fetch time; set fields.posted_date_estimated when the source has no date")
+	}
+	if !job.ExpiresDate.IsZero() && !job.PostedDate.IsZero() && job.ExpiresDate.Before(job.PostedDate) {
+		add("ExpiresDate %v is before PostedDate %v", job.ExpiresDate, job.PostedDate)
+	}
+
+	// Salary
+	if job.SalaryMin != nil && job.SalaryMax != nil && *job.SalaryMin > *job.SalaryMax {
+		add("SalaryMin %d is above SalaryMax %d", *job.SalaryMin, *job.SalaryMax)
+	}
+	if job.SalaryCurrency != "" && !currencyRe.MatchString(job.SalaryCurrency) {
+		add("SalaryCurrency %q is not an ISO 4217 code", job.SalaryCurrency)
+	}
+
+	return problems
+}
+
+func fetch(t *testing.T, newConnector Factory) []models.JobPost {
+	t.Helper()
+	jobs, err := newConnector(t, nil).FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs failed: %v", err)
+	}
+	return jobs
+}
+
+// checkSyncLogs expects exactly one successful log for the run
+func checkSyncLogs(t *testing.T, run string, logs []models.SyncLog, connectorID string, start, end time.Time) {
+	t.Helper()
+	if len(logs) != 1 {
+		t.Errorf("%s SyncJobs wrote %d sync logs, expected 1", run, len(logs))
+		return
+	}
+	log := logs[0]
+	if log.ConnectorName != connectorID {
+		t.Errorf("%s sync log connector_name is %q, expected %q", run, log.ConnectorName, connectorID)
+	}
+	if log.Status != "success" {
+		t.Errorf("%s sync log status is %q (%s)", run, log.Status, log.ErrorMessage)
+	}
+	if log.StartedAt.Before(start.Add(-time.Second)) || log.CompletedAt.After(end.Add(time.Second)) || log.CompletedAt.Before(log.StartedAt) {
+		t.Errorf("%s sync log times %v - %v are outside the run", run, log.StartedAt, log.CompletedAt)
+	}
+	if log.JobsInserted+log.JobsDuplicates > log.JobsFetched {
+		t.Errorf("%s sync log counts %d inserted + %d duplicates exceed %d fetched",
+			run, log.JobsInserted, log.JobsDuplicates, log.JobsFetched)
+	}
+}
+
+func checkURL(raw string) error {
+	u, err := url.Parse(raw)
+	switch {
+	case raw == "":
+		return fmt.Errorf("empty")
+	case err != nil:
+		return err
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("%q is not an absolute http(s) URL", raw)
+	case u.Host == "":
+		return fmt.Errorf("%q has no host", raw)
+	}
+	return nil
+}
+
+// fieldTime accepts the time.Time connectors set and the string it becomes once stored
+func fieldTime(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, !v.IsZero()
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		return parsed, err == nil
+	}
+	return time.Time{}, false
+}
+
+func duplicateIDs(jobs []models.JobPost) []string {
+	seen := map[string]bool{}
+	dup := []string{}
+	for _, job := range jobs {
+		if seen[job.ID] {
+			dup = append(dup, job.ID)
+		}
+		seen[job.ID] = true
+	}
+	return dup
+}
+
+func sortedIDs(jobs []models.JobPost) []string {
+	ids := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,76 @@
+package conformance
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/models"
+)
+
+func validJob(now time.Time) models.JobPost {
+	return models.JobPost{
+		ID:           "remotive:123",
+		Title:        "Go Developer",
+		Company:      "Acme",
+		URL:          "https://remotive.com/remote-jobs/123",
+		PostedDate:   now.Add(-48 * time.Hour),
+		Requirements: []string{},
+		Benefits:     []string{},
+		Fields: map[string]interface{}{
+			"source":      "remotive",
+			"source_url":  "https://remotive.com/remote-jobs/123",
+			"original_id": "123",
+			"connector":   "remotive",
+			"fetched_at":  now,
+		},
+	}
+}
+
+func TestCheckJob(t *testing.T) {
+	start := time.Now()
+	end := start.Add(time.Second)
+
+	if problems := CheckJob(validJob(start), "remotive", "", start, end); len(problems) != 0 {
+		t.Errorf("Expected a valid job, got %v", problems)
+	}
+
+	min, max := 60000, 50000
+	tests := []struct {
+		name   string
+		modify func(*models.JobPost)
+		want   string
+	}{
+		{"prefix", func(j *models.JobPost) { j.ID = "123" }, "namespaced"},
+		{"spaces in ID", func(j *models.JobPost) { j.ID = "remotive:1 2" }, "whitespace"},
+		{"company", func(j *models.JobPost) { j.Company = " " }, "empty Company"},
+		{"relative URL", func(j *models.JobPost) { j.URL = "/jobs/123" }, "absolute"},
+		{"fetch time as posted date", func(j *models.JobPost) { j.PostedDate = start }, "posted_date_estimated"},
+		{"zero posted date", func(j *models.JobPost) { j.PostedDate = time.Time{} }, "zero PostedDate"},
+		{"future posted date", func(j *models.JobPost) { j.PostedDate = start.AddDate(0, 1, 0) }, "future"},
+		{"expires before posted", func(j *models.JobPost) { j.ExpiresDate = j.PostedDate.Add(-time.Hour) }, "ExpiresDate"},
+		{"missing source", func(j *models.JobPost) { delete(j.Fields, "source") }, "fields.source"},
+		{"field key", func(j *models.JobPost) { j.Fields["jobType"] = "x" }, "snake_case"},
+		{"connector", func(j *models.JobPost) { j.Fields["connector"] = "other" }, "connector ID"},
+		{"nil slices", func(j *models.JobPost) { j.Requirements = nil }, "not nil"},
+		{"salary range", func(j *models.JobPost) { j.SalaryMin, j.SalaryMax = &min, &max }, "SalaryMin"},
+		{"currency", func(j *models.JobPost) { j.SalaryCurrency = "kr" }, "ISO 4217"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := validJob(start)
+			tt.modify(&job)
+			problems := CheckJob(job, "remotive", "", start, end)
+			if !strings.Contains(strings.Join(problems, "\n"), tt.want) {
+				t.Errorf("Expected a problem mentioning %q, got %v", tt.want, problems)
+			}
+		})
+	}
+
+	estimated := validJob(start)
+	estimated.PostedDate = start
+	estimated.Fields["posted_date_estimated"] = true
+	if problems := CheckJob(estimated, "remotive", "", start, end); len(problems) != 0 {
+		t.Errorf("Estimated posted dates are allowed, got %v", problems)
+	}
+}
@@ -0,0 +1,184 @@
+// Package currency converts annual salaries to euros with a versioned exchange-rate
+// table, so salaries from different connectors can be filtered and compared. The
+// table is loaded from a local file (EXCHANGE_RATES_FILE): a JSON table or the ECB's
+// eurofxref.csv. Without one, a built-in snapshot is used.
+package currency
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"openjobs/pkg/models"
+)
+
+// Rates is one version of the exchange-rate table
+type Rates struct {
+	Version string             `json:"version"`
+	Rates   map[string]float64 `json:"rates"` // ISO 4217 code -> units per euro, like the ECB reference rates
+}
+
+//go:embed rates.json
+var builtinRates []byte
+
+// Default is the built-in table, a rounded snapshot for offline use. Load current
+// rates with EXCHANGE_RATES_FILE.
+var Default = mustParse(builtinRates)
+
+func mustParse(data []byte) *Rates {
+	rates, err := parseJSON(data)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in exchange rates: %v", err))
+	}
+	return rates
+}
+
+// FromEnv loads the table named by EXCHANGE_RATES_FILE, or returns Default when it is
+// unset or can't be read
+func FromEnv() *Rates {
+	path := os.Getenv("EXCHANGE_RATES_FILE")
+	if path == "" {
+		return Default
+	}
+	rates, err := Load(path)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load exchange rates from %s, using built-in rates: %v\n", path, err)
+		return Default
+	}
+	return rates
+}
+
+// Load reads a .json table ({"version": ..., "rates": {"SEK": 11.0, ...}}) or the
+// ECB's eurofxref.csv, whose version is its date ("ecb-2025-10-01")
+func Load(path string) (*Rates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates *Rates
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		rates, err = parseJSON(data)
+	case ".csv":
+		rates, err = parseECB(data)
+	default:
+		return nil, fmt.Errorf("unsupported exchange rate file %s (want .json or .csv)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return rates, nil
+}
+
+func parseJSON(data []byte) (*Rates, error) {
+	var rates Rates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return nil, err
+	}
+	if rates.Version == "" {
+		return nil, fmt.Errorf("missing version")
+	}
+	return rates.validate()
+}
+
+// parseECB reads "Date, USD, JPY, ..." followed by one row of rates
+func parseECB(data []byte) (*Rates, error) {
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 || len(records[0]) < 2 || records[0][0] != "Date" {
+		return nil, fmt.Errorf("not an ECB reference rate file")
+	}
+
+	date, err := time.Parse("02 January 2006", strings.TrimSpace(records[1][0]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", records[1][0])
+	}
+	rates := Rates{Version: "ecb-" + date.Format("2006-01-02"), Rates: map[string]float64{}}
+	for i, code := range records[0][1:] {
+		code = strings.TrimSpace(code)
+		if code == "" || i+1 >= len(records[1]) {
+			continue
+		}
+		if rate, err := strconv.ParseFloat(strings.TrimSpace(records[1][i+1]), 64); err == nil {
+			rates.Rates[code] = rate
+		}
+	}
+	return rates.validate()
+}
+
+func (r *Rates) validate() (*Rates, error) {
+	if r.Rates == nil {
+		r.Rates = map[string]float64{}
+	}
+	for code, rate := range r.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid rate %v for %s", rate, code)
+		}
+	}
+	r.Rates["EUR"] = 1
+	return r, nil
+}
+
+// ToEUR converts an amount; ok is false for currencies the table doesn't have
+func (r *Rates) ToEUR(amount float64, currency string) (int, bool) {
+	rate, ok := r.Rates[strings.ToUpper(currency)]
+	if !ok {
+		return 0, false
+	}
+	return int(math.Round(amount / rate)), true
+}
+
+// Normalize sets the job's salary_annual_min_eur and salary_annual_max_eur from
+// fields.salary_annual_min and salary_annual_max (see pkg/salary) and records the
+// table version in fields.salary_rates_version. Jobs without an annual salary or with
+// a currency the table doesn't have get none.
+func (r *Rates) Normalize(job *models.JobPost) {
+	job.SalaryAnnualMinEUR, job.SalaryAnnualMaxEUR = nil, nil
+	if job.Fields != nil {
+		delete(job.Fields, "salary_rates_version")
+	}
+
+	convert := func(key string) *int {
+		amount, ok := number(job.Fields[key])
+		if !ok {
+			return nil
+		}
+		eur, ok := r.ToEUR(amount, job.SalaryCurrency)
+		if !ok {
+			return nil
+		}
+		return &eur
+	}
+	job.SalaryAnnualMinEUR = convert("salary_annual_min")
+	job.SalaryAnnualMaxEUR = convert("salary_annual_max")
+	if job.SalaryAnnualMinEUR != nil || job.SalaryAnnualMaxEUR != nil {
+		job.Fields["salary_rates_version"] = r.Version
+	}
+}
+
+// number reads a Fields value, which is an int when set and a float64 once stored
+func number(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"openjobs/pkg/models"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "rates.json")
+	csvPath := filepath.Join(dir, "eurofxref.csv")
+	os.WriteFile(jsonPath, []byte(`{"version": "test-1", "rates": {"SEK": 10, "USD": 1.25}}`), 0644)
+	os.WriteFile(csvPath, []byte("Date, USD, JPY, SEK, \n01 October 2025, 1.1741, 173.76, 11.0315, \n"), 0644)
+
+	rates, err := Load(jsonPath)
+	if err != nil || rates.Version != "test-1" || rates.Rates["SEK"] != 10 || rates.Rates["EUR"] != 1 {
+		t.Errorf("Load(json) = %+v, %v", rates, err)
+	}
+	rates, err = Load(csvPath)
+	if err != nil || rates.Version != "ecb-2025-10-01" || rates.Rates["SEK"] != 11.0315 || len(rates.Rates) != 4 {
+		t.Errorf("Load(csv) = %+v, %v", rates, err)
+	}
+
+	os.WriteFile(jsonPath, []byte(`{"version": "bad", "rates": {"SEK": 0}}`), 0644)
+	if _, err := Load(jsonPath); err == nil {
+		t.Error("Expected an error for a zero rate")
+	}
+	if _, err := Load(filepath.Join(dir, "rates.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	rates := &Rates{Version: "test-1", Rates: map[string]float64{"EUR": 1, "SEK": 10, "USD": 1.25}}
+
+	if eur, ok := rates.ToEUR(125000, "usd"); !ok || eur != 100000 {
+		t.Errorf("ToEUR(125000 USD) = %d, %v", eur, ok)
+	}
+	if _, ok := rates.ToEUR(1000, "XYZ"); ok {
+		t.Error("Expected an unknown currency to fail")
+	}
+
+	job := &models.JobPost{SalaryCurrency: "SEK", Fields: map[string]interface{}{"salary_annual_min": 540000, "salary_annual_max": float64(600000)}}
+	rates.Normalize(job)
+	if *job.SalaryAnnualMinEUR != 54000 || *job.SalaryAnnualMaxEUR != 60000 || job.Fields["salary_rates_version"] != "test-1" {
+		t.Errorf("Unexpected normalized salary: %v %v %v", *job.SalaryAnnualMinEUR, *job.SalaryAnnualMaxEUR, job.Fields)
+	}
+
+	job.SalaryCurrency = "XYZ"
+	rates.Normalize(job)
+	if job.SalaryAnnualMinEUR != nil || job.Fields["salary_rates_version"] != nil {
+		t.Errorf("Expected no EUR salary for an unknown currency: %+v", job)
+	}
+
+	rates.Normalize(&models.JobPost{SalaryCurrency: "SEK"})
+}
@@ -0,0 +1,141 @@
+package dedup
+
+import (
+	"fmt"
+	"time"
+
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+// batchSize is the number of unclustered jobs read per round
+const batchSize = 500
+
+// candidateWindow is how far apart in posted date two ads of a posting can be
+const candidateWindow = 90 * 24 * time.Hour
+
+// Result counts what a clustering run did
+type Result struct {
+	Clustered  int // Jobs that got a cluster
+	Duplicates int // Of those, jobs that joined another job's cluster
+	Failed     int
+	Clusters   []string // Clusters that got jobs, for Merger.Rebuild
+}
+
+// Clusterer assigns stored jobs to duplicate clusters. A cluster's ID is the ID of
+// its first job, which is the one shown by /jobs?collapse=true.
+type Clusterer struct {
+	store *storage.JobStore
+}
+
+// NewClusterer creates a clusterer for the jobs in store
+func NewClusterer(store *storage.JobStore) *Clusterer {
+	return &Clusterer{store: store}
+}
+
+// Run clusters every job that has no fields.cluster_id yet, oldest first. Jobs are
+// only compared with clustered jobs of the same company posted within candidateWindow,
+// so a run after each sync only looks at what the sync inserted.
+func (c *Clusterer) Run() (Result, error) {
+	result := Result{}
+	touched := map[string]bool{}
+	var last *models.JobPost
+	for {
+		jobs, err := c.store.GetUnclusteredJobs(last, batchSize)
+		if err != nil {
+			return result, fmt.Errorf("failed to read unclustered jobs: %w", err)
+		}
+
+		for _, job := range jobs {
+			clusterID, err := c.assign(job)
+			if err != nil {
+				fmt.Printf("⚠️  Failed to cluster job %s: %v\n", job.ID, err)
+				result.Failed++
+				continue
+			}
+			result.Clustered++
+			if clusterID != job.ID {
+				result.Duplicates++
+			}
+			if !touched[clusterID] {
+				touched[clusterID] = true
+				result.Clusters = append(result.Clusters, clusterID)
+			}
+		}
+
+		// Jobs that failed stay unclustered until the next run; page past them
+		if len(jobs) < batchSize {
+			return result, nil
+		}
+		last = jobs[len(jobs)-1]
+	}
+}
+
+// assign stores the cluster of one job and returns its ID
+func (c *Clusterer) assign(job *models.JobPost) (string, error) {
+	fp := FingerprintOf(job)
+
+	clusterID := job.ID
+	if fp.Company != "" {
+		posted := job.PostedDate
+		if posted.IsZero() {
+			posted = time.Now()
+		}
+		candidates, err := c.store.GetJobsByCompanyKey(fp.Company, posted.Add(-candidateWindow), posted.Add(candidateWindow))
+		if err != nil {
+			return "", err
+		}
+		clusterID = bestCluster(job, fp, candidates)
+	}
+
+	if job.Fields == nil {
+		job.Fields = map[string]interface{}{}
+	}
+	job.Fields["company_key"] = fp.Company
+	job.Fields["cluster_id"] = clusterID
+	if clusterID != job.ID {
+		job.Fields["duplicate"] = true
+	} else {
+		delete(job.Fields, "duplicate")
+	}
+
+	if err := c.store.UpdateJob(job); err != nil {
+		return "", err
+	}
+	return clusterID, nil
+}
+
+// bestCluster returns the cluster of the most similar clustered candidate, or the
+// job's own ID when none reaches Threshold. A cluster takes one job per source: two
+// similar jobs from the same source are two openings, not a duplicate.
+func bestCluster(job *models.JobPost, fp Fingerprint, candidates []*models.JobPost) string {
+	source := fieldString(job, "source")
+	sources := map[string]map[string]bool{}
+	for _, candidate := range candidates {
+		clusterID := fieldString(candidate, "cluster_id")
+		if clusterID == "" || candidate.ID == job.ID {
+			continue
+		}
+		if sources[clusterID] == nil {
+			sources[clusterID] = map[string]bool{}
+		}
+		sources[clusterID][fieldString(candidate, "source")] = true
+	}
+
+	best, bestScore := job.ID, 0.0
+	for _, candidate := range candidates {
+		clusterID := fieldString(candidate, "cluster_id")
+		if clusterID == "" || candidate.ID == job.ID || (source != "" && sources[clusterID][source]) {
+			continue
+		}
+		if score := Similarity(fp, FingerprintOf(candidate)); score >= Threshold && score > bestScore {
+			best, bestScore = clusterID, score
+		}
+	}
+	return best
+}
+
+func fieldString(job *models.JobPost, key string) string {
+	value, _ := job.Fields[key].(string)
+	return value
+}
@@ -0,0 +1,342 @@
+package dedup
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage/storagetest"
+)
+
+const afText = `Vi söker en erfaren backendutvecklare som vill bygga våra betaltjänster i Go.
+Du arbetar nära produktteamet, äger tjänster från design till drift och är med och
+formar vår plattform. Vi erbjuder flexibla arbetstider, friskvårdsbidrag och en
+kollektivavtalad pension. Välkommen med din ansökan!`
+
+func job(id, source, title, company, location, description string) *models.JobPost {
+	return &models.JobPost{
+		ID:          id,
+		Title:       title,
+		Company:     company,
+		Location:    location,
+		Description: description,
+		PostedDate:  time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+		Fields:      map[string]interface{}{"source": source},
+	}
+}
+
+func TestFingerprintOf(t *testing.T) {
+	fp := FingerprintOf(job("x", "x", "Backend-utvecklare (m/w/d)", "AB Klarna Bank AB (publ)", "Göteborg, Västra Götaland", ""))
+	if fp.Title != "backend utvecklare" || fp.Company != "klarna bank" || fp.Location != "gothenburg" {
+		t.Errorf("Unexpected fingerprint: %+v", fp)
+	}
+	if fp := FingerprintOf(job("x", "x", "Dev", "Acme", "Remote", "")); fp.Location != "" {
+		t.Errorf("Expected a remote location to be unknown, got %q", fp.Location)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	af := job("a", "af", "Backendutvecklare Go", "Payments AB", "Stockholm, Stockholms län", afText)
+	tests := []struct {
+		name  string
+		other *models.JobPost
+		match bool
+	}{
+		{"snippet of the same ad", job("b", "jooble", "Backendutvecklare Go", "Payments", "Stockholm",
+			"Vi söker en erfaren backendutvecklare som vill bygga våra betaltjänster i Go. Du arbetar nära produktteamet"), true},
+		{"same title, no description", job("c", "indeed", "Backendutvecklare (Go)", "Payments AB", "Stockholm", ""), true},
+		{"other company", job("d", "indeed", "Backendutvecklare Go", "Other AB", "Stockholm", afText), false},
+		{"other city", job("e", "indeed", "Backendutvecklare Go", "Payments AB", "Malmö", afText), false},
+		{"other role, shared boilerplate", job("f", "jooble", "Frontendutvecklare React", "Payments AB", "Stockholm",
+			"Vi söker en frontendutvecklare som bygger vår app i React. Vi erbjuder flexibla arbetstider, friskvårdsbidrag och en kollektivavtalad pension."), false},
+		{"similar title, no description", job("g", "indeed", "Senior Backendutvecklare", "Payments AB", "Stockholm", ""), false},
+	}
+	for _, tt := range tests {
+		score := Similarity(FingerprintOf(af), FingerprintOf(tt.other))
+		if (score >= Threshold) != tt.match {
+			t.Errorf("%s: similarity %.2f, expected match = %v", tt.name, score, tt.match)
+		}
+	}
+}
+
+func TestClustererRun(t *testing.T) {
+	server := storagetest.NewServer(t)
+	store := server.Store()
+
+	jobs := []*models.JobPost{
+		job("arbetsformedlingen:1", "arbetsformedlingen", "Backendutvecklare Go", "Payments AB", "Stockholm", afText),
+		job("jooble:2", "jooble", "Backendutvecklare Go", "Payments", "Stockholm, Sweden", afText[:120]),
+		job("indeed:3", "indeed", "Backendutvecklare (Go)", "Payments AB", "Stockholm", ""),
+		// A second AF ad with the same title is another opening, not a duplicate
+		job("arbetsformedlingen:4", "arbetsformedlingen", "Backendutvecklare Go", "Payments AB", "Stockholm", afText),
+		job("remotive:5", "remotive", "Data Engineer", "Payments AB", "Remote", ""),
+	}
+	for i, j := range jobs {
+		j.PostedDate = j.PostedDate.AddDate(0, 0, i)
+		if err := store.CreateJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := NewClusterer(store).Run()
+	if err != nil || result.Clustered != 5 || result.Duplicates != 2 || result.Failed != 0 {
+		t.Fatalf("Run() = %+v, %v; want 5 clustered, 2 duplicates", result, err)
+	}
+
+	want := map[string]string{
+		"arbetsformedlingen:1": "arbetsformedlingen:1",
+		"jooble:2":             "arbetsformedlingen:1",
+		"indeed:3":             "arbetsformedlingen:1",
+		"arbetsformedlingen:4": "arbetsformedlingen:4",
+		"remotive:5":           "remotive:5",
+	}
+	for _, stored := range server.Jobs() {
+		if stored.Fields["cluster_id"] != want[stored.ID] {
+			t.Errorf("%s: cluster %v, want %s", stored.ID, stored.Fields["cluster_id"], want[stored.ID])
+		}
+		if duplicate := stored.Fields["duplicate"] == true; duplicate != (want[stored.ID] != stored.ID) {
+			t.Errorf("%s: duplicate = %v", stored.ID, duplicate)
+		}
+	}
+
+	collapsed, err := store.GetRepresentativeJobs(20, 0)
+	if err != nil || len(collapsed) != 3 {
+		t.Errorf("GetRepresentativeJobs() returned %d jobs, %v; want 3", len(collapsed), err)
+	}
+
+	// Nothing is left to cluster, and a re-synced representative stays one
+	if result, _ := NewClusterer(store).Run(); result.Clustered != 0 {
+		t.Errorf("Second run clustered %d jobs, want 0", result.Clustered)
+	}
+	first := job("arbetsformedlingen:1", "arbetsformedlingen", "Backendutvecklare Go", "Payments AB", "Stockholm", afText)
+	if err := store.UpdateJob(first); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := NewClusterer(store).Run(); result.Clustered != 1 || result.Duplicates != 0 {
+		t.Errorf("Re-clustering the representative = %+v, want it to keep its cluster", result)
+	}
+}
+
+// TestClustererRunPagesPastFailures checks that a full batch of jobs that can't be
+// updated doesn't stop the run: the newer jobs behind it are still clustered
+func TestClustererRunPagesPastFailures(t *testing.T) {
+	server := storagetest.NewServer(t)
+	posted := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < batchSize+5; i++ {
+		j := job(fmt.Sprintf("indeed:%04d", i), "indeed", "Developer", "", "Stockholm", "")
+		j.PostedDate = posted.Add(time.Duration(i) * time.Minute)
+		if err := server.Insert("job_posts", j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Updates of the oldest batch always fail
+	target, _ := url.Parse(server.URL)
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Query().Get("id"), "eq.")
+		if r.Method == http.MethodPatch && id < fmt.Sprintf("indeed:%04d", batchSize) {
+			http.Error(w, "update failed", http.StatusInternalServerError)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer failing.Close()
+	t.Setenv("SUPABASE_URL", failing.URL)
+
+	result, err := NewClusterer(server.Store()).Run()
+	if err != nil || result.Failed != batchSize || result.Clustered != 5 {
+		t.Fatalf("Run() = %d clustered, %d failed, %v; want 5 clustered, %d failed", result.Clustered, result.Failed, err, batchSize)
+	}
+	for _, stored := range server.Jobs() {
+		if clustered := stored.Fields["cluster_id"] != nil; clustered != (stored.ID >= fmt.Sprintf("indeed:%04d", batchSize)) {
+			t.Errorf("%s: clustered = %v", stored.ID, clustered)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	min, max := 45000, 55000
+	af := job("arbetsformedlingen:1", "arbetsformedlingen", "Backendutvecklare", "Payments AB", "Stockholm", "Kort text")
+	af.Fields["connector"] = "arbetsformedlingen"
+	af.Salary, af.SalaryMin, af.SalaryMax, af.SalaryCurrency = "45-55k/mån", &min, &max, "SEK"
+	af.URL = "https://arbetsformedlingen.se/platsbanken/annonser/1"
+	af.Fields["salary_period"] = "month"
+
+	ats := job("greenhouse:payments-9", "greenhouse", "Backend Engineer (Go)", "Payments", "Stockholm, Sweden", "The full description from the employer")
+	ats.Fields["connector"] = "greenhouse"
+	ats.URL = "https://boards.greenhouse.io/payments/jobs/9"
+	ats.SalaryCurrency = "EUR" // A currency alone is not a salary
+	ats.Fields["salary_period"] = "year"
+
+	jooble := job("jooble:3", "jooble", "Backendutvecklare", "Payments", "Stockholm", "Snippet")
+	jooble.Fields["connector"] = "jooble"
+	jooble.Fields["posted_date_estimated"] = true
+	jooble.PostedDate = af.PostedDate.AddDate(0, 0, -3)
+	jooble.ExperienceLevel = "Mid-level"
+
+	record := Merge(af.ID, []*models.JobPost{af, ats, jooble}, DefaultTrust)
+	golden := record.Job
+	if golden.ID != af.ID || golden.Description != ats.Description || golden.URL != ats.URL || golden.Title != ats.Title {
+		t.Errorf("Expected the ATS text and apply URL, got %q %q %q", golden.Title, golden.Description, golden.URL)
+	}
+	if golden.SalaryMin != &min || golden.SalaryCurrency != "SEK" || golden.Location != af.Location {
+		t.Errorf("Expected the AF salary and location, got %v %q %q", golden.SalaryMin, golden.SalaryCurrency, golden.Location)
+	}
+	if !golden.PostedDate.Equal(af.PostedDate) || golden.ExperienceLevel != "Mid-level" {
+		t.Errorf("Expected AF's posted date and Jooble's experience level, got %v %q", golden.PostedDate, golden.ExperienceLevel)
+	}
+	if golden.Fields["salary_period"] != "month" {
+		t.Errorf("Expected the salary period of the salary's source, got %v", golden.Fields["salary_period"])
+	}
+	if p := record.Provenance["salary"]; p.JobID != af.ID || p.Source != "arbetsformedlingen" || record.Provenance["description"].Connector != "greenhouse" {
+		t.Errorf("Unexpected provenance: %+v", record.Provenance)
+	}
+	if _, ok := record.Provenance["benefits"]; ok || len(record.JobIDs) != 3 || golden.Fields["cluster_id"] != af.ID {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+}
+
+// TestMergeClosure checks that a golden record is only closed when every job of its
+// cluster is, even when a closed job is the most trusted source
+func TestMergeClosure(t *testing.T) {
+	closedAt := time.Date(2025, 10, 20, 6, 0, 0, 0, time.UTC)
+	af := job("arbetsformedlingen:1", "arbetsformedlingen", "Backendutvecklare", "Payments AB", "Stockholm", "")
+	af.Fields["connector"] = "arbetsformedlingen"
+	af.Fields["closed"], af.Fields["closed_at"], af.Fields["closed_reason"] = true, closedAt, "removed"
+	jooble := job("jooble:2", "jooble", "Backendutvecklare", "Payments", "Stockholm", "")
+	jooble.Fields["connector"] = "jooble"
+
+	golden := Merge(af.ID, []*models.JobPost{af, jooble}, DefaultTrust).Job
+	for _, key := range closureFields {
+		if value, ok := golden.Fields[key]; ok {
+			t.Errorf("Expected an open golden record while jooble:2 is open, got %s = %v", key, value)
+		}
+	}
+
+	// Once stored, closed_at is a string; the last closure wins
+	jooble.Fields["closed"], jooble.Fields["closed_at"], jooble.Fields["closed_reason"] = true, "2025-10-22T06:00:00Z", "missing_from_feed"
+	golden = Merge(af.ID, []*models.JobPost{af, jooble}, DefaultTrust).Job
+	if golden.Fields["closed"] != true || golden.Fields["closed_at"] != "2025-10-22T06:00:00Z" || golden.Fields["closed_reason"] != "missing_from_feed" {
+		t.Errorf("Expected the golden record closed as of the last closure, got %v", golden.Fields)
+	}
+}
+
+func TestLoadTrust(t *testing.T) {
+	path := t.TempDir() + "/trust.yaml"
+	config := "default: 20\nconnectors:\n  jooble:\n    description: 95\n  mysite:\n    default: 99\n"
+	if err := os.WriteFile(path, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	trust, err := LoadTrust(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		connector, field string
+		want             int
+	}{
+		{"jooble", "description", 95},
+		{"jooble", "salary", 10},
+		{"jooble", "title", 30},
+		{"mysite", "url", 99},
+		{"greenhouse", "url", 100},
+		{"unknown", "title", 20},
+	}
+	for _, tt := range tests {
+		if got := trust.Score(tt.connector, tt.field); got != tt.want {
+			t.Errorf("Score(%s, %s) = %d, want %d", tt.connector, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestMergerRebuild(t *testing.T) {
+	server := storagetest.NewServer(t)
+	store := server.Store()
+	for _, j := range []*models.JobPost{
+		job("arbetsformedlingen:1", "arbetsformedlingen", "Backendutvecklare Go", "Payments AB", "Stockholm", afText),
+		job("jooble:2", "jooble", "Backendutvecklare Go", "Payments", "Stockholm", afText[:120]),
+		job("remotive:3", "remotive", "Data Engineer", "Other", "Remote", ""),
+	} {
+		j.Fields["connector"] = j.Fields["source"]
+		if err := store.CreateJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := NewClusterer(store).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := NewMerger(store, DefaultTrust).Rebuild(result.Clusters)
+	if err != nil || stored != 1 {
+		t.Fatalf("Rebuild() = %d, %v; want one golden record", stored, err)
+	}
+
+	records, err := store.GetGoldenRecords([]string{"arbetsformedlingen:1", "remotive:3"})
+	if err != nil || len(records) != 1 {
+		t.Fatalf("GetGoldenRecords() = %v, %v", records, err)
+	}
+	record := records["arbetsformedlingen:1"]
+	if record == nil || record.Job.Description != afText || record.Provenance["description"].JobID != "arbetsformedlingen:1" ||
+		len(record.JobIDs) != 2 {
+		t.Errorf("Unexpected golden record: %+v", record)
+	}
+}
+
+// TestMergerRefresh checks that a cluster's golden record follows a job that is updated
+// after it was clustered
+func TestMergerRefresh(t *testing.T) {
+	server := storagetest.NewServer(t)
+	store := server.Store()
+	af := job("arbetsformedlingen:1", "arbetsformedlingen", "Backendutvecklare Go", "Payments AB", "Stockholm", afText)
+	for _, j := range []*models.JobPost{af, job("jooble:2", "jooble", "Backendutvecklare Go", "Payments", "Stockholm", afText[:120])} {
+		j.Fields["connector"] = j.Fields["source"]
+		if err := store.CreateJob(j); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	merger := NewMerger(store, DefaultTrust)
+	result, err := NewClusterer(store).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored, err := merger.Refresh(result.Clusters); err != nil || stored != 1 {
+		t.Fatalf("Refresh() = %d, %v; want one golden record", stored, err)
+	}
+	if stored, err := merger.Refresh(nil); err != nil || stored != 0 {
+		t.Fatalf("Refresh() without changes = %d, %v; want nothing rebuilt", stored, err)
+	}
+
+	// The AF ad is closed by its connector, which knows nothing about clusters
+	if closed, err := store.CloseJob(af.ID, time.Now(), "removed"); err != nil || !closed {
+		t.Fatalf("CloseJob() = %v, %v", closed, err)
+	}
+	if stored, err := merger.Refresh(nil); err != nil || stored != 1 {
+		t.Fatalf("Refresh() after closing = %d, %v; want the cluster rebuilt", stored, err)
+	}
+	records, err := store.GetGoldenRecords([]string{af.ID})
+	if err != nil || records[af.ID] == nil || records[af.ID].Job.Fields["closed"] != nil {
+		t.Errorf("Expected the golden record open while the Jooble ad is: %+v, %v", records[af.ID], err)
+	}
+
+	// Once the Jooble ad is gone too, the posting is closed
+	if closed, err := store.CloseJob("jooble:2", time.Now(), "missing_from_feed"); err != nil || !closed {
+		t.Fatalf("CloseJob() = %v, %v", closed, err)
+	}
+	if stored, err := merger.Refresh(nil); err != nil || stored != 1 {
+		t.Fatalf("Refresh() after closing = %d, %v; want the cluster rebuilt", stored, err)
+	}
+	records, err = store.GetGoldenRecords([]string{af.ID})
+	if err != nil || records[af.ID] == nil || records[af.ID].Job.Fields["closed_reason"] != "missing_from_feed" {
+		t.Errorf("Golden record doesn't reflect the closed jobs: %+v, %v", records[af.ID], err)
+	}
+}
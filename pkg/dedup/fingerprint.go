@@ -0,0 +1,121 @@
+// Package dedup finds the same posting published by several sources (AF, Jooble,
+// Indeed, Adzuna, ...) under different IDs. Jobs are fingerprinted on a normalized
+// title, company and location plus shingles of the description, compared fuzzily, and
+// grouped into clusters: every job gets fields.cluster_id, and all but the first job of
+// a cluster are flagged fields.duplicate.
+package dedup
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"openjobs/pkg/models"
+)
+
+// shingleSize is the number of words per description shingle
+const shingleSize = 3
+
+var (
+	htmlTagRe   = regexp.MustCompile(`<[^>]*>`)
+	genderTagRe = regexp.MustCompile(`(?i)\(\s*(?:[mwfdxhk]\s*/\s*)+[mwfdxhk]\s*\)|\(\s*all genders\s*\)`)
+	nonWordRe   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// foldReplacer folds the diacritics that occur in our sources' languages
+var foldReplacer = strings.NewReplacer(
+	"å", "a", "ä", "a", "á", "a", "à", "a", "â", "a", "ã", "a",
+	"ö", "o", "ø", "o", "ó", "o", "ò", "o", "ô", "o", "õ", "o",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"ü", "u", "ú", "u", "ù", "u", "û", "u",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ç", "c", "ñ", "n", "ł", "l", "ś", "s", "š", "s", "ž", "z", "ź", "z", "ż", "z",
+	"æ", "ae", "ß", "ss",
+)
+
+// legalForms are company suffixes that differ between sources for the same employer
+var legalForms = map[string]bool{
+	"ab": true, "aktiebolag": true, "publ": true, "hb": true, "kb": true,
+	"as": true, "asa": true, "aps": true, "oy": true, "oyj": true,
+	"gmbh": true, "ag": true, "kg": true, "se": true, "bv": true, "nv": true,
+	"sa": true, "sas": true, "sarl": true, "srl": true, "spa": true,
+	"ltd": true, "limited": true, "plc": true, "llc": true, "inc": true,
+	"corp": true, "corporation": true, "co": true,
+}
+
+// remoteLocations are locations that do not pin a job to a place
+var remoteLocations = map[string]bool{
+	"remote": true, "distans": true, "anywhere": true, "worldwide": true,
+}
+
+// cityNames maps local city names to the English ones other sources use
+var cityNames = map[string]string{
+	"goteborg":    "gothenburg",
+	"kobenhavn":   "copenhagen",
+	"munchen":     "munich",
+	"koln":        "cologne",
+	"wien":        "vienna",
+	"praha":       "prague",
+	"warszawa":    "warsaw",
+	"lisboa":      "lisbon",
+	"bruxelles":   "brussels",
+	"den haag":    "the hague",
+	"helsingfors": "helsinki",
+}
+
+// Fingerprint is the normalized form of a job that matching compares
+type Fingerprint struct {
+	Title    string
+	Company  string
+	Location string // First part of the location; empty when unknown or remote
+	Shingles map[uint64]bool
+}
+
+// FingerprintOf returns the fingerprint of a job
+func FingerprintOf(job *models.JobPost) Fingerprint {
+	location := normalize(strings.SplitN(job.Location, ",", 2)[0])
+	if city, ok := cityNames[location]; ok {
+		location = city
+	}
+	fp := Fingerprint{
+		Title:    normalize(genderTagRe.ReplaceAllString(job.Title, " ")),
+		Company:  CompanyKey(job.Company),
+		Location: location,
+		Shingles: shingles(job.Description),
+	}
+	if remoteLocations[location] {
+		fp.Location = ""
+	}
+	return fp
+}
+
+// CompanyKey is the normalized company name jobs are compared within: lowercase,
+// without diacritics, punctuation or legal forms ("Spotify AB" -> "spotify")
+func CompanyKey(company string) string {
+	words := strings.Fields(normalize(company))
+	if len(words) > 1 && words[0] == "ab" {
+		words = words[1:] // "AB Volvo"
+	}
+	for len(words) > 1 && legalForms[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// normalize lowercases s, folds diacritics and reduces it to words separated by spaces
+func normalize(s string) string {
+	s = foldReplacer.Replace(strings.ToLower(s))
+	return strings.TrimSpace(nonWordRe.ReplaceAllString(s, " "))
+}
+
+// shingles hashes every run of shingleSize words of a description
+func shingles(description string) map[uint64]bool {
+	words := strings.Fields(normalize(htmlTagRe.ReplaceAllString(description, " ")))
+	set := map[uint64]bool{}
+	for i := 0; i+shingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+shingleSize], " ")))
+		set[h.Sum64()] = true
+	}
+	return set
+}
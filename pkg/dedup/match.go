@@ -0,0 +1,82 @@
+package dedup
+
+import "strings"
+
+const (
+	// Threshold is the similarity from which two jobs are the same posting
+	Threshold = 0.75
+	// titleOnlyThreshold applies when a description is missing (Indeed cards, feed
+	// items without a body), so only the title can tell two postings apart
+	titleOnlyThreshold = 0.9
+	// minShingles is the description length, in shingles, worth comparing
+	minShingles = 10
+)
+
+// Similarity scores how likely two fingerprints are the same posting, from 0 to 1.
+// Jobs of different companies or incompatible locations never match. Otherwise the
+// score averages title trigram similarity and description containment, which finds a
+// Jooble snippet inside the full AF text; without two descriptions the title alone
+// has to reach titleOnlyThreshold.
+func Similarity(a, b Fingerprint) float64 {
+	if a.Company == "" || a.Company != b.Company || !sameLocation(a.Location, b.Location) {
+		return 0
+	}
+
+	title := jaccard(trigrams(a.Title), trigrams(b.Title))
+	if len(a.Shingles) < minShingles || len(b.Shingles) < minShingles {
+		if title < titleOnlyThreshold {
+			return title * Threshold // Never enough on its own
+		}
+		return title
+	}
+	return (title + containment(a.Shingles, b.Shingles)) / 2
+}
+
+// sameLocation is true when the locations can be the same place: either is unknown,
+// or one names the other ("stockholm" and "stockholm city")
+func sameLocation(a, b string) bool {
+	if a == "" || b == "" || a == b {
+		return true
+	}
+	return strings.Contains(" "+a+" ", " "+b+" ") || strings.Contains(" "+b+" ", " "+a+" ")
+}
+
+// trigrams returns the character trigrams of s, padded so short words count
+func trigrams(s string) map[string]bool {
+	padded := []rune("  " + s + " ")
+	set := map[string]bool{}
+	for i := 0; i+3 <= len(padded); i++ {
+		set[string(padded[i:i+3])] = true
+	}
+	return set
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// containment is the share of the smaller shingle set found in the larger one
+func containment(a, b map[uint64]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(a) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a))
+}
@@ -0,0 +1,287 @@
+package dedup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+// mergedFields are the JobPost fields a golden record picks a source for, by JSON name.
+// "salary" covers salary, salary_min, salary_max, salary_currency and the euro range,
+// which are only taken together so a range never gets another source's currency, and
+// "location" the structured location resolved from it.
+var mergedFields = []string{
+	"title", "company", "description", "location", "salary", "is_remote", "url",
+	"employment_type", "experience_level", "posted_date", "expires_date",
+	"requirements", "benefits",
+}
+
+// salaryFields are the Fields entries salary.Salary.Apply and currency.Rates.Normalize
+// derive from a salary
+var salaryFields = []string{"salary_period", "salary_annual_min", "salary_annual_max", "salary_rates_version"}
+
+// closureFields are the Fields entries storage.JobStore.CloseJob sets on a closed job
+var closureFields = []string{"closed", "closed_at", "closed_reason"}
+
+// Merge composes the golden record of a cluster from its jobs, oldest first: each
+// field comes from the job whose connector is trusted most for it, the older job on a
+// tie. Fields entries are merged the same way, by each connector's default score.
+func Merge(clusterID string, jobs []*models.JobPost, trust Trust) *models.GoldenRecord {
+	record := &models.GoldenRecord{
+		ClusterID:  clusterID,
+		Provenance: map[string]models.Provenance{},
+		JobIDs:     make([]string, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		record.JobIDs = append(record.JobIDs, job.ID)
+	}
+	if len(jobs) == 0 {
+		return record
+	}
+
+	golden := &record.Job
+	golden.ID = clusterID
+	golden.Requirements = []string{}
+	golden.Benefits = []string{}
+
+	for _, field := range mergedFields {
+		var best *models.JobPost
+		bestScore := 0
+		for _, job := range jobs {
+			if !hasValue(job, field) {
+				continue
+			}
+			if score := trust.Score(fieldString(job, "connector"), field); best == nil || score > bestScore {
+				best, bestScore = job, score
+			}
+		}
+		if best == nil && field == "posted_date" {
+			best = jobs[0] // Only estimated dates: the first time any source saw the job
+		}
+		if best != nil {
+			copyField(golden, best, field)
+			record.Provenance[field] = provenanceOf(best)
+		}
+	}
+
+	// Least trusted first, so the most trusted source wins each key
+	byTrust := append([]*models.JobPost{}, jobs...)
+	sort.SliceStable(byTrust, func(i, j int) bool {
+		return trust.Score(fieldString(byTrust[i], "connector"), "default") <
+			trust.Score(fieldString(byTrust[j], "connector"), "default")
+	})
+	golden.Fields = map[string]interface{}{}
+	for _, job := range byTrust {
+		for key, value := range job.Fields {
+			golden.Fields[key] = value
+		}
+	}
+
+	// The salary's period and annualized range belong to the job the salary came from
+	for _, key := range salaryFields {
+		delete(golden.Fields, key)
+	}
+	for _, job := range jobs {
+		if job.ID != record.Provenance["salary"].JobID {
+			continue
+		}
+		for _, key := range salaryFields {
+			if value, ok := job.Fields[key]; ok {
+				golden.Fields[key] = value
+			}
+		}
+	}
+
+	// The posting is closed once every source closed it, as of the last closure; an
+	// open source that never closed anything has no closure keys to win with
+	for _, key := range closureFields {
+		delete(golden.Fields, key)
+	}
+	var lastClosed *models.JobPost
+	for _, job := range jobs {
+		if closed, _ := job.Fields["closed"].(bool); !closed {
+			lastClosed = nil
+			break
+		}
+		if lastClosed == nil || closedAt(job).After(closedAt(lastClosed)) {
+			lastClosed = job
+		}
+	}
+	if lastClosed != nil {
+		for _, key := range closureFields {
+			if value, ok := lastClosed.Fields[key]; ok {
+				golden.Fields[key] = value
+			}
+		}
+	}
+
+	delete(golden.Fields, "duplicate")
+	golden.Fields["cluster_id"] = clusterID
+
+	return record
+}
+
+// hasValue is true when a job has something to offer for a field
+func hasValue(job *models.JobPost, field string) bool {
+	switch field {
+	case "title":
+		return strings.TrimSpace(job.Title) != ""
+	case "company":
+		return strings.TrimSpace(job.Company) != ""
+	case "description":
+		return strings.TrimSpace(job.Description) != ""
+	case "location":
+		return strings.TrimSpace(job.Location) != ""
+	case "salary":
+		return strings.TrimSpace(job.Salary) != "" || job.SalaryMin != nil || job.SalaryMax != nil
+	case "is_remote":
+		return true
+	case "url":
+		return strings.TrimSpace(job.URL) != ""
+	case "employment_type":
+		return strings.TrimSpace(job.EmploymentType) != ""
+	case "experience_level":
+		return strings.TrimSpace(job.ExperienceLevel) != ""
+	case "posted_date":
+		estimated, _ := job.Fields["posted_date_estimated"].(bool)
+		return !job.PostedDate.IsZero() && !estimated
+	case "expires_date":
+		return !job.ExpiresDate.IsZero()
+	case "requirements":
+		return len(job.Requirements) > 0
+	case "benefits":
+		return len(job.Benefits) > 0
+	}
+	return false
+}
+
+// closedAt returns when a job was closed: a time.Time as set by CloseJob, or its
+// RFC3339 form once stored
+func closedAt(job *models.JobPost) time.Time {
+	switch value := job.Fields["closed_at"].(type) {
+	case time.Time:
+		return value
+	case string:
+		t, _ := time.Parse(time.RFC3339Nano, value)
+		return t
+	}
+	return time.Time{}
+}
+
+// copyField copies one of mergedFields from src to dst
+func copyField(dst, src *models.JobPost, field string) {
+	switch field {
+	case "title":
+		dst.Title = src.Title
+	case "company":
+		dst.Company = src.Company
+	case "description":
+		dst.Description = src.Description
+	case "location":
+		dst.Location = src.Location
+		dst.LocationCountry, dst.LocationRegion, dst.LocationCity = src.LocationCountry, src.LocationRegion, src.LocationCity
+		dst.Latitude, dst.Longitude, dst.RemoteScope = src.Latitude, src.Longitude, src.RemoteScope
+	case "salary":
+		dst.Salary, dst.SalaryMin, dst.SalaryMax, dst.SalaryCurrency = src.Salary, src.SalaryMin, src.SalaryMax, src.SalaryCurrency
+		dst.SalaryAnnualMinEUR, dst.SalaryAnnualMaxEUR = src.SalaryAnnualMinEUR, src.SalaryAnnualMaxEUR
+	case "is_remote":
+		dst.IsRemote = src.IsRemote
+	case "url":
+		dst.URL = src.URL
+	case "employment_type":
+		dst.EmploymentType = src.EmploymentType
+	case "experience_level":
+		dst.ExperienceLevel = src.ExperienceLevel
+	case "posted_date":
+		dst.PostedDate = src.PostedDate
+	case "expires_date":
+		dst.ExpiresDate = src.ExpiresDate
+	case "requirements":
+		dst.Requirements = src.Requirements
+	case "benefits":
+		dst.Benefits = src.Benefits
+	}
+}
+
+func provenanceOf(job *models.JobPost) models.Provenance {
+	return models.Provenance{
+		JobID:     job.ID,
+		Source:    fieldString(job, "source"),
+		Connector: fieldString(job, "connector"),
+	}
+}
+
+// Merger keeps the stored golden records of duplicate clusters up to date
+type Merger struct {
+	store *storage.JobStore
+	trust Trust
+}
+
+// NewMerger creates a merger that ranks sources by trust
+func NewMerger(store *storage.JobStore, trust Trust) *Merger {
+	return &Merger{store: store, trust: trust}
+}
+
+// The checkpoint where Refresh keeps the time of its last run
+const (
+	refreshConnectorID = "dedup"
+	refreshQueryKey    = "golden_records"
+)
+
+// Refresh rebuilds the given clusters and every cluster with a job updated since the
+// last Refresh (a job closed or changed by a sync), then commits the time it looked.
+// The first Refresh rebuilds every cluster.
+func (m *Merger) Refresh(clusterIDs []string) (int, error) {
+	checkpoint, err := m.store.GetCheckpoint(refreshConnectorID, refreshQueryKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read golden record checkpoint: %w", err)
+	}
+
+	now := time.Now()
+	changed, err := m.store.GetClustersUpdatedSince(checkpoint.HighWatermark)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read updated clusters: %w", err)
+	}
+	seen := map[string]bool{}
+	clusters := []string{}
+	for _, clusterID := range append(append([]string{}, clusterIDs...), changed...) {
+		if !seen[clusterID] {
+			seen[clusterID] = true
+			clusters = append(clusters, clusterID)
+		}
+	}
+
+	stored, err := m.Rebuild(clusters)
+	if err != nil {
+		return stored, err
+	}
+	checkpoint.HighWatermark = now
+	if err := m.store.SaveCheckpoint(checkpoint); err != nil {
+		return stored, fmt.Errorf("failed to commit golden record checkpoint: %w", err)
+	}
+	return stored, nil
+}
+
+// Rebuild recomposes and stores the golden records of the given clusters and returns
+// how many it stored. A cluster of one job needs none: the job is its own record.
+func (m *Merger) Rebuild(clusterIDs []string) (int, error) {
+	stored := 0
+	for _, clusterID := range clusterIDs {
+		jobs, err := m.store.GetClusterJobs(clusterID)
+		if err != nil {
+			return stored, fmt.Errorf("failed to read cluster %s: %w", clusterID, err)
+		}
+		if len(jobs) < 2 {
+			continue
+		}
+		if err := m.store.SaveGoldenRecord(Merge(clusterID, jobs, m.trust)); err != nil {
+			return stored, fmt.Errorf("failed to save golden record %s: %w", clusterID, err)
+		}
+		stored++
+	}
+	return stored, nil
+}
@@ -0,0 +1,93 @@
+package dedup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Trust ranks connectors per field when a golden record is composed: the source with
+// the highest score for a field provides it. Fields are JobPost JSON names; "salary"
+// stands for the salary text, range and currency together.
+type Trust struct {
+	Default    int                       `json:"default"`    // Score of connectors and fields not listed
+	Connectors map[string]map[string]int `json:"connectors"` // connector ID -> field (or "default") -> score
+}
+
+// DefaultTrust prefers the employer's own ATS and career pages for the text and apply
+// URL, the public employment services for structured data such as salary and
+// deadlines, and aggregators (which shorten descriptions and guess currencies) last
+var DefaultTrust = Trust{
+	Default: 50,
+	Connectors: map[string]map[string]int{
+		"greenhouse":         {"default": 80, "description": 100, "url": 100},
+		"lever":              {"default": 80, "description": 100, "url": 100},
+		"workable":           {"default": 80, "description": 100, "url": 100},
+		"jsonld":             {"default": 70, "description": 90, "url": 90},
+		"arbetsformedlingen": {"default": 70, "salary": 90, "location": 90, "employment_type": 90, "expires_date": 90},
+		"offentligajobb":     {"default": 70, "salary": 80, "expires_date": 90},
+		"eures":              {"default": 60},
+		"reed":               {"default": 55, "salary": 70},
+		"adzuna":             {"default": 40},
+		"indeed":             {"default": 40, "description": 30},
+		"jooble":             {"default": 30, "description": 10, "salary": 10, "experience_level": 0},
+	},
+}
+
+// Score returns the trust of a connector for one field
+func (t Trust) Score(connector, field string) int {
+	if scores, ok := t.Connectors[connector]; ok {
+		if score, ok := scores[field]; ok {
+			return score
+		}
+		if score, ok := scores["default"]; ok {
+			return score
+		}
+	}
+	return t.Default
+}
+
+// LoadTrust reads a trust ranking from a .json, .yaml or .yml file. It is laid over
+// DefaultTrust: connectors and fields the file does not list keep their default score.
+func LoadTrust(path string) (Trust, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Trust{}, fmt.Errorf("failed to read trust config %s: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); err != nil {
+			return Trust{}, fmt.Errorf("failed to parse YAML trust config %s: %w", path, err)
+		}
+		if data, err = json.Marshal(generic); err != nil {
+			return Trust{}, fmt.Errorf("failed to convert YAML trust config %s: %w", path, err)
+		}
+	}
+
+	var override Trust
+	if err := json.Unmarshal(data, &override); err != nil {
+		return Trust{}, fmt.Errorf("failed to parse trust config %s: %w", path, err)
+	}
+
+	trust := Trust{Default: DefaultTrust.Default, Connectors: map[string]map[string]int{}}
+	if override.Default != 0 {
+		trust.Default = override.Default
+	}
+	for _, layer := range []Trust{DefaultTrust, override} {
+		for connector, scores := range layer.Connectors {
+			if trust.Connectors[connector] == nil {
+				trust.Connectors[connector] = map[string]int{}
+			}
+			for field, score := range scores {
+				trust.Connectors[connector][field] = score
+			}
+		}
+	}
+	return trust, nil
+}
@@ -0,0 +1,562 @@
+// Package geo resolves the free-text locations of job ads ("Stockholm, Stockholms län,
+// Sverige", "Berlin, Germany (Remote)", "USA Only") into a structured location:
+// country, region, city, coordinates and, for remote jobs, where they may be done
+// from. It uses an offline gazetteer built from a GeoNames dump by
+// cmd/build-gazetteer (GAZETTEER_FILE), with Swedish and English place names.
+// Without one, a built-in excerpt covering Swedish towns and major cities is used.
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"openjobs/pkg/models"
+)
+
+// Remote scopes besides the continents ("europe", "north_america", ...)
+const (
+	RemoteWorldwide = "worldwide" // From anywhere, or no restriction given
+	RemoteCountry   = "country"   // Within Location.Country
+	RemoteCountries = "countries" // Within several countries on different continents
+)
+
+// continents maps GeoNames continent codes to remote scopes
+var continents = map[string]string{
+	"EU": "europe",
+	"NA": "north_america",
+	"SA": "south_america",
+	"AS": "asia",
+	"AF": "africa",
+	"OC": "oceania",
+	"AN": "antarctica",
+}
+
+// Location is a structured job location
+type Location struct {
+	Country     string // ISO 3166-1 alpha-2
+	Region      string
+	City        string
+	Lat, Lon    *float64
+	RemoteScope string // "" for on-site jobs; RemoteWorldwide, a continent, RemoteCountry or RemoteCountries
+}
+
+// Place is a city of the gazetteer
+type Place struct {
+	Name       string
+	Country    string
+	Region     string
+	Lat, Lon   float64
+	Population int
+	regionKey  string
+}
+
+type country struct {
+	code, continent, name string
+}
+
+type region struct {
+	key, country, name string
+}
+
+// Gazetteer looks up countries, regions and cities by their names in any case and
+// with or without diacritics
+type Gazetteer struct {
+	countries    map[string]*country
+	countryNames map[string]*country
+	regions      map[string]*region
+	regionNames  map[string][]*region
+	places       map[string][]*Place // Most populous first
+	all          []*Place
+}
+
+//go:embed gazetteer.tsv
+var builtinGazetteer []byte
+
+// Default is the built-in gazetteer
+var Default = mustParse(builtinGazetteer)
+
+func mustParse(data []byte) *Gazetteer {
+	g, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in gazetteer: %v", err))
+	}
+	return g
+}
+
+// FromEnv loads the gazetteer named by GAZETTEER_FILE, or returns Default when it is
+// unset or can't be read
+func FromEnv() *Gazetteer {
+	path := os.Getenv("GAZETTEER_FILE")
+	if path == "" {
+		return Default
+	}
+	g, err := Load(path)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to load gazetteer from %s, using built-in gazetteer: %v\n", path, err)
+		return Default
+	}
+	return g
+}
+
+// Load reads a gazetteer file written by cmd/build-gazetteer, gzipped if it ends in .gz
+func Load(path string) (*Gazetteer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	g, err := Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return g, nil
+}
+
+// Parse reads the gazetteer format: one tab-separated record per line, see
+// gazetteer.tsv
+func Parse(r io.Reader) (*Gazetteer, error) {
+	g := &Gazetteer{
+		countries:    map[string]*country{},
+		countryNames: map[string]*country{},
+		regions:      map[string]*region{},
+		regionNames:  map[string][]*region{},
+		places:       map[string][]*Place{},
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		cols := strings.Split(text, "\t")
+		if err := g.add(cols); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, places := range g.places {
+		sort.SliceStable(places, func(i, j int) bool { return places[i].Population > places[j].Population })
+	}
+	return g, nil
+}
+
+func (g *Gazetteer) add(cols []string) error {
+	field := func(i int) string {
+		if i < len(cols) {
+			return strings.TrimSpace(cols[i])
+		}
+		return ""
+	}
+	names := func(i int) []string {
+		list := []string{field(i - 1)}
+		for _, alt := range strings.Split(field(i), "|") {
+			if alt != "" {
+				list = append(list, alt)
+			}
+		}
+		return list
+	}
+
+	switch field(0) {
+	case "country":
+		c := &country{code: field(1), continent: field(2), name: field(3)}
+		if len(c.code) != 2 || c.name == "" {
+			return fmt.Errorf("invalid country %q", strings.Join(cols, " "))
+		}
+		g.countries[c.code] = c
+		for _, name := range names(4) {
+			g.countryNames[fold(name)] = c
+		}
+	case "region":
+		r := &region{key: field(1), name: field(2)}
+		r.country, _, _ = strings.Cut(r.key, ".")
+		if r.name == "" {
+			return fmt.Errorf("invalid region %q", strings.Join(cols, " "))
+		}
+		g.regions[r.key] = r
+		for _, name := range names(3) {
+			key := fold(name)
+			g.regionNames[key] = append(g.regionNames[key], r)
+		}
+	case "city":
+		lat, err1 := strconv.ParseFloat(field(3), 64)
+		lon, err2 := strconv.ParseFloat(field(4), 64)
+		if err1 != nil || err2 != nil || field(2) == "" {
+			return fmt.Errorf("invalid city %q", strings.Join(cols, " "))
+		}
+		population, _ := strconv.Atoi(field(5))
+		p := &Place{Name: field(2), Lat: lat, Lon: lon, Population: population, regionKey: field(1)}
+		p.Country, _, _ = strings.Cut(p.regionKey, ".")
+		if r, ok := g.regions[p.regionKey]; ok {
+			p.Region = r.name
+		}
+		g.all = append(g.all, p)
+		seen := map[string]bool{}
+		for _, name := range append(names(6), field(2)) {
+			key := fold(name)
+			if !seen[key] {
+				seen[key] = true
+				g.places[key] = append(g.places[key], p)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown record %q", field(0))
+	}
+	return nil
+}
+
+var (
+	// What separates the parts of a location text
+	separatorRe = regexp.MustCompile(`[,;/|()\[\]\n]|\s+-\s+|\s+(?:or|eller|and|och|&)\s+`)
+	remoteRe    = regexp.MustCompile(`\b(?:remote|distans|distansarbete|hemifran|hemarbete|wfh|work from home|telework|anywhere|worldwide)\b`)
+	worldwideRe = regexp.MustCompile(`\b(?:worldwide|anywhere|global|globally|hela varlden|var som helst|world)\b`)
+
+	// Words that qualify a location without being part of its name
+	noiseWords = map[string]bool{
+		"only": true, "remote": true, "remotely": true, "fully": true, "distans": true, "distansarbete": true,
+		"hybrid": true, "onsite": true, "on-site": true, "office": true, "based": true, "in": true,
+		"within": true, "from": true, "hq": true, "first": true, "remote-first": true, "friendly": true,
+		"endast": true, "hemifran": true, "i": true, "inom": true, "eller": true, "wfh": true,
+		"the": true, "timezone": true, "timezones": true, "time": true, "zone": true,
+	}
+
+	continentWords = map[string]string{
+		"europe": "europe", "europa": "europe", "eu": "europe", "european union": "europe", "eea": "europe",
+		"nordics": "europe", "norden": "europe", "scandinavia": "europe", "skandinavien": "europe",
+		"north america": "north_america", "nordamerika": "north_america",
+		"latam": "south_america", "latin america": "south_america", "south america": "south_america", "sydamerika": "south_america",
+		"asia": "asia", "asien": "asia", "apac": "asia",
+		"africa": "africa", "afrika": "africa",
+		"oceania": "oceania", "oceanien": "oceania",
+	}
+
+	// Suffixes and prefixes of administrative names: "Stockholms län", "Region Skåne"
+	adminSuffixes = []string{" lan", " county", " fylke", " state", " region", " kommun", " municipality"}
+	adminPrefixes = []string{"region ", "state of "}
+)
+
+// Resolve reads a location text. remote marks remote jobs whose text doesn't say so.
+// The most populous matching city wins, within the country and region the text names
+// when it names them.
+func (g *Gazetteer) Resolve(text string, remote bool) Location {
+	lower := fold(text)
+	loc := Location{}
+	remote = remote || remoteRe.MatchString(lower)
+	worldwide := worldwideRe.MatchString(lower)
+
+	var countries []*country
+	var candidates []string
+	continent := ""
+	for _, part := range separatorRe.Split(lower, -1) {
+		part = stripNoise(part)
+		if part == "" {
+			continue
+		}
+		if c, ok := continentWords[part]; ok {
+			continent = c
+			continue
+		}
+		if c := g.lookupCountry(part); c != nil {
+			if !containsCountry(countries, c) {
+				countries = append(countries, c)
+			}
+			continue
+		}
+		candidates = append(candidates, part)
+	}
+
+	inCountries := func(code string) bool {
+		return len(countries) == 0 || containsCountry(countries, g.countries[code])
+	}
+
+	// Regions the text names, to pick the right city among namesakes
+	var regions []*region
+	for _, part := range candidates {
+		for _, r := range g.lookupRegions(part) {
+			if inCountries(r.country) {
+				regions = append(regions, r)
+			}
+		}
+	}
+
+	var city *Place
+	for _, part := range candidates {
+		var best *Place
+		for _, p := range g.lookupPlaces(part) {
+			if !inCountries(p.Country) {
+				continue
+			}
+			if len(regions) > 0 && !containsRegion(regions, p.regionKey) {
+				if best == nil {
+					best = p // Outside the named region, unless a better one comes
+				}
+				continue
+			}
+			best = p
+			break
+		}
+		if best != nil {
+			city = best
+			break
+		}
+	}
+
+	switch {
+	case city != nil:
+		lat, lon := city.Lat, city.Lon
+		loc.Country, loc.Region, loc.City, loc.Lat, loc.Lon = city.Country, city.Region, city.Name, &lat, &lon
+	case len(regions) > 0:
+		loc.Country, loc.Region = regions[0].country, regions[0].name
+	case len(countries) == 1:
+		loc.Country = countries[0].code
+	}
+
+	if remote {
+		switch {
+		case worldwide:
+			loc.RemoteScope = RemoteWorldwide
+		case len(countries) > 1:
+			loc.Country, loc.Region, loc.City, loc.Lat, loc.Lon = "", "", "", nil, nil
+			loc.RemoteScope = RemoteCountries
+			if c := sharedContinent(countries); c != "" {
+				loc.RemoteScope = c
+			}
+		case loc.Country != "":
+			loc.RemoteScope = RemoteCountry
+		case continent != "":
+			loc.RemoteScope = continent
+		default:
+			loc.RemoteScope = RemoteWorldwide
+		}
+	}
+	return loc
+}
+
+// CountryCode returns the ISO code of a country given by code or name ("se",
+// "Sverige", "Sweden"), or "" when the gazetteer doesn't know it
+func (g *Gazetteer) CountryCode(name string) string {
+	if c, ok := g.countries[strings.ToUpper(strings.TrimSpace(name))]; ok {
+		return c.code
+	}
+	if c := g.lookupCountry(fold(name)); c != nil {
+		return c.code
+	}
+	return ""
+}
+
+// Nearest returns the city closest to a point within maxKm, or nil
+func (g *Gazetteer) Nearest(lat, lon, maxKm float64) *Place {
+	var nearest *Place
+	best := maxKm
+	for _, p := range g.all {
+		if d := Distance(lat, lon, p.Lat, p.Lon); d <= best {
+			nearest, best = p, d
+		}
+	}
+	return nearest
+}
+
+// Normalize sets the job's structured location from its Location text, IsRemote and
+// fields.coordinates ([lon, lat], as AF, Adzuna and Indeed give them), which are more
+// precise than the city's and place jobs whose text the gazetteer doesn't know
+func (g *Gazetteer) Normalize(job *models.JobPost) {
+	loc := g.Resolve(job.Location, job.IsRemote)
+	if lat, lon, ok := coordinates(job.Fields); ok {
+		loc.Lat, loc.Lon = &lat, &lon
+		if loc.Country == "" && loc.RemoteScope != RemoteCountries {
+			if p := g.Nearest(lat, lon, 50); p != nil {
+				loc.Country, loc.Region, loc.City = p.Country, p.Region, p.Name
+				if loc.RemoteScope != "" {
+					loc.RemoteScope = RemoteCountry
+				}
+			}
+		}
+	}
+	loc.Apply(job)
+}
+
+// Apply stores the location in the job's structured location fields, replacing what
+// was there, with the geohash of its coordinates for radius search
+func (l Location) Apply(job *models.JobPost) {
+	job.LocationCountry, job.LocationRegion, job.LocationCity = l.Country, l.Region, l.City
+	job.Latitude, job.Longitude, job.Geohash = l.Lat, l.Lon, ""
+	if l.Lat != nil && l.Lon != nil {
+		job.Geohash = Geohash(*l.Lat, *l.Lon, GeohashPrecision)
+	}
+	job.RemoteScope = l.RemoteScope
+}
+
+// Distance returns the great-circle distance between two points in kilometers
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371.0
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func (g *Gazetteer) lookupCountry(name string) *country {
+	return g.countryNames[name]
+}
+
+func (g *Gazetteer) lookupRegions(name string) []*region {
+	for _, key := range adminVariants(name) {
+		if regions, ok := g.regionNames[key]; ok {
+			return regions
+		}
+	}
+	return nil
+}
+
+func (g *Gazetteer) lookupPlaces(name string) []*Place {
+	if places, ok := g.places[name]; ok {
+		return places
+	}
+	// "Stockholm City", "Göteborgs stad"; not the county suffixes, or "Uppsala län"
+	// would be the city
+	for _, affix := range []string{" city", " stad", "city of "} {
+		stripped := strings.TrimSuffix(strings.TrimPrefix(name, affix), affix)
+		if stripped == name {
+			continue
+		}
+		for _, key := range []string{stripped, strings.TrimSuffix(stripped, "s")} {
+			if places, ok := g.places[key]; ok {
+				return places
+			}
+		}
+	}
+	return nil
+}
+
+// adminVariants returns name and name without administrative affixes, and without the
+// Swedish genitive s that comes with them ("stockholms lan" -> "stockholm")
+func adminVariants(name string) []string {
+	variants := []string{name}
+	stripped := name
+	for _, prefix := range adminPrefixes {
+		stripped = strings.TrimPrefix(stripped, prefix)
+	}
+	for _, suffix := range adminSuffixes {
+		if strings.HasSuffix(stripped, suffix) {
+			stripped = strings.TrimSuffix(stripped, suffix)
+			if strings.HasSuffix(stripped, "s") {
+				variants = append(variants, stripped, strings.TrimSuffix(stripped, "s"))
+			}
+			break
+		}
+	}
+	if stripped != name {
+		variants = append(variants, stripped)
+	}
+	return variants
+}
+
+func stripNoise(part string) string {
+	words := strings.Fields(part)
+	kept := words[:0]
+	for _, word := range words {
+		if !noiseWords[word] {
+			kept = append(kept, word)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// fold lowercases text and strips diacritics and dots, so "Göteborg" matches
+// "Goteborg" and "U.S.A." matches "USA"
+func fold(text string) string {
+	text = foldReplacer.Replace(strings.ToLower(text))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var foldReplacer = strings.NewReplacer(
+	"å", "a", "ä", "a", "á", "a", "à", "a", "â", "a", "ã", "a",
+	"ö", "o", "ø", "o", "ó", "o", "ò", "o", "ô", "o", "õ", "o",
+	"é", "e", "è", "e", "ê", "e", "ë", "e", "ě", "e",
+	"ü", "u", "ú", "u", "ù", "u", "û", "u",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"æ", "ae", "ß", "ss", "ñ", "n", "ç", "c", "ł", "l", "ś", "s", "š", "s",
+	"ż", "z", "ź", "z", "ž", "z", "ć", "c", "č", "c", "ń", "n", "ř", "r", "ð", "d", "þ", "th",
+	".", "", " ", " ",
+)
+
+// coordinates reads fields.coordinates, [lon, lat] as a []float64 when set and a
+// []interface{} once stored
+func coordinates(fields map[string]interface{}) (lat, lon float64, ok bool) {
+	var values []float64
+	switch v := fields["coordinates"].(type) {
+	case []float64:
+		values = v
+	case []interface{}:
+		for _, item := range v {
+			f, isFloat := item.(float64)
+			if !isFloat {
+				return 0, 0, false
+			}
+			values = append(values, f)
+		}
+	}
+	if len(values) != 2 || (values[0] == 0 && values[1] == 0) ||
+		math.Abs(values[1]) > 90 || math.Abs(values[0]) > 180 {
+		return 0, 0, false
+	}
+	return values[1], values[0], true
+}
+
+func containsCountry(countries []*country, c *country) bool {
+	for _, other := range countries {
+		if other == c {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRegion(regions []*region, key string) bool {
+	for _, r := range regions {
+		if r.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// sharedContinent returns the remote scope of the continent all countries are on, or ""
+func sharedContinent(countries []*country) string {
+	continent := countries[0].continent
+	for _, c := range countries[1:] {
+		if c.continent != continent {
+			return ""
+		}
+	}
+	return continents[continent]
+}
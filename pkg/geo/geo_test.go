@@ -0,0 +1,140 @@
+package geo
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"openjobs/pkg/models"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		text                  string
+		remote                bool
+		country, region, city string
+		scope                 string
+	}{
+		{"Stockholm, Stockholms län, Sverige", false, "SE", "Stockholm", "Stockholm", ""},
+		{"Göteborg, Västra Götalands län, Sverige", false, "SE", "Västra Götaland", "Gothenburg", ""},
+		{"Malmo, Sweden", false, "SE", "Skåne", "Malmö", ""},
+		{"Uppsala län", false, "SE", "Uppsala", "", ""},
+		{"Skåne", false, "SE", "Skåne", "", ""},
+		{"Hybrid - Kista", false, "SE", "Stockholm", "Kista", ""},
+		{"Sverige", false, "SE", "", "", ""},
+		{"San Francisco, CA", false, "US", "California", "San Francisco", ""},
+		{"Berlin, Germany (Remote)", false, "DE", "", "Berlin", RemoteCountry},
+		{"Worldwide", true, "", "", "", RemoteWorldwide},
+		{"Remote", false, "", "", "", RemoteWorldwide},
+		{"USA Only", true, "US", "", "", RemoteCountry},
+		{"Europe", true, "", "", "", "europe"},
+		{"UK, Germany", true, "", "", "", "europe"},
+		{"USA, Germany", true, "", "", "", RemoteCountries},
+		{"Distans inom Sverige", false, "SE", "", "", RemoteCountry},
+		{"Köpenhamn", false, "DK", "", "Copenhagen", ""},
+		{"Atlantis", false, "", "", "", ""},
+		{"", false, "", "", "", ""},
+	}
+	for _, tt := range tests {
+		loc := Default.Resolve(tt.text, tt.remote)
+		if loc.Country != tt.country || loc.Region != tt.region || loc.City != tt.city || loc.RemoteScope != tt.scope {
+			t.Errorf("Resolve(%q) = %q %q %q %q, want %q %q %q %q", tt.text, loc.Country, loc.Region, loc.City, loc.RemoteScope,
+				tt.country, tt.region, tt.city, tt.scope)
+		}
+		if (loc.City != "") != (loc.Lat != nil) {
+			t.Errorf("Resolve(%q): expected coordinates exactly for cities, got %v", tt.text, loc.Lat)
+		}
+	}
+}
+
+func TestCountryCode(t *testing.T) {
+	for name, want := range map[string]string{"se": "SE", "Sverige": "SE", "sweden": "SE", "U.S.A.": "US", "Narnia": ""} {
+		if got := Default.CountryCode(name); got != want {
+			t.Errorf("CountryCode(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	job := &models.JobPost{Location: "Lund, Skåne län, Sverige", Fields: map[string]interface{}{
+		"coordinates": []interface{}{13.2, 55.71},
+	}}
+	Default.Normalize(job)
+	if job.LocationCountry != "SE" || job.LocationRegion != "Skåne" || job.LocationCity != "Lund" || *job.Latitude != 55.71 || *job.Longitude != 13.2 {
+		t.Errorf("Unexpected location: %q %q %q %v %v", job.LocationCountry, job.LocationRegion, job.LocationCity, *job.Latitude, *job.Longitude)
+	}
+
+	// Coordinates place a job whose text the gazetteer doesn't know
+	job = &models.JobPost{Location: "Lindholmen", Fields: map[string]interface{}{"coordinates": []float64{11.94, 57.71}}}
+	Default.Normalize(job)
+	if job.LocationCity != "Gothenburg" || job.LocationRegion != "Västra Götaland" {
+		t.Errorf("Expected the nearest city, got %q %q", job.LocationCity, job.LocationRegion)
+	}
+
+	job.Location, job.Fields = "Remote", nil
+	job.IsRemote = true
+	Default.Normalize(job)
+	if job.LocationCity != "" || job.Latitude != nil || job.RemoteScope != RemoteWorldwide {
+		t.Errorf("Expected the old location to be replaced: %+v", job)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gazetteer.tsv")
+	os.WriteFile(path, []byte("country\tSE\tEU\tSweden\tSverige\nregion\tSE.26\tStockholm\t\ncity\tSE.26\tSolna\t59.36\t18.0\t82000\t\n"), 0644)
+	g, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc := g.Resolve("Solna, Sverige", false); loc.City != "Solna" || loc.Region != "Stockholm" {
+		t.Errorf("Resolve() = %+v", loc)
+	}
+
+	os.WriteFile(path, []byte("city\tSE\tNowhere\tnorth\teast\t0\t\n"), 0644)
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for invalid coordinates")
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Stockholm - Gothenburg is about 400 km
+	if d := Distance(59.32938, 18.06871, 57.70716, 11.96679); math.Abs(d-398) > 5 {
+		t.Errorf("Distance() = %.1f", d)
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	if got := Geohash(57.64911, 10.40744, 11); got != "u4pruydqqvj" {
+		t.Errorf("Geohash() = %q, want u4pruydqqvj", got)
+	}
+
+	// Every point within the radius lies in one of the covering cells
+	uppsala := Point{Lat: 59.85882, Lon: 17.63889}
+	cells := CoveringCells(uppsala, 30, 16)
+	if len(cells) == 0 || len(cells) > 16 {
+		t.Fatalf("CoveringCells() returned %d cells", len(cells))
+	}
+	for _, p := range []Point{{59.85882, 17.63889}, {59.62, 17.72}, {60.1, 17.64}, {59.86, 17.1}, {59.86, 18.16}} {
+		hash := Geohash(p.Lat, p.Lon, GeohashPrecision)
+		covered := false
+		for _, cell := range cells {
+			covered = covered || strings.HasPrefix(hash, cell)
+		}
+		if !covered {
+			t.Errorf("%v (%.1f km away) is not in %v", p, Distance(uppsala.Lat, uppsala.Lon, p.Lat, p.Lon), cells)
+		}
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	if p, ok := ParsePoint("59.8586, 17.6389"); !ok || p.Lat != 59.8586 || p.Lon != 17.6389 {
+		t.Errorf("ParsePoint() = %v, %v", p, ok)
+	}
+	for _, text := range []string{"Uppsala", "59.8", "91,17", "59.8,x"} {
+		if _, ok := ParsePoint(text); ok {
+			t.Errorf("Expected ParsePoint(%q) to fail", text)
+		}
+	}
+}
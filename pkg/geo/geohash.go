@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeohashPrecision is the length of the geohashes stored with jobs, cells of about 5 m
+const GeohashPrecision = 9
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// Point is a position in degrees
+type Point struct {
+	Lat, Lon float64
+}
+
+// ParsePoint reads "lat,lon" ("59.8586,17.6389")
+func ParsePoint(text string) (Point, bool) {
+	latText, lonText, ok := strings.Cut(text, ",")
+	if !ok {
+		return Point{}, false
+	}
+	lat, err1 := strconv.ParseFloat(strings.TrimSpace(latText), 64)
+	lon, err2 := strconv.ParseFloat(strings.TrimSpace(lonText), 64)
+	if err1 != nil || err2 != nil || math.Abs(lat) > 90 || math.Abs(lon) > 180 {
+		return Point{}, false
+	}
+	return Point{Lat: lat, Lon: lon}, true
+}
+
+// Geohash encodes a point as a geohash of the given length. Points in the same cell
+// share a prefix, so a cell and everything in it can be found with a prefix query.
+func Geohash(lat, lon float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+	var hash strings.Builder
+	bit, ch, even := 0, 0, true
+	for hash.Len() < precision {
+		r, v := &latRange, lat
+		if even {
+			r, v = &lonRange, lon
+		}
+		mid := (r[0] + r[1]) / 2
+		ch <<= 1
+		if v >= mid {
+			ch |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bit++; bit == 5 {
+			hash.WriteByte(geohashAlphabet[ch])
+			bit, ch = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// CoveringCells returns the geohash cells that together cover the circle of radiusKm
+// around a point, at the finest precision that needs no more than maxCells of them
+func CoveringCells(center Point, radiusKm float64, maxCells int) []string {
+	const kmPerDegree = 111.32
+	latDelta := radiusKm / kmPerDegree
+	lonDelta := 180.0
+	if cos := math.Cos(center.Lat * math.Pi / 180); cos > 0.01 {
+		lonDelta = math.Min(180, radiusKm/(kmPerDegree*cos))
+	}
+	minLat, maxLat := math.Max(-90, center.Lat-latDelta), math.Min(90, center.Lat+latDelta)
+	minLon, maxLon := center.Lon-lonDelta, center.Lon+lonDelta
+
+	cells := []string{""}
+	for precision := 1; precision <= GeohashPrecision; precision++ {
+		bits := 5 * precision
+		cellLat := 180 / math.Pow(2, float64(bits/2))
+		cellLon := 360 / math.Pow(2, float64(bits-bits/2))
+		rows := math.Floor(maxLat/cellLat) - math.Floor(minLat/cellLat) + 1
+		cols := math.Floor(maxLon/cellLon) - math.Floor(minLon/cellLon) + 1
+		if rows*cols > float64(maxCells) {
+			break
+		}
+
+		seen := map[string]bool{}
+		var next []string
+		for lat := minLat; ; lat += cellLat {
+			lat = math.Min(lat, maxLat)
+			for lon := minLon; ; lon += cellLon {
+				lon = math.Min(lon, maxLon)
+				if hash := Geohash(lat, wrapLon(lon), precision); !seen[hash] {
+					seen[hash] = true
+					next = append(next, hash)
+				}
+				if lon >= maxLon {
+					break
+				}
+			}
+			if lat >= maxLat {
+				break
+			}
+		}
+		cells = next
+	}
+	return cells
+}
+
+func wrapLon(lon float64) float64 {
+	for lon < -180 {
+		lon += 360
+	}
+	for lon >= 180 {
+		lon -= 360
+	}
+	return lon
+}
@@ -0,0 +1,164 @@
+// Package httpclient is the shared outbound HTTP layer for connectors. Every
+// request goes through a per-host token bucket, backs off on 429 and 503 using
+// Retry-After, and can be checked against the target's robots.txt.
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDisallowed is returned for URLs the site's robots.txt does not allow us to fetch
+var ErrDisallowed = errors.New("disallowed by robots.txt")
+
+const defaultContact = "https://github.com/magnusfroste/openjobs"
+
+// Options configures one connector's client. Rate limits are shared per host by all
+// clients in the process, and the strictest interval asked for a host wins.
+type Options struct {
+	UserAgent     string        // Set on requests that have none; see UserAgent()
+	Timeout       time.Duration // Whole request timeout, default 30s
+	Interval      time.Duration // Minimum gap between requests to one host; 0 means unlimited
+	Burst         int           // Requests allowed back to back before Interval applies, default 1
+	RespectRobots bool          // Refuse URLs disallowed by robots.txt (scrapers)
+	MaxRetries    int           // Retries on 429/503, default HTTP_MAX_RETRIES or 3; negative disables
+	MaxRetryWait  time.Duration // Longer Retry-After values are not waited for, default 2m
+}
+
+// UserAgent returns the identifying User-Agent for a connector: HTTP_USER_AGENT when
+// set, otherwise product followed by HTTP_USER_AGENT_CONTACT (the project URL by default)
+func UserAgent(product string) string {
+	if ua := os.Getenv("HTTP_USER_AGENT"); ua != "" {
+		return ua
+	}
+	contact := os.Getenv("HTTP_USER_AGENT_CONTACT")
+	if contact == "" {
+		contact = defaultContact
+	}
+	return fmt.Sprintf("%s (+%s)", product, contact)
+}
+
+// New returns an *http.Client whose transport applies the polite crawling rules
+func New(opts Options) *http.Client {
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	return &http.Client{Timeout: timeout, Transport: NewTransport(opts)}
+}
+
+// NewTransport returns the polite round tripper on its own, for colly collectors
+// and other clients that manage their own *http.Client
+func NewTransport(opts Options) http.RoundTripper {
+	if opts.Burst <= 0 {
+		opts.Burst = 1
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+		if n, err := strconv.Atoi(os.Getenv("HTTP_MAX_RETRIES")); err == nil {
+			opts.MaxRetries = n
+		}
+	}
+	if opts.MaxRetryWait == 0 {
+		opts.MaxRetryWait = 2 * time.Minute
+	}
+	if opts.RespectRobots && os.Getenv("HTTP_RESPECT_ROBOTS") == "false" {
+		opts.RespectRobots = false
+	}
+	return &transport{opts: opts, base: http.DefaultTransport}
+}
+
+type transport struct {
+	opts Options
+	base http.RoundTripper
+}
+
+// RoundTrip waits for the host's rate limit, sends the request and retries it while
+// the host answers 429 or 503. The final response is returned as is, so callers keep
+// their own status handling.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("User-Agent") == "" && t.opts.UserAgent != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.opts.UserAgent)
+	}
+
+	host := req.URL.Host
+	limiter := limiterFor(host, t.opts.Interval, t.opts.Burst)
+
+	if t.opts.RespectRobots && req.URL.Path != "/robots.txt" {
+		if !t.robotsAllowed(req) {
+			limiter.record(func(s *HostStats) { s.RobotsBlocked++ })
+			return nil, fmt.Errorf("%w: %s", ErrDisallowed, req.URL)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		limiter.record(func(s *HostStats) { s.Requests++ })
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+
+		limiter.record(func(s *HostStats) {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				s.RateLimited++
+			} else {
+				s.Unavailable++
+			}
+		})
+
+		// Every client of this host backs off, not just this request
+		wait := retryAfter(resp.Header.Get("Retry-After"), attempt)
+		limiter.pause(min(wait, t.opts.MaxRetryWait))
+
+		if attempt >= t.opts.MaxRetries || wait > t.opts.MaxRetryWait {
+			return resp, nil
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+
+		limiter.record(func(s *HostStats) { s.Retries++ })
+		fmt.Printf("⏳ %s returned %d, retrying in %v\n", host, resp.StatusCode, wait)
+	}
+}
+
+// retryAfter parses a Retry-After header (seconds or HTTP date), falling back to
+// exponential backoff starting at one second
+func retryAfter(header string, attempt int) time.Duration {
+	header = strings.TrimSpace(header)
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+		return 0
+	}
+	return time.Second << min(attempt, 6)
+}
@@ -0,0 +1,162 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func hostOf(t *testing.T, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
+
+func TestIntervalPerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := New(Options{Interval: 50 * time.Millisecond})
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	// The first request uses the burst token, the other three wait one interval each
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("Expected requests to be spaced out, took %v", elapsed)
+	}
+
+	stats := Snapshot()[hostOf(t, server.URL)]
+	if stats.Requests != 4 || stats.Throttled != 3 || stats.IntervalMs != 50 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("Expected body to be replayed, got %q", body)
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	client := New(Options{UserAgent: "OpenJobs-Test/1.0"})
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls != 2 {
+		t.Errorf("Expected a retry after 429, got status %d after %d calls", resp.StatusCode, calls)
+	}
+	stats := Snapshot()[hostOf(t, server.URL)]
+	if stats.RateLimited != 1 || stats.Retries != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestGivesUpOnLongRetryAfter(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(Options{MaxRetryWait: 10 * time.Millisecond})
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	// The caller sees the 503 instead of waiting an hour
+	if resp.StatusCode != http.StatusServiceUnavailable || calls != 1 {
+		t.Errorf("Expected one 503 without retry, got %d after %d calls", resp.StatusCode, calls)
+	}
+	if stats := Snapshot()[hostOf(t, server.URL)]; stats.Unavailable != 1 || stats.PausedUntil == nil {
+		t.Errorf("Expected the host to be paused, got %+v", stats)
+	}
+}
+
+func TestRobotsTxt(t *testing.T) {
+	var fetched int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			atomic.AddInt32(&fetched, 1)
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private\n\nUser-agent: BadBot\nDisallow: /\n")
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	client := New(Options{UserAgent: "OpenJobs-Test/1.0", RespectRobots: true})
+
+	resp, err := client.Get(server.URL + "/jobs")
+	if err != nil {
+		t.Fatalf("Expected /jobs to be allowed: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := client.Get(server.URL + "/private/list"); !errors.Is(err, ErrDisallowed) {
+		t.Errorf("Expected ErrDisallowed, got %v", err)
+	}
+	if fetched != 1 {
+		t.Errorf("Expected robots.txt to be fetched once, got %d", fetched)
+	}
+
+	if Allowed(context.Background(), server.URL+"/jobs", "BadBot/2.0") {
+		t.Error("Expected BadBot to be disallowed")
+	}
+	if stats := Snapshot()[hostOf(t, server.URL)]; stats.RobotsBlocked != 2 {
+		t.Errorf("Expected 2 robots blocks, got %+v", stats)
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	t.Setenv("HTTP_USER_AGENT_CONTACT", "jobs@example.com")
+	if ua := UserAgent("OpenJobs-Test/1.0"); ua != "OpenJobs-Test/1.0 (+jobs@example.com)" {
+		t.Errorf("Unexpected user agent: %s", ua)
+	}
+	t.Setenv("HTTP_USER_AGENT", "Custom/2.0")
+	if ua := UserAgent("OpenJobs-Test/1.0"); ua != "Custom/2.0" {
+		t.Errorf("Expected HTTP_USER_AGENT override, got %s", ua)
+	}
+}
+
+func TestRetryAfterParsing(t *testing.T) {
+	if d := retryAfter("7", 0); d != 7*time.Second {
+		t.Errorf("Expected 7s, got %v", d)
+	}
+	if d := retryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 0); d < 58*time.Second || d > time.Minute {
+		t.Errorf("Expected about a minute, got %v", d)
+	}
+	if d := retryAfter("", 2); d != 4*time.Second {
+		t.Errorf("Expected exponential fallback of 4s, got %v", d)
+	}
+}
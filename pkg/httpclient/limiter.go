@@ -0,0 +1,178 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HostStats are the counters exposed for one host
+type HostStats struct {
+	IntervalMs       int64      `json:"interval_ms"`
+	Requests         int64      `json:"requests"`
+	Throttled        int64      `json:"throttled"`
+	ThrottledSeconds float64    `json:"throttled_seconds"`
+	RateLimited      int64      `json:"rate_limited"` // 429 responses
+	Unavailable      int64      `json:"unavailable"`  // 503 responses
+	Retries          int64      `json:"retries"`
+	RobotsBlocked    int64      `json:"robots_blocked"`
+	PausedUntil      *time.Time `json:"paused_until,omitempty"`
+}
+
+// hostLimiter is a token bucket for one host plus a pause set by Retry-After
+type hostLimiter struct {
+	mu          sync.Mutex
+	interval    time.Duration
+	burst       int
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	fixed       bool // Interval comes from HTTP_HOST_RATE_LIMITS and is not tightened by clients
+	stats       HostStats
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*hostLimiter{}
+
+	overridesOnce sync.Once
+	overrides     map[string]time.Duration
+)
+
+// hostOverrides parses HTTP_HOST_RATE_LIMITS, e.g. "api.adzuna.com=2s,se.indeed.com=5s"
+func hostOverrides() map[string]time.Duration {
+	overridesOnce.Do(func() {
+		overrides = map[string]time.Duration{}
+		for _, entry := range strings.Split(os.Getenv("HTTP_HOST_RATE_LIMITS"), ",") {
+			host, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok {
+				continue
+			}
+			interval, err := time.ParseDuration(strings.TrimSpace(value))
+			if err != nil {
+				fmt.Printf("⚠️  Ignoring HTTP_HOST_RATE_LIMITS entry %q: %v\n", entry, err)
+				continue
+			}
+			overrides[strings.ToLower(strings.TrimSpace(host))] = interval
+		}
+	})
+	return overrides
+}
+
+// limiterFor returns the shared limiter of a host, tightening its interval if this
+// client asks for a slower rate than earlier ones
+func limiterFor(host string, interval time.Duration, burst int) *hostLimiter {
+	host = strings.ToLower(host)
+
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	l, ok := limiters[host]
+	if !ok {
+		l = &hostLimiter{interval: interval, burst: burst, tokens: float64(burst), last: time.Now()}
+		if override, ok := hostOverrides()[host]; ok {
+			l.interval, l.fixed = override, true
+		}
+		l.stats.IntervalMs = l.interval.Milliseconds()
+		limiters[host] = l
+		return l
+	}
+
+	l.mu.Lock()
+	if !l.fixed && interval > l.interval {
+		l.interval = interval
+		l.stats.IntervalMs = interval.Milliseconds()
+	}
+	l.mu.Unlock()
+	return l
+}
+
+// wait blocks until the bucket has a token and any Retry-After pause has passed
+func (l *hostLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	var delay time.Duration
+	if l.interval > 0 {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+		l.last = now
+		l.tokens-- // Reserve our token; a negative balance is the queue ahead of us
+		if l.tokens < 0 {
+			delay = time.Duration(-l.tokens * float64(l.interval))
+		}
+	}
+	if pause := l.pausedUntil.Sub(now); pause > delay {
+		delay = pause
+	}
+	if delay > 0 {
+		l.stats.Throttled++
+		l.stats.ThrottledSeconds += delay.Seconds()
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause holds every request to the host for d
+func (l *hostLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+func (l *hostLimiter) record(update func(*HostStats)) {
+	l.mu.Lock()
+	update(&l.stats)
+	l.mu.Unlock()
+}
+
+// Snapshot returns the current counters per host, for health and metrics endpoints
+func Snapshot() map[string]HostStats {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+
+	snapshot := make(map[string]HostStats, len(limiters))
+	for host, l := range limiters {
+		l.mu.Lock()
+		stats := l.stats
+		if until := l.pausedUntil; until.After(time.Now()) {
+			stats.PausedUntil = &until
+		}
+		snapshot[host] = stats
+		l.mu.Unlock()
+	}
+	return snapshot
+}
+
+// Wait blocks until a request to rawURL's host is allowed, for fetches that bypass
+// the transport such as headless Chrome page loads
+func Wait(ctx context.Context, rawURL string, interval time.Duration) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", rawURL, err)
+	}
+	limiter := limiterFor(u.Host, interval, 1)
+	if err := limiter.wait(ctx); err != nil {
+		return err
+	}
+	limiter.record(func(s *HostStats) { s.Requests++ })
+	return nil
+}
@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/temoto/robotstxt"
+)
+
+const robotsTTL = 12 * time.Hour
+
+type robotsEntry struct {
+	data    *robotstxt.RobotsData
+	fetched time.Time
+}
+
+var (
+	robotsMu    sync.Mutex
+	robotsCache = map[string]robotsEntry{}
+)
+
+// robotsAllowed checks the request path against the host's cached robots.txt.
+// A robots.txt that cannot be fetched or parsed allows everything.
+func (t *transport) robotsAllowed(req *http.Request) bool {
+	origin := req.URL.Scheme + "://" + req.URL.Host
+
+	robotsMu.Lock()
+	entry, ok := robotsCache[origin]
+	robotsMu.Unlock()
+
+	if !ok || time.Since(entry.fetched) > robotsTTL {
+		entry = robotsEntry{data: t.fetchRobots(req, origin), fetched: time.Now()}
+		robotsMu.Lock()
+		robotsCache[origin] = entry
+		robotsMu.Unlock()
+	}
+	if entry.data == nil {
+		return true
+	}
+
+	path := req.URL.EscapedPath()
+	if req.URL.RawQuery != "" {
+		path += "?" + req.URL.RawQuery
+	}
+	return entry.data.TestAgent(path, req.Header.Get("User-Agent"))
+}
+
+func (t *transport) fetchRobots(req *http.Request, origin string) *robotstxt.RobotsData {
+	robotsReq, err := http.NewRequestWithContext(req.Context(), "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return nil
+	}
+	robotsReq.Header.Set("User-Agent", req.Header.Get("User-Agent"))
+
+	resp, err := t.RoundTrip(robotsReq)
+	if err != nil {
+		fmt.Printf("⚠️  Could not fetch %s/robots.txt, assuming allowed: %v\n", origin, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	data, err := robotstxt.FromResponse(resp)
+	if err != nil {
+		fmt.Printf("⚠️  Could not parse %s/robots.txt, assuming allowed: %v\n", origin, err)
+		return nil
+	}
+	return data
+}
+
+// Allowed reports whether robots.txt lets userAgent fetch rawURL, for fetches that
+// bypass the transport such as headless Chrome page loads. HTTP_RESPECT_ROBOTS=false
+// allows everything.
+func Allowed(ctx context.Context, rawURL, userAgent string) bool {
+	if os.Getenv("HTTP_RESPECT_ROBOTS") == "false" {
+		return true
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return true
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	t := NewTransport(Options{UserAgent: userAgent}).(*transport)
+	if t.robotsAllowed(req) {
+		return true
+	}
+	limiterFor(req.URL.Host, 0, 1).record(func(s *HostStats) { s.RobotsBlocked++ })
+	return false
+}
@@ -0,0 +1,130 @@
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nowTolerance is how close to the test run a timestamp must be to count as "now"
+const nowTolerance = 2 * time.Minute
+
+// AssertGolden compares got, encoded as indented JSON, with the golden file at path.
+// Timestamps taken from the clock during the test (fetched_at, fallback posted dates)
+// are written as "<now>", and ignore lists dotted keys such as "fields.edited_at" to
+// drop from every element. Run with UPDATE_GOLDEN=1 to rewrite the file.
+func AssertGolden(t testing.TB, path string, got interface{}, ignore ...string) {
+	t.Helper()
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		t.Fatalf("failed to decode result: %v", err)
+	}
+
+	if list, ok := generic.([]interface{}); ok {
+		for _, item := range list {
+			for _, key := range ignore {
+				deleteKey(item, strings.Split(key, "."))
+			}
+		}
+	} else {
+		for _, key := range ignore {
+			deleteKey(generic, strings.Split(key, "."))
+		}
+	}
+	generic = scrubNow(generic, time.Now())
+
+	// Descriptions are HTML, so keep it readable instead of \u003c-escaped
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(generic); err != nil {
+		t.Fatalf("failed to encode result: %v", err)
+	}
+	actual := buf.Bytes()
+
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing golden file %s (create it with UPDATE_GOLDEN=1): %v", path, err)
+	}
+	if string(expected) != string(actual) {
+		t.Errorf("result differs from %s (run with UPDATE_GOLDEN=1 to accept):\n%s", path, diff(string(expected), string(actual)))
+	}
+}
+
+func deleteKey(v interface{}, path []string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+	if len(path) == 1 {
+		delete(obj, path[0])
+		return
+	}
+	deleteKey(obj[path[0]], path[1:])
+}
+
+// scrubNow replaces RFC 3339 timestamps close to now with "<now>"
+func scrubNow(v interface{}, now time.Time) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for k, item := range value {
+			value[k] = scrubNow(item, now)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = scrubNow(item, now)
+		}
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			if d := now.Sub(ts); d < nowTolerance && d > -nowTolerance {
+				return "<now>"
+			}
+		}
+	}
+	return v
+}
+
+// diff lists the first differing lines, which is enough to spot a regression
+func diff(expected, actual string) string {
+	exp := strings.Split(expected, "\n")
+	act := strings.Split(actual, "\n")
+	var out []string
+	for i := 0; i < len(exp) || i < len(act); i++ {
+		var e, a string
+		if i < len(exp) {
+			e = exp[i]
+		}
+		if i < len(act) {
+			a = act[i]
+		}
+		if e != a {
+			out = append(out, fmt.Sprintf("line %d:\n  want: %s\n  got:  %s", i+1, e, a))
+			if len(out) == 10 {
+				out = append(out, "...")
+				break
+			}
+		}
+	}
+	return strings.Join(out, "\n")
+}
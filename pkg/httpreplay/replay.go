@@ -0,0 +1,338 @@
+// Package httpreplay records real HTTP responses into fixture files once and replays
+// them offline, so connector tests are deterministic and need no network or keys.
+//
+// Tests replay by default. Run them with HTTPREPLAY=record (and the connector's
+// credentials in the environment) to refresh the fixtures from the live sources.
+package httpreplay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"openjobs/pkg/httpclient"
+)
+
+// redacted replaces secrets in recorded requests
+const redacted = "REDACTED"
+
+// secretParams are query parameters that carry credentials
+var secretParams = map[string]bool{
+	"app_id": true, "app_key": true, "api_key": true, "apikey": true, "key": true,
+	"publisher": true, "token": true, "access_token": true,
+}
+
+// Fixture is the file format: every request made during a test and its response
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded request/response pair
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request identifies a recorded request. Only the method, URL and body are kept, so
+// credentials in headers never reach a fixture; the host is for reference only.
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that replays a fixture file, or records one when
+// HTTPREPLAY=record
+type Recorder struct {
+	t       testing.TB
+	path    string
+	record  bool
+	real    http.RoundTripper
+	secrets []string
+	ignored map[string]bool
+
+	mu      sync.Mutex
+	fixture Fixture
+	served  map[int]bool
+}
+
+// New loads the fixture at path for replay, or prepares to record it. A recording is
+// written when the test finishes.
+func New(t testing.TB, path string) *Recorder {
+	t.Helper()
+	r := &Recorder{
+		t:      t,
+		path:   path,
+		record: os.Getenv("HTTPREPLAY") == "record",
+		served: map[int]bool{},
+	}
+
+	if r.record {
+		r.real = httpclient.NewTransport(httpclient.Options{Interval: time.Second, MaxRetries: 2})
+		t.Cleanup(r.save)
+		return r
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("missing fixture %s (record it with HTTPREPLAY=record): %v", path, err)
+	}
+	if err := json.Unmarshal(data, &r.fixture); err != nil {
+		t.Fatalf("invalid fixture %s: %v", path, err)
+	}
+	return r
+}
+
+// Redact hides a secret (API key, token in a path) in recordings and replaces it
+// when matching, so tests can run with any placeholder value
+func (r *Recorder) Redact(secrets ...string) {
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, s)
+		}
+	}
+}
+
+// IgnoreParams leaves query parameters out of matching, for values computed from the
+// clock such as "days since the last sync"
+func (r *Recorder) IgnoreParams(names ...string) {
+	if r.ignored == nil {
+		r.ignored = map[string]bool{}
+	}
+	for _, name := range names {
+		r.ignored[name] = true
+	}
+}
+
+// Client returns an *http.Client that goes through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r, Timeout: 30 * time.Second}
+}
+
+// Server serves the fixture over a local HTTP server, for colly and chromedp scrapers
+// that are pointed at a base URL instead of taking an http.Client. Requests are
+// matched on path, query and body, and links to the recorded hosts in response bodies
+// are rewritten to the server. Fixtures are recorded through the transport, so tests
+// using Server are skipped while recording.
+func (r *Recorder) Server() *httptest.Server {
+	if r.record {
+		r.t.Skip("httpreplay: Server replays fixtures recorded through the transport")
+	}
+
+	origins := map[string]bool{}
+	for _, in := range r.fixture.Interactions {
+		if u, err := url.Parse(in.Request.URL); err == nil && u.Host != "" {
+			origins[u.Scheme+"://"+u.Host] = true
+		}
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		resp, err := r.replay(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		for origin := range origins {
+			body = bytes.ReplaceAll(body, []byte(origin), []byte(server.URL))
+		}
+		for name, values := range resp.Header {
+			w.Header()[name] = values
+		}
+		w.Header().Del("Content-Length")
+		w.WriteHeader(resp.StatusCode)
+		w.Write(body)
+	}))
+	r.t.Cleanup(server.Close)
+	return server
+}
+
+// RoundTrip replays the matching interaction, or performs and records the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !r.record {
+		return r.replay(req)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.real.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only headers connectors act on are kept, so cookies never reach a fixture
+	headers := map[string]string{}
+	for _, name := range []string{"Content-Type", "Location", "ETag", "Last-Modified", "Retry-After"} {
+		if v := resp.Header.Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+
+	r.mu.Lock()
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    r.redactURL(req.URL),
+			Body:   r.redactString(string(body)),
+		},
+		Response: Response{Status: resp.StatusCode, Headers: headers, Body: string(respBody)},
+	})
+	r.mu.Unlock()
+
+	return newResponse(req, resp.StatusCode, headers, respBody), nil
+}
+
+// replay finds the first unserved interaction matching the request; once all matches
+// have been served the last one is repeated
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	key := r.matchKey(req.Method, req.URL, string(body))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	last := -1
+	for i, in := range r.fixture.Interactions {
+		recorded, err := url.Parse(in.Request.URL)
+		if err != nil || r.matchKey(in.Request.Method, recorded, in.Request.Body) != key {
+			continue
+		}
+		last = i
+		if !r.served[i] {
+			break
+		}
+	}
+	if last < 0 {
+		return nil, fmt.Errorf("httpreplay: no recorded response in %s for %s %s (re-record with HTTPREPLAY=record)",
+			filepath.Base(r.path), req.Method, r.redactURL(req.URL))
+	}
+
+	r.served[last] = true
+	in := r.fixture.Interactions[last]
+	return newResponse(req, in.Response.Status, in.Response.Headers, []byte(in.Response.Body)), nil
+}
+
+// matchKey is method, path, sorted query and body, with secrets redacted and ignored
+// parameters dropped
+func (r *Recorder) matchKey(method string, u *url.URL, body string) string {
+	redactedURL, _ := url.Parse(r.redactURL(u))
+	query := redactedURL.Query()
+	for name := range r.ignored {
+		query.Del(name)
+	}
+	return method + " " + redactedURL.EscapedPath() + "?" + query.Encode() + "\n" + strings.TrimSpace(r.redactString(body))
+}
+
+// redactURL returns the URL with secret query parameters and values replaced and the
+// query sorted
+func (r *Recorder) redactURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for name := range query {
+		if secretParams[strings.ToLower(name)] {
+			query.Set(name, redacted)
+		}
+	}
+	clean.RawQuery = query.Encode() // Encode sorts by key
+	clean.Path = r.redactString(clean.Path)
+	clean.RawPath = ""
+	return r.redactString(clean.String())
+}
+
+func (r *Recorder) redactString(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+		s = strings.ReplaceAll(s, url.PathEscape(secret), redacted)
+	}
+	return s
+}
+
+func (r *Recorder) save() {
+	if r.t.Failed() {
+		r.t.Logf("not writing %s: test failed", r.path)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false) // Keep recorded pages readable in diffs
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(r.fixture); err != nil {
+		r.t.Errorf("failed to encode fixture: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		r.t.Errorf("failed to create fixture directory: %v", err)
+		return
+	}
+	if err := os.WriteFile(r.path, data.Bytes(), 0o644); err != nil {
+		r.t.Errorf("failed to write fixture: %v", err)
+		return
+	}
+	r.t.Logf("recorded %d interactions to %s", len(r.fixture.Interactions), r.path)
+}
+
+// readBody reads the request body and puts it back for the real transport
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func newResponse(req *http.Request, status int, headers map[string]string, body []byte) *http.Response {
+	header := http.Header{}
+	for name, value := range headers {
+		header.Set(name, value)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,141 @@
+package httpreplay
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, client *http.Client, url string) string {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=private")
+		fmt.Fprintf(w, `{"call": %d, "next": "http://%s/jobs/2"}`, calls, r.Host)
+	}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	t.Run("record", func(t *testing.T) {
+		t.Setenv("HTTPREPLAY", "record")
+		rec := New(t, path)
+		rec.Redact("s3cr3t")
+		client := rec.Client()
+		get(t, client, upstream.URL+"/jobs/s3cr3t?page=1&api_key=k1")
+		get(t, client, upstream.URL+"/jobs/s3cr3t?api_key=k1&page=1")
+	})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Fixture not written: %v", err)
+	}
+	if strings.Contains(string(data), "s3cr3t") || strings.Contains(string(data), "k1") {
+		t.Errorf("Secrets leaked into fixture:\n%s", data)
+	}
+	if strings.Contains(string(data), "session=private") {
+		t.Errorf("Unlisted response header recorded:\n%s", data)
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		rec := New(t, path)
+		rec.Redact("other-token")
+		client := rec.Client()
+
+		// Matched regardless of host, query order and secret values; served in order, then the last repeats
+		for i, want := range []string{`"call": 1`, `"call": 2`, `"call": 2`} {
+			body := get(t, client, "https://api.example.com/jobs/other-token?page=1&api_key=k2")
+			if !strings.Contains(body, want) {
+				t.Errorf("Request %d: expected %s, got %s", i+1, want, body)
+			}
+		}
+
+		if _, err := client.Get("https://api.example.com/jobs/other-token?page=2"); err == nil {
+			t.Error("Expected an error for an unrecorded request")
+		}
+
+		rec.IgnoreParams("max_days_old")
+		if body := get(t, client, "https://api.example.com/jobs/other-token?page=1&api_key=k2&max_days_old=3"); !strings.Contains(body, `"call": 2`) {
+			t.Errorf("Expected ignored parameter to match, got %s", body)
+		}
+	})
+	if calls != 2 {
+		t.Errorf("Expected replay to stay offline, upstream saw %d calls", calls)
+	}
+
+	t.Run("server", func(t *testing.T) {
+		rec := New(t, path)
+		rec.Redact("s3cr3t")
+		server := rec.Server()
+
+		body := get(t, server.Client(), server.URL+"/jobs/s3cr3t?page=1&api_key=k3")
+		if !strings.Contains(body, `"next": "`+server.URL+`/jobs/2"`) {
+			t.Errorf("Expected recorded origin rewritten to the server, got %s", body)
+		}
+	})
+}
+
+func TestAssertGolden(t *testing.T) {
+	type job struct {
+		ID        string                 `json:"id"`
+		FetchedAt time.Time              `json:"fetched_at"`
+		Posted    time.Time              `json:"posted"`
+		Fields    map[string]interface{} `json:"fields"`
+	}
+	jobs := []job{{
+		ID:        "a",
+		FetchedAt: time.Now(),
+		Posted:    time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC),
+		Fields:    map[string]interface{}{"edited_at": time.Now().Add(-time.Hour).String(), "source": "x"},
+	}}
+	path := filepath.Join(t.TempDir(), "jobs.golden.json")
+
+	t.Setenv("UPDATE_GOLDEN", "1")
+	AssertGolden(t, path, jobs, "fields.edited_at")
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"fetched_at": "<now>"`) || strings.Contains(string(data), "edited_at") {
+		t.Errorf("Unexpected golden file:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"posted": "2025-10-01T08:00:00Z"`) {
+		t.Errorf("Fixed timestamps must be kept:\n%s", data)
+	}
+
+	t.Setenv("UPDATE_GOLDEN", "")
+	jobs[0].FetchedAt = time.Now() // A later run still matches
+	AssertGolden(t, path, jobs, "fields.edited_at")
+
+	mock := &failureTB{TB: t}
+	jobs[0].ID = "b"
+	AssertGolden(mock, path, jobs, "fields.edited_at")
+	if !mock.failed {
+		t.Error("Expected a mismatch to fail")
+	}
+}
+
+// failureTB records failures instead of failing the real test
+type failureTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *failureTB) Errorf(format string, args ...interface{}) { f.failed = true }
+func (f *failureTB) Fatalf(format string, args ...interface{}) { f.failed = true }
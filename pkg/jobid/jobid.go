@@ -0,0 +1,112 @@
+// Package jobid derives the IDs job posts are stored under. Every connector builds its
+// IDs here so they share one shape: "<source>:<upstream id>", where the upstream ID is
+// whatever the source itself uses to identify the posting, or "<source>:<hash>" of the
+// canonical posting URL when the source has no ID of its own. The same posting always
+// gets the same ID, across runs and across connector versions.
+package jobid
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Separator sits between the source and the upstream ID
+const Separator = ":"
+
+// safePart matches upstream IDs that are used as they are
+var safePart = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// trackingParams are query parameters that never identify a posting
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"msclkid": true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+}
+
+// New returns the ID of a posting the source identifies by upstreamID. Several parts
+// (an ID that is only unique per company board or feed, say) are joined with "-".
+// Parts that are not URL and filter safe are hashed, so IDs can be used in /jobs/{id}
+// and PostgREST filters as they are.
+func New(source string, upstreamID ...string) string {
+	parts := make([]string, len(upstreamID))
+	for i, part := range upstreamID {
+		part = strings.TrimSpace(part)
+		if !safePart.MatchString(part) {
+			part = hash(part)
+		}
+		parts[i] = part
+	}
+	return source + Separator + strings.Join(parts, "-")
+}
+
+// FromURL returns the ID of a posting that has no upstream ID: a hash of its canonical URL
+func FromURL(source, rawURL string) string {
+	return New(source, URLHash(rawURL))
+}
+
+// Derive returns New(source, upstreamID), or FromURL when upstreamID is empty. It
+// returns "" when there is neither, and the posting cannot be given a stable ID.
+func Derive(source, upstreamID, rawURL string) string {
+	switch {
+	case strings.TrimSpace(upstreamID) != "":
+		return New(source, upstreamID)
+	case strings.TrimSpace(rawURL) != "":
+		return FromURL(source, rawURL)
+	default:
+		return ""
+	}
+}
+
+// URLHash returns the first 16 hex characters of the SHA-1 of the canonical URL
+func URLHash(rawURL string) string {
+	return hash(CanonicalURL(rawURL))
+}
+
+func hash(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// CanonicalURL normalizes the parts of a URL that do not change which page it points
+// at: scheme and host case, default ports, the fragment, tracking parameters, query
+// parameter order and a trailing slash. Anything that does not parse as an absolute
+// URL is returned trimmed.
+func CanonicalURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if len(u.Path) > 1 {
+		u.Path = strings.TrimRight(u.Path, "/")
+		u.RawPath = ""
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+				query.Del(key)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+
+	return u.String()
+}
@@ -0,0 +1,59 @@
+package jobid
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		source string
+		parts  []string
+		want   string
+	}{
+		{"remotive", []string{"12345"}, "remotive:12345"},
+		{"greenhouse", []string{"spotify", "4012"}, "greenhouse:spotify-4012"},
+		{"eures", []string{" abc "}, "eures:abc"},
+		{"custom", []string{"a/b?c#d e"}, "custom:" + hash("a/b?c#d e")},
+		{"eures", []string{"NDU2 MQ=="}, "eures:" + hash("NDU2 MQ==")},
+	}
+	for _, tt := range tests {
+		if got := New(tt.source, tt.parts...); got != tt.want {
+			t.Errorf("New(%q, %q) = %q, want %q", tt.source, tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://example.com/jobs/1", "https://example.com/jobs/1"},
+		{"  HTTPS://Example.COM:443/jobs/1/#apply ", "https://example.com/jobs/1"},
+		{"http://example.com:80/jobs?b=2&a=1", "http://example.com/jobs?a=1&b=2"},
+		{"https://example.com:8443/jobs?utm_source=x&id=7&fbclid=y", "https://example.com:8443/jobs?id=7"},
+		{"https://example.com/?utm_campaign=x", "https://example.com/"},
+		{"/relative/path", "/relative/path"},
+	}
+	for _, tt := range tests {
+		if got := CanonicalURL(tt.in); got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDerive(t *testing.T) {
+	if got := Derive("jooble", "42", "https://jooble.org/desc/42"); got != "jooble:42" {
+		t.Errorf("Expected the upstream ID to win, got %q", got)
+	}
+
+	a := Derive("jooble", "", "https://jooble.org/desc/abc?utm_source=feed")
+	b := Derive("jooble", "", "https://JOOBLE.org/desc/abc/")
+	if a != b || len(a) != len("jooble:")+16 {
+		t.Errorf("Expected equal URL hash IDs for the same page, got %q and %q", a, b)
+	}
+	if c := Derive("jooble", "", "https://jooble.org/desc/def"); c == a {
+		t.Errorf("Expected different pages to get different IDs, both got %q", c)
+	}
+
+	if got := Derive("jooble", " ", ""); got != "" {
+		t.Errorf("Expected no ID without upstream ID or URL, got %q", got)
+	}
+}
@@ -19,8 +19,8 @@ type SourceAnalytics struct {
 	FulltimeJobs     int        `json:"fulltime_jobs"`
 	ParttimeJobs     int        `json:"parttime_jobs"`
 	RemoteJobs       int        `json:"remote_jobs"`
-	AvgMinSalary     *int       `json:"avg_min_salary,omitempty"`
-	AvgMaxSalary     *int       `json:"avg_max_salary,omitempty"`
+	AvgMinSalaryEUR  *int       `json:"avg_min_salary_eur,omitempty"`
+	AvgMaxSalaryEUR  *int       `json:"avg_max_salary_eur,omitempty"`
 	CategoriesCount  int        `json:"categories_count"`
 	JobsWithMetadata int        `json:"jobs_with_metadata"`
 	AvgTagsPerJob    *float64   `json:"avg_tags_per_job,omitempty"`
@@ -29,6 +29,15 @@ type SourceAnalytics struct {
 	HoursActive      *float64   `json:"hours_active,omitempty"`
 }
 
+// SalarySummary averages the annual salaries in euros (salary_annual_min_eur and
+// salary_annual_max_eur) of all jobs that have one
+type SalarySummary struct {
+	JobsWithSalary  int    `json:"jobs_with_salary"`
+	AvgAnnualMinEUR *int   `json:"avg_annual_min_eur,omitempty"`
+	AvgAnnualMaxEUR *int   `json:"avg_annual_max_eur,omitempty"`
+	RatesVersion    string `json:"rates_version,omitempty"` // Latest version in exchange_rates
+}
+
 // GeographyData shows geographic distribution of jobs
 type GeographyData struct {
 	Country string         `json:"country"`
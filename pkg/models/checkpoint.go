@@ -0,0 +1,108 @@
+package models
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Checkpoint is the sync position a connector committed for one query. Connectors
+// only save it after their jobs are stored, so a failed run is retried from the
+// same position.
+type Checkpoint struct {
+	ConnectorID   string            `json:"connector_id" db:"connector_id"`
+	QueryKey      string            `json:"query_key" db:"query_key"`           // "" for connectors with a single query
+	Cursor        string            `json:"cursor" db:"cursor"`                 // Opaque resume position (e.g. a JobStream timestamp)
+	HighWatermark time.Time         `json:"high_watermark" db:"high_watermark"` // Newest posted date of the committed runs
+	ETags         map[string]string `json:"etags" db:"etags"`                   // Upstream validators by URL (see Conditional)
+	UpdatedAt     time.Time         `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// Advance moves HighWatermark to the newest posted date among jobs. Estimated dates
+// are the fetch time, so they are left out; they would skip jobs the source dates earlier.
+func (c *Checkpoint) Advance(jobs []JobPost) {
+	for _, job := range jobs {
+		if estimated, _ := job.Fields["posted_date_estimated"].(bool); estimated {
+			continue
+		}
+		if job.PostedDate.After(c.HighWatermark) {
+			c.HighWatermark = job.PostedDate
+		}
+	}
+}
+
+// Truncate records a run that stopped before the end of its results (a page limit or a
+// failed page). HighWatermark stays, so the next run gets the same results and resumes
+// at offset; the newest posted date seen waits in Cursor until Complete commits it.
+func (c *Checkpoint) Truncate(offset int, jobs []JobPost) {
+	_, newest := c.resume()
+	pending := Checkpoint{HighWatermark: newest}
+	pending.Advance(jobs)
+
+	values := url.Values{}
+	values.Set("offset", strconv.Itoa(offset))
+	if !pending.HighWatermark.IsZero() {
+		values.Set("newest", pending.HighWatermark.Format(time.RFC3339))
+	}
+	c.Cursor = values.Encode()
+}
+
+// Complete records a run that reached the end of its results: HighWatermark advances
+// past jobs and the truncated runs before it, and the next run starts from the top
+func (c *Checkpoint) Complete(jobs []JobPost) {
+	if _, newest := c.resume(); newest.After(c.HighWatermark) {
+		c.HighWatermark = newest
+	}
+	c.Advance(jobs)
+	c.Cursor = ""
+}
+
+// ResumeOffset returns where the last truncated run stopped, 0 after a complete run
+func (c *Checkpoint) ResumeOffset() int {
+	offset, _ := c.resume()
+	return offset
+}
+
+func (c *Checkpoint) resume() (int, time.Time) {
+	values, err := url.ParseQuery(c.Cursor)
+	if err != nil {
+		return 0, time.Time{}
+	}
+	offset, _ := strconv.Atoi(values.Get("offset"))
+	newest, _ := time.Parse(time.RFC3339, values.Get("newest"))
+	return offset, newest
+}
+
+// lastModifiedPrefix marks the Last-Modified entries in ETags; plain URL keys hold the ETag
+const lastModifiedPrefix = "last-modified "
+
+// Conditional adds If-None-Match / If-Modified-Since for the validators committed for
+// the request URL, so an unchanged upstream answers 304 Not Modified
+func (c *Checkpoint) Conditional(req *http.Request) {
+	key := req.URL.String()
+	if etag := c.ETags[key]; etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	if lastModified := c.ETags[lastModifiedPrefix+key]; lastModified != "" {
+		req.Header.Set("If-Modified-Since", lastModified)
+	}
+}
+
+// KeepValidators remembers the ETag and Last-Modified of a response to url for the
+// next Conditional request. They are only persisted when the checkpoint is saved.
+func (c *Checkpoint) KeepValidators(url string, header http.Header) {
+	if c.ETags == nil {
+		c.ETags = map[string]string{}
+	}
+	for key, value := range map[string]string{
+		url:                      header.Get("ETag"),
+		lastModifiedPrefix + url: header.Get("Last-Modified"),
+	} {
+		if value == "" {
+			delete(c.ETags, key)
+		} else {
+			c.ETags[key] = value
+		}
+	}
+}
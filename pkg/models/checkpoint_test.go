@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCheckpointTruncate checks that truncated runs keep the watermark until the run
+// that reaches the end commits the newest date of the whole chain
+func TestCheckpointTruncate(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 8, 0, 0, 0, time.UTC) }
+	posted := func(days ...int) []JobPost {
+		jobs := []JobPost{}
+		for _, d := range days {
+			jobs = append(jobs, JobPost{PostedDate: day(d)})
+		}
+		return jobs
+	}
+
+	c := Checkpoint{HighWatermark: day(1)}
+	c.Truncate(500, posted(9, 8))
+	c.Truncate(1000, posted(5, 4))
+	if !c.HighWatermark.Equal(day(1)) || c.ResumeOffset() != 1000 {
+		t.Fatalf("Truncated runs moved the checkpoint: %v, offset %d", c.HighWatermark, c.ResumeOffset())
+	}
+
+	c.Complete(posted(3, 2))
+	if !c.HighWatermark.Equal(day(9)) || c.ResumeOffset() != 0 || c.Cursor != "" {
+		t.Errorf("Complete run: watermark %v (want %v), cursor %q", c.HighWatermark, day(9), c.Cursor)
+	}
+}
@@ -0,0 +1,382 @@
+package models
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecPluginConnector implements PluginConnector by running a plugin binary as a
+// subprocess and exchanging framed JSON messages over its stdin/stdout.
+// The messages carry the same endpoints (/health, /jobs, /sync) and the same
+// response envelope as the HTTP plugins, so any plugin main can serve both.
+type ExecPluginConnector struct {
+	pluginID       string
+	pluginName     string
+	path           string
+	args           []string
+	requestTimeout time.Duration
+	maxRestarts    int
+	restartWindow  time.Duration
+
+	mu        sync.Mutex
+	proc      *execProcess
+	nextID    int64
+	restarts  []time.Time
+	lastError string
+}
+
+// ExecPluginRequest is a single request frame sent to an executable plugin
+type ExecPluginRequest struct {
+	ID     int64  `json:"id"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// ExecPluginResponse is a single response frame returned by an executable plugin
+type ExecPluginResponse struct {
+	ID     int64              `json:"id"`
+	Status int                `json:"status"`
+	Body   HTTPPluginResponse `json:"body"`
+}
+
+// execProcess is one running instance of a plugin binary
+type execProcess struct {
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	responses chan ExecPluginResponse
+	exited    chan struct{}
+	exitErr   error
+}
+
+// NewExecPluginConnector creates a new subprocess-based plugin connector.
+// The binary is started lazily on first use and kept running between syncs.
+func NewExecPluginConnector(id, name, path string, args ...string) *ExecPluginConnector {
+	return &ExecPluginConnector{
+		pluginID:       id,
+		pluginName:     name,
+		path:           path,
+		args:           append([]string{"--stdio"}, args...),
+		requestTimeout: 6 * time.Minute, // Same budget as HTTP plugins (Chrome scraping)
+		maxRestarts:    5,
+		restartWindow:  10 * time.Minute,
+	}
+}
+
+// GetID returns the plugin ID
+func (e *ExecPluginConnector) GetID() string {
+	return e.pluginID
+}
+
+// GetName returns the plugin name
+func (e *ExecPluginConnector) GetName() string {
+	return e.pluginName
+}
+
+// FetchJobs asks the plugin process for its latest jobs
+func (e *ExecPluginConnector) FetchJobs() ([]JobPost, error) {
+	resp, err := e.call("GET", "/jobs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jobs from plugin %s: %w", e.pluginName, err)
+	}
+
+	if resp.Status != 200 || !resp.Body.Success {
+		return nil, fmt.Errorf("plugin %s error (status %d): %s", e.pluginName, resp.Status, resp.Body.Error)
+	}
+
+	jobs, err := jobsFromPluginData(resp.Body.Data)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", e.pluginName, err)
+	}
+	return jobs, nil
+}
+
+// SyncJobs triggers job synchronization inside the plugin process
+func (e *ExecPluginConnector) SyncJobs() error {
+	resp, err := e.call("POST", "/sync")
+	if err != nil {
+		return fmt.Errorf("failed to sync jobs with plugin %s: %w", e.pluginName, err)
+	}
+
+	if resp.Status != 200 || !resp.Body.Success {
+		return fmt.Errorf("plugin %s sync returned status %d: %s", e.pluginName, resp.Status, resp.Body.Error)
+	}
+
+	return nil
+}
+
+// Health checks whether the plugin process is up and answering requests
+func (e *ExecPluginConnector) Health() error {
+	resp, err := e.call("GET", "/health")
+	if err != nil {
+		return err
+	}
+	if resp.Status != 200 {
+		return fmt.Errorf("plugin %s unhealthy (status %d): %s", e.pluginName, resp.Status, resp.Body.Error)
+	}
+	return nil
+}
+
+// Close stops the plugin process. It is restarted on the next request.
+func (e *ExecPluginConnector) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.proc == nil {
+		return nil
+	}
+	err := e.proc.stop(10 * time.Second)
+	e.proc = nil
+	return err
+}
+
+// call sends a request frame and waits for the matching response,
+// restarting the plugin process if it has crashed
+func (e *ExecPluginConnector) call(method, path string) (*ExecPluginResponse, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// One retry after a restart covers a process that died between syncs
+	for attempt := 0; attempt < 2; attempt++ {
+		proc, err := e.ensureRunning()
+		if err != nil {
+			return nil, err
+		}
+
+		e.nextID++
+		req := ExecPluginRequest{ID: e.nextID, Method: method, Path: path}
+
+		resp, err := proc.roundTrip(req, e.requestTimeout)
+		if err == nil {
+			return resp, nil
+		}
+
+		e.lastError = err.Error()
+		if !errors.Is(err, errPluginExited) {
+			return nil, err
+		}
+		log.Printf("⚠️  Plugin %s exited during %s %s: %v", e.pluginID, method, path, proc.exitErr)
+		e.proc = nil
+	}
+
+	return nil, fmt.Errorf("plugin %s crashed repeatedly: %s", e.pluginName, e.lastError)
+}
+
+// ensureRunning starts the plugin process if it is not running,
+// enforcing the restart budget
+func (e *ExecPluginConnector) ensureRunning() (*execProcess, error) {
+	if e.proc != nil {
+		select {
+		case <-e.proc.exited:
+			log.Printf("⚠️  Plugin %s is not running (%v), restarting", e.pluginID, e.proc.exitErr)
+			e.proc = nil
+		default:
+			return e.proc, nil
+		}
+	}
+
+	// Drop restarts that fall outside the window
+	cutoff := time.Now().Add(-e.restartWindow)
+	recent := e.restarts[:0]
+	for _, t := range e.restarts {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	e.restarts = recent
+
+	if len(e.restarts) >= e.maxRestarts {
+		return nil, fmt.Errorf("plugin %s restarted %d times in %v, giving up until the window passes",
+			e.pluginName, len(e.restarts), e.restartWindow)
+	}
+
+	// Back off between consecutive starts so a crash loop doesn't spin
+	if n := len(e.restarts); n > 0 {
+		time.Sleep(time.Duration(n) * time.Second)
+	}
+
+	proc, err := startExecProcess(e.pluginID, e.path, e.args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start plugin %s: %w", e.pluginName, err)
+	}
+
+	e.restarts = append(e.restarts, time.Now())
+	e.proc = proc
+	fmt.Printf("🔌 Started executable plugin %s (%s, pid %d)\n", e.pluginID, e.path, proc.cmd.Process.Pid)
+	return proc, nil
+}
+
+var errPluginExited = errors.New("plugin process exited")
+
+// startExecProcess spawns the plugin binary and wires up its pipes
+func startExecProcess(id, path string, args []string) (*execProcess, error) {
+	cmd := exec.Command(path, args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	proc := &execProcess{
+		cmd:       cmd,
+		stdin:     stdin,
+		responses: make(chan ExecPluginResponse, 16),
+		exited:    make(chan struct{}),
+	}
+
+	// Capture plugin logs line by line with the plugin ID as prefix
+	stderrDone := make(chan struct{})
+	go func() {
+		defer close(stderrDone)
+		scanner := bufio.NewScanner(stderr)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			log.Printf("[plugin %s] %s", id, scanner.Text())
+		}
+		// Keep draining after an oversized line so the plugin never blocks on stderr
+		io.Copy(io.Discard, stderr)
+	}()
+
+	// Read response frames until stdout closes
+	go func() {
+		reader := bufio.NewReader(stdout)
+		for {
+			var resp ExecPluginResponse
+			if err := ReadExecFrame(reader, &resp); err != nil {
+				if err != io.EOF {
+					log.Printf("⚠️  Plugin %s sent an invalid frame: %v", id, err)
+				}
+				break
+			}
+			select {
+			case proc.responses <- resp:
+			default:
+				log.Printf("⚠️  Plugin %s: dropping unclaimed response %d", id, resp.ID)
+			}
+		}
+		// Drain stdout so the process never blocks on a full pipe
+		io.Copy(io.Discard, reader)
+		<-stderrDone
+		proc.exitErr = cmd.Wait()
+		close(proc.exited)
+	}()
+
+	return proc, nil
+}
+
+// roundTrip writes one request and waits for its response
+func (p *execProcess) roundTrip(req ExecPluginRequest, timeout time.Duration) (*ExecPluginResponse, error) {
+	if err := WriteExecFrame(p.stdin, req); err != nil {
+		select {
+		case <-p.exited:
+			return nil, errPluginExited
+		case <-time.After(time.Second):
+			return nil, fmt.Errorf("failed to write request: %w", err)
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case resp := <-p.responses:
+			if resp.ID != req.ID {
+				// Late answer to a request that already timed out
+				continue
+			}
+			return &resp, nil
+		case <-p.exited:
+			return nil, errPluginExited
+		case <-timer.C:
+			return nil, fmt.Errorf("timed out after %v waiting for %s %s", timeout, req.Method, req.Path)
+		}
+	}
+}
+
+// stop closes stdin so the plugin can exit cleanly, killing it after the grace period
+func (p *execProcess) stop(grace time.Duration) error {
+	p.stdin.Close()
+	select {
+	case <-p.exited:
+		return nil
+	case <-time.After(grace):
+		if err := p.cmd.Process.Kill(); err != nil {
+			return err
+		}
+		<-p.exited
+		return nil
+	}
+}
+
+// WriteExecFrame writes a message as a Content-Length framed JSON frame
+func WriteExecFrame(w io.Writer, v interface{}) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal frame: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(payload)); err != nil {
+		return err
+	}
+	_, err = w.Write(payload)
+	return err
+}
+
+// ReadExecFrame reads one Content-Length framed JSON frame into v
+func ReadExecFrame(r *bufio.Reader, v interface{}) error {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length == -1 {
+				return io.EOF
+			}
+			return fmt.Errorf("failed to read frame header: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			return fmt.Errorf("malformed frame header %q", line)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid Content-Length %q", value)
+			}
+			length = n
+		}
+	}
+
+	if length < 0 {
+		return fmt.Errorf("frame is missing Content-Length header")
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return fmt.Errorf("failed to read frame body: %w", err)
+	}
+	return json.Unmarshal(payload, v)
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeExecConnector is served by the test binary when it is re-executed as a plugin
+type fakeExecConnector struct {
+	crashMarker string
+}
+
+func (f *fakeExecConnector) GetID() string   { return "fake" }
+func (f *fakeExecConnector) GetName() string { return "Fake Plugin" }
+
+func (f *fakeExecConnector) FetchJobs() ([]JobPost, error) {
+	fmt.Println("this log line must not corrupt the protocol stream")
+	return []JobPost{
+		{ID: "fake-1", Title: "Go Developer", Company: "Acme AB", PostedDate: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "fake-2", Title: "SRE", Company: "Acme AB", Fields: map[string]interface{}{"source": "fake"}},
+	}, nil
+}
+
+// SyncJobs crashes the process the first time it is called
+func (f *fakeExecConnector) SyncJobs() error {
+	if _, err := os.Stat(f.crashMarker); os.IsNotExist(err) {
+		os.WriteFile(f.crashMarker, []byte("crashed"), 0o644)
+		os.Exit(3)
+	}
+	return nil
+}
+
+func TestMain(m *testing.M) {
+	if os.Getenv("OPENJOBS_EXEC_PLUGIN_HELPER") == "1" {
+		out, _ := StdioPluginMode()
+		connector := &fakeExecConnector{crashMarker: os.Getenv("OPENJOBS_EXEC_CRASH_MARKER")}
+		if err := ServeExecPlugin(connector, os.Stdin, out); err != nil {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func newHelperConnector(t *testing.T) *ExecPluginConnector {
+	t.Helper()
+	t.Setenv("OPENJOBS_EXEC_PLUGIN_HELPER", "1")
+	t.Setenv("OPENJOBS_EXEC_CRASH_MARKER", filepath.Join(t.TempDir(), "crashed"))
+
+	connector := NewExecPluginConnector("fake", "Fake Plugin", os.Args[0])
+	connector.requestTimeout = 10 * time.Second
+	t.Cleanup(func() { connector.Close() })
+	return connector
+}
+
+func TestExecPluginFetchJobs(t *testing.T) {
+	connector := newHelperConnector(t)
+
+	if err := connector.Health(); err != nil {
+		t.Fatalf("Health() failed: %v", err)
+	}
+
+	jobs, err := connector.FetchJobs()
+	if err != nil {
+		t.Fatalf("FetchJobs() failed: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("Expected 2 jobs, got %d", len(jobs))
+	}
+	if jobs[0].ID != "fake-1" || jobs[0].Company != "Acme AB" {
+		t.Errorf("Unexpected first job: %+v", jobs[0])
+	}
+	if !jobs[0].PostedDate.Equal(time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("PostedDate not preserved: %v", jobs[0].PostedDate)
+	}
+	if jobs[1].Fields["source"] != "fake" {
+		t.Errorf("Fields not preserved: %v", jobs[1].Fields)
+	}
+}
+
+func TestExecPluginRestartsAfterCrash(t *testing.T) {
+	connector := newHelperConnector(t)
+
+	// First sync kills the process; the connector restarts it and retries once
+	if err := connector.SyncJobs(); err != nil {
+		t.Fatalf("SyncJobs() should succeed after restart, got: %v", err)
+	}
+	if len(connector.restarts) != 2 {
+		t.Errorf("Expected 2 process starts, got %d", len(connector.restarts))
+	}
+
+	// The restarted process keeps serving requests
+	if _, err := connector.FetchJobs(); err != nil {
+		t.Fatalf("FetchJobs() after restart failed: %v", err)
+	}
+}
+
+func TestExecFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	req := ExecPluginRequest{ID: 7, Method: "GET", Path: "/jobs"}
+	if err := WriteExecFrame(&buf, req); err != nil {
+		t.Fatalf("WriteExecFrame() failed: %v", err)
+	}
+	if err := WriteExecFrame(&buf, req); err != nil {
+		t.Fatalf("WriteExecFrame() failed: %v", err)
+	}
+
+	reader := bufio.NewReader(&buf)
+	for i := 0; i < 2; i++ {
+		var got ExecPluginRequest
+		if err := ReadExecFrame(reader, &got); err != nil {
+			t.Fatalf("ReadExecFrame() #%d failed: %v", i, err)
+		}
+		if got != req {
+			t.Errorf("Frame #%d: expected %+v, got %+v", i, req, got)
+		}
+	}
+
+	var extra ExecPluginRequest
+	if err := ReadExecFrame(reader, &extra); err == nil {
+		t.Error("Expected EOF after the last frame")
+	}
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// StdioPluginMode reports whether a plugin binary was started by the core as an
+// executable plugin (with --stdio). In that mode stdout carries protocol frames,
+// so it is returned for ServeExecPlugin and os.Stdout is pointed at stderr to keep
+// the connectors' fmt.Printf logging out of the protocol stream.
+func StdioPluginMode() (io.Writer, bool) {
+	for _, arg := range os.Args[1:] {
+		if arg == "--stdio" {
+			protocolOut := os.Stdout
+			os.Stdout = os.Stderr
+			return protocolOut, true
+		}
+	}
+	return nil, false
+}
+
+// ServeExecPlugin answers framed plugin requests from in until it is closed.
+// It mirrors the /health, /jobs and /sync endpoints of the HTTP plugin servers.
+func ServeExecPlugin(connector PluginConnector, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+
+	for {
+		var req ExecPluginRequest
+		if err := ReadExecFrame(reader, &req); err != nil {
+			if err == io.EOF {
+				// Core closed stdin - normal shutdown
+				return nil
+			}
+			return err
+		}
+
+		resp := handleExecRequest(connector, req)
+		if err := WriteExecFrame(out, resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+}
+
+// handleExecRequest dispatches a single request to the connector
+func handleExecRequest(connector PluginConnector, req ExecPluginRequest) ExecPluginResponse {
+	resp := ExecPluginResponse{ID: req.ID, Status: 200}
+
+	switch {
+	case req.Path == "/health" && req.Method == "GET":
+		resp.Body = HTTPPluginResponse{
+			Success: true,
+			Data: map[string]interface{}{
+				"status":    "healthy",
+				"plugin":    connector.GetName(),
+				"plugin_id": connector.GetID(),
+			},
+		}
+
+	case req.Path == "/jobs" && req.Method == "GET":
+		jobs, err := connector.FetchJobs()
+		if err != nil {
+			resp.Status = 500
+			resp.Body = HTTPPluginResponse{Success: false, Error: fmt.Sprintf("Failed to fetch jobs: %v", err)}
+			break
+		}
+		resp.Body = HTTPPluginResponse{Success: true, Data: jobs}
+
+	case req.Path == "/sync" && req.Method == "POST":
+		if err := connector.SyncJobs(); err != nil {
+			resp.Status = 500
+			resp.Body = HTTPPluginResponse{Success: false, Error: fmt.Sprintf("Sync failed: %v", err)}
+			break
+		}
+		resp.Body = HTTPPluginResponse{
+			Success: true,
+			Message: fmt.Sprintf("%s sync completed successfully", connector.GetName()),
+		}
+
+	default:
+		resp.Status = 404
+		resp.Body = HTTPPluginResponse{Success: false, Error: fmt.Sprintf("unknown endpoint %s %s", req.Method, req.Path)}
+	}
+
+	return resp
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Provenance is the stored job a golden record field was taken from
+type Provenance struct {
+	JobID     string `json:"job_id"`
+	Source    string `json:"source"`
+	Connector string `json:"connector"`
+}
+
+// GoldenRecord is the canonical view of one duplicate cluster: every field comes from
+// the source trusted most for it (see pkg/dedup). The per-source JobPosts it was
+// composed from stay stored as they are.
+type GoldenRecord struct {
+	ClusterID  string                `json:"cluster_id" db:"cluster_id"`
+	Job        JobPost               `json:"job" db:"job"`               // ID is the cluster ID
+	Provenance map[string]Provenance `json:"provenance" db:"provenance"` // Keyed by JobPost JSON field
+	JobIDs     []string              `json:"job_ids" db:"job_ids"`       // Cluster members, oldest first
+	UpdatedAt  time.Time             `json:"updated_at,omitempty" db:"updated_at"`
+}
+
+// JobView is a job as the API returns it, with the provenance of each field when the
+// client asked for it
+type JobView struct {
+	JobPost
+	Provenance map[string]Provenance `json:"provenance,omitempty"`
+	MergedFrom []string              `json:"merged_from,omitempty"`
+}
@@ -74,25 +74,9 @@ func (h *HTTPPluginConnector) FetchJobs() ([]JobPost, error) {
 		return nil, fmt.Errorf("plugin %s error: %s", h.pluginName, response.Error)
 	}
 
-	// Convert interface{} data to []JobPost
-	jobsData, ok := response.Data.([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("plugin %s returned invalid data format", h.pluginName)
-	}
-
-	var jobs []JobPost
-	for _, jobData := range jobsData {
-		jobMap, ok := jobData.(map[string]interface{})
-		if !ok {
-			return nil, fmt.Errorf("plugin %s returned invalid job data format", h.pluginName)
-		}
-
-		job, err := h.mapToJobPost(jobMap)
-		if err != nil {
-			return nil, fmt.Errorf("failed to map job data from plugin %s: %w", h.pluginName, err)
-		}
-
-		jobs = append(jobs, *job)
+	jobs, err := jobsFromPluginData(response.Data)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", h.pluginName, err)
 	}
 
 	return jobs, nil
@@ -116,8 +100,33 @@ func (h *HTTPPluginConnector) SyncJobs() error {
 	return nil
 }
 
+// jobsFromPluginData converts the data field of a plugin /jobs response to []JobPost
+func jobsFromPluginData(data interface{}) ([]JobPost, error) {
+	jobsData, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("returned invalid data format")
+	}
+
+	var jobs []JobPost
+	for _, jobData := range jobsData {
+		jobMap, ok := jobData.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("returned invalid job data format")
+		}
+
+		job, err := mapToJobPost(jobMap)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map job data: %w", err)
+		}
+
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, nil
+}
+
 // mapToJobPost converts map[string]interface{} to JobPost struct
-func (h *HTTPPluginConnector) mapToJobPost(data map[string]interface{}) (*JobPost, error) {
+func mapToJobPost(data map[string]interface{}) (*JobPost, error) {
 	job := &JobPost{}
 
 	// Basic field mapping
@@ -8,24 +8,33 @@ import (
 
 // JobPost represents a job posting with flexible attributes
 type JobPost struct {
-	ID              string                 `json:"id" db:"id"`
-	Title           string                 `json:"title" db:"title"`
-	Company         string                 `json:"company" db:"company"`
-	Description     string                 `json:"description" db:"description"`
-	Location        string                 `json:"location" db:"location"`
-	Salary          string                 `json:"salary" db:"salary"`
-	SalaryMin       *int                   `json:"salary_min,omitempty" db:"salary_min"`
-	SalaryMax       *int                   `json:"salary_max,omitempty" db:"salary_max"`
-	SalaryCurrency  string                 `json:"salary_currency,omitempty" db:"salary_currency"`
-	IsRemote        bool                   `json:"is_remote" db:"is_remote"`
-	URL             string                 `json:"url,omitempty" db:"url"`
-	EmploymentType  string                 `json:"employment_type" db:"employment_type"`
-	ExperienceLevel string                 `json:"experience_level" db:"experience_level"`
-	PostedDate      time.Time              `json:"posted_date" db:"posted_date"`
-	ExpiresDate     time.Time              `json:"expires_date" db:"expires_date"`
-	Requirements    []string               `json:"requirements" db:"requirements"`
-	Benefits        []string               `json:"benefits" db:"benefits"`
-	Fields          map[string]interface{} `json:"fields" db:"fields"`
+	ID                 string                 `json:"id" db:"id"`
+	Title              string                 `json:"title" db:"title"`
+	Company            string                 `json:"company" db:"company"`
+	Description        string                 `json:"description" db:"description"`
+	Location           string                 `json:"location" db:"location"`
+	LocationCountry    string                 `json:"location_country,omitempty" db:"location_country"`
+	LocationRegion     string                 `json:"location_region,omitempty" db:"location_region"`
+	LocationCity       string                 `json:"location_city,omitempty" db:"location_city"`
+	Latitude           *float64               `json:"latitude,omitempty" db:"latitude"`
+	Longitude          *float64               `json:"longitude,omitempty" db:"longitude"`
+	Geohash            string                 `json:"geohash,omitempty" db:"geohash"`
+	Salary             string                 `json:"salary" db:"salary"`
+	SalaryMin          *int                   `json:"salary_min,omitempty" db:"salary_min"`
+	SalaryMax          *int                   `json:"salary_max,omitempty" db:"salary_max"`
+	SalaryCurrency     string                 `json:"salary_currency,omitempty" db:"salary_currency"`
+	SalaryAnnualMinEUR *int                   `json:"salary_annual_min_eur,omitempty" db:"salary_annual_min_eur"`
+	SalaryAnnualMaxEUR *int                   `json:"salary_annual_max_eur,omitempty" db:"salary_annual_max_eur"`
+	IsRemote           bool                   `json:"is_remote" db:"is_remote"`
+	RemoteScope        string                 `json:"remote_scope,omitempty" db:"remote_scope"`
+	URL                string                 `json:"url,omitempty" db:"url"`
+	EmploymentType     string                 `json:"employment_type" db:"employment_type"`
+	ExperienceLevel    string                 `json:"experience_level" db:"experience_level"`
+	PostedDate         time.Time              `json:"posted_date" db:"posted_date"`
+	ExpiresDate        time.Time              `json:"expires_date" db:"expires_date"`
+	Requirements       []string               `json:"requirements" db:"requirements"`
+	Benefits           []string               `json:"benefits" db:"benefits"`
+	Fields             map[string]interface{} `json:"fields" db:"fields"`
 }
 
 // JobPostTraditional represents a job posting with fixed schema
@@ -72,8 +81,11 @@ type SyncLog struct {
 	JobsFetched    int       `json:"jobs_fetched" db:"jobs_fetched"`
 	JobsInserted   int       `json:"jobs_inserted" db:"jobs_inserted"`
 	JobsDuplicates int       `json:"jobs_duplicates" db:"jobs_duplicates"`
+	JobsUpdated    int       `json:"jobs_updated,omitempty" db:"jobs_updated"`
+	JobsRemoved    int       `json:"jobs_removed,omitempty" db:"jobs_removed"`
 	Status         string    `json:"status" db:"status"` // success, error, partial
 	ErrorMessage   string    `json:"error_message,omitempty" db:"error_message"`
+	Checkpoint     string    `json:"checkpoint,omitempty" db:"checkpoint"` // Legacy resume position; connectors commit theirs to connector_checkpoints
 	CreatedAt      time.Time `json:"created_at,omitempty" db:"created_at"`
 }
 
@@ -0,0 +1,326 @@
+// Package salary parses the salary texts of job ads into a range, currency and pay
+// period, and annualizes them so salaries from different connectors can be compared.
+// It reads Swedish, English and German formats: "45 000 – 65 000 kr/mån",
+// "£30k-£35k per annum", "ab 4.500 € monatlich", "upp till 40 000 kr".
+package salary
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"openjobs/pkg/models"
+)
+
+// Period is the time a salary amount is paid for
+type Period string
+
+const (
+	Hour  Period = "hour"
+	Day   Period = "day"
+	Week  Period = "week"
+	Month Period = "month"
+	Year  Period = "year"
+)
+
+// perYear is the number of periods in a working year: 52 weeks of 5 days of 8 hours
+var perYear = map[Period]int{Hour: 2080, Day: 260, Week: 52, Month: 12, Year: 1}
+
+// minAmount is the smallest plausible amount per period; smaller numbers in a salary
+// text are notice periods, experience or equity. Without a period only thousands count.
+var minAmount = map[Period]float64{Hour: 5, Day: 30, Week: 100, Month: 300, Year: 3000, "": 1000}
+
+// Salary is a parsed pay range
+type Salary struct {
+	Min      *int
+	Max      *int
+	Currency string // ISO 4217; "" when neither the text nor the caller names one
+	Period   Period // "" when unknown
+}
+
+var (
+	// An amount with space, dot, comma or apostrophe thousands separators, an optional
+	// one or two digit decimal part and an optional thousands suffix
+	amountRe = regexp.MustCompile(`(?:^|[^\p{L}\d])(\d+(?:[ .,'’]\d{3})*(?:[.,]\d{1,2})?)(?:\s*(k|tkr|tsd|tusen)\b)?`)
+	// Units after a number that make it something else than an amount
+	notAmountRe = regexp.MustCompile(`^\+?\s*(?:%|(?:years?|yrs?|år|års|jahre|jahren|hours|timmar|stunden|månader|months|monate|dagar|days|tage)(?:[^\p{L}]|$))`)
+
+	yearRe = regexp.MustCompile(`^(?:19|20)\d\d$`)
+
+	currencyCodeRe = regexp.MustCompile(`(?:^|[^a-z])(sek|nok|dkk|eur|usd|gbp|chf|cad|aud|pln|isk)(?:[^a-z]|$)`)
+	kronorRe       = regexp.MustCompile(`(?:^|[^\p{L}])(?:kr|kronor|tkr)(?:[^\p{L}]|$)|:-`)
+
+	periodRes = []struct {
+		re     *regexp.Regexp
+		period Period
+	}{
+		{regexp.MustCompile(`/\s*(?:h|hr|hour|tim|timme|std|stunde)\b|\bper (?:hour|timme|stunde)\b|\ban hour\b|\bhourly\b|\bi timmen\b|\btimlön|\bstundenlohn|\bpro stunde`), Hour},
+		{regexp.MustCompile(`/\s*(?:d|day|dag|tag)\b|\bper (?:day|dag|tag)\b|\ba day\b|\bdaily\b|\bday rate\b|\bdagslön|\btagessatz|\bpro tag\b`), Day},
+		{regexp.MustCompile(`/\s*(?:wk|week|v|vecka|woche)\b|\bper (?:week|vecka|woche)\b|\ba week\b|\bweekly\b|\bveckolön|\bpro woche|\bwöchentlich`), Week},
+		{regexp.MustCompile(`/\s*(?:mo|mon|month|mån|månad|mnd|monat|mtl)\b|\bper (?:month|mån|månad|monat)\b|\ba month\b|\bmonthly\b|\bi månaden|\bmånadslön|\bmonatlich|\bpro monat|\bmtl\.`), Month},
+		{regexp.MustCompile(`/\s*(?:y|yr|year|a|år|jahr)\b|\bper (?:year|annum|år|jahr)\b|\ba year\b|\bp\.\s?a\b|\bannual|\byearly\b|\bårslön|\bi året|\bjährlich|\bpro jahr`), Year},
+	}
+
+	// What may stand between the two amounts of a range
+	rangeRe = regexp.MustCompile(`^\s*(?:[$€£]|kr|sek|eur|usd|gbp)?\s*(?:-|till|to|bis|och|and|und)\s*(?:[$€£]|kr|sek|eur|usd|gbp)?\s*$`)
+
+	// Sentence and list item boundaries in ad texts; a dot between digits is a separator
+	sentenceRe = regexp.MustCompile(`\n|[;•]|[.!?](?:\s+|$)|(?i)<(?:br|/?p|/?li|/?div|/?h\d)\b[^>]*>`)
+	tagRe      = regexp.MustCompile(`<[^>]*>`)
+	// Words that mark a sentence of an ad text as its salary
+	salaryWordRe = regexp.MustCompile(`salary|\bpay\b|compensation|\bwages?\b|lön|gehalt|vergütung|verdienst|salaire|rémunération|salario|retribución|stipendio|retribuzione|\bloon\b|lønn|løn|palkka|wynagrodzenie`)
+
+	// Words right before a single amount that make it a lower or upper bound
+	fromWords = []string{"från", "from", "ab", "starting at", "minst", "mindestens", "at least"}
+	upToWords = []string{"upp till", "up to", "bis zu", "max", "max.", "maximal", "högst", "höchstens"}
+)
+
+// Parse reads a salary text. currency and period are used when the text names none,
+// e.g. "SEK" and Month for Swedish ads; pass "" to leave them unknown. A text without
+// an amount ("Enligt överenskommelse") returns a Salary without Min and Max.
+func Parse(text, currency string, period Period) Salary {
+	lower := normalize(text)
+	s := Salary{Currency: currency, Period: period}
+	named := false
+	if c := detectCurrency(lower, currency); c != "" {
+		s.Currency, named = c, true
+	}
+	if p := detectPeriod(lower); p != "" {
+		s.Period, named = p, true
+	}
+
+	type amount struct {
+		value      float64
+		start, end int
+	}
+	var amounts []amount
+	for _, m := range amountRe.FindAllStringSubmatchIndex(lower, -1) {
+		if notAmountRe.MatchString(lower[m[1]:]) {
+			continue
+		}
+		raw := lower[m[2]:m[3]]
+		value, ok := parseNumber(raw)
+		if !ok || (!named && m[4] < 0 && yearRe.MatchString(raw)) {
+			// "Tillträde 2026" in a text that names no currency or period
+			continue
+		}
+		if m[4] >= 0 {
+			value *= 1000
+		}
+		amounts = append(amounts, amount{value, m[2], m[1]})
+	}
+
+	isRange := func(a, b amount) bool {
+		return rangeRe.MatchString(lower[a.end:b.start])
+	}
+
+	// "50-60k" and "45-55 000 kr": the first amount shares the second one's thousands
+	if len(amounts) >= 2 && isRange(amounts[0], amounts[1]) &&
+		amounts[0].value < 1000 && amounts[0].value*1000 <= amounts[1].value {
+		amounts[0].value *= 1000
+	}
+
+	var plausible []amount
+	for _, a := range amounts {
+		if a.value >= minAmount[s.Period] {
+			plausible = append(plausible, a)
+		}
+	}
+
+	switch {
+	case len(plausible) == 0:
+		return s
+	case len(plausible) == 1 || !isRange(plausible[0], plausible[1]):
+		// A single amount, or one followed by the same salary in another period
+		value := round(plausible[0].value)
+		before := strings.TrimRight(lower[:plausible[0].start], " $€£:")
+		switch {
+		case hasSuffixWord(before, upToWords):
+			s.Max = &value
+		case hasSuffixWord(before, fromWords):
+			s.Min = &value
+		default:
+			max := value
+			s.Min, s.Max = &value, &max
+		}
+	default:
+		min, max := round(plausible[0].value), round(plausible[1].value)
+		if min > max {
+			min, max = max, min
+		}
+		s.Min, s.Max = &min, &max
+	}
+	return s
+}
+
+// Find looks for the salary in a free ad text such as a description: the first sentence
+// that mentions a salary and names an amount in a currency. It returns the salary and
+// the sentence, or a Salary without amounts and "" when no sentence qualifies. Only the
+// period is used as a fallback; an amount without a currency is too often something else.
+func Find(text string, period Period) (Salary, string) {
+	for _, sentence := range sentenceRe.Split(text, -1) {
+		sentence = strings.Join(strings.Fields(tagRe.ReplaceAllString(sentence, " ")), " ")
+		if !salaryWordRe.MatchString(normalize(sentence)) {
+			continue
+		}
+		if s := Parse(sentence, "", period); s.HasAmount() && s.Currency != "" {
+			return s, sentence
+		}
+	}
+	return Salary{Period: period}, ""
+}
+
+// ParsePeriod reads a period label such as Lever's "per-year-salary", Reed's
+// "per annum" or schema.org's "MONTH". It returns "" for labels it doesn't know.
+func ParsePeriod(label string) Period {
+	lower := normalize(label)
+	for _, unit := range []struct {
+		word   string
+		period Period
+	}{
+		{"hour", Hour}, {"timme", Hour}, {"tim", Hour}, {"stunde", Hour},
+		{"day", Day}, {"daily", Day}, {"dag", Day}, {"tag", Day},
+		{"week", Week}, {"veck", Week}, {"woche", Week},
+		{"month", Month}, {"mån", Month}, {"monat", Month},
+		{"year", Year}, {"annu", Year}, {"år", Year}, {"jahr", Year},
+	} {
+		if strings.Contains(lower, unit.word) {
+			return unit.period
+		}
+	}
+	return ""
+}
+
+// HasAmount reports whether the salary has a lower or upper bound
+func (s Salary) HasAmount() bool {
+	return s.Min != nil || s.Max != nil
+}
+
+// Annual returns the range for a full working year, or nils when the period is unknown
+func (s Salary) Annual() (*int, *int) {
+	n, ok := perYear[s.Period]
+	if !ok {
+		return nil, nil
+	}
+	scale := func(v *int) *int {
+		if v == nil {
+			return nil
+		}
+		annual := *v * n
+		return &annual
+	}
+	return scale(s.Min), scale(s.Max)
+}
+
+// Apply stores the salary on job: the range and currency in their JobPost fields, and
+// the period and annualized range in fields.salary_period, salary_annual_min and
+// salary_annual_max. A salary without an amount leaves job as it is.
+func (s Salary) Apply(job *models.JobPost) {
+	if !s.HasAmount() {
+		return
+	}
+	job.SalaryMin, job.SalaryMax = s.Min, s.Max
+	if s.Currency != "" {
+		job.SalaryCurrency = s.Currency
+	}
+	if s.Period == "" {
+		return
+	}
+
+	if job.Fields == nil {
+		job.Fields = map[string]interface{}{}
+	}
+	job.Fields["salary_period"] = string(s.Period)
+	min, max := s.Annual()
+	if min != nil {
+		job.Fields["salary_annual_min"] = *min
+	}
+	if max != nil {
+		job.Fields["salary_annual_max"] = *max
+	}
+}
+
+// normalize lowercases text and replaces dash and space variants
+func normalize(text string) string {
+	return strings.NewReplacer(
+		"–", "-", "—", "-", "‒", "-", "‐", "-", "‑", "-", "−", "-",
+		"\u00a0", " ", "\u202f", " ", "\u2009", " ",
+	).Replace(strings.ToLower(strings.TrimSpace(text)))
+}
+
+// detectCurrency returns the currency named by a code or symbol. "kr" is SEK unless
+// the caller expects Norwegian or Danish kroner.
+func detectCurrency(lower, fallback string) string {
+	if m := currencyCodeRe.FindStringSubmatch(lower); m != nil {
+		return strings.ToUpper(m[1])
+	}
+	switch {
+	case strings.Contains(lower, "€") || strings.Contains(lower, "euro"):
+		return "EUR"
+	case strings.Contains(lower, "£"):
+		return "GBP"
+	case strings.Contains(lower, "c$") || strings.Contains(lower, "ca$"):
+		return "CAD"
+	case strings.Contains(lower, "a$") || strings.Contains(lower, "au$"):
+		return "AUD"
+	case strings.Contains(lower, "$"):
+		return "USD"
+	case strings.Contains(lower, "chf") || strings.Contains(lower, "fr."):
+		return "CHF"
+	case kronorRe.MatchString(lower):
+		if fallback == "NOK" || fallback == "DKK" {
+			return fallback
+		}
+		return "SEK"
+	}
+	return ""
+}
+
+// detectPeriod returns the period mentioned first, so "45 000 kr/mån (540 000 kr/år)"
+// is monthly
+func detectPeriod(lower string) Period {
+	period, first := Period(""), len(lower)+1
+	for _, p := range periodRes {
+		if loc := p.re.FindStringIndex(lower); loc != nil && loc[0] < first {
+			period, first = p.period, loc[0]
+		}
+	}
+	return period
+}
+
+// parseNumber reads "45 000", "45,000", "45.000,50" and "4,5": a separator followed
+// by one or two digits at the end is a decimal point, any other a thousands separator
+func parseNumber(s string) (float64, bool) {
+	decimals := ""
+	if i := strings.LastIndexAny(s, ".,"); i >= 0 && len(s)-i-1 <= 2 {
+		s, decimals = s[:i], s[i+1:]
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+	if decimals != "" {
+		digits += "." + decimals
+	}
+	value, err := strconv.ParseFloat(digits, 64)
+	return value, err == nil && value > 0
+}
+
+func hasSuffixWord(text string, words []string) bool {
+	for _, word := range words {
+		if strings.HasSuffix(text, word) && (len(text) == len(word) || !isLetter(text[len(text)-len(word)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLetter(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 0x80
+}
+
+func round(value float64) int {
+	return int(value + 0.5)
+}
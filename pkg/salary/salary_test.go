@@ -0,0 +1,112 @@
+package salary
+
+import (
+	"testing"
+
+	"openjobs/pkg/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text     string
+		currency string
+		period   Period
+		min, max int // 0 for nil
+		wantCur  string
+		wantPer  Period
+	}{
+		{"45 000 – 65 000 kr/mån", "", "", 45000, 65000, "SEK", Month},
+		{"45000 - 65000 SEK/månad", "", "", 45000, 65000, "SEK", Month},
+		{"SEK 45,000 - 65,000", "", Month, 45000, 65000, "SEK", Month},
+		{"45-55 000 kr per månad", "", "", 45000, 55000, "SEK", Month},
+		{"Från 30 000 kr", "SEK", Month, 30000, 0, "SEK", Month},
+		{"Upp till 40 000 kr i månaden", "", "", 0, 40000, "SEK", Month},
+		{"35 000 kr/mån (420 000 kr/år)", "", "", 35000, 35000, "SEK", Month},
+		{"Mellan 40 000 kr och 48 000 kr", "SEK", Month, 40000, 48000, "SEK", Month},
+		{"Timlön 165 kr", "SEK", Month, 165, 165, "SEK", Hour},
+		{"Enligt överenskommelse", "SEK", Month, 0, 0, "SEK", Month},
+		{"Fast lön, 6 månaders provanställning", "SEK", Month, 0, 0, "SEK", Month},
+		{"Tillträde 2026", "", "", 0, 0, "", ""},
+		{"$150k-$200k", "", "", 150000, 200000, "USD", ""},
+		{"€60k–€80k + 0.5% equity", "", Year, 60000, 80000, "EUR", Year},
+		{"£30,000 - £35,000 per annum", "", "", 30000, 35000, "GBP", Year},
+		{"$50/hr", "", "", 50, 50, "USD", Hour},
+		{"50-60k", "", "", 50000, 60000, "", ""},
+		{"45.5k EUR p.a.", "", "", 45500, 45500, "EUR", Year},
+		{"ab 4.500 € brutto monatlich", "", "", 4500, 0, "EUR", Month},
+		{"50.000 - 60.000 EUR / Jahr", "", "", 50000, 60000, "EUR", Year},
+		{"bis zu 75.000,50 €", "", "", 0, 75001, "EUR", ""},
+		{"5+ years, competitive salary", "", "", 0, 0, "", ""},
+		{"550 kr per day", "NOK", "", 550, 550, "NOK", Day},
+	}
+	for _, tt := range tests {
+		s := Parse(tt.text, tt.currency, tt.period)
+		if value(s.Min) != tt.min || value(s.Max) != tt.max || s.Currency != tt.wantCur || s.Period != tt.wantPer {
+			t.Errorf("Parse(%q) = %d-%d %q %q, want %d-%d %q %q", tt.text, value(s.Min), value(s.Max), s.Currency, s.Period,
+				tt.min, tt.max, tt.wantCur, tt.wantPer)
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	for label, want := range map[string]Period{
+		"per-year-salary": Year,
+		"per-hour-wage":   Hour,
+		"per annum":       Year,
+		"per day":         Day,
+		"MONTH":           Month,
+		"Annual Salary":   Year,
+		"Fast månadslön":  Month,
+		"":                "",
+	} {
+		if got := ParsePeriod(label); got != want {
+			t.Errorf("ParsePeriod(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		text     string
+		min, max int // 0 for nil
+		wantCur  string
+		wantPer  Period
+		sentence string
+	}{
+		{"We are a team of 1500 people. Salary: €3,500 - €4,200 per month. Apply by 2026-11-01.",
+			3500, 4200, "EUR", Month, "Salary: €3,500 - €4,200 per month"},
+		{"<p>Founded in 2010, 50 000 customers.</p><ul><li>Gehalt: ab 55.000 EUR jährlich</li></ul>",
+			55000, 0, "EUR", Year, "Gehalt: ab 55.000 EUR jährlich"},
+		{"Remote role\nCompensation $140k-$170k + equity", 140000, 170000, "USD", "", "Compensation $140k-$170k + equity"},
+		{"Competitive salary. Revenue of €20 000 000 last year.", 0, 0, "", "", ""},
+		{"Salary 45 000 - 50 000 depending on experience", 0, 0, "", "", ""},
+	}
+	for _, tt := range tests {
+		s, sentence := Find(tt.text, "")
+		if value(s.Min) != tt.min || value(s.Max) != tt.max || s.Currency != tt.wantCur || s.Period != tt.wantPer || sentence != tt.sentence {
+			t.Errorf("Find(%q) = %d-%d %q %q %q, want %d-%d %q %q %q", tt.text, value(s.Min), value(s.Max), s.Currency, s.Period,
+				sentence, tt.min, tt.max, tt.wantCur, tt.wantPer, tt.sentence)
+		}
+	}
+}
+
+func TestApply(t *testing.T) {
+	job := &models.JobPost{SalaryCurrency: "SEK"}
+	Parse("Enligt avtal", "SEK", Month).Apply(job)
+	if job.SalaryMin != nil || job.Fields != nil {
+		t.Errorf("Expected a salary without amounts to leave the job alone: %+v", job)
+	}
+
+	Parse("180 kr/tim", "SEK", Month).Apply(job)
+	if *job.SalaryMin != 180 || job.Fields["salary_period"] != "hour" ||
+		job.Fields["salary_annual_min"] != 374400 || job.Fields["salary_annual_max"] != 374400 {
+		t.Errorf("Unexpected hourly salary: %v %v", *job.SalaryMin, job.Fields)
+	}
+}
+
+func value(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
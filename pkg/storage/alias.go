@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"openjobs/pkg/models"
+)
+
+// ResolveJobAlias returns the current ID of a job ID retired by a rename (see
+// migrations/007_derive_job_ids.sql), or "" when id is not an alias
+func (js *JobStore) ResolveJobAlias(id string) (string, error) {
+	endpoint := fmt.Sprintf("%s/rest/v1/job_id_aliases?select=job_id&alias=eq.%s", js.supabaseURL, url.QueryEscape(id))
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var rows []struct {
+		JobID string `json:"job_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(rows) == 0 {
+		return "", nil
+	}
+	return rows[0].JobID, nil
+}
+
+// GetJobOrAlias returns a job by its ID, or by an old ID it was renamed from
+func (js *JobStore) GetJobOrAlias(id string) (*models.JobPost, error) {
+	job, err := js.GetJob(id)
+	if err == nil || err.Error() != "sql: no rows in result set" {
+		return job, err
+	}
+
+	current, aliasErr := js.ResolveJobAlias(id)
+	if aliasErr != nil {
+		// Before migration 007 there is no alias table; the job is just not found
+		fmt.Printf("⚠️  Failed to resolve job alias %s: %v\n", id, aliasErr)
+		return nil, err
+	}
+	if current == "" || current == id {
+		return nil, err
+	}
+	return js.GetJob(current)
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"openjobs/pkg/models"
+)
+
+// GetCheckpoint returns the committed checkpoint of one connector query, or an empty
+// one (zero watermark, no cursor) when nothing has been committed yet
+func (js *JobStore) GetCheckpoint(connectorID, queryKey string) (*models.Checkpoint, error) {
+	query := fmt.Sprintf("connector_id=eq.%s&query_key=eq.%s", url.QueryEscape(connectorID), url.QueryEscape(queryKey))
+	checkpoints, err := js.getCheckpoints(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(checkpoints) == 0 {
+		return &models.Checkpoint{ConnectorID: connectorID, QueryKey: queryKey}, nil
+	}
+	return &checkpoints[0], nil
+}
+
+// GetCheckpoints lists the checkpoints of a connector, or of every connector when
+// connectorID is empty
+func (js *JobStore) GetCheckpoints(connectorID string) ([]models.Checkpoint, error) {
+	query := ""
+	if connectorID != "" {
+		query = "connector_id=eq." + url.QueryEscape(connectorID)
+	}
+	return js.getCheckpoints(query)
+}
+
+func (js *JobStore) getCheckpoints(query string) ([]models.Checkpoint, error) {
+	endpoint := fmt.Sprintf("%s/rest/v1/connector_checkpoints?select=*&order=connector_id.asc,query_key.asc", js.supabaseURL)
+	if query != "" {
+		endpoint += "&" + query
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+
+	checkpoints := []models.Checkpoint{}
+	if err := json.NewDecoder(resp.Body).Decode(&checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// SaveCheckpoint inserts or replaces the checkpoint of a connector query
+func (js *JobStore) SaveCheckpoint(checkpoint *models.Checkpoint) error {
+	checkpoint.UpdatedAt = time.Now()
+	checkpointJSON, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/rest/v1/connector_checkpoints?on_conflict=connector_id,query_key", js.supabaseURL)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(checkpointJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+	req.Header.Set("Prefer", "resolution=merge-duplicates")
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// AdvanceCheckpoint moves the high watermark of a connector query past the posted
+// dates of jobs and commits it. Call it only once the jobs are stored.
+func (js *JobStore) AdvanceCheckpoint(connectorID, queryKey string, jobs []models.JobPost) error {
+	checkpoint, err := js.GetCheckpoint(connectorID, queryKey)
+	if err != nil {
+		return err
+	}
+	checkpoint.Advance(jobs)
+	return js.SaveCheckpoint(checkpoint)
+}
+
+// DeleteCheckpoints resets a connector so its next sync starts from scratch. With
+// queryKey set only that query is reset.
+func (js *JobStore) DeleteCheckpoints(connectorID string, queryKey *string) error {
+	endpoint := fmt.Sprintf("%s/rest/v1/connector_checkpoints?connector_id=eq.%s", js.supabaseURL, url.QueryEscape(connectorID))
+	if queryKey != nil {
+		endpoint += "&query_key=eq." + url.QueryEscape(*queryKey)
+	}
+
+	req, err := http.NewRequest("DELETE", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
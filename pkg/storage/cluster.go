@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"openjobs/pkg/models"
+)
+
+// GetUnclusteredJobs returns up to limit jobs that have no fields.cluster_id yet,
+// oldest first so the first job seen of a posting represents its cluster. It pages by
+// key: after is the last job of the previous page (nil for the first), so jobs that
+// stay unclustered aren't returned again.
+func (js *JobStore) GetUnclusteredJobs(after *models.JobPost, limit int) ([]*models.JobPost, error) {
+	query := fmt.Sprintf("fields->>cluster_id=is.null&order=posted_date.asc,id.asc&limit=%d", limit)
+	if after != nil {
+		posted := after.PostedDate.UTC().Format(time.RFC3339Nano)
+		query += "&or=" + url.QueryEscape(fmt.Sprintf("(posted_date.gt.%s,and(posted_date.eq.%s,id.gt.%s))", posted, posted, after.ID))
+	}
+	return js.queryJobs(query)
+}
+
+// GetJobsByCompanyKey returns the jobs whose fields.company_key (see pkg/dedup) is key
+// and that were posted between from and to, paged so a large employer isn't cut off at
+// max-rows
+func (js *JobStore) GetJobsByCompanyKey(key string, from, to time.Time) ([]*models.JobPost, error) {
+	jobs := []*models.JobPost{}
+	for offset := 0; ; offset += openJobsPageSize {
+		page, err := js.queryJobs(fmt.Sprintf("fields->>company_key=eq.%s&posted_date=gte.%s&posted_date=lte.%s&order=posted_date.asc,id.asc&limit=%d&offset=%d",
+			url.QueryEscape(key), url.QueryEscape(from.UTC().Format(time.RFC3339)), url.QueryEscape(to.UTC().Format(time.RFC3339)), openJobsPageSize, offset))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+		if len(page) < openJobsPageSize {
+			return jobs, nil
+		}
+	}
+}
+
+// updatedAtLayout is updated_at at Postgres' microsecond precision
+const updatedAtLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// GetClustersUpdatedSince returns the clusters that have a job updated after since
+// (closed, or changed by a sync). updated_at is kept current by migrations/013.
+func (js *JobStore) GetClustersUpdatedSince(since time.Time) ([]string, error) {
+	clusters := []string{}
+	seen := map[string]bool{}
+	for offset := 0; ; offset += openJobsPageSize {
+		page, err := js.queryJobColumns("id,fields", fmt.Sprintf("fields->>cluster_id=not.is.null&updated_at=gt.%s&order=id.asc&limit=%d&offset=%d",
+			url.QueryEscape(since.UTC().Format(updatedAtLayout)), openJobsPageSize, offset))
+		if err != nil {
+			return nil, err
+		}
+		for _, job := range page {
+			if clusterID, _ := job.Fields["cluster_id"].(string); clusterID != "" && !seen[clusterID] {
+				seen[clusterID] = true
+				clusters = append(clusters, clusterID)
+			}
+		}
+		if len(page) < openJobsPageSize {
+			return clusters, nil
+		}
+	}
+}
+
+// GetClusterJobs returns the jobs of one duplicate cluster, oldest first
+func (js *JobStore) GetClusterJobs(clusterID string) ([]*models.JobPost, error) {
+	return js.queryJobs("fields->>cluster_id=eq." + url.QueryEscape(clusterID) + "&order=posted_date.asc,id.asc")
+}
+
+// GetRepresentativeJobs lists jobs like GetAllJobs, with one job per duplicate cluster
+func (js *JobStore) GetRepresentativeJobs(limit, offset int) ([]*models.JobPost, error) {
+	return js.queryJobs(fmt.Sprintf("fields->>duplicate=is.null&order=posted_date.desc&limit=%d&offset=%d", limit, offset))
+}
+
+func (js *JobStore) queryJobs(query string) ([]*models.JobPost, error) {
+	return js.queryJobColumns("*", query)
+}
+
+// queryJobColumns is queryJobs for jobs with only the given columns
+func (js *JobStore) queryJobColumns(columns, query string) ([]*models.JobPost, error) {
+	endpoint := fmt.Sprintf("%s/rest/v1/job_posts?select=%s&%s", js.supabaseURL, columns, query)
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var jobs []*models.JobPost
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return jobs, nil
+}
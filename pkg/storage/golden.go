@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"openjobs/pkg/models"
+)
+
+// SaveGoldenRecord inserts or replaces the golden record of a duplicate cluster
+func (js *JobStore) SaveGoldenRecord(record *models.GoldenRecord) error {
+	record.UpdatedAt = time.Now()
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal golden record: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/rest/v1/job_golden_records?on_conflict=cluster_id", js.supabaseURL)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(recordJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+	req.Header.Set("Prefer", "resolution=merge-duplicates")
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// GetGoldenRecords returns the stored golden records of the given clusters by cluster
+// ID. Clusters of a single job have none.
+func (js *JobStore) GetGoldenRecords(clusterIDs []string) (map[string]*models.GoldenRecord, error) {
+	records := map[string]*models.GoldenRecord{}
+	if len(clusterIDs) == 0 {
+		return records, nil
+	}
+
+	quoted := make([]string, len(clusterIDs))
+	for i, id := range clusterIDs {
+		quoted[i] = `"` + id + `"`
+	}
+	endpoint := fmt.Sprintf("%s/rest/v1/job_golden_records?select=*&cluster_id=in.%s",
+		js.supabaseURL, url.QueryEscape("("+strings.Join(quoted, ",")+")"))
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var rows []*models.GoldenRecord
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	for _, record := range rows {
+		records[record.ClusterID] = record
+	}
+	return records, nil
+}
@@ -6,10 +6,15 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"openjobs/pkg/currency"
+	"openjobs/pkg/geo"
 	"openjobs/pkg/models"
 )
 
@@ -18,6 +23,9 @@ type JobStore struct {
 	supabaseURL string
 	supabaseKey string
 	httpClient  *http.Client
+	rates       *currency.Rates
+	ratesSaved  sync.Once
+	gazetteer   *geo.Gazetteer
 }
 
 // NewJobStore creates a new job store
@@ -26,6 +34,8 @@ func NewJobStore() *JobStore {
 		supabaseURL: os.Getenv("SUPABASE_URL"),
 		supabaseKey: os.Getenv("SUPABASE_ANON_KEY"),
 		httpClient:  &http.Client{},
+		rates:       currency.FromEnv(),
+		gazetteer:   geo.FromEnv(),
 	}
 }
 
@@ -33,6 +43,9 @@ func NewJobStore() *JobStore {
 func (js *JobStore) CreateJob(job *models.JobPost) error {
 	fmt.Printf("📝 Attempting to create job: %s (ID: %s)\n", job.Title, job.ID)
 
+	js.saveActiveRates()
+	js.rates.Normalize(job)
+	js.gazetteer.Normalize(job)
 	jobJSON, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("failed to marshal job: %w", err)
@@ -146,6 +159,9 @@ func (js *JobStore) GetAllJobs(limit, offset int) ([]*models.JobPost, error) {
 
 // UpdateJob updates an existing job in Supabase
 func (js *JobStore) UpdateJob(job *models.JobPost) error {
+	js.saveActiveRates()
+	js.rates.Normalize(job)
+	js.gazetteer.Normalize(job)
 	jobJSON, err := json.Marshal(job)
 	if err != nil {
 		return fmt.Errorf("failed to marshal job: %w", err)
@@ -390,3 +406,85 @@ func (js *JobStore) GetMostRecentJob(idPrefix string) (*models.JobPost, error) {
 
 	return &jobs[0], nil
 }
+
+// GetPluginConfig retrieves the config JSONB of a row in the plugins table
+func (js *JobStore) GetPluginConfig(pluginID string) (map[string]interface{}, error) {
+	endpoint := fmt.Sprintf("%s/rest/v1/plugins?select=config&id=eq.%s", js.supabaseURL, url.QueryEscape(pluginID))
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var rows []struct {
+		Config map[string]interface{} `json:"config"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(rows) == 0 || rows[0].Config == nil {
+		return nil, fmt.Errorf("no config found for plugin %s", pluginID)
+	}
+
+	return rows[0].Config, nil
+}
+
+// CloseJob marks a job as no longer open: expires_date is set to closedAt and the
+// reason is recorded in fields. Returns (false, nil) when the job is not stored.
+func (js *JobStore) CloseJob(id string, closedAt time.Time, reason string) (bool, error) {
+	job, err := js.GetJob(id)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return false, nil
+		}
+		return false, err
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	if job.Fields == nil {
+		job.Fields = map[string]interface{}{}
+	}
+	job.ExpiresDate = closedAt
+	job.Fields["closed"] = true
+	job.Fields["closed_at"] = closedAt
+	job.Fields["closed_reason"] = reason
+
+	if err := js.UpdateJob(job); err != nil {
+		return false, fmt.Errorf("failed to close job %s: %w", id, err)
+	}
+	return true, nil
+}
+
+// GetJobsByField retrieves the jobs whose fields JSONB has key = value, paging past
+// PostgREST's max-rows
+func (js *JobStore) GetJobsByField(key, value string) ([]*models.JobPost, error) {
+	jobs := []*models.JobPost{}
+	for offset := 0; ; offset += openJobsPageSize {
+		page, err := js.queryJobs(fmt.Sprintf("fields->>%s=eq.%s&order=id.asc&limit=%d&offset=%d",
+			url.QueryEscape(key), url.QueryEscape(value), openJobsPageSize, offset))
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, page...)
+		if len(page) < openJobsPageSize {
+			return jobs, nil
+		}
+	}
+}
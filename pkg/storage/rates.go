@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+
+	"openjobs/pkg/currency"
+	"openjobs/pkg/models"
+)
+
+// exchangeRate is one row of the exchange_rates table
+type exchangeRate struct {
+	Version  string    `json:"version"`
+	Currency string    `json:"currency"`
+	PerEUR   float64   `json:"per_eur"`
+	LoadedAt time.Time `json:"loaded_at"`
+}
+
+// SaveExchangeRates stores a version of the exchange-rate table, so the
+// salary_rates_version of a job can be traced back to its rates
+func (js *JobStore) SaveExchangeRates(rates *currency.Rates) error {
+	codes := make([]string, 0, len(rates.Rates))
+	for code := range rates.Rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	rows := make([]exchangeRate, 0, len(codes))
+	now := time.Now()
+	for _, code := range codes {
+		rows = append(rows, exchangeRate{Version: rates.Version, Currency: code, PerEUR: rates.Rates[code], LoadedAt: now})
+	}
+	rowsJSON, err := json.Marshal(rows)
+	if err != nil {
+		return fmt.Errorf("failed to marshal exchange rates: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/rest/v1/exchange_rates?on_conflict=version,currency", js.supabaseURL)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(rowsJSON))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+	req.Header.Set("Prefer", "resolution=merge-duplicates")
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// saveActiveRates stores the store's exchange-rate table the first time a job is
+// normalized with it, so every salary_rates_version stamped on a job has its rows in
+// exchange_rates. A failure is logged; the jobs are stored regardless.
+func (js *JobStore) saveActiveRates() {
+	js.ratesSaved.Do(func() {
+		if js.rates == nil {
+			return
+		}
+		if err := js.SaveExchangeRates(js.rates); err != nil {
+			fmt.Printf("⚠️  Failed to store exchange rates %s: %v\n", js.rates.Version, err)
+		}
+	})
+}
+
+// GetSalarySummary returns the average annual salary in euros over all jobs that have
+// one (get_salary_summary in migrations/010)
+func (js *JobStore) GetSalarySummary() (*models.SalarySummary, error) {
+	endpoint := fmt.Sprintf("%s/rest/v1/rpc/get_salary_summary", js.supabaseURL)
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBufferString("{}"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+	req.Header.Set("apikey", js.supabaseKey)
+
+	resp, err := js.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var rows []models.SalarySummary
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(rows) == 0 {
+		return &models.SalarySummary{}, nil
+	}
+	return &rows[0], nil
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"openjobs/pkg/geo"
+	"openjobs/pkg/models"
+)
+
+// JobFilter narrows SearchJobs; zero values don't filter
+type JobFilter struct {
+	Collapse     bool // One job per duplicate cluster, like GetRepresentativeJobs
+	MinSalaryEUR int  // Lower bound of the annual salary in euros is at least this
+	MaxSalaryEUR int  // Upper bound of the annual salary in euros is at most this
+
+	// Structured location (see pkg/geo); names are resolved with the gazetteer, so
+	// "Sverige" and "Göteborg" find the jobs stored as SE and Gothenburg
+	Country     string
+	Region      string
+	City        string
+	RemoteScope string
+
+	// Near limits the jobs to RadiusKm around a point and sorts them by distance
+	Near     *geo.Point
+	RadiusKm float64
+}
+
+// maxNearCells bounds the geohash cells a radius search queries; fewer cells are
+// larger and fetch more candidates outside the circle
+const maxNearCells = 16
+
+// kmPerDegree is the length of a degree of latitude on geo.Distance's sphere, rounded
+// down so the latitude band of a radius search errs on the wide side
+const kmPerDegree = 111.19
+
+// SearchJobs lists jobs like GetAllJobs, newest first, narrowed by filter. With
+// filter.Near they come nearest first, with their distance in fields.distance_km.
+func (js *JobStore) SearchJobs(filter JobFilter, limit, offset int) ([]*models.JobPost, error) {
+	query := js.filterQuery(filter)
+	if filter.Near != nil {
+		return js.searchNear(query, *filter.Near, filter.RadiusKm, limit, offset)
+	}
+	query = append(query, fmt.Sprintf("order=posted_date.desc,id.asc&limit=%d&offset=%d", limit, offset))
+	return js.queryJobs(strings.Join(query, "&"))
+}
+
+// Gazetteer returns the gazetteer the store resolves job locations with
+func (js *JobStore) Gazetteer() *geo.Gazetteer {
+	return js.gazetteer
+}
+
+// filterQuery returns the PostgREST filters of filter
+func (js *JobStore) filterQuery(filter JobFilter) []string {
+	query := []string{}
+	if filter.Collapse {
+		query = append(query, "fields->>duplicate=is.null")
+	}
+	if filter.MinSalaryEUR > 0 {
+		query = append(query, fmt.Sprintf("salary_annual_min_eur=gte.%d", filter.MinSalaryEUR))
+	}
+	if filter.MaxSalaryEUR > 0 {
+		query = append(query, fmt.Sprintf("salary_annual_max_eur=lte.%d", filter.MaxSalaryEUR))
+	}
+	if filter.Country != "" {
+		country := js.gazetteer.CountryCode(filter.Country)
+		if country == "" {
+			country = strings.ToUpper(filter.Country)
+		}
+		query = append(query, "location_country=eq."+url.QueryEscape(country))
+	}
+	if filter.Region != "" {
+		region := filter.Region
+		if loc := js.gazetteer.Resolve(region, false); loc.Region != "" {
+			region = loc.Region
+		}
+		query = append(query, "location_region=ilike."+url.QueryEscape(region))
+	}
+	if filter.City != "" {
+		city := filter.City
+		if loc := js.gazetteer.Resolve(city, false); loc.City != "" {
+			city = loc.City
+		}
+		query = append(query, "location_city=ilike."+url.QueryEscape(city))
+	}
+	if filter.RemoteScope != "" {
+		query = append(query, "remote_scope=eq."+url.QueryEscape(strings.ToLower(filter.RemoteScope)))
+	}
+	return query
+}
+
+// searchNear finds the jobs within radiusKm of center: the coordinates of the jobs in
+// the geohash cells covering the circle first, then the page of the nearest ones
+func (js *JobStore) searchNear(query []string, center geo.Point, radiusKm float64, limit, offset int) ([]*models.JobPost, error) {
+	cells := geo.CoveringCells(center, radiusKm, maxNearCells)
+	prefixes := make([]string, len(cells))
+	for i, cell := range cells {
+		prefixes[i] = "geohash.like." + cell + "*"
+	}
+	query = append(query, "or="+url.QueryEscape("("+strings.Join(prefixes, ",")+")"))
+	// The latitude band narrows the cells' corners, and the whole table when the circle
+	// needs more than maxNearCells cells of any precision
+	latDelta := radiusKm / kmPerDegree
+	query = append(query, fmt.Sprintf("latitude=gte.%f&latitude=lte.%f", center.Lat-latDelta, center.Lat+latDelta))
+
+	// Page through the candidates, since PostgREST caps a response at db-max-rows
+	candidates := []*models.JobPost{}
+	for from := 0; ; from += openJobsPageSize {
+		page, err := js.queryJobColumns("id,latitude,longitude",
+			strings.Join(query, "&")+fmt.Sprintf("&order=id.asc&limit=%d&offset=%d", openJobsPageSize, from))
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, page...)
+		if len(page) < openJobsPageSize {
+			break
+		}
+	}
+
+	type hit struct {
+		id string
+		km float64
+	}
+	hits := []hit{}
+	for _, job := range candidates {
+		if job.Latitude == nil || job.Longitude == nil {
+			continue
+		}
+		if km := geo.Distance(center.Lat, center.Lon, *job.Latitude, *job.Longitude); km <= radiusKm {
+			hits = append(hits, hit{job.ID, km})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].km != hits[j].km {
+			return hits[i].km < hits[j].km
+		}
+		return hits[i].id < hits[j].id
+	})
+	if offset >= len(hits) {
+		return []*models.JobPost{}, nil
+	}
+	hits = hits[offset:]
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	quoted := make([]string, len(hits))
+	for i, h := range hits {
+		quoted[i] = `"` + h.id + `"`
+	}
+	jobs, err := js.queryJobs("id=in." + url.QueryEscape("("+strings.Join(quoted, ",")+")"))
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]*models.JobPost{}
+	for _, job := range jobs {
+		byID[job.ID] = job
+	}
+
+	result := make([]*models.JobPost, 0, len(hits))
+	for _, h := range hits {
+		job, ok := byID[h.id]
+		if !ok {
+			continue // Deleted in between
+		}
+		if job.Fields == nil {
+			job.Fields = map[string]interface{}{}
+		}
+		job.Fields["distance_km"] = math.Round(h.km*10) / 10
+		result = append(result, job)
+	}
+	return result, nil
+}
+
+// GetJobsAfter pages through all jobs by ID: it returns up to limit jobs whose ID
+// sorts after afterID ("" for the first page)
+func (js *JobStore) GetJobsAfter(afterID string, limit int) ([]*models.JobPost, error) {
+	query := fmt.Sprintf("order=id.asc&limit=%d", limit)
+	if afterID != "" {
+		query = "id=gt." + url.QueryEscape(afterID) + "&" + query
+	}
+	return js.queryJobs(query)
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// openJobsPageSize stays under PostgREST's default max-rows
+const openJobsPageSize = 1000
+
+// GetOpenJobIDs returns the IDs of a connector's stored jobs that are not closed
+func (js *JobStore) GetOpenJobIDs(connectorID string) ([]string, error) {
+	ids := []string{}
+	for offset := 0; ; offset += openJobsPageSize {
+		endpoint := fmt.Sprintf("%s/rest/v1/job_posts?select=id&fields->>connector=eq.%s&fields->>closed=is.null&order=id.asc&limit=%d&offset=%d",
+			js.supabaseURL, url.QueryEscape(connectorID), openJobsPageSize, offset)
+
+		req, err := http.NewRequest("GET", endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", js.supabaseKey))
+		req.Header.Set("apikey", js.supabaseKey)
+
+		resp, err := js.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute request: %w", err)
+		}
+
+		if resp.StatusCode >= 400 {
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("supabase error %d: %s", resp.StatusCode, string(body))
+		}
+
+		var rows []struct {
+			ID string `json:"id"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&rows)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+		}
+		if len(rows) < openJobsPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// CloseMissingJobs diffs a full-list source against the store: open jobs of the
+// connector whose IDs are not in listed (the complete current listing) are closed with
+// reason "missing_from_feed". Returns the closed IDs. An empty listing is an error, so
+// an upstream outage never closes the whole inventory.
+func (js *JobStore) CloseMissingJobs(connectorID string, listed []string, closedAt time.Time) ([]string, error) {
+	if len(listed) == 0 {
+		return nil, fmt.Errorf("refusing to close every %s job: the listing is empty", connectorID)
+	}
+
+	open, err := js.GetOpenJobIDs(connectorID)
+	if err != nil {
+		return nil, err
+	}
+
+	present := make(map[string]bool, len(listed))
+	for _, id := range listed {
+		present[id] = true
+	}
+
+	closed := []string{}
+	for _, id := range open {
+		if present[id] {
+			continue
+		}
+		ok, err := js.CloseJob(id, closedAt, "missing_from_feed")
+		if err != nil {
+			return closed, err
+		}
+		if ok {
+			closed = append(closed, id)
+		}
+	}
+	return closed, nil
+}
@@ -0,0 +1,589 @@
+// Package storagetest provides an in-memory stand-in for the Supabase REST API, so
+// storage.JobStore and the connectors using it can be tested without a database.
+package storagetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+// updatedAtLayout is how the server stamps job_posts.updated_at on updates, at
+// Postgres' precision so the timestamps compare as strings
+const updatedAtLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// Server keeps PostgREST tables in memory. It understands the subset of the API the
+// job store uses: eq/neq/like/is/in and comparison filters (including fields->>key)
+// and or=(...) of them (with nested and(...)), order, limit, offset, select and exact counts. Every table is
+// keyed by "id"; inserts with on_conflict and Prefer: resolution=merge-duplicates are
+// upserts on those columns. Updates to job_posts stamp updated_at like the trigger in
+// migrations/013.
+type Server struct {
+	*httptest.Server
+
+	// MaxRows caps every response like PostgREST's db-max-rows (Supabase: 1000), so
+	// reads that don't page are caught. 0 means unlimited.
+	MaxRows int
+
+	mu     sync.Mutex
+	tables map[string][]map[string]interface{}
+	nextID int
+}
+
+// NewServer starts an empty server and points SUPABASE_URL and SUPABASE_ANON_KEY at
+// it for the rest of the test
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{MaxRows: 1000, tables: map[string][]map[string]interface{}{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	t.Cleanup(s.Close)
+
+	t.Setenv("SUPABASE_URL", s.URL)
+	t.Setenv("SUPABASE_ANON_KEY", "storagetest")
+	return s
+}
+
+// Store returns a job store backed by the server
+func (s *Server) Store() *storage.JobStore {
+	return storage.NewJobStore()
+}
+
+// Rows returns a copy of a table's rows in insertion order
+func (s *Server) Rows(table string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := make([]map[string]interface{}, 0, len(s.tables[table]))
+	for _, row := range s.tables[table] {
+		rows = append(rows, copyRow(row))
+	}
+	return rows
+}
+
+// Jobs returns the stored job posts
+func (s *Server) Jobs() []models.JobPost {
+	jobs := []models.JobPost{}
+	s.decode("job_posts", &jobs)
+	return jobs
+}
+
+// SyncLogs returns the stored sync logs, oldest first
+func (s *Server) SyncLogs() []models.SyncLog {
+	logs := []models.SyncLog{}
+	s.decode("sync_logs", &logs)
+	return logs
+}
+
+// Checkpoints returns the committed connector checkpoints
+func (s *Server) Checkpoints() []models.Checkpoint {
+	checkpoints := []models.Checkpoint{}
+	s.decode("connector_checkpoints", &checkpoints)
+	return checkpoints
+}
+
+// Insert adds rows to a table directly, e.g. to seed plugin configs
+func (s *Server) Insert(table string, rows ...interface{}) error {
+	for _, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return fmt.Errorf("failed to encode row: %w", err)
+		}
+		if status, err := s.insert(table, data, nil); err != nil {
+			return fmt.Errorf("insert into %s failed (%d): %w", table, status, err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) decode(table string, v interface{}) {
+	data, _ := json.Marshal(s.Rows(table))
+	json.Unmarshal(data, v)
+}
+
+var tablePathRe = regexp.MustCompile(`^/rest/v1/([a-z_]+)$`)
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	m := tablePathRe.FindStringSubmatch(r.URL.Path)
+	if m == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown path %s", r.URL.Path))
+		return
+	}
+	table := m[1]
+
+	filters, err := parseFilters(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.mu.Lock()
+		rows := matching(s.tables[table], filters)
+		s.mu.Unlock()
+
+		rows, err = orderRows(rows, r.URL.Query().Get("order"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		total := len(rows)
+		rows = page(rows, r.URL.Query().Get("offset"), r.URL.Query().Get("limit"))
+		if s.MaxRows > 0 && len(rows) > s.MaxRows {
+			rows = rows[:s.MaxRows]
+		}
+
+		if strings.Contains(r.Header.Get("Prefer"), "count=exact") {
+			w.Header().Set("Content-Range", contentRange(r.URL.Query().Get("offset"), len(rows), total))
+		}
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		writeJSON(w, http.StatusOK, project(rows, r.URL.Query().Get("select")))
+
+	case http.MethodPost:
+		body, _ := io.ReadAll(r.Body)
+		var onConflict []string
+		if strings.Contains(r.Header.Get("Prefer"), "resolution=merge-duplicates") && r.URL.Query().Get("on_conflict") != "" {
+			onConflict = strings.Split(r.URL.Query().Get("on_conflict"), ",")
+		}
+		if status, err := s.insert(table, body, onConflict); err != nil {
+			writeError(w, status, err)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodPatch:
+		var patch map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		s.mu.Lock()
+		for _, row := range s.tables[table] {
+			if matches(row, filters) {
+				for k, v := range patch {
+					row[k] = v
+				}
+				if table == "job_posts" {
+					row["updated_at"] = time.Now().UTC().Format(updatedAtLayout)
+				}
+			}
+		}
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodDelete:
+		s.mu.Lock()
+		kept := []map[string]interface{}{}
+		for _, row := range s.tables[table] {
+			if !matches(row, filters) {
+				kept = append(kept, row)
+			}
+		}
+		s.tables[table] = kept
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not supported", r.Method))
+	}
+}
+
+// insert adds one object or an array of objects. Like the primary keys in the real
+// schema, a duplicate id is a conflict; rows without one get a generated id. Rows
+// matching an existing one on all onConflict columns are merged into it instead.
+func (s *Server) insert(table string, body []byte, onConflict []string) (int, error) {
+	var rows []map[string]interface{}
+	if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+		if err := json.Unmarshal(body, &rows); err != nil {
+			return http.StatusBadRequest, err
+		}
+	} else {
+		var row map[string]interface{}
+		if err := json.Unmarshal(body, &row); err != nil {
+			return http.StatusBadRequest, err
+		}
+		rows = append(rows, row)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, row := range rows {
+		if existing := findRow(s.tables[table], row, onConflict); existing != nil {
+			for k, v := range row {
+				existing[k] = v
+			}
+			continue
+		}
+		if id, ok := row["id"]; ok && id != "" && id != nil {
+			for _, existing := range s.tables[table] {
+				if fmt.Sprint(existing["id"]) == fmt.Sprint(id) {
+					return http.StatusConflict, fmt.Errorf(`duplicate key value violates unique constraint "%s_pkey"`, table)
+				}
+			}
+		} else {
+			s.nextID++
+			row["id"] = strconv.Itoa(s.nextID)
+		}
+		if _, ok := row["created_at"]; !ok {
+			row["created_at"] = time.Now().UTC().Format(time.RFC3339Nano)
+		}
+		s.tables[table] = append(s.tables[table], row)
+	}
+	return http.StatusCreated, nil
+}
+
+// findRow returns the row with the same values in all columns, if any
+func findRow(rows []map[string]interface{}, row map[string]interface{}, columns []string) map[string]interface{} {
+	if len(columns) == 0 {
+		return nil
+	}
+	for _, existing := range rows {
+		same := true
+		for _, column := range columns {
+			if fmt.Sprint(existing[column]) != fmt.Sprint(row[column]) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return existing
+		}
+	}
+	return nil
+}
+
+// filter is one column condition such as status=eq.success or fields->>source=eq.x
+type filter struct {
+	path  []string
+	op    string
+	value string
+	not   bool
+	any   []filter // Conditions of an or=(...) or and(...) group
+}
+
+var reservedParams = map[string]bool{"select": true, "order": true, "limit": true, "offset": true, "on_conflict": true}
+
+func parseFilters(query url.Values) ([]filter, error) {
+	filters := []filter{}
+	for column, values := range query {
+		if reservedParams[column] {
+			continue
+		}
+		for _, raw := range values {
+			if column == "or" {
+				f, err := parseGroup("or", raw)
+				if err != nil {
+					return nil, err
+				}
+				filters = append(filters, f)
+				continue
+			}
+			f, err := parseFilter(column, raw)
+			if err != nil {
+				return nil, err
+			}
+			filters = append(filters, f)
+		}
+	}
+	return filters, nil
+}
+
+func parseFilter(column, raw string) (filter, error) {
+	f := filter{path: strings.Split(strings.ReplaceAll(column, "->>", "->"), "->")}
+	if strings.HasPrefix(raw, "not.") {
+		f.not = true
+		raw = strings.TrimPrefix(raw, "not.")
+	}
+	op, value, ok := strings.Cut(raw, ".")
+	if !ok {
+		return f, fmt.Errorf("invalid filter %s=%s", column, raw)
+	}
+	switch op {
+	case "eq", "neq", "gt", "gte", "lt", "lte", "like", "ilike", "is", "in":
+	default:
+		return f, fmt.Errorf("unsupported operator %q in %s", op, column)
+	}
+	f.op, f.value = op, value
+	return f, nil
+}
+
+// parseGroup reads the (col.op.value,...) of or=, where an item may itself be an
+// and(...) or or(...) group; values can't contain commas or parentheses
+func parseGroup(op, raw string) (filter, error) {
+	if !strings.HasPrefix(raw, "(") || !strings.HasSuffix(raw, ")") {
+		return filter{}, fmt.Errorf("invalid filter %s=%s", op, raw)
+	}
+	f := filter{op: op}
+	for _, part := range splitGroup(raw[1 : len(raw)-1]) {
+		var sub filter
+		var err error
+		if group, rest, ok := strings.Cut(part, "("); ok && (group == "and" || group == "or") {
+			sub, err = parseGroup(group, "("+rest)
+		} else if column, rest, ok := strings.Cut(part, "."); ok {
+			sub, err = parseFilter(column, rest)
+		} else {
+			err = fmt.Errorf("invalid filter %s=%s", op, raw)
+		}
+		if err != nil {
+			return f, err
+		}
+		f.any = append(f.any, sub)
+	}
+	return f, nil
+}
+
+// splitGroup splits the items of a group at the commas outside nested groups
+func splitGroup(inner string) []string {
+	parts := []string{}
+	depth, start := 0, 0
+	for i, r := range inner {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, inner[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, inner[start:])
+}
+
+func matching(rows []map[string]interface{}, filters []filter) []map[string]interface{} {
+	out := []map[string]interface{}{}
+	for _, row := range rows {
+		if matches(row, filters) {
+			out = append(out, copyRow(row))
+		}
+	}
+	return out
+}
+
+func matches(row map[string]interface{}, filters []filter) bool {
+	for _, f := range filters {
+		if !f.match(row) {
+			return false
+		}
+	}
+	return true
+}
+
+// match evaluates a condition, or an or/and group of them, against a row
+func (f filter) match(row map[string]interface{}) bool {
+	switch f.op {
+	case "or":
+		for _, sub := range f.any {
+			if sub.match(row) {
+				return true
+			}
+		}
+		return false
+	case "and":
+		return matches(row, f.any)
+	}
+	return f.eval(lookup(row, f.path)) != f.not
+}
+
+func (f filter) eval(v interface{}) bool {
+	if f.op == "is" {
+		switch f.value {
+		case "null":
+			return v == nil
+		case "true", "false":
+			return fmt.Sprint(v) == f.value
+		}
+		return false
+	}
+	if v == nil {
+		return false
+	}
+
+	s := scalar(v)
+	switch f.op {
+	case "eq":
+		return compare(s, f.value) == 0
+	case "neq":
+		return compare(s, f.value) != 0
+	case "gt":
+		return compare(s, f.value) > 0
+	case "gte":
+		return compare(s, f.value) >= 0
+	case "lt":
+		return compare(s, f.value) < 0
+	case "lte":
+		return compare(s, f.value) <= 0
+	case "like", "ilike":
+		pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(f.value), `\*`, ".*") + "$"
+		pattern = strings.ReplaceAll(pattern, "%", ".*")
+		if f.op == "ilike" {
+			pattern = "(?i)" + pattern
+		}
+		return regexp.MustCompile(pattern).MatchString(s)
+	case "in":
+		for _, item := range strings.Split(strings.Trim(f.value, "()"), ",") {
+			if compare(s, strings.Trim(item, `"`)) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookup follows a column path into JSONB values
+func lookup(row map[string]interface{}, path []string) interface{} {
+	var v interface{} = row
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func scalar(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+// compare orders timestamps by time and numbers numerically, like the typed columns
+func compare(a, b string) int {
+	if ta, err := time.Parse(time.RFC3339Nano, a); err == nil {
+		if tb, err := time.Parse(time.RFC3339Nano, b); err == nil {
+			return ta.Compare(tb)
+		}
+	}
+	if fa, err := strconv.ParseFloat(a, 64); err == nil {
+		if fb, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+// orderRows applies order=col.desc,col2.asc; nulls sort last like in Postgres
+func orderRows(rows []map[string]interface{}, order string) ([]map[string]interface{}, error) {
+	if order == "" {
+		return rows, nil
+	}
+	type key struct {
+		path []string
+		desc bool
+	}
+	keys := []key{}
+	for _, part := range strings.Split(order, ",") {
+		column, dir, _ := strings.Cut(part, ".")
+		if dir != "" && dir != "asc" && dir != "desc" {
+			return nil, fmt.Errorf("unsupported order %q", part)
+		}
+		keys = append(keys, key{path: strings.Split(strings.ReplaceAll(column, "->>", "->"), "->"), desc: dir == "desc"})
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, k := range keys {
+			a, b := lookup(rows[i], k.path), lookup(rows[j], k.path)
+			if a == nil || b == nil {
+				if (a == nil) != (b == nil) {
+					return b == nil
+				}
+				continue
+			}
+			if c := compare(scalar(a), scalar(b)); c != 0 {
+				return (c < 0) != k.desc
+			}
+		}
+		return false
+	})
+	return rows, nil
+}
+
+func page(rows []map[string]interface{}, offset, limit string) []map[string]interface{} {
+	if n, err := strconv.Atoi(offset); err == nil && n > 0 {
+		if n >= len(rows) {
+			return []map[string]interface{}{}
+		}
+		rows = rows[n:]
+	}
+	if n, err := strconv.Atoi(limit); err == nil && n >= 0 && n < len(rows) {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+func contentRange(offset string, count, total int) string {
+	start, _ := strconv.Atoi(offset)
+	if count == 0 {
+		return fmt.Sprintf("*/%d", total)
+	}
+	return fmt.Sprintf("%d-%d/%d", start, start+count-1, total)
+}
+
+// project keeps the selected columns; "*" and count selects return whole rows
+func project(rows []map[string]interface{}, sel string) []map[string]interface{} {
+	if sel == "" || sel == "*" || sel == "count" {
+		return rows
+	}
+	columns := strings.Split(sel, ",")
+	out := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		projected := map[string]interface{}{}
+		for _, column := range columns {
+			column = strings.TrimSpace(column)
+			if v, ok := row[column]; ok {
+				projected[column] = v
+			}
+		}
+		out = append(out, projected)
+	}
+	return out
+}
+
+func copyRow(row map[string]interface{}) map[string]interface{} {
+	data, _ := json.Marshal(row)
+	var out map[string]interface{}
+	json.Unmarshal(data, &out)
+	return out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"message": err.Error()})
+}
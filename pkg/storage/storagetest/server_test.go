@@ -0,0 +1,412 @@
+package storagetest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"openjobs/pkg/currency"
+	"openjobs/pkg/geo"
+	"openjobs/pkg/models"
+	"openjobs/pkg/storage"
+)
+
+func TestJobStoreAgainstServer(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+
+	posted := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, id := range []string{"af-1", "af-2", "remotive-1"} {
+		job := &models.JobPost{
+			ID:         id,
+			Title:      "Developer",
+			IsRemote:   id == "remotive-1",
+			PostedDate: posted.AddDate(0, 0, i),
+			Fields:     map[string]interface{}{"source": "test", "original_id": id},
+		}
+		if err := store.CreateJob(job); err != nil {
+			t.Fatalf("CreateJob(%s) failed: %v", id, err)
+		}
+	}
+	if err := store.CreateJob(&models.JobPost{ID: "af-1"}); err == nil {
+		t.Error("Expected a conflict for a duplicate ID")
+	}
+
+	if job, err := store.GetJob("af-2"); err != nil || job.ID != "af-2" {
+		t.Errorf("GetJob() = %v, %v", job, err)
+	}
+	if _, err := store.GetJob("missing"); err == nil || err.Error() != "sql: no rows in result set" {
+		t.Errorf("Expected no rows error, got %v", err)
+	}
+	if job, err := store.GetMostRecentJob("af-"); err != nil || job.ID != "af-2" {
+		t.Errorf("GetMostRecentJob() = %v, %v", job, err)
+	}
+	if jobs, err := store.GetJobsByField("original_id", "remotive-1"); err != nil || len(jobs) != 1 {
+		t.Errorf("GetJobsByField() = %d jobs, %v", len(jobs), err)
+	}
+	if n, err := store.GetTotalJobCount(); err != nil || n != 3 {
+		t.Errorf("GetTotalJobCount() = %d, %v", n, err)
+	}
+	if n, err := store.GetRemoteJobCount(); err != nil || n != 1 {
+		t.Errorf("GetRemoteJobCount() = %d, %v", n, err)
+	}
+
+	if closed, err := store.CloseJob("af-1", posted, "removed"); err != nil || !closed {
+		t.Errorf("CloseJob() = %v, %v", closed, err)
+	}
+	if job, _ := store.GetJob("af-1"); job.Fields["closed_reason"] != "removed" || !job.ExpiresDate.Equal(posted) {
+		t.Errorf("Job not closed: %+v", job)
+	}
+	if err := store.DeleteJob("af-1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.Jobs()) != 2 {
+		t.Errorf("Expected 2 jobs after delete, got %d", len(server.Jobs()))
+	}
+
+	for _, log := range []models.SyncLog{
+		{ConnectorName: "af", StartedAt: posted, Status: "success"},
+		{ConnectorName: "af", StartedAt: posted.Add(time.Hour), Status: "error"},
+		{ConnectorName: "af", StartedAt: posted.Add(2 * time.Hour), Status: "success"},
+	} {
+		if err := store.LogSync(&log); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if logs, err := store.GetRecentSyncLogs(2); err != nil || len(logs) != 2 || logs[0].Status != "success" {
+		t.Errorf("GetRecentSyncLogs() = %+v, %v", logs, err)
+	}
+}
+
+func TestPluginConfig(t *testing.T) {
+	server := NewServer(t)
+	if err := server.Insert("plugins", map[string]interface{}{"id": "htmlscraper", "config": map[string]interface{}{"name": "x"}}); err != nil {
+		t.Fatal(err)
+	}
+	config, err := server.Store().GetPluginConfig("htmlscraper")
+	if err != nil || config["name"] != "x" {
+		t.Errorf("GetPluginConfig() = %v, %v", config, err)
+	}
+}
+
+func TestCheckpoints(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+
+	checkpoint, err := store.GetCheckpoint("remotive", "")
+	if err != nil || !checkpoint.HighWatermark.IsZero() || checkpoint.ConnectorID != "remotive" {
+		t.Fatalf("GetCheckpoint() = %+v, %v", checkpoint, err)
+	}
+
+	posted := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	jobs := []models.JobPost{
+		{ID: "remotive-1", PostedDate: posted},
+		{ID: "remotive-2", PostedDate: posted.Add(time.Hour)},
+		{ID: "remotive-3", PostedDate: posted.Add(48 * time.Hour), Fields: map[string]interface{}{"posted_date_estimated": true}},
+	}
+	if err := store.AdvanceCheckpoint("remotive", "", jobs); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AdvanceCheckpoint("remotive", "", jobs[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveCheckpoint(&models.Checkpoint{ConnectorID: "af", QueryKey: "jobstream", Cursor: "c1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(server.Checkpoints()); n != 2 {
+		t.Fatalf("Expected 2 checkpoints after upserts, got %d", n)
+	}
+	checkpoint, _ = store.GetCheckpoint("remotive", "")
+	if want := posted.Add(time.Hour); !checkpoint.HighWatermark.Equal(want) {
+		t.Errorf("HighWatermark = %v, want %v (estimated dates and older runs never move it)", checkpoint.HighWatermark, want)
+	}
+	if checkpoint, _ := store.GetCheckpoint("af", "jobstream"); checkpoint.Cursor != "c1" {
+		t.Errorf("Cursor = %q, want c1", checkpoint.Cursor)
+	}
+
+	if err := store.DeleteCheckpoints("remotive", nil); err != nil {
+		t.Fatal(err)
+	}
+	if checkpoints, err := store.GetCheckpoints(""); err != nil || len(checkpoints) != 1 || checkpoints[0].ConnectorID != "af" {
+		t.Errorf("GetCheckpoints() after reset = %+v, %v", checkpoints, err)
+	}
+}
+
+func TestCloseMissingJobs(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+	for _, id := range []string{"remoteok-1", "remoteok-2", "remoteok-3", "remotive-1"} {
+		job := &models.JobPost{ID: id, Fields: map[string]interface{}{"connector": id[:len(id)-2]}}
+		if err := store.CreateJob(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.CloseJob("remoteok-3", time.Now(), "removed")
+
+	if _, err := store.CloseMissingJobs("remoteok", nil, time.Now()); err == nil {
+		t.Error("Expected an empty listing to be refused")
+	}
+	closed, err := store.CloseMissingJobs("remoteok", []string{"remoteok-2"}, time.Now())
+	if err != nil || len(closed) != 1 || closed[0] != "remoteok-1" {
+		t.Errorf("CloseMissingJobs() = %v, %v; want only remoteok-1 (remoteok-3 was already closed)", closed, err)
+	}
+	if ids, _ := store.GetOpenJobIDs("remoteok"); len(ids) != 1 || ids[0] != "remoteok-2" {
+		t.Errorf("GetOpenJobIDs() = %v", ids)
+	}
+}
+
+// TestGetJobsByFieldPages checks that a field value needing escaping matches, and that
+// more matches than max-rows are all returned
+func TestGetJobsByFieldPages(t *testing.T) {
+	server := NewServer(t)
+	for i := 0; i < server.MaxRows+5; i++ {
+		row := map[string]interface{}{
+			"id":     fmt.Sprintf("hackernews:%04d", i),
+			"fields": map[string]interface{}{"thread": "Who is hiring? (October & more)"},
+		}
+		if err := server.Insert("job_posts", row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := map[string]interface{}{"id": "hackernews:other", "fields": map[string]interface{}{"thread": "Who is hiring?"}}
+	if err := server.Insert("job_posts", other); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := server.Store().GetJobsByField("thread", "Who is hiring? (October & more)")
+	if err != nil || len(jobs) != server.MaxRows+5 {
+		t.Fatalf("GetJobsByField() = %d jobs, %v; want %d", len(jobs), err, server.MaxRows+5)
+	}
+	if jobs[0].ID != "hackernews:0000" || jobs[len(jobs)-1].ID != fmt.Sprintf("hackernews:%04d", server.MaxRows+4) {
+		t.Errorf("Expected the jobs in ID order, got %s..%s", jobs[0].ID, jobs[len(jobs)-1].ID)
+	}
+}
+
+// TestGetJobsByCompanyKeyPages checks that an employer with more jobs than max-rows
+// gets all of them, within the posted date window
+func TestGetJobsByCompanyKeyPages(t *testing.T) {
+	server := NewServer(t)
+	posted := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < server.MaxRows+5; i++ {
+		row := map[string]interface{}{
+			"id":          fmt.Sprintf("ats:acme-%04d", i),
+			"posted_date": posted.Add(time.Duration(i) * time.Minute),
+			"fields":      map[string]interface{}{"company_key": "acme"},
+		}
+		if err := server.Insert("job_posts", row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := map[string]interface{}{"id": "ats:acme-old", "posted_date": posted.AddDate(-1, 0, 0), "fields": map[string]interface{}{"company_key": "acme"}}
+	if err := server.Insert("job_posts", old); err != nil {
+		t.Fatal(err)
+	}
+
+	jobs, err := server.Store().GetJobsByCompanyKey("acme", posted.AddDate(0, 0, -7), posted.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != server.MaxRows+5 || jobs[len(jobs)-1].ID != fmt.Sprintf("ats:acme-%04d", server.MaxRows+4) {
+		t.Errorf("Expected %d jobs up to the newest, got %d", server.MaxRows+5, len(jobs))
+	}
+}
+
+func TestGetJobOrAlias(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+	if err := store.CreateJob(&models.JobPost{ID: "arbetsformedlingen:1", Title: "Developer"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Insert("job_id_aliases", map[string]interface{}{"alias": "af-1", "job_id": "arbetsformedlingen:1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"arbetsformedlingen:1", "af-1"} {
+		if job, err := store.GetJobOrAlias(id); err != nil || job.ID != "arbetsformedlingen:1" {
+			t.Errorf("GetJobOrAlias(%s) = %v, %v; want arbetsformedlingen:1", id, job, err)
+		}
+	}
+	if _, err := store.GetJobOrAlias("af-2"); err == nil || err.Error() != "sql: no rows in result set" {
+		t.Errorf("Expected an unknown ID to be not found, got %v", err)
+	}
+}
+
+func TestSearchJobsBySalary(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+
+	posted := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, job := range []*models.JobPost{
+		{ID: "af-1", SalaryCurrency: "SEK", Fields: map[string]interface{}{"salary_annual_min": 540000, "salary_annual_max": 660000}},
+		{ID: "remotive-1", SalaryCurrency: "USD", Fields: map[string]interface{}{"salary_annual_min": 117000, "salary_annual_max": 140400}},
+		{ID: "af-2", SalaryCurrency: "SEK"},
+	} {
+		job.Title, job.PostedDate = "Developer", posted.AddDate(0, 0, i)
+		if err := store.CreateJob(job); err != nil {
+			t.Fatalf("CreateJob(%s) failed: %v", job.ID, err)
+		}
+	}
+
+	job, err := store.GetJob("af-1")
+	if err != nil || job.SalaryAnnualMinEUR == nil || *job.SalaryAnnualMinEUR != 49091 || *job.SalaryAnnualMaxEUR != 60000 ||
+		job.Fields["salary_rates_version"] != currency.Default.Version {
+		t.Errorf("Expected annual EUR salary on af-1: %+v, %v", job, err)
+	}
+
+	for _, tt := range []struct {
+		filter storage.JobFilter
+		want   []string
+	}{
+		{storage.JobFilter{}, []string{"af-2", "remotive-1", "af-1"}},
+		{storage.JobFilter{MinSalaryEUR: 50000}, []string{"remotive-1"}},
+		{storage.JobFilter{MaxSalaryEUR: 110000}, []string{"af-1"}},
+		{storage.JobFilter{MinSalaryEUR: 40000, MaxSalaryEUR: 130000}, []string{"remotive-1", "af-1"}},
+	} {
+		jobs, err := store.SearchJobs(tt.filter, 20, 0)
+		var ids []string
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		if err != nil || strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("SearchJobs(%+v) = %v, %v; want %v", tt.filter, ids, err, tt.want)
+		}
+	}
+
+	// Storing the first job stored the active table; saving it again upserts the same rows
+	if rows := server.Rows("exchange_rates"); len(rows) != len(currency.Default.Rates) || rows[0]["version"] != currency.Default.Version {
+		t.Errorf("Expected the active rates to be stored with the first job, got %d rows", len(rows))
+	}
+	if err := store.SaveExchangeRates(currency.Default); err != nil || len(server.Rows("exchange_rates")) != len(currency.Default.Rates) {
+		t.Errorf("SaveExchangeRates() stored %d rows, %v", len(server.Rows("exchange_rates")), err)
+	}
+}
+
+func TestSearchJobsByLocation(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+
+	posted := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, job := range []*models.JobPost{
+		{ID: "af-1", Location: "Göteborg, Västra Götalands län, Sverige"},
+		{ID: "af-2", Location: "Stockholm, Stockholms län, Sverige"},
+		{ID: "remotive-1", Location: "Europe", IsRemote: true},
+		{ID: "remotive-2", Location: "Sweden", IsRemote: true},
+	} {
+		job.Title, job.PostedDate = "Developer", posted.AddDate(0, 0, i)
+		if err := store.CreateJob(job); err != nil {
+			t.Fatalf("CreateJob(%s) failed: %v", job.ID, err)
+		}
+	}
+
+	job, err := store.GetJob("af-1")
+	if err != nil || job.LocationCountry != "SE" || job.LocationCity != "Gothenburg" || job.Latitude == nil {
+		t.Errorf("Expected a structured location on af-1: %+v, %v", job, err)
+	}
+
+	for _, tt := range []struct {
+		filter storage.JobFilter
+		want   []string
+	}{
+		{storage.JobFilter{Country: "Sverige"}, []string{"remotive-2", "af-2", "af-1"}},
+		{storage.JobFilter{City: "göteborg"}, []string{"af-1"}},
+		{storage.JobFilter{Region: "Stockholms län"}, []string{"af-2"}},
+		{storage.JobFilter{RemoteScope: "europe"}, []string{"remotive-1"}},
+		{storage.JobFilter{Country: "se", RemoteScope: "country"}, []string{"remotive-2"}},
+	} {
+		jobs, err := store.SearchJobs(tt.filter, 20, 0)
+		var ids []string
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		if err != nil || strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("SearchJobs(%+v) = %v, %v; want %v", tt.filter, ids, err, tt.want)
+		}
+	}
+}
+
+func TestSearchJobsNear(t *testing.T) {
+	server := NewServer(t)
+	store := server.Store()
+
+	posted := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	for i, job := range []*models.JobPost{
+		{ID: "af-1", Location: "Uppsala, Uppsala län, Sverige", Fields: map[string]interface{}{"coordinates": []float64{17.70, 59.80}}},
+		{ID: "jooble-1", Location: "Uppsala, Sweden"}, // Geocoded from the city
+		{ID: "af-2", Location: "Enköping, Uppsala län, Sverige"},
+		{ID: "af-3", Location: "Stockholm, Stockholms län, Sverige"},
+		{ID: "remotive-1", Location: "Worldwide", IsRemote: true},
+	} {
+		job.Title, job.PostedDate = "Developer", posted.AddDate(0, 0, i)
+		if err := store.CreateJob(job); err != nil {
+			t.Fatalf("CreateJob(%s) failed: %v", job.ID, err)
+		}
+	}
+
+	uppsala := &geo.Point{Lat: 59.85882, Lon: 17.63889}
+	for _, tt := range []struct {
+		filter        storage.JobFilter
+		limit, offset int
+		want          []string
+	}{
+		{storage.JobFilter{Near: uppsala, RadiusKm: 30}, 20, 0, []string{"jooble-1", "af-1"}},
+		{storage.JobFilter{Near: uppsala, RadiusKm: 50}, 20, 0, []string{"jooble-1", "af-1", "af-2"}},
+		{storage.JobFilter{Near: uppsala, RadiusKm: 50}, 1, 1, []string{"af-1"}},
+		{storage.JobFilter{Near: uppsala, RadiusKm: 80, City: "Stockholm"}, 20, 0, []string{"af-3"}},
+		{storage.JobFilter{Near: uppsala, RadiusKm: 50}, 20, 5, nil},
+	} {
+		jobs, err := store.SearchJobs(tt.filter, tt.limit, tt.offset)
+		var ids []string
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		if err != nil || strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("SearchJobs(%+v, %d, %d) = %v, %v; want %v", tt.filter, tt.limit, tt.offset, ids, err, tt.want)
+		}
+	}
+
+	jobs, _ := store.SearchJobs(storage.JobFilter{Near: uppsala, RadiusKm: 30}, 20, 0)
+	if len(jobs) != 2 || jobs[0].Fields["distance_km"] != 0.0 || jobs[1].Fields["distance_km"] != 7.4 || jobs[1].Location == "" {
+		t.Errorf("Expected whole jobs with their distance: %+v", jobs)
+	}
+}
+
+// TestSearchJobsNearPages checks that a radius search ranks every candidate when there
+// are more of them than max-rows, not just the first page by ID
+func TestSearchJobsNearPages(t *testing.T) {
+	server := NewServer(t)
+	total := server.MaxRows + 5
+	// The higher the ID, the closer to the center
+	for i := 0; i < total; i++ {
+		lat, lon := 59.33+float64(total-i)*0.0001, 18.07
+		row := map[string]interface{}{
+			"id":        fmt.Sprintf("af-%04d", i),
+			"latitude":  lat,
+			"longitude": lon,
+			"geohash":   geo.Geohash(lat, lon, geo.GeohashPrecision),
+		}
+		if err := server.Insert("job_posts", row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store := server.Store()
+
+	stockholm := &geo.Point{Lat: 59.33, Lon: 18.07}
+	for _, tt := range []struct {
+		limit, offset int
+		want          []string
+	}{
+		{3, 0, []string{fmt.Sprintf("af-%04d", total-1), fmt.Sprintf("af-%04d", total-2), fmt.Sprintf("af-%04d", total-3)}},
+		{10, total - 2, []string{"af-0001", "af-0000"}},
+	} {
+		jobs, err := store.SearchJobs(storage.JobFilter{Near: stockholm, RadiusKm: 30}, tt.limit, tt.offset)
+		var ids []string
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		if err != nil || strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("SearchJobs(%d, %d) = %v, %v; want %v", tt.limit, tt.offset, ids, err, tt.want)
+		}
+	}
+}
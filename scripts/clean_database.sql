@@ -7,10 +7,20 @@ DELETE FROM job_posts;
 -- Clear all sync logs
 DELETE FROM sync_logs;
 
+-- Clear connector checkpoints so every connector starts from scratch
+DELETE FROM connector_checkpoints;
+
+-- Clear golden records (they merge job posts that are gone now)
+DELETE FROM job_golden_records;
+
 -- Verify cleanup
 SELECT 'job_posts' as table_name, COUNT(*) as remaining_rows FROM job_posts
 UNION ALL
-SELECT 'sync_logs' as table_name, COUNT(*) as remaining_rows FROM sync_logs;
+SELECT 'sync_logs' as table_name, COUNT(*) as remaining_rows FROM sync_logs
+UNION ALL
+SELECT 'connector_checkpoints' as table_name, COUNT(*) as remaining_rows FROM connector_checkpoints
+UNION ALL
+SELECT 'job_golden_records' as table_name, COUNT(*) as remaining_rows FROM job_golden_records;
 
 -- Success message
 SELECT 'OpenJobs database cleaned! Ready for fresh sync.' as status;